@@ -2,17 +2,43 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"free-games-scrape/internal/app"
+	"free-games-scrape/internal/bot"
+	"free-games-scrape/internal/setup"
+	"free-games-scrape/internal/smoketest"
+	"free-games-scrape/internal/transfer"
 	"github.com/joho/godotenv"
 )
 
 func main() {
+	// `setup` writes a .env file interactively instead of running the bot
+	if len(os.Args) > 1 && os.Args[1] == "setup" {
+		os.Exit(setup.Main(os.Args[2:]))
+	}
+
 	// Load .env file
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found or error loading it, using system environment variables")
 	}
 
+	// `export` and `import` copy games and server settings between instances;
+	// `smoketest` checks a deployment end to end; `cleanup-commands` deletes
+	// every registered slash command
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "smoketest":
+			os.Exit(smoketest.Main(os.Args[2:]))
+		case "export":
+			os.Exit(transfer.ExportMain(os.Args[2:]))
+		case "import":
+			os.Exit(transfer.ImportMain(os.Args[2:]))
+		case "cleanup-commands":
+			os.Exit(bot.CleanupCommandsMain(os.Args[2:]))
+		}
+	}
+
 	// Create and run the application
 	application, err := app.New()
 	if err != nil {
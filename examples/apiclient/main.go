@@ -0,0 +1,47 @@
+// Command apiclient shows how to query a running bot with pkg/client.
+//
+//	go run ./examples/apiclient -url http://localhost:3000
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"free-games-scrape/pkg/client"
+)
+
+func main() {
+	baseURL := flag.String("url", "http://localhost:3000", "Base URL of the bot's web server")
+	token := flag.String("token", os.Getenv("API_TOKEN"), "Bearer token for authenticated endpoints")
+	flag.Parse()
+
+	c, err := client.New(*baseURL, client.WithToken(*token))
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	status, err := c.Status(ctx)
+	if err != nil {
+		if errors.Is(err, client.ErrRateLimited) {
+			log.Fatalf("Rate limited by the bot, try again later: %v", err)
+		}
+		log.Fatalf("Failed to get status: %v", err)
+	}
+	fmt.Printf("Status: %s (%d servers, %d games, uptime %s)\n",
+		status.Status, status.ServerCount, status.GameCount, status.Uptime)
+
+	games, err := c.Games(ctx)
+	if err != nil {
+		log.Fatalf("Failed to get games: %v", err)
+	}
+	fmt.Printf("Games: %d free now, %d coming soon (updated %s)\n",
+		games.FreeNow, games.ComingSoon, games.LastUpdated.Format(time.RFC1123))
+}
@@ -2,6 +2,8 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"free-games-scrape/internal/bot"
 	"free-games-scrape/internal/config"
 	"free-games-scrape/internal/database"
@@ -9,16 +11,28 @@ import (
 	"free-games-scrape/internal/metrics"
 	"free-games-scrape/internal/models"
 	"free-games-scrape/internal/ratelimit"
-	"free-games-scrape/internal/scraper"
 	"free-games-scrape/internal/security"
 	"free-games-scrape/internal/service"
 	"free-games-scrape/internal/web"
 	"log"
 	"os"
 	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 )
 
+// Version identifies this build in logs and the restart history
+const Version = "v2.0"
+
+// expiryReminderInterval is how often games ending soon are checked for
+// "last chance" reminders
+const expiryReminderInterval = 15 * time.Minute
+
+// channelReconcileInterval is how often channel reconciliation resumes or
+// checks whether a new pass is due
+const channelReconcileInterval = time.Hour
+
 // App represents the main application
 type App struct {
 	config      *config.Config
@@ -30,9 +44,18 @@ type App struct {
 	metrics     *metrics.Metrics
 	rateLimiter *ratelimit.DiscordRateLimiter
 	validator   *security.Validator
-	lastCheck   time.Time
+	lastScrape  []models.Game
+	restartID   int64
+	scheduleMu  sync.RWMutex
+	tickerStart time.Time
 	ctx         context.Context
 	cancel      context.CancelFunc
+
+	// lastScrapeAlerts is when operators were last told about each class of
+	// scrape failure
+	lastScrapeAlerts map[string]time.Time
+	// outageSince is when the current store outage started, zero if none
+	outageSince time.Time
 }
 
 // New creates a new application instance with enhanced features
@@ -45,7 +68,22 @@ func New() (*App, error) {
 
 	// Initialize logger
 	appLogger := logger.New(logger.LogLevel(cfg.App.LogLevel), cfg.App.Environment)
-	appLogger.Info("Starting Free Games Bot v2.0")
+	appLogger.Info("Starting Free Games Bot " + Version)
+
+	// Check that this host can run the configuration. Fatal issues stop
+	// startup; the rest only degrade the affected subsystem.
+	var fatal []error
+	for _, issue := range cfg.CheckEnvironment() {
+		if issue.Fatal {
+			appLogger.Error("Environment check failed", "subsystem", issue.Subsystem, "error", issue.Err)
+			fatal = append(fatal, issue)
+			continue
+		}
+		appLogger.Warn("Environment check found a degraded subsystem", "subsystem", issue.Subsystem, "error", issue.Err)
+	}
+	if len(fatal) > 0 {
+		return nil, fmt.Errorf("environment check failed: %w", errors.Join(fatal...))
+	}
 
 	// Validate Discord token
 	validator := security.NewValidator()
@@ -53,47 +91,27 @@ func New() (*App, error) {
 		return nil, err
 	}
 
-	// Initialize metrics
-	appMetrics := metrics.New()
-
-	// Initialize rate limiter
-	rateLimiter := ratelimit.NewDiscordRateLimiter()
-
-	// Initialize database
-	db, err := database.New(cfg.Database.Path)
-	if err != nil {
-		return nil, err
-	}
-
-	// Initialize Epic Games scraper
-	epicScraper := scraper.NewEpicScraper(&cfg.Scraper)
-
-	// Initialize game service
-	gameService := service.NewGameService(db, epicScraper)
-
-	// Initialize Discord bot with game service and database
-	discordBot, err := bot.NewDiscordBot(&cfg.Discord, gameService, db)
+	components, err := NewComponents(cfg, appLogger)
 	if err != nil {
 		return nil, err
 	}
 
 	// Initialize web server for documentation
-	webServer := web.NewWebServer(cfg.Web.Port, gameService, db)
+	webServer := web.NewWebServer(&cfg.Web, components.GameService, components.DB, components.Metrics, components.Bot, appLogger)
 
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &App{
 		config:      cfg,
-		discordBot:  discordBot,
-		gameService: gameService,
-		db:          db,
+		discordBot:  components.Bot,
+		gameService: components.GameService,
+		db:          components.DB,
 		webServer:   webServer,
 		logger:      appLogger,
-		metrics:     appMetrics,
-		rateLimiter: rateLimiter,
+		metrics:     components.Metrics,
+		rateLimiter: components.RateLimiter,
 		validator:   validator,
-		lastCheck:   time.Now(),
 		ctx:         ctx,
 		cancel:      cancel,
 	}, nil
@@ -101,6 +119,8 @@ func New() (*App, error) {
 
 // Run starts the application
 func (a *App) Run() error {
+	uncleanRestarts := a.recordStartup()
+
 	// Start web server in a goroutine
 	go func() {
 		log.Println("Starting web server for documentation...")
@@ -110,74 +130,243 @@ func (a *App) Run() error {
 	}()
 
 	// Start Discord bot
+	a.discordBot.SetSchedule(a.config.App.RefreshInterval, a.NextCheck)
 	if err := a.discordBot.Start(); err != nil {
 		return err
 	}
 	defer a.discordBot.Stop()
 	defer a.db.Close()
+	defer a.shutdown()
 
 	// Handle graceful shutdown
 	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
-	// Run initial scraping immediately on startup
-	log.Println("Running initial game check...")
-	if err := a.performGameCheck(); err != nil {
-		log.Printf("Initial scraping failed: %v", err)
-		a.discordBot.SendErrorMessage("Failed to perform initial game check. Will retry in 24 hours.")
+	if !a.handleCrashLoop(uncleanRestarts, stop) {
+		log.Println("Received shutdown signal")
+		a.recordCleanShutdown()
+		return nil
 	}
 
-	// Ticker for periodic scraping (every 6 hours for more frequent updates)
-	ticker := time.NewTicker(6 * time.Hour)
-	defer ticker.Stop()
+	// Cancel in-flight scrapes as soon as a shutdown signal arrives
+	go func() {
+		select {
+		case <-stop:
+			log.Println("Received shutdown signal")
+			a.cancel()
+		case <-a.ctx.Done():
+		}
+	}()
+
+	// Run initial scraping on startup, unless a previous run scraped within
+	// half an interval, so restarts do not hammer the stores. Skipping keeps
+	// the gap until the first scheduled check within StaleFactor intervals.
+	refresh, err := a.gameService.ShouldRefresh(a.config.App.RefreshInterval / 2)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	if !refresh {
+		log.Println("Skipping initial game check: games were scraped less than half a refresh interval ago")
+	} else {
+		log.Println("Running initial game check...")
+		if err := a.performGameCheck(); err != nil && a.ctx.Err() == nil {
+			log.Printf("Initial scraping failed: %v", err)
+			a.discordBot.SendErrorMessage(fmt.Sprintf("Failed to perform initial game check. Will retry in %s.", a.config.App.RefreshInterval))
+		}
+	}
+
+	if a.config.Scraper.StoreURLBackfill {
+		go a.runStoreURLBackfill()
+	}
+
+	// Schedule for periodic scraping (REFRESH_INTERVAL, at least 1 hour)
+	tickerStart := time.Now()
+	ticks := scheduleTicks(a.ctx, systemClock{}, tickerStart, a.config.App.RefreshInterval)
+	a.setTickerStart(tickerStart)
+	log.Printf("Checking for new games every %s", a.config.App.RefreshInterval)
+
+	// Ticker for "last chance" reminders before games end
+	a.sendExpiryReminders()
+	reminderTicker := time.NewTicker(expiryReminderInterval)
+	defer reminderTicker.Stop()
+
+	// Ticker for reconciling server configs against their channels. The first
+	// run waits a tick so the guild state cache is populated.
+	reconcileTicker := time.NewTicker(channelReconcileInterval)
+	defer reconcileTicker.Stop()
 
 	log.Println("Bot is now running. Press Ctrl+C to stop.")
 
 	for {
 		select {
-		case <-stop:
-			log.Println("Received shutdown signal")
+		case <-a.ctx.Done():
+			a.recordCleanShutdown()
 			return nil
-		case <-ticker.C:
+		case <-ticks:
 			log.Println("Performing scheduled game check...")
-			if err := a.performGameCheck(); err != nil {
+			if err := a.performGameCheck(); err != nil && a.ctx.Err() == nil {
 				log.Printf("Scheduled scraping failed: %v", err)
-				a.discordBot.SendErrorMessage("Failed to check for free games. Will retry in 6 hours.")
+				a.discordBot.SendErrorMessage(fmt.Sprintf("Failed to check for free games. Will retry in %s.", a.config.App.RefreshInterval))
 			}
+		case <-reminderTicker.C:
+			a.sendExpiryReminders()
+		case <-reconcileTicker.C:
+			a.discordBot.ReconcileChannels(a.ctx)
+		}
+	}
+}
+
+// excludeGames returns the games that are not part of collection
+func excludeGames(games []models.Game, collection *models.GameCollection) []models.Game {
+	skip := make(map[string]bool)
+	for _, game := range collection.All() {
+		skip[database.NotificationKey(game)] = true
+	}
+	var remaining []models.Game
+	for _, game := range games {
+		if !skip[database.NotificationKey(game)] {
+			remaining = append(remaining, game)
 		}
 	}
+	return remaining
+}
+
+// sendExpiryReminders posts reminders for Free Now games ending within
+// bot.ExpiryReminderWindow
+func (a *App) sendExpiryReminders() {
+	games, err := a.gameService.GetGamesEndingWithin(bot.ExpiryReminderWindow)
+	if err != nil {
+		log.Printf("Error getting games ending soon: %v", err)
+		return
+	}
+	a.discordBot.SendExpiryReminders(a.ctx, games)
+}
+
+// shutdown cancels background work and stops the web server, giving
+// in-flight requests up to GRACEFUL_TIMEOUT to finish
+func (a *App) shutdown() {
+	a.cancel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.config.App.GracefulTimeout)
+	defer cancel()
+
+	if err := a.webServer.Stop(ctx); err != nil {
+		log.Printf("Error stopping web server: %v", err)
+	}
+}
+
+// setTickerStart records when the periodic schedule started
+func (a *App) setTickerStart(t time.Time) {
+	a.scheduleMu.Lock()
+	defer a.scheduleMu.Unlock()
+	a.tickerStart = t
+}
+
+// NextCheck returns when the periodic schedule ticks next, or the zero time
+// before it has started. The schedule keeps a fixed cadence from its start, so
+// manual refreshes and slow checks do not move it.
+func (a *App) NextCheck() time.Time {
+	a.scheduleMu.RLock()
+	start := a.tickerStart
+	a.scheduleMu.RUnlock()
+
+	if start.IsZero() {
+		return time.Time{}
+	}
+	return nextTick(start, time.Now(), a.config.App.RefreshInterval)
 }
 
 // performGameCheck scrapes games and sends updates for new games only
-func (a *App) performGameCheck() error {
+func (a *App) performGameCheck() (err error) {
+	ctx := logger.ContextWithRequestID(a.ctx, logger.NewRequestID())
+	ctx = logger.ContextWithComponent(ctx, "scheduler")
+	cycleLogger := a.logger.WithContext(ctx)
+	start := time.Now()
+	cycleLogger.Info("Starting game check")
+	defer func() {
+		cycleLogger.WithError(err).WithFields(map[string]interface{}{
+			"duration_ms": time.Since(start).Milliseconds(),
+		}).Info("Game check finished")
+	}()
+
 	// Scrape games from Epic Games Store
-	scrapedGames, err := a.gameService.ScrapeGames()
+	scrapedGames, err := a.gameService.ScrapeGames(ctx)
+	if errors.Is(err, models.ErrScraperUnavailable) {
+		log.Printf("Skipping game check: %v", err)
+		return nil
+	}
 	if err != nil {
+		a.alertScrapeFailure(err)
 		return err
 	}
+	a.outageSince = time.Time{}
 
 	if len(scrapedGames) == 0 {
 		log.Println("No games found during scraping")
 		return nil
 	}
 
-	// Get current games from database to compare
+	// Get current games from database for the first ops changelog
 	currentGames, err := a.gameService.GetActiveGames()
 	if err != nil {
 		return err
 	}
 
-	// Find truly new games by comparing scraped games with database
-	newGames := a.findNewGames(scrapedGames, currentGames)
+	// Diff against the previous scrape (or the database on the first cycle
+	// after startup) for the ops changelog
+	previous := a.lastScrape
+	if previous == nil {
+		previous = currentGames.All()
+	}
+	changes := models.DiffGames(previous, scrapedGames, time.Now())
+	a.lastScrape = scrapedGames
 
 	// Save all scraped games to database (updates existing, adds new)
-	if err := a.gameService.SaveGames(scrapedGames); err != nil {
+	edits, err := a.gameService.SaveGames(scrapedGames)
+	if err != nil {
+		return err
+	}
+	// Edits saved by manual refreshes since the last check go the same way
+	edits = append(a.gameService.TakeRefreshEdits(), edits...)
+
+	// Promotions whose dates the store corrected keep their announcements,
+	// which are edited below, so this runs before new games are looked up
+	edits = append(edits, a.gameService.CorrectAnnouncedDates(changes)...)
+
+	// The changelog includes the field-level edits recorded while saving
+	if err := a.discordBot.SendOpsChangelog(changes, edits); err != nil {
+		log.Printf("Error sending ops changelog: %v", err)
+	}
+
+	// New games are the ones never announced, however they got into the database
+	newGames, err := a.gameService.GetUnnotifiedGames(scrapedGames)
+	if err != nil {
 		return err
 	}
+	for _, game := range newGames.All() {
+		log.Printf("Found new game: %s (Status: %s, Free until: %s)", game.Title, game.Status, game.FreeTo)
+	}
+
+	// Coming Soon games that became free since they were stored are announced
+	// as released first, so servers get the "Now Available" post rather than
+	// a plain one. New games are announced below.
+	if released := excludeGames(models.ReleasedGames(scrapedGames, edits), newGames); len(released) > 0 {
+		log.Printf("%d Coming Soon games became free", len(released))
+		if err := a.discordBot.SendReleaseUpdates(released); err != nil {
+			log.Printf("Error sending release updates: %v", err)
+		}
+	}
 
-	// Send updates to Discord only for new games
+	// Every server is offered all current games and gets the ones it was not
+	// told about yet, so servers configured since the last check catch up
+	// Games held for link verification are marked notified by the bot once
+	// they are announced, so only the ones announced now are marked here
+	announced, err := a.discordBot.SendGameUpdates(models.NewGameCollection(scrapedGames), newGames)
+	if err != nil {
+		return err
+	}
 	if len(newGames.FreeNow) > 0 || len(newGames.ComingSoon) > 0 {
-		if err := a.discordBot.SendGameUpdates(newGames); err != nil {
+		if err := a.gameService.MarkGamesNotified(announced); err != nil {
 			return err
 		}
 		log.Printf("Sent updates for %d new Free Now games and %d new Coming Soon games",
@@ -186,39 +375,9 @@ func (a *App) performGameCheck() error {
 		log.Println("No new games found since last check")
 	}
 
-	// Update last check time
-	a.lastCheck = time.Now()
+	// Edit the messages posted with dates that turned out to be wrong
+	a.discordBot.CorrectPostedMessages(a.ctx)
 
 	return nil
 }
 
-// findNewGames compares scraped games with current database games to find truly new ones
-func (a *App) findNewGames(scrapedGames []models.Game, currentGames *models.GameCollection) *models.GameCollection {
-	// Create a map of existing games with their free-to dates for quick lookup
-	// Key format: "GameTitle|FreeTo" to handle cases where the same game becomes free again
-	existingGames := make(map[string]bool)
-	
-	// Add all current games to the map
-	for _, game := range currentGames.FreeNow {
-		key := game.Title + "|" + game.FreeTo
-		existingGames[key] = true
-	}
-	for _, game := range currentGames.ComingSoon {
-		key := game.Title + "|" + game.FreeTo
-		existingGames[key] = true
-	}
-
-	// Find games that are in scraped but not in existing with the same free-to date
-	var newGames []models.Game
-	for _, game := range scrapedGames {
-		key := game.Title + "|" + game.FreeTo
-		if !existingGames[key] {
-			newGames = append(newGames, game)
-			log.Printf("Found new game: %s (Status: %s, Free until: %s)", 
-				game.Title, game.Status, game.FreeTo)
-		}
-	}
-
-	return models.NewGameCollection(newGames)
-}
-
@@ -0,0 +1,25 @@
+package app
+
+import (
+	"testing"
+
+	"free-games-scrape/internal/models"
+)
+
+func TestExcludeGames(t *testing.T) {
+	released := models.Game{Title: "Released Game", Status: models.StatusFreeNow, FreeTo: "Jan 15"}
+	alsoNew := models.Game{Title: "Also New", Status: models.StatusFreeNow, FreeTo: "Jan 15"}
+	rerun := models.Game{Title: "Released Game", Status: models.StatusFreeNow, FreeTo: "Feb 15"}
+
+	// A game announced as new this check doesn't get a release post too,
+	// but another promotion of the same title does
+	newGames := models.NewGameCollection([]models.Game{alsoNew, rerun})
+	remaining := excludeGames([]models.Game{released, alsoNew}, newGames)
+	if len(remaining) != 1 || remaining[0].Title != "Released Game" || remaining[0].FreeTo != "Jan 15" {
+		t.Errorf("excludeGames = %v, want only Released Game until Jan 15", remaining)
+	}
+
+	if remaining := excludeGames([]models.Game{released}, models.NewGameCollection(nil)); len(remaining) != 1 {
+		t.Errorf("excludeGames with nothing to exclude = %v, want the game kept", remaining)
+	}
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"log"
+
+	"free-games-scrape/internal/bot"
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/logger"
+	"free-games-scrape/internal/metrics"
+	"free-games-scrape/internal/ratelimit"
+	"free-games-scrape/internal/scraper"
+	"free-games-scrape/internal/service"
+)
+
+// Components are the parts of the application wired from a configuration.
+// Run drives them on a schedule; one-off commands such as the smoke test
+// build and drive them on their own.
+type Components struct {
+	Config      *config.Config
+	Logger      *logger.Logger
+	Metrics     *metrics.Metrics
+	RateLimiter *ratelimit.DiscordRateLimiter
+	DB          *database.Database
+	GameService *service.GameService
+	Bot         *bot.DiscordBot
+}
+
+// NewComponents opens the database at cfg.Database.Path and wires the game
+// service and Discord bot around it. Without scrapers, the ones configured in
+// cfg.Scraper are used. The Discord connection is not opened.
+func NewComponents(cfg *config.Config, appLogger *logger.Logger, scrapers ...scraper.Scraper) (*Components, error) {
+	// Initialize metrics
+	appMetrics := metrics.New()
+
+	// Initialize rate limiter
+	rateLimiter := ratelimit.NewDiscordRateLimiter(cfg.Discord.RateLimitIdleTimeout)
+
+	// Initialize database
+	db, err := database.New(cfg.Database.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(scrapers) == 0 && !cfg.Scraper.Enabled {
+		log.Println("Scraping is disabled (SCRAPING_ENABLED=false); serving stored games only")
+	} else if len(scrapers) == 0 {
+		// Initialize Epic Games scraper (JSON API, headless Chrome, or API with Chrome fallback)
+		gameScraper, err := scraper.New(&cfg.Scraper)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+
+		scrapers = []scraper.Scraper{gameScraper}
+		if cfg.Scraper.GOGEnabled {
+			scrapers = append(scrapers, scraper.NewGOGScraper(&cfg.Scraper))
+		}
+	}
+
+	// Initialize game service
+	gameService := service.NewGameService(db, appMetrics, scrapers...)
+	gameService.SetRefreshInterval(cfg.App.RefreshInterval)
+
+	// Initialize Discord bot with game service and database
+	discordBot, err := bot.NewDiscordBot(&cfg.Discord, gameService, db, appLogger, appMetrics, rateLimiter)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Components{
+		Config:      cfg,
+		Logger:      appLogger,
+		Metrics:     appMetrics,
+		RateLimiter: rateLimiter,
+		DB:          db,
+		GameService: gameService,
+		Bot:         discordBot,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// crashLoopWindow is how far back unclean restarts are counted
+const crashLoopWindow = time.Hour
+
+// recordStartup records this run and reports how many unclean restarts
+// happened within crashLoopWindow when the previous run did not shut down
+// cleanly. A count above CRASH_LOOP_THRESHOLD means the bot is crash looping.
+func (a *App) recordStartup() int {
+	previous, err := a.db.GetRestarts(1)
+	if err != nil {
+		log.Printf("Error loading restart history: %v", err)
+	}
+
+	now := time.Now()
+	id, err := a.db.RecordStartup(Version, now)
+	if err != nil {
+		log.Printf("Error recording startup: %v", err)
+		return 0
+	}
+	a.restartID = id
+
+	if len(previous) == 0 || previous[0].CleanShutdown {
+		return 0
+	}
+
+	unclean, err := a.db.CountUncleanRestarts(now.Add(-crashLoopWindow), id)
+	if err != nil {
+		log.Printf("Error counting unclean restarts: %v", err)
+		return 0
+	}
+
+	log.Printf("Previous run (started %s) did not shut down cleanly; %d unclean restarts in the last %s",
+		previous[0].StartedAt.Format(time.RFC3339), unclean, crashLoopWindow)
+	return unclean
+}
+
+// handleCrashLoop warns the owner about a crash loop and waits out the
+// configured backoff before scraping. It returns false if stop fired first.
+func (a *App) handleCrashLoop(unclean int, stop <-chan os.Signal) bool {
+	if unclean <= a.config.App.CrashLoopThreshold {
+		return true
+	}
+
+	backoff := a.config.App.CrashLoopBackoff
+	message := fmt.Sprintf("Crash loop detected: %d unclean restarts in the last %s. Delaying the first scrape by %s.",
+		unclean, crashLoopWindow, backoff)
+	a.logger.WithFields(map[string]interface{}{
+		"unclean_restarts": unclean,
+		"backoff":          backoff.String(),
+	}).Warn("Crash loop detected")
+
+	if err := a.discordBot.NotifyOwner(message); err != nil {
+		log.Printf("Error notifying owner about crash loop: %v", err)
+	}
+
+	if backoff <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// recordCleanShutdown flags the current run as having stopped gracefully
+func (a *App) recordCleanShutdown() {
+	if a.restartID == 0 {
+		return
+	}
+	if err := a.db.MarkCleanShutdown(a.restartID, time.Now()); err != nil {
+		log.Printf("Error recording clean shutdown: %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"free-games-scrape/internal/bot"
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/logger"
+	"free-games-scrape/internal/metrics"
+	"free-games-scrape/internal/ratelimit"
+)
+
+// ownerDMs points the Discord endpoints at a fake server and returns the
+// messages it received in DMs
+func ownerDMs(t *testing.T) func() []string {
+	t.Helper()
+	var mu sync.Mutex
+	var messages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/users/@me/channels":
+			io.WriteString(w, `{"id": "dm"}`)
+		case r.URL.Path == "/api/channels/dm/messages":
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			messages = append(messages, string(body))
+			mu.Unlock()
+			io.WriteString(w, `{"id": "1"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"message": "Unknown"}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	endpoints := []*string{&discordgo.EndpointAPI, &discordgo.EndpointChannels, &discordgo.EndpointUsers}
+	api := discordgo.EndpointAPI
+	saved := make([]string, len(endpoints))
+	for i, endpoint := range endpoints {
+		saved[i] = *endpoint
+		*endpoint = server.URL + "/api/" + strings.TrimPrefix(*endpoint, api)
+	}
+	t.Cleanup(func() {
+		for i, endpoint := range endpoints {
+			*endpoint = saved[i]
+		}
+	})
+
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), messages...)
+	}
+}
+
+// newRestartApp returns an app with just what the restart tracking needs,
+// sharing an in-memory database between apps of the same test
+func newRestartApp(t *testing.T, appConfig config.AppConfig) *App {
+	t.Helper()
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := database.New(fmt.Sprintf("file:%s?mode=memory&cache=shared", name))
+	if err != nil {
+		t.Fatalf("database.New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	rateLimiter := ratelimit.NewDiscordRateLimiter(0)
+	t.Cleanup(rateLimiter.Close)
+	appLogger := logger.NewWithOutput(logger.LevelError, "test", io.Discard)
+	discordConfig := &config.DiscordConfig{Token: "token", OwnerID: "owner", MaxConcurrentHandlers: 1}
+	discordBot, err := bot.NewDiscordBot(discordConfig, nil, db, appLogger, metrics.New(), rateLimiter)
+	if err != nil {
+		t.Fatalf("NewDiscordBot: %v", err)
+	}
+
+	return &App{
+		config:     &config.Config{App: appConfig},
+		discordBot: discordBot,
+		db:         db,
+		logger:     appLogger,
+	}
+}
+
+func TestRecordStartupCountsUncleanRestarts(t *testing.T) {
+	a := newRestartApp(t, config.AppConfig{CrashLoopThreshold: 3})
+
+	// run starts the bot again on the same database, as a restart would
+	run := func() int {
+		a = &App{config: a.config, discordBot: a.discordBot, db: a.db, logger: a.logger}
+		return a.recordStartup()
+	}
+
+	steps := []struct {
+		name  string
+		clean bool
+		want  int
+	}{
+		{name: "first start", want: 0},
+		{name: "after a clean shutdown", clean: true, want: 0},
+		{name: "after a crash", want: 1},
+		{name: "after a second crash", want: 2},
+		{name: "after a third crash", want: 3},
+		{name: "clean shutdown resets", clean: true, want: 0},
+		{name: "earlier crashes in the window still count", want: 4},
+	}
+	for i, step := range steps {
+		if i > 0 && step.clean {
+			a.recordCleanShutdown()
+		}
+		if got := run(); got != step.want {
+			t.Errorf("%s: recordStartup = %d, want %d", step.name, got, step.want)
+		}
+		if a.restartID == 0 {
+			t.Fatalf("%s: the run was not recorded", step.name)
+		}
+	}
+}
+
+func TestRecordStartupIgnoresOldCrashes(t *testing.T) {
+	a := newRestartApp(t, config.AppConfig{CrashLoopThreshold: 3})
+
+	// Crashes from yesterday don't count towards a loop today
+	for i := 0; i < 5; i++ {
+		if _, err := a.db.RecordStartup(Version, time.Now().Add(-24*time.Hour)); err != nil {
+			t.Fatalf("RecordStartup: %v", err)
+		}
+	}
+	if got := a.recordStartup(); got != 0 {
+		t.Errorf("recordStartup = %d, want 0 with every crash outside the window", got)
+	}
+}
+
+func TestHandleCrashLoop(t *testing.T) {
+	tests := []struct {
+		name       string
+		unclean    int
+		backoff    time.Duration
+		stop       bool
+		want       bool
+		wantNotify bool
+	}{
+		{name: "below the threshold", unclean: 2, backoff: time.Hour, want: true},
+		{name: "at the threshold", unclean: 3, backoff: time.Hour, want: true},
+		{name: "crash loop without backoff", unclean: 4, want: true, wantNotify: true},
+		{name: "crash loop waits out the backoff", unclean: 4, backoff: 10 * time.Millisecond, want: true, wantNotify: true},
+		{name: "stopped during the backoff", unclean: 4, backoff: time.Hour, stop: true, want: false, wantNotify: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := ownerDMs(t)
+			a := newRestartApp(t, config.AppConfig{CrashLoopThreshold: 3, CrashLoopBackoff: tt.backoff})
+
+			stop := make(chan os.Signal, 1)
+			if tt.stop {
+				stop <- syscall.SIGTERM
+			}
+			done := make(chan bool, 1)
+			go func() { done <- a.handleCrashLoop(tt.unclean, stop) }()
+
+			select {
+			case got := <-done:
+				if got != tt.want {
+					t.Errorf("handleCrashLoop = %v, want %v", got, tt.want)
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatal("handleCrashLoop did not return")
+			}
+
+			sent := messages()
+			if notified := len(sent) > 0; notified != tt.wantNotify {
+				t.Fatalf("owner notified %v (%q), want %v", notified, sent, tt.wantNotify)
+			}
+			if tt.wantNotify && !strings.Contains(sent[0], fmt.Sprintf("%d unclean restarts", tt.unclean)) {
+				t.Errorf("notification %q does not give the restart count", sent[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"time"
+)
+
+// clock is the scheduler's time source, replaced by a fake one in tests
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock is the real clock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// nextTick returns the first tick after now of a schedule ticking every
+// interval from start
+func nextTick(start, now time.Time, interval time.Duration) time.Time {
+	if now.Before(start) {
+		return start.Add(interval)
+	}
+	return start.Add((now.Sub(start)/interval + 1) * interval)
+}
+
+// scheduleTicks sends a tick every interval from start until ctx is done.
+// Each tick is computed from start rather than from the previous one, so timer
+// latency and slow checks don't make the schedule drift. Like time.Ticker, it
+// drops ticks while the previous one is unread.
+func scheduleTicks(ctx context.Context, clk clock, start time.Time, interval time.Duration) <-chan time.Time {
+	ticks := make(chan time.Time, 1)
+	go func() {
+		for {
+			now := clk.Now()
+			next := nextTick(start, now, interval)
+			select {
+			case <-ctx.Done():
+				return
+			case <-clk.After(next.Sub(now)):
+			}
+
+			select {
+			case ticks <- next:
+			default:
+			}
+		}
+	}()
+	return ticks
+}
@@ -0,0 +1,185 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock only moves when advanced. Waiters registered with After fire
+// once the clock reaches their deadline.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+	waiting chan struct{}
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now, waiting: make(chan struct{}, 1)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	select {
+	case c.waiting <- struct{}{}:
+	default:
+	}
+	return ch
+}
+
+// waitForWaiter blocks until the scheduler is waiting on the clock
+func (c *fakeClock) waitForWaiter(t *testing.T) {
+	t.Helper()
+	for {
+		c.mu.Lock()
+		n := len(c.waiters)
+		c.mu.Unlock()
+		if n > 0 {
+			return
+		}
+		select {
+		case <-c.waiting:
+		case <-time.After(10 * time.Second):
+			t.Fatal("the scheduler never waited on the clock")
+		}
+	}
+}
+
+// advance moves the clock forward and fires the waiters that are due
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	remaining := c.waiters[:0]
+	for _, waiter := range c.waiters {
+		if waiter.at.After(c.now) {
+			remaining = append(remaining, waiter)
+			continue
+		}
+		waiter.ch <- c.now
+	}
+	c.waiters = remaining
+}
+
+// runSchedule simulates period in steps of step on a fake clock, reading
+// every tick, and returns the ticks received
+func runSchedule(t *testing.T, interval, period, step time.Duration) []time.Time {
+	t.Helper()
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clk := newFakeClock(start)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ticks := scheduleTicks(ctx, clk, start, interval)
+
+	var received []time.Time
+	for elapsed := time.Duration(0); elapsed < period; elapsed += step {
+		clk.waitForWaiter(t)
+		clk.advance(step)
+		if (elapsed+step)%interval < step {
+			select {
+			case tick := <-ticks:
+				received = append(received, tick)
+			case <-time.After(10 * time.Second):
+				t.Fatalf("no tick after %s", elapsed+step)
+			}
+		}
+	}
+
+	select {
+	case tick := <-ticks:
+		t.Errorf("unexpected extra tick at %s", tick)
+	default:
+	}
+	return received
+}
+
+func TestScheduleTicksOverSimulatedDay(t *testing.T) {
+	tests := []struct {
+		name      string
+		interval  time.Duration
+		step      time.Duration
+		wantTicks int
+	}{
+		{name: "default interval", interval: 6 * time.Hour, step: time.Hour, wantTicks: 4},
+		{name: "shorter interval from the environment", interval: 2 * time.Hour, step: time.Hour, wantTicks: 12},
+		{name: "clock overshooting each tick", interval: 6 * time.Hour, step: 7 * time.Minute, wantTicks: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+			ticks := runSchedule(t, tt.interval, 24*time.Hour, tt.step)
+			if len(ticks) != tt.wantTicks {
+				t.Fatalf("got %d ticks in a day, want %d", len(ticks), tt.wantTicks)
+			}
+			// Late wake-ups don't shift later ticks off the fixed cadence
+			for i, tick := range ticks {
+				if want := start.Add(time.Duration(i+1) * tt.interval); !tick.Equal(want) {
+					t.Errorf("tick %d at %s, want %s", i+1, tick, want)
+				}
+			}
+		})
+	}
+}
+
+func TestScheduleTicksDropsTicksWhileBusy(t *testing.T) {
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clk := newFakeClock(start)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ticks := scheduleTicks(ctx, clk, start, time.Hour)
+
+	// A check running for three intervals gets one tick afterwards, not three
+	for i := 0; i < 3; i++ {
+		clk.waitForWaiter(t)
+		clk.advance(time.Hour)
+	}
+	clk.waitForWaiter(t)
+	if tick := <-ticks; !tick.Equal(start.Add(time.Hour)) {
+		t.Errorf("first tick at %s, want %s", tick, start.Add(time.Hour))
+	}
+	select {
+	case tick := <-ticks:
+		t.Errorf("got a queued tick at %s, want missed ticks dropped", tick)
+	default:
+	}
+
+	// The schedule carries on from its start
+	clk.advance(time.Hour)
+	if tick := <-ticks; !tick.Equal(start.Add(4 * time.Hour)) {
+		t.Errorf("next tick at %s, want %s", tick, start.Add(4*time.Hour))
+	}
+}
+
+func TestNextTick(t *testing.T) {
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		now  time.Time
+		want time.Time
+	}{
+		{now: start, want: start.Add(6 * time.Hour)},
+		{now: start.Add(5 * time.Hour), want: start.Add(6 * time.Hour)},
+		{now: start.Add(6 * time.Hour), want: start.Add(12 * time.Hour)},
+		{now: start.Add(-time.Minute), want: start.Add(6 * time.Hour)},
+	}
+	for _, tt := range tests {
+		if got := nextTick(start, tt.now, 6*time.Hour); !got.Equal(tt.want) {
+			t.Errorf("nextTick at %s = %s, want %s", tt.now, got, tt.want)
+		}
+	}
+}
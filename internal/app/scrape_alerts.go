@@ -0,0 +1,77 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"free-games-scrape/internal/models"
+	"free-games-scrape/internal/service"
+)
+
+// retryAlertInterval is the minimum time between alerts about the same class
+// of scrape failure, so a long outage does not flood the owner
+const retryAlertInterval = 6 * time.Hour
+
+// outageAlertDelay is how long a store outage must last before operators are
+// told, since the store usually recovers on its own
+const outageAlertDelay = 2 * time.Hour
+
+// maxAlertErrorLength caps the underlying error quoted in an alert
+const maxAlertErrorLength = 500
+
+// alertScrapeFailure tells the operators when a scheduled scrape failed
+// because its source exhausted every retry. Extraction failures, usually a
+// scraper bug, are reported right away, while store outages are only
+// reported once they lasted outageAlertDelay. Each failure class is reported
+// at most once per retryAlertInterval.
+func (a *App) alertScrapeFailure(err error) {
+	var exhausted *models.RetriesExhaustedError
+	if !errors.As(err, &exhausted) {
+		return
+	}
+
+	class := models.ClassifyScrapeFailure(err)
+	now := time.Now()
+	if class == models.FailureUpstreamOutage {
+		if a.outageSince.IsZero() {
+			a.outageSince = now
+		}
+		if now.Sub(a.outageSince) < outageAlertDelay {
+			log.Printf("Store outage since %s; alerting once it lasts %s", a.outageSince.Format(time.RFC3339), outageAlertDelay)
+			return
+		}
+	}
+
+	if a.lastScrapeAlerts == nil {
+		a.lastScrapeAlerts = make(map[string]time.Time)
+	}
+	if last := a.lastScrapeAlerts[class]; !last.IsZero() && now.Sub(last) < retryAlertInterval {
+		log.Printf("Scraper retries exhausted again (%s); alert suppressed until %s", class, last.Add(retryAlertInterval).Format(time.RFC3339))
+		return
+	}
+	a.lastScrapeAlerts[class] = now
+
+	lastErr := fmt.Sprint(exhausted.Err)
+	if len(lastErr) > maxAlertErrorLength {
+		lastErr = lastErr[:maxAlertErrorLength] + "…"
+	}
+
+	var message string
+	switch class {
+	case models.FailureUpstreamOutage:
+		message = fmt.Sprintf("⚠️ %s: %s has been failing since %s.\nLast error: %s\nThis looks like a store outage, not a scraper bug. No new free games are announced until the store recovers.",
+			service.OutageMessage, exhausted.Source, a.outageSince.UTC().Format(time.RFC3339), lastErr)
+	case models.FailureExtraction:
+		message = fmt.Sprintf("🐛 Scraping failed: %s loaded but no games could be read after %d attempts, which usually means the store changed its layout.\nLast error: %s\nNo new free games are announced until the scraper is fixed.",
+			exhausted.Source, exhausted.Attempts, lastErr)
+	default:
+		message = fmt.Sprintf("⚠️ Scraping failed (%s): %s gave up after %d attempts.\nLast error: %s\nNo new free games are announced until scraping recovers.",
+			class, exhausted.Source, exhausted.Attempts, lastErr)
+	}
+	message += fmt.Sprintf(" Further failures of this kind are not reported for %s.", retryAlertInterval)
+	if err := a.discordBot.NotifyOperators(message); err != nil {
+		log.Printf("Error alerting operators about a failed scrape: %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package app
+
+import (
+	"log"
+	"time"
+
+	"free-games-scrape/internal/scraper"
+)
+
+// runStoreURLBackfill looks up store links of stored games without one at
+// STORE_URL_LOOKUPS_PER_MINUTE until the app stops. Lookups that failed are
+// retried after the rest; ambiguous ones wait for /admin linkgame.
+func (a *App) runStoreURLBackfill() {
+	searcher := scraper.NewCatalogSearcher(&a.config.Scraper)
+	ticker := time.NewTicker(time.Minute / time.Duration(a.config.Scraper.StoreURLLookupsPerMinute))
+	defer ticker.Stop()
+
+	log.Printf("Backfilling missing store URLs at %d lookups per minute", a.config.Scraper.StoreURLLookupsPerMinute)
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := a.gameService.BackfillNextStoreURL(a.ctx, searcher); err != nil && a.ctx.Err() == nil {
+				log.Printf("Store URL backfill: %v", err)
+			}
+		}
+	}
+}
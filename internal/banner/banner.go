@@ -0,0 +1,227 @@
+// Package banner composes the announcement banner: the covers of the games
+// in a rotation side by side with their titles, on a background in the
+// instance's branding colors, encoded as a PNG ready to upload to Discord.
+package banner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"  // decode GIF covers
+	_ "image/jpeg" // decode JPEG covers
+	"image/png"
+	"io"
+	"net/http"
+)
+
+// Banner layout, in pixels. Covers are drawn 16:9, up to three per row.
+const (
+	Width       = 1200
+	MaxCovers   = 6
+	columns     = 3
+	padding     = 24
+	headerScale = 4
+	titleScale  = 3
+	titleGap    = 12
+)
+
+// Limits on the cover images downloaded, so a huge or hostile image can't
+// exhaust memory
+const (
+	maxImageBytes  = 8 << 20
+	maxImagePixels = 4096 * 4096
+)
+
+// ErrNoCovers is returned when none of the games' cover images could be
+// loaded, leaving nothing worth composing
+var ErrNoCovers = errors.New("no cover image could be loaded")
+
+// Cover is one game on the banner
+type Cover struct {
+	Title    string
+	ImageURL string
+}
+
+// Style is the branding the banner is drawn in
+type Style struct {
+	// Name is shown across the top of the banner
+	Name string
+	// AccentColor is the 0xRRGGBB color the background is derived from
+	AccentColor int
+}
+
+// Fetcher loads a cover image
+type Fetcher func(ctx context.Context, url string) (image.Image, error)
+
+// HTTPFetcher returns a Fetcher downloading covers with client. Images over
+// the size limits are refused before they are decoded.
+func HTTPFetcher(client *http.Client) Fetcher {
+	return func(ctx context.Context, url string) (image.Image, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > maxImageBytes {
+			return nil, fmt.Errorf("image is larger than %d bytes", maxImageBytes)
+		}
+		return decodeImage(data)
+	}
+}
+
+// decodeImage decodes a cover, checking its dimensions before allocating them
+func decodeImage(data []byte) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image header: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxImagePixels {
+		return nil, fmt.Errorf("image is %dx%d, larger than allowed", config.Width, config.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// Compose draws the banner for covers and returns it as a PNG. Covers are
+// fetched and drawn one at a time, so at most one source image is held in
+// memory. A cover that fails to load is drawn as a plain tile; when every
+// cover fails Compose returns ErrNoCovers. Covers past MaxCovers are left
+// out.
+func Compose(ctx context.Context, covers []Cover, style Style, fetch Fetcher) ([]byte, error) {
+	if len(covers) > MaxCovers {
+		covers = covers[:MaxCovers]
+	}
+	if len(covers) == 0 {
+		return nil, ErrNoCovers
+	}
+
+	layout := newLayout(len(covers))
+	canvas := image.NewRGBA(image.Rect(0, 0, Width, layout.height))
+	accent := rgb(style.AccentColor)
+	fill(canvas, canvas.Bounds(), shade(accent, 0.2))
+	drawText(canvas, padding, padding, fitText(style.Name, headerScale, Width-2*padding), headerScale, color.RGBA{255, 255, 255, 255})
+
+	loaded := 0
+	for i, cover := range covers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		tile := layout.tile(i)
+
+		img, err := fetch(ctx, cover.ImageURL)
+		if err == nil && img != nil {
+			drawCover(canvas, tile, img)
+			loaded++
+		} else {
+			fill(canvas, tile, shade(accent, 0.6))
+		}
+
+		title := fitText(cover.Title, titleScale, tile.Dx())
+		drawText(canvas, tile.Min.X, tile.Max.Y+titleGap, title, titleScale, color.RGBA{255, 255, 255, 255})
+	}
+	if loaded == 0 {
+		return nil, ErrNoCovers
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas); err != nil {
+		return nil, fmt.Errorf("failed to encode banner: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// layout places n covers in rows of up to three tiles below the header
+type layout struct {
+	columns    int
+	tileWidth  int
+	tileHeight int
+	top        int
+	rowHeight  int
+	height     int
+}
+
+func newLayout(n int) layout {
+	l := layout{columns: min(n, columns)}
+	l.tileWidth = (Width - padding*(l.columns+1)) / l.columns
+	l.tileHeight = l.tileWidth * 9 / 16
+	l.top = padding + glyphHeight*headerScale + padding
+	l.rowHeight = l.tileHeight + titleGap + glyphHeight*titleScale + padding
+	rows := (n + l.columns - 1) / l.columns
+	l.height = l.top + rows*l.rowHeight
+	return l
+}
+
+// tile returns where the i-th cover is drawn
+func (l layout) tile(i int) image.Rectangle {
+	row, col := i/l.columns, i%l.columns
+	x := padding + col*(l.tileWidth+padding)
+	y := l.top + row*l.rowHeight
+	return image.Rect(x, y, x+l.tileWidth, y+l.tileHeight)
+}
+
+// drawCover scales src to fill dst, cropping it around its center to dst's
+// aspect ratio. Each destination pixel averages the source pixels it covers.
+func drawCover(canvas *image.RGBA, dst image.Rectangle, src image.Image) {
+	crop := src.Bounds()
+	if crop.Dx()*dst.Dy() > crop.Dy()*dst.Dx() {
+		w := crop.Dy() * dst.Dx() / dst.Dy()
+		crop.Min.X += (crop.Dx() - w) / 2
+		crop.Max.X = crop.Min.X + w
+	} else {
+		h := crop.Dx() * dst.Dy() / dst.Dx()
+		crop.Min.Y += (crop.Dy() - h) / 2
+		crop.Max.Y = crop.Min.Y + h
+	}
+
+	for y := 0; y < dst.Dy(); y++ {
+		y0 := crop.Min.Y + y*crop.Dy()/dst.Dy()
+		y1 := max(crop.Min.Y+(y+1)*crop.Dy()/dst.Dy(), y0+1)
+		for x := 0; x < dst.Dx(); x++ {
+			x0 := crop.Min.X + x*crop.Dx()/dst.Dx()
+			x1 := max(crop.Min.X+(x+1)*crop.Dx()/dst.Dx(), x0+1)
+
+			var r, g, b, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, _ := src.At(sx, sy).RGBA()
+					r, g, b, n = r+pr, g+pg, b+pb, n+1
+				}
+			}
+			canvas.SetRGBA(dst.Min.X+x, dst.Min.Y+y, color.RGBA{uint8(r / n >> 8), uint8(g / n >> 8), uint8(b / n >> 8), 255})
+		}
+	}
+}
+
+// fill paints rect in c
+func fill(dst *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	draw.Draw(dst, rect, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// rgb converts a 0xRRGGBB color
+func rgb(value int) color.RGBA {
+	return color.RGBA{uint8(value >> 16), uint8(value >> 8), uint8(value), 255}
+}
+
+// shade darkens c to factor of its brightness
+func shade(c color.RGBA, factor float64) color.RGBA {
+	return color.RGBA{uint8(float64(c.R) * factor), uint8(float64(c.G) * factor), uint8(float64(c.B) * factor), 255}
+}
@@ -0,0 +1,277 @@
+package banner
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden banners in testdata")
+
+// fixture draws a deterministic cover: w x h pixels of 20px blocks
+// alternating between two colors
+func fixture(w, h int, a, b color.RGBA) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := a
+			if (x/20+y/20)%2 == 1 {
+				c = b
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// fakeFetcher serves the fixture for each URL, and fails for the rest
+func fakeFetcher(images map[string]image.Image) Fetcher {
+	return func(ctx context.Context, url string) (image.Image, error) {
+		if img, ok := images[url]; ok {
+			return img, nil
+		}
+		return nil, fmt.Errorf("no fixture for %s", url)
+	}
+}
+
+var (
+	red    = color.RGBA{200, 40, 40, 255}
+	orange = color.RGBA{240, 150, 30, 255}
+	green  = color.RGBA{40, 160, 60, 255}
+	blue   = color.RGBA{40, 90, 200, 255}
+	white  = color.RGBA{240, 240, 240, 255}
+	black  = color.RGBA{20, 20, 20, 255}
+
+	fixtures = map[string]image.Image{
+		"wide.png":   fixture(640, 360, red, orange),
+		"tall.png":   fixture(300, 400, green, white),
+		"square.png": fixture(200, 200, blue, black),
+	}
+	style = Style{Name: "Free Games Bot", AccentColor: 0x7289da}
+)
+
+func TestComposeGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		covers []Cover
+		style  Style
+	}{
+		{
+			name: "two_covers",
+			covers: []Cover{
+				{Title: "Control", ImageURL: "wide.png"},
+				{Title: "Sid Meier's Civilization® VI", ImageURL: "tall.png"},
+			},
+			style: style,
+		},
+		{
+			name: "missing_cover",
+			covers: []Cover{
+				{Title: "Hades", ImageURL: "square.png"},
+				{Title: "Missing Cover", ImageURL: "missing.png"},
+				{Title: "The Witcher 3: Wild Hunt – Game of the Year Edition", ImageURL: "wide.png"},
+			},
+			style: Style{Name: "Loot Herald", AccentColor: 0xaa3377},
+		},
+		{
+			name: "two_rows",
+			covers: []Cover{
+				{Title: "One", ImageURL: "wide.png"},
+				{Title: "Two", ImageURL: "tall.png"},
+				{Title: "Three", ImageURL: "square.png"},
+				{Title: "Four", ImageURL: "wide.png"},
+			},
+			style: style,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compose(context.Background(), tt.covers, tt.style, fakeFetcher(fixtures))
+			if err != nil {
+				t.Fatalf("Compose: %v", err)
+			}
+
+			golden := filepath.Join("testdata", tt.name+".png")
+			if *update {
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("reading the golden banner (run with -update to create it): %v", err)
+			}
+			assertSameImage(t, got, want)
+		})
+	}
+}
+
+// assertSameImage compares two PNGs pixel by pixel
+func assertSameImage(t *testing.T, got, want []byte) {
+	t.Helper()
+	gotImage, err := png.Decode(bytes.NewReader(got))
+	if err != nil {
+		t.Fatalf("the banner is not a valid PNG: %v", err)
+	}
+	wantImage, err := png.Decode(bytes.NewReader(want))
+	if err != nil {
+		t.Fatalf("the golden banner is not a valid PNG: %v", err)
+	}
+	if gotImage.Bounds() != wantImage.Bounds() {
+		t.Fatalf("banner is %v, want %v", gotImage.Bounds(), wantImage.Bounds())
+	}
+	bounds := gotImage.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if g, w := color.RGBAModel.Convert(gotImage.At(x, y)), color.RGBAModel.Convert(wantImage.At(x, y)); g != w {
+				t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, g, w)
+			}
+		}
+	}
+}
+
+func TestComposeLayout(t *testing.T) {
+	tests := []struct {
+		covers     int
+		wantHeight int
+	}{
+		{covers: 2, wantHeight: newLayout(2).top + newLayout(2).rowHeight},
+		{covers: 3, wantHeight: newLayout(3).top + newLayout(3).rowHeight},
+		{covers: 4, wantHeight: newLayout(4).top + 2*newLayout(4).rowHeight},
+		// Covers past MaxCovers are left out
+		{covers: MaxCovers + 3, wantHeight: newLayout(MaxCovers).top + 2*newLayout(MaxCovers).rowHeight},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.covers), func(t *testing.T) {
+			var covers []Cover
+			for i := 0; i < tt.covers; i++ {
+				covers = append(covers, Cover{Title: fmt.Sprint("Game ", i), ImageURL: "square.png"})
+			}
+			data, err := Compose(context.Background(), covers, style, fakeFetcher(fixtures))
+			if err != nil {
+				t.Fatalf("Compose: %v", err)
+			}
+			config, err := png.DecodeConfig(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("DecodeConfig: %v", err)
+			}
+			if config.Width != Width || config.Height != tt.wantHeight {
+				t.Errorf("banner is %dx%d, want %dx%d", config.Width, config.Height, Width, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestComposeWithoutCovers(t *testing.T) {
+	if _, err := Compose(context.Background(), nil, style, fakeFetcher(fixtures)); !errors.Is(err, ErrNoCovers) {
+		t.Errorf("Compose of no games = %v, want ErrNoCovers", err)
+	}
+	covers := []Cover{{Title: "A", ImageURL: "missing-a.png"}, {Title: "B", ImageURL: "missing-b.png"}}
+	if _, err := Compose(context.Background(), covers, style, fakeFetcher(fixtures)); !errors.Is(err, ErrNoCovers) {
+		t.Errorf("Compose with every cover failing = %v, want ErrNoCovers", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Compose(ctx, []Cover{{Title: "A", ImageURL: "wide.png"}}, style, fakeFetcher(fixtures)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Compose with a cancelled context = %v, want context.Canceled", err)
+	}
+}
+
+// pngHeader returns the start of a PNG claiming to be w x h pixels, enough
+// for image.DecodeConfig
+func pngHeader(w, h uint32) []byte {
+	var ihdr bytes.Buffer
+	ihdr.WriteString("IHDR")
+	binary.Write(&ihdr, binary.BigEndian, w)
+	binary.Write(&ihdr, binary.BigEndian, h)
+	ihdr.Write([]byte{8, 2, 0, 0, 0})
+
+	var data bytes.Buffer
+	data.WriteString("\x89PNG\r\n\x1a\n")
+	binary.Write(&data, binary.BigEndian, uint32(ihdr.Len()-4))
+	data.Write(ihdr.Bytes())
+	binary.Write(&data, binary.BigEndian, crc32.ChecksumIEEE(ihdr.Bytes()))
+	return data.Bytes()
+}
+
+func TestHTTPFetcher(t *testing.T) {
+	var cover bytes.Buffer
+	if err := png.Encode(&cover, fixtures["square.png"]); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cover.png":
+			w.Write(cover.Bytes())
+		case "/huge.png":
+			w.Write(pngHeader(20000, 20000))
+		case "/large-file.png":
+			w.Write(bytes.Repeat([]byte{0}, maxImageBytes+1))
+		case "/not-an-image":
+			w.Write([]byte("<html>challenge</html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	fetch := HTTPFetcher(server.Client())
+
+	img, err := fetch(context.Background(), server.URL+"/cover.png")
+	if err != nil {
+		t.Fatalf("fetching a cover: %v", err)
+	}
+	if img.Bounds().Dx() != 200 || img.Bounds().Dy() != 200 {
+		t.Errorf("cover is %v, want 200x200", img.Bounds())
+	}
+
+	for _, path := range []string{"/huge.png", "/large-file.png", "/not-an-image", "/missing.png"} {
+		if _, err := fetch(context.Background(), server.URL+path); err == nil {
+			t.Errorf("fetching %s succeeded, want an error", path)
+		}
+	}
+}
+
+func TestFitText(t *testing.T) {
+	tests := []struct {
+		text     string
+		maxWidth int
+		want     string
+	}{
+		{text: "Control", maxWidth: 1000, want: "CONTROL"},
+		{text: "Pokémon™ Café", maxWidth: 1000, want: "POKEMON CAFE"},
+		{text: "Sid Meier’s Civilization® VI", maxWidth: 1000, want: "SID MEIER'S CIVILIZATION VI"},
+		{text: "日本 Game", maxWidth: 1000, want: "?? GAME"},
+		{text: "Borderlands 3", maxWidth: textWidth("BORDER...", 1), want: "BORDER..."},
+		{text: "Borderlands 3", maxWidth: textWidth("BORDERLANDS 3", 1), want: "BORDERLANDS 3"},
+		{text: "Borderlands", maxWidth: 5, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := fitText(tt.text, 1, tt.maxWidth)
+			if got != tt.want {
+				t.Errorf("fitText(%q, %d) = %q, want %q", tt.text, tt.maxWidth, got, tt.want)
+			}
+			if textWidth(got, 1) > tt.maxWidth {
+				t.Errorf("fitText(%q) = %q is %d pixels wide, more than %d", tt.text, got, textWidth(got, 1), tt.maxWidth)
+			}
+			for _, r := range got {
+				if _, ok := glyphs[r]; !ok {
+					t.Errorf("fitText(%q) kept %q, which the font lacks", tt.text, r)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/models"
+	"free-games-scrape/internal/security"
+)
+
+// maxUnlinkedListed caps the games listed by /admin unlinked
+const maxUnlinkedListed = 20
+
+// handleAdminCommand handles the owner-only /admin unlinked and /admin
+// linkgame slash commands
+func (b *DiscordBot) handleAdminCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.isOwner(i) {
+		b.respondToInteraction(s, i, "This command is restricted to the bot owner.", true)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please choose a subcommand.", true)
+		return
+	}
+
+	switch options[0].Name {
+	case "unlinked":
+		b.handleAdminUnlinked(s, i)
+	case "linkgame":
+		var id int64
+		var url string
+		for _, option := range options[0].Options {
+			switch option.Name {
+			case "id":
+				id = option.IntValue()
+			case "url":
+				url = option.StringValue()
+			}
+		}
+		b.handleAdminLinkGame(s, i, id, url)
+	}
+}
+
+// handleAdminUnlinked lists games the store URL backfill could not match
+// confidently
+func (b *DiscordBot) handleAdminUnlinked(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	games, err := b.gameService.GetAmbiguousStoreURLGames(maxUnlinkedListed)
+	if err != nil {
+		log.Printf("Error loading unlinked games: %v", err)
+		b.respondToInteraction(s, i, "Failed to load the games. Please try again.", true)
+		return
+	}
+	if len(games) == 0 {
+		b.respondToInteraction(s, i, "No games are waiting for a store link.", true)
+		return
+	}
+
+	lines := []string{"Games without a confident store link match. Set one with `/admin linkgame <id> <url>`:"}
+	for _, game := range games {
+		lines = append(lines, fmt.Sprintf("`%d` **%s** (free until %s)", game.ID, game.Title, game.FreeTo))
+	}
+	b.respondToInteraction(s, i, strings.Join(lines, "\n"), true)
+}
+
+// handleAdminLinkGame sets the store link of a game by hand
+func (b *DiscordBot) handleAdminLinkGame(s *discordgo.Session, i *discordgo.InteractionCreate, id int64, url string) {
+	url = models.NormalizeStoreURL(url)
+	if err := security.ValidateURL(url); err != nil {
+		b.respondToInteraction(s, i, fmt.Sprintf("Invalid URL: %v", err), true)
+		return
+	}
+
+	title, err := b.gameService.SetGameStoreURL(id, url)
+	if err != nil {
+		log.Printf("Error setting store URL of game %d: %v", id, err)
+		b.respondToInteraction(s, i, "Failed to save the link. Please try again.", true)
+		return
+	}
+	if title == "" {
+		b.respondToInteraction(s, i, fmt.Sprintf("No game has ID %d.", id), true)
+		return
+	}
+
+	log.Printf("Owner linked %s to %s", title, url)
+	b.respondToInteraction(s, i, fmt.Sprintf("Linked **%s** to %s.", title, url), true)
+}
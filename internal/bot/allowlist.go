@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// guildAllowed reports whether the bot may operate in a guild. An empty
+// GUILD_ALLOWLIST allows every guild, and DMs (no guild) are always allowed.
+func (b *DiscordBot) guildAllowed(guildID string) bool {
+	if len(b.config.GuildAllowlist) == 0 || guildID == "" {
+		return true
+	}
+	return slices.Contains(b.config.GuildAllowlist, guildID)
+}
+
+// handleGuildCreate handles a guild becoming available, on joining it or on
+// connecting. Guilds outside GUILD_ALLOWLIST are left; new ones are welcomed.
+func (b *DiscordBot) handleGuildCreate(s *discordgo.Session, g *discordgo.GuildCreate) {
+	if !b.guildAllowed(g.ID) {
+		b.leaveUnlistedGuild(s, g)
+		return
+	}
+	b.registerGuildCommands(g.ID)
+	if b.isNewGuildJoin(g) {
+		log.Printf("Joined guild: %s (ID: %s)", g.Name, g.ID)
+		b.metrics.IncrementServersJoined()
+		b.sendWelcomeMessage(s, g)
+	}
+}
+
+// unlistedGuildMessage explains why the bot won't stay in a guild
+func unlistedGuildMessage(botName string) string {
+	return fmt.Sprintf("Thanks for adding %s! This is a private instance that only runs in servers its operator has allowed, so it can't be used here and is leaving this server. No settings were saved.", botName)
+}
+
+// leaveUnlistedGuild explains itself to a guild outside GUILD_ALLOWLIST,
+// if it can find a channel to post in, and leaves it. Nothing about the
+// guild is recorded.
+func (b *DiscordBot) leaveUnlistedGuild(s *discordgo.Session, g *discordgo.GuildCreate) {
+	log.Printf("Guild %s (ID: %s) is not in GUILD_ALLOWLIST, leaving it", g.Name, g.ID)
+	b.metrics.IncrementServersRejected()
+
+	if channelID := welcomeChannel(s, g); channelID != "" {
+		if _, err := s.ChannelMessageSend(channelID, unlistedGuildMessage(b.branding().Name)); err != nil {
+			log.Printf("Error explaining the allowlist to guild %s: %v", g.ID, err)
+		}
+	}
+	if err := s.GuildLeave(g.ID); err != nil {
+		log.Printf("Error leaving guild %s: %v", g.ID, err)
+	}
+}
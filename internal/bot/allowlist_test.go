@@ -0,0 +1,127 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestGuildAllowed(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowlist []string
+		guildID   string
+		want      bool
+	}{
+		{name: "allowlisted", allowlist: []string{"111", "222"}, guildID: "222", want: true},
+		{name: "not allowlisted", allowlist: []string{"111", "222"}, guildID: "333"},
+		{name: "empty list allows every guild", guildID: "333", want: true},
+		{name: "DMs are always allowed", allowlist: []string{"111"}, guildID: "", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBot(t)
+			b.config.GuildAllowlist = tt.allowlist
+			if got := b.guildAllowed(tt.guildID); got != tt.want {
+				t.Errorf("guildAllowed(%q) with %v = %v, want %v", tt.guildID, tt.allowlist, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleGuildCreateAllowlist(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowlist []string
+		wantLeft  bool
+	}{
+		{name: "allowlisted", allowlist: []string{"111", "222"}},
+		{name: "not allowlisted", allowlist: []string{"111"}, wantLeft: true},
+		{name: "empty list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBot(t)
+			b.config.GuildAllowlist = tt.allowlist
+			discord := useFakeDiscord(t, b)
+
+			guild := &discordgo.GuildCreate{Guild: &discordgo.Guild{ID: "222", Name: "Guild", SystemChannelID: "900"}}
+			b.handleGuildCreate(b.session, guild)
+
+			left := discord.find("DELETE", "users/@me/guilds/222")
+			messages := discord.find("POST", "channels/900/messages")
+			if len(messages) != 1 {
+				t.Fatalf("sent %d messages to the system channel, want 1", len(messages))
+			}
+			isNew, err := b.database.RecordGuild("222", "Guild", 0)
+			if err != nil {
+				t.Fatalf("RecordGuild: %v", err)
+			}
+
+			if tt.wantLeft {
+				if len(left) != 1 {
+					t.Errorf("left the guild %d times, want once", len(left))
+				}
+				if content, _ := messages[0].Body["content"].(string); content != unlistedGuildMessage(b.branding().Name) {
+					t.Errorf("message = %q, want the allowlist explanation", content)
+				}
+				if got := b.metrics.GetServersRejected(); got != 1 {
+					t.Errorf("GetServersRejected = %d, want 1", got)
+				}
+				if got := b.metrics.GetServersJoined(); got != 0 {
+					t.Errorf("GetServersJoined = %d, want 0", got)
+				}
+				if !isNew {
+					t.Error("a guild outside the allowlist was recorded")
+				}
+				return
+			}
+
+			if len(left) != 0 {
+				t.Errorf("left an allowed guild %d times", len(left))
+			}
+			if _, ok := messages[0].Body["embeds"]; !ok {
+				t.Errorf("message = %v, want the welcome embed", messages[0].Body)
+			}
+			if got := b.metrics.GetServersRejected(); got != 0 {
+				t.Errorf("GetServersRejected = %d, want 0", got)
+			}
+			if got := b.metrics.GetServersJoined(); got != 1 {
+				t.Errorf("GetServersJoined = %d, want 1", got)
+			}
+			if isNew {
+				t.Error("an allowed guild was not recorded")
+			}
+		})
+	}
+}
+
+func TestInteractionHandlerRefusesUnlistedGuild(t *testing.T) {
+	b := newTestBot(t)
+	b.config.GuildAllowlist = []string{"111"}
+	discord := useFakeDiscord(t, b)
+
+	interaction := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:      "1",
+		Token:   "token",
+		Type:    discordgo.InteractionApplicationCommand,
+		GuildID: "222",
+		Data:    discordgo.ApplicationCommandInteractionData{Name: "games"},
+	}}
+	b.interactionHandler(b.session, interaction)
+
+	responses := discord.find("POST", "interactions/1/token/callback")
+	if len(responses) != 1 {
+		t.Fatalf("sent %d interaction responses, want 1", len(responses))
+	}
+	data, _ := responses[0].Body["data"].(map[string]interface{})
+	if content, _ := data["content"].(string); content != unlistedGuildMessage(b.branding().Name) {
+		t.Errorf("response = %q, want the allowlist explanation", content)
+	}
+	if flags, _ := data["flags"].(float64); discordgo.MessageFlags(flags)&discordgo.MessageFlagsEphemeral == 0 {
+		t.Error("the refusal is not ephemeral")
+	}
+	if got := b.metrics.GetCommandsExecuted(); got != 0 {
+		t.Errorf("GetCommandsExecuted = %d, want 0 for a refused command", got)
+	}
+}
@@ -0,0 +1,190 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/banner"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// bannerTimeout bounds how long composing a banner may hold a server's
+// announcements back; the plain header is posted when it runs out
+const bannerTimeout = 10 * time.Second
+
+// maxCachedBanners bounds the banners kept in memory. Servers filtering
+// differently see different rotations, so a cycle can need a few.
+const maxCachedBanners = 8
+
+// bannerFileName is the name the banner is uploaded under
+const bannerFileName = "free-games.png"
+
+// bannerCache holds the banners composed for the current rotations, keyed by
+// rotationKey, so every server getting the same games shares one banner.
+// Composition holds mu, so banners are composed one at a time.
+type bannerCache struct {
+	mu      sync.Mutex
+	banners map[string]composedBanner
+	// fetch loads cover images; tests replace it
+	fetch banner.Fetcher
+}
+
+// composedBanner is a cached banner, or why it could not be composed
+type composedBanner struct {
+	png []byte
+	err error
+}
+
+// bannersEnabled reports whether a server's Free Now announcements start
+// with a header carrying the rotation's banner. Both the operator
+// (BANNER_IMAGES) and the server (/customize banner) have to opt in.
+func (b *DiscordBot) bannersEnabled(cfg *database.ServerConfig) bool {
+	return b.config.BannerImages && cfg != nil && cfg.BannerImages
+}
+
+// rotationKey identifies a rotation of games drawn in a style
+func rotationKey(games []models.Game, style banner.Style) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%06x\x00", style.Name, style.AccentColor)
+	for _, game := range games {
+		fmt.Fprintf(hash, "%s\x00%s\x00", database.NotificationKey(game), game.ImageURL)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// rotationBanner returns the banner of games, composing it unless it is
+// cached. A failed composition is cached too, so a broken cover is not
+// downloaded again for every server in the cycle.
+func (b *DiscordBot) rotationBanner(ctx context.Context, games []models.Game) ([]byte, error) {
+	style := banner.Style{Name: b.branding().Name, AccentColor: b.branding().AccentColor}
+	key := rotationKey(games, style)
+
+	b.banners.mu.Lock()
+	defer b.banners.mu.Unlock()
+	if cached, ok := b.banners.banners[key]; ok {
+		return cached.png, cached.err
+	}
+
+	fetch := b.banners.fetch
+	if fetch == nil {
+		fetch = banner.HTTPFetcher(&http.Client{Timeout: bannerTimeout})
+	}
+	covers := make([]banner.Cover, 0, len(games))
+	for _, game := range games {
+		covers = append(covers, banner.Cover{Title: game.Title, ImageURL: game.ImageURL})
+	}
+
+	composeCtx, cancel := context.WithTimeout(ctx, bannerTimeout)
+	defer cancel()
+	start := time.Now()
+	png, err := banner.Compose(composeCtx, covers, style, fetch)
+	if ctx.Err() != nil {
+		// Shutting down; don't remember the interrupted banner
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		log.Printf("Failed to compose the banner of %d games: %v", len(games), err)
+	} else {
+		log.Printf("Composed the banner of %d games in %s (%d bytes)", len(games), time.Since(start).Round(time.Millisecond), len(png))
+	}
+
+	if b.banners.banners == nil || len(b.banners.banners) >= maxCachedBanners {
+		b.banners.banners = make(map[string]composedBanner)
+	}
+	b.banners.banners[key] = composedBanner{png: png, err: err}
+	return png, err
+}
+
+// bannerHeaderText is the header posted above a server's Free Now
+// announcements
+func bannerHeaderText(games []models.Game, mention string) string {
+	text := fmt.Sprintf("🎮 **%d free games are available now!**", len(games))
+	if len(games) > banner.MaxCovers {
+		text += fmt.Sprintf(" The banner shows the first %d.", banner.MaxCovers)
+	}
+	if mention != "" {
+		text = mention + "\n" + text
+	}
+	return text
+}
+
+// sendBannerHeader posts the header above a server's Free Now announcements,
+// with the rotation's banner attached. When the banner can't be composed, the
+// server's content policy is text-only or the channel refuses the
+// attachment, the plain header is posted instead.
+func (b *DiscordBot) sendBannerHeader(ctx context.Context, channelID string, cfg *database.ServerConfig, games []models.Game, mention string) error {
+	allowedMentions := &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}}
+	if mention != "" {
+		allowedMentions.Roles = []string{cfg.RoleID}
+	}
+	header := func(png []byte) *discordgo.MessageSend {
+		message := &discordgo.MessageSend{Content: bannerHeaderText(games, mention), AllowedMentions: allowedMentions}
+		if png != nil {
+			message.Files = []*discordgo.File{{Name: bannerFileName, ContentType: "image/png", Reader: bytes.NewReader(png)}}
+		}
+		return message
+	}
+
+	var png []byte
+	if !cfg.Policy().TextOnly {
+		composed, err := b.rotationBanner(ctx, games)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == nil {
+			png = composed
+		}
+	}
+
+	if err := b.rateLimiter.WaitForChannel(ctx, channelID); err != nil {
+		return fmt.Errorf("rate limiter wait failed: %w", err)
+	}
+	// The file reader is used up by a send, so every attempt gets a new one
+	err := b.retryOnRateLimit("channel "+channelID, func() error {
+		_, err := b.session.ChannelMessageSendComplex(channelID, header(png), discordgo.WithRetryOnRatelimit(false), discordgo.WithContext(ctx))
+		return err
+	})
+	if err != nil && png != nil && isEmbedRejected(err) {
+		log.Printf("Banner rejected in channel %s, posting the plain header: %v", channelID, err)
+		err = b.retryOnRateLimit("channel "+channelID, func() error {
+			_, err := b.session.ChannelMessageSendComplex(channelID, header(nil), discordgo.WithRetryOnRatelimit(false), discordgo.WithContext(ctx))
+			return err
+		})
+	}
+	return err
+}
+
+// handleCustomizeBanner handles /customize banner, turning the banner header
+// above the server's announcements on or off
+func (b *DiscordBot) handleCustomizeBanner(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please specify whether the banner is enabled.", true)
+		return
+	}
+	enabled := options[0].BoolValue()
+
+	if err := b.database.SetBannerImages(i.GuildID, enabled); err != nil {
+		log.Printf("Error saving banner setting for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to save the setting. Please try again.", true)
+		return
+	}
+
+	switch {
+	case !enabled:
+		b.respondToInteraction(s, i, "Announcements will no longer start with a banner.", false)
+	case !b.config.BannerImages:
+		b.respondToInteraction(s, i, "Banner saved, but the bot operator has turned banners off, so announcements won't show one until they turn them on.", false)
+	default:
+		b.respondToInteraction(s, i, "When several games are free at once, announcements will now start with a banner showing all of them.", false)
+	}
+	log.Printf("Server %s set banner images to %t", i.GuildID, enabled)
+}
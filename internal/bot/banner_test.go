@@ -0,0 +1,249 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// coverFetcher stands in for the cover downloads, counting them. Covers of
+// URLs containing "broken" fail to load.
+func coverFetcher(fetches *atomic.Int32) func(context.Context, string) (image.Image, error) {
+	return func(ctx context.Context, url string) (image.Image, error) {
+		fetches.Add(1)
+		if strings.Contains(url, "broken") {
+			return nil, errors.New("cover unavailable")
+		}
+		cover := image.NewRGBA(image.Rect(0, 0, 32, 18))
+		for i := range cover.Pix {
+			cover.Pix[i] = 0x80
+		}
+		return cover, nil
+	}
+}
+
+func bannerGames(n int, imageURL string) []models.Game {
+	games := make([]models.Game, n)
+	for i := range games {
+		games[i] = runningGame(fmt.Sprintf("Banner%d", i))
+		games[i].ImageURL = fmt.Sprintf("%s/%d.png", imageURL, i)
+	}
+	return games
+}
+
+// rejectAttachments makes fake Discord refuse messages with files the way it
+// does without the Attach Files permission
+func rejectAttachments(request fakeRequest) (int, string) {
+	if len(request.Files) > 0 {
+		return http.StatusForbidden, `{"code": 50013, "message": "Missing Permissions"}`
+	}
+	return 0, ""
+}
+
+func TestDeliverStartsWithBanner(t *testing.T) {
+	b := newTestBot(t)
+	b.config.BannerImages = true
+	discord := useFakeDiscord(t, b)
+	var fetches atomic.Int32
+	b.banners.fetch = coverFetcher(&fetches)
+
+	const guildID = "guild-banner"
+	if _, err := b.database.SaveServerConfig(guildID, "channel-banner"); err != nil {
+		t.Fatalf("SaveServerConfig: %v", err)
+	}
+	if err := b.database.SetMentionRole(guildID, "555"); err != nil {
+		t.Fatalf("SetMentionRole: %v", err)
+	}
+	if err := b.database.SetBannerImages(guildID, true); err != nil {
+		t.Fatalf("SetBannerImages: %v", err)
+	}
+	cfg, err := b.database.GetServerConfig(guildID)
+	if err != nil || cfg == nil {
+		t.Fatalf("GetServerConfig: %v, %v", cfg, err)
+	}
+
+	games := bannerGames(3, "https://cdn.example.com")
+	result := b.deliverToChannel(b.ctx, deliveryJob{
+		cycleID:   "cycle",
+		guildID:   guildID,
+		channelID: cfg.ChannelID,
+		config:    cfg,
+		games:     &models.GameCollection{FreeNow: games},
+	})
+	if result.err != nil {
+		t.Fatalf("deliverToChannel: %v", result.err)
+	}
+
+	sends := discord.find("POST", "channels/channel-banner/messages")
+	if len(sends) != 1+len(games) {
+		t.Fatalf("made %d sends, want a header and %d announcements", len(sends), len(games))
+	}
+	header := sends[0]
+	if fmt.Sprint(header.Files) != "["+bannerFileName+"]" {
+		t.Errorf("header attachments = %v, want the banner", header.Files)
+	}
+	if content, _ := header.Body["content"].(string); !strings.Contains(content, "<@&555>") || !strings.Contains(content, "3 free games") {
+		t.Errorf("header content = %q, want the role ping and game count", content)
+	}
+	for _, send := range sends[1:] {
+		if len(send.Files) != 0 {
+			t.Errorf("announcement has attachments %v", send.Files)
+		}
+		if content, _ := send.Body["content"].(string); strings.Contains(content, "<@&555>") {
+			t.Errorf("announcement %q pings the role again", content)
+		}
+	}
+	if got := fetches.Load(); got != int32(len(games)) {
+		t.Errorf("fetched %d covers, want %d", got, len(games))
+	}
+	for _, decision := range result.decisions {
+		if !decision.Delivered {
+			t.Errorf("%s was not delivered: %s", decision.GameTitle, decision.Reason)
+		}
+	}
+}
+
+func TestDeliverBannerGating(t *testing.T) {
+	tests := []struct {
+		name       string
+		global     bool
+		server     bool
+		games      int
+		wantHeader bool
+	}{
+		{name: "enabled", global: true, server: true, games: 2, wantHeader: true},
+		{name: "operator flag off", server: true, games: 2},
+		{name: "server flag off", global: true, games: 2},
+		{name: "single game", global: true, server: true, games: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBot(t)
+			b.config.BannerImages = tt.global
+			discord := useFakeDiscord(t, b)
+			var fetches atomic.Int32
+			b.banners.fetch = coverFetcher(&fetches)
+
+			cfg := &database.ServerConfig{GuildID: "guild", ChannelID: "900", BannerImages: tt.server}
+			b.deliverToChannel(b.ctx, deliveryJob{
+				guildID:   "guild",
+				channelID: "900",
+				config:    cfg,
+				games:     &models.GameCollection{FreeNow: bannerGames(tt.games, "https://cdn.example.com")},
+			})
+
+			sends := discord.find("POST", "channels/900/messages")
+			wantSends := tt.games
+			if tt.wantHeader {
+				wantSends++
+			}
+			if len(sends) != wantSends {
+				t.Fatalf("made %d sends, want %d", len(sends), wantSends)
+			}
+			if gotHeader := len(sends[0].Files) > 0; gotHeader != tt.wantHeader {
+				t.Errorf("posted a banner = %v, want %v", gotHeader, tt.wantHeader)
+			}
+			if !tt.wantHeader && fetches.Load() != 0 {
+				t.Errorf("fetched %d covers without a banner", fetches.Load())
+			}
+		})
+	}
+}
+
+func TestSendBannerHeaderFallsBackToPlainHeader(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      string
+		imageURL    string
+		reject      bool
+		wantSends   int
+		wantFetches int32
+	}{
+		{name: "banner", imageURL: "https://cdn.example.com", wantSends: 1, wantFetches: 2},
+		{name: "no cover loads", imageURL: "https://cdn.example.com/broken", wantSends: 1, wantFetches: 2},
+		{name: "text-only policy", policy: database.PolicyTextOnly, imageURL: "https://cdn.example.com", wantSends: 1},
+		{name: "attachment rejected", imageURL: "https://cdn.example.com", reject: true, wantSends: 2, wantFetches: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBot(t)
+			b.config.BannerImages = true
+			discord := useFakeDiscord(t, b)
+			if tt.reject {
+				discord.fail = rejectAttachments
+			}
+			var fetches atomic.Int32
+			b.banners.fetch = coverFetcher(&fetches)
+
+			cfg := &database.ServerConfig{GuildID: "guild", ChannelID: "900", BannerImages: true, ContentPolicy: tt.policy}
+			if err := b.sendBannerHeader(b.ctx, "900", cfg, bannerGames(2, tt.imageURL), ""); err != nil {
+				t.Fatalf("sendBannerHeader: %v", err)
+			}
+
+			sends := discord.find("POST", "channels/900/messages")
+			if len(sends) != tt.wantSends {
+				t.Fatalf("made %d sends, want %d", len(sends), tt.wantSends)
+			}
+			last := sends[len(sends)-1]
+			wantBanner := tt.name == "banner"
+			if gotBanner := len(last.Files) > 0; gotBanner != wantBanner {
+				t.Errorf("posted a banner = %v, want %v", gotBanner, wantBanner)
+			}
+			if content, _ := last.Body["content"].(string); !strings.Contains(content, "2 free games") {
+				t.Errorf("header content = %q, want the game count", content)
+			}
+			if got := fetches.Load(); got != tt.wantFetches {
+				t.Errorf("fetched %d covers, want %d", got, tt.wantFetches)
+			}
+		})
+	}
+}
+
+func TestRotationBannerIsCached(t *testing.T) {
+	b := newTestBot(t)
+	var fetches atomic.Int32
+	b.banners.fetch = coverFetcher(&fetches)
+
+	rotation := bannerGames(2, "https://cdn.example.com")
+	first, err := b.rotationBanner(b.ctx, rotation)
+	if err != nil {
+		t.Fatalf("rotationBanner: %v", err)
+	}
+	again, err := b.rotationBanner(b.ctx, bannerGames(2, "https://cdn.example.com"))
+	if err != nil {
+		t.Fatalf("rotationBanner: %v", err)
+	}
+	if string(first) != string(again) || fetches.Load() != 2 {
+		t.Errorf("same rotation fetched %d covers, want it composed once", fetches.Load())
+	}
+
+	// A failed rotation is remembered too, so its covers aren't retried
+	broken := bannerGames(2, "https://cdn.example.com/broken")
+	for i := 0; i < 2; i++ {
+		if _, err := b.rotationBanner(b.ctx, broken); err == nil {
+			t.Fatal("rotation without covers composed a banner")
+		}
+	}
+	if got := fetches.Load(); got != 4 {
+		t.Errorf("fetched %d covers, want the broken rotation tried once", got)
+	}
+
+	// A cancelled cycle is not cached
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	next := bannerGames(3, "https://cdn.example.com/next")
+	if _, err := b.rotationBanner(ctx, next); !errors.Is(err, context.Canceled) {
+		t.Fatalf("rotationBanner with a cancelled context = %v, want context.Canceled", err)
+	}
+	if _, err := b.rotationBanner(b.ctx, next); err != nil {
+		t.Errorf("rotationBanner after the cancelled cycle: %v", err)
+	}
+}
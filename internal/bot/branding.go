@@ -0,0 +1,30 @@
+package bot
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/models"
+)
+
+// brandFooter returns the footer for embeds that are not about a single game
+func brandFooter(branding config.BrandingConfig) *discordgo.MessageEmbedFooter {
+	return &discordgo.MessageEmbedFooter{
+		Text:    branding.Footer,
+		IconURL: branding.LogoURL,
+	}
+}
+
+// gameFooter returns the footer for a game embed, naming the game's store
+func gameFooter(branding config.BrandingConfig, game models.Game) *discordgo.MessageEmbedFooter {
+	return &discordgo.MessageEmbedFooter{
+		Text:    fmt.Sprintf("%s - %s", game.SourceName(), branding.Name),
+		IconURL: branding.LogoURL,
+	}
+}
+
+// branding returns this instance's configured branding
+func (b *DiscordBot) branding() config.BrandingConfig {
+	return b.config.Branding
+}
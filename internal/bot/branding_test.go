@@ -0,0 +1,90 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/models"
+)
+
+// customBranding is an instance branding sharing nothing with the defaults
+var customBranding = config.BrandingConfig{
+	Name:        "Loot Herald",
+	Footer:      "Loot Herald by the Guild",
+	LogoURL:     "https://example.com/herald.png",
+	AccentColor: 0xaa3377,
+}
+
+// assertBranded fails unless rendered shows the custom branding and none of
+// the default strings or accent color
+func assertBranded(t *testing.T, what string, rendered interface{}) {
+	t.Helper()
+	encoded, err := json.Marshal(rendered)
+	if err != nil {
+		t.Fatalf("encoding %s: %v", what, err)
+	}
+	text := string(encoded)
+	if !strings.Contains(text, customBranding.Name) {
+		t.Errorf("%s does not show the branding name: %s", what, text)
+	}
+	if strings.Contains(text, config.DefaultBrandingName) {
+		t.Errorf("%s shows the default name %q: %s", what, config.DefaultBrandingName, text)
+	}
+	if strings.Contains(text, fmt.Sprintf(`"color":%d`, config.DefaultBrandingAccentColor)) {
+		t.Errorf("%s uses the default accent color: %s", what, text)
+	}
+}
+
+func TestGameEmbedsUseBranding(t *testing.T) {
+	b := newTestBot(t)
+	b.config.Branding = customBranding
+
+	game := runningGame("Branded")
+	upcoming := game
+	upcoming.Status = models.StatusComingSoon
+
+	embeds := map[string]*discordgo.MessageEmbed{
+		"Free Now embed":    b.freeNowEmbed(game, 0, 1, nil, false),
+		"release embed":     b.freeNowEmbed(game, 0, 1, nil, true),
+		"Coming Soon embed": b.comingSoonEmbed(upcoming, 0, 1, nil),
+	}
+	for what, embed := range embeds {
+		assertBranded(t, what, embed)
+		if embed.Footer == nil || embed.Footer.IconURL != customBranding.LogoURL {
+			t.Errorf("%s footer = %+v, want the branding logo", what, embed.Footer)
+		}
+	}
+}
+
+func TestMessagesUseBranding(t *testing.T) {
+	b := newTestBot(t)
+	b.config.Branding = customBranding
+	discord := useFakeDiscord(t, b)
+
+	b.handleGuildCreate(b.session, &discordgo.GuildCreate{Guild: &discordgo.Guild{ID: "guild", SystemChannelID: "welcome"}})
+	b.handleHelpSlashCommand(b.session, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:    "1",
+		Token: "token",
+		Type:  discordgo.InteractionApplicationCommand,
+		Data:  discordgo.ApplicationCommandInteractionData{Name: "help"},
+	}})
+
+	welcome := discord.find("POST", "channels/welcome/messages")
+	if len(welcome) != 1 {
+		t.Fatalf("sent %d welcome messages, want 1", len(welcome))
+	}
+	assertBranded(t, "welcome message", welcome[0].Body)
+
+	help := discord.find("POST", "interactions/1/token/callback")
+	if len(help) != 1 {
+		t.Fatalf("sent %d help responses, want 1", len(help))
+	}
+	assertBranded(t, "help response", help[0].Body)
+	if !strings.Contains(fmt.Sprint(help[0].Body), customBranding.Footer) {
+		t.Errorf("help response does not use the branding footer: %v", help[0].Body)
+	}
+}
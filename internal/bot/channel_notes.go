@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// defaultEveryonePermissions stands in for the @everyone role's guild
+// permissions when the role is not in the state cache
+const defaultEveryonePermissions int64 = discordgo.PermissionViewChannel | discordgo.PermissionSendMessages | discordgo.PermissionAddReactions
+
+// applyOverwrites computes a member's permissions in a channel from their
+// guild-level permissions, following Discord's order: the @everyone overwrite
+// first, then the overwrites of all the member's roles together, then the
+// member's own overwrite. Within each step allow wins over deny, and later
+// steps win over earlier ones. The @everyone role shares its ID with the guild.
+func applyOverwrites(base int64, overwrites []*discordgo.PermissionOverwrite, guildID string, roleIDs []string, memberID string) int64 {
+	if base&discordgo.PermissionAdministrator != 0 {
+		return discordgo.PermissionAll
+	}
+
+	permissions := base
+	for _, overwrite := range overwrites {
+		if overwrite.Type == discordgo.PermissionOverwriteTypeRole && overwrite.ID == guildID {
+			permissions = permissions&^overwrite.Deny | overwrite.Allow
+		}
+	}
+
+	var roleAllow, roleDeny int64
+	for _, overwrite := range overwrites {
+		if overwrite.Type != discordgo.PermissionOverwriteTypeRole || overwrite.ID == guildID {
+			continue
+		}
+		for _, roleID := range roleIDs {
+			if overwrite.ID == roleID {
+				roleAllow |= overwrite.Allow
+				roleDeny |= overwrite.Deny
+			}
+		}
+	}
+	permissions = permissions&^roleDeny | roleAllow
+
+	if memberID != "" {
+		for _, overwrite := range overwrites {
+			if overwrite.Type == discordgo.PermissionOverwriteTypeMember && overwrite.ID == memberID {
+				permissions = permissions&^overwrite.Deny | overwrite.Allow
+			}
+		}
+	}
+	return permissions
+}
+
+// channelNotes inspects what @everyone may do in a channel, given the
+// @everyone role's guild permissions, and its slowmode, and returns
+// informational notes for admins. It never reports errors: the notes are
+// advisory and must not block setup.
+func channelNotes(channel *discordgo.Channel, guildID string, everyone int64) []string {
+	if channel == nil {
+		return nil
+	}
+
+	mention := fmt.Sprintf("<#%s>", channel.ID)
+	var notes []string
+
+	permissions := applyOverwrites(everyone, channel.PermissionOverwrites, guildID, nil, "")
+	if permissions&discordgo.PermissionSendMessages == 0 {
+		notes = append(notes, fmt.Sprintf("@everyone cannot send messages in %s — that's fine for an announcements-only channel, just making sure it's intentional.", mention))
+	}
+	if permissions&discordgo.PermissionAddReactions == 0 {
+		notes = append(notes, fmt.Sprintf("@everyone cannot add reactions in %s, so members won't be able to react to announcements.", mention))
+	}
+	if permissions&discordgo.PermissionViewChannel == 0 {
+		notes = append(notes, fmt.Sprintf("@everyone cannot view %s — only members with a role that grants access will see announcements.", mention))
+	}
+
+	if channel.RateLimitPerUser > 0 {
+		slowmode := time.Duration(channel.RateLimitPerUser) * time.Second
+		notes = append(notes, fmt.Sprintf("%s has a %s slowmode, which may slow down discussion of new games.", mention, slowmode))
+	}
+
+	return notes
+}
+
+// fetchChannelNotes loads a channel from the state cache or the API and returns its notes
+func (b *DiscordBot) fetchChannelNotes(s *discordgo.Session, channelID, guildID string) []string {
+	channel, err := s.State.Channel(channelID)
+	if err != nil {
+		channel, err = s.Channel(channelID)
+		if err != nil {
+			log.Printf("Could not fetch channel %s for audit: %v", channelID, err)
+			return nil
+		}
+	}
+	everyone := defaultEveryonePermissions
+	if role, err := s.State.Role(guildID, guildID); err == nil {
+		everyone = role.Permissions
+	}
+	return channelNotes(channel, guildID, everyone)
+}
+
+// formatChannelNotes renders notes as a bulleted list prefixed with "Note:"
+func formatChannelNotes(notes []string) string {
+	var result string
+	for _, note := range notes {
+		result += "\n• Note: " + note
+	}
+	return result
+}
@@ -0,0 +1,196 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestApplyOverwrites(t *testing.T) {
+	const (
+		guildID = "guild"
+		send    = discordgo.PermissionSendMessages
+		react   = discordgo.PermissionAddReactions
+		view    = discordgo.PermissionViewChannel
+	)
+	everyone := func(allow, deny int64) *discordgo.PermissionOverwrite {
+		return &discordgo.PermissionOverwrite{ID: guildID, Type: discordgo.PermissionOverwriteTypeRole, Allow: allow, Deny: deny}
+	}
+	role := func(id string, allow, deny int64) *discordgo.PermissionOverwrite {
+		return &discordgo.PermissionOverwrite{ID: id, Type: discordgo.PermissionOverwriteTypeRole, Allow: allow, Deny: deny}
+	}
+	member := func(id string, allow, deny int64) *discordgo.PermissionOverwrite {
+		return &discordgo.PermissionOverwrite{ID: id, Type: discordgo.PermissionOverwriteTypeMember, Allow: allow, Deny: deny}
+	}
+
+	tests := []struct {
+		name       string
+		base       int64
+		overwrites []*discordgo.PermissionOverwrite
+		roles      []string
+		member     string
+		want       int64
+	}{
+		{
+			name: "no overwrites keeps the base",
+			base: view | send,
+			want: view | send,
+		},
+		{
+			name:       "everyone deny",
+			base:       view | send | react,
+			overwrites: []*discordgo.PermissionOverwrite{everyone(0, send)},
+			want:       view | react,
+		},
+		{
+			name:       "everyone allow adds to the base",
+			base:       view,
+			overwrites: []*discordgo.PermissionOverwrite{everyone(send, 0)},
+			want:       view | send,
+		},
+		{
+			name:       "everyone allow wins over its own deny",
+			base:       view,
+			overwrites: []*discordgo.PermissionOverwrite{everyone(send, send)},
+			want:       view | send,
+		},
+		{
+			name:       "role allow wins over everyone deny",
+			base:       view | send,
+			overwrites: []*discordgo.PermissionOverwrite{everyone(0, send), role("mods", send, 0)},
+			roles:      []string{"mods"},
+			want:       view | send,
+		},
+		{
+			name:       "role deny wins over everyone allow",
+			base:       view,
+			overwrites: []*discordgo.PermissionOverwrite{everyone(send, 0), role("muted", 0, send)},
+			roles:      []string{"muted"},
+			want:       view,
+		},
+		{
+			name:       "allow wins between roles",
+			base:       view | send,
+			overwrites: []*discordgo.PermissionOverwrite{role("muted", 0, send), role("mods", send, 0)},
+			roles:      []string{"muted", "mods"},
+			want:       view | send,
+		},
+		{
+			name:       "overwrites of roles the member lacks are ignored",
+			base:       view | send,
+			overwrites: []*discordgo.PermissionOverwrite{role("muted", 0, send)},
+			roles:      []string{"mods"},
+			want:       view | send,
+		},
+		{
+			name:       "member deny wins over role allow",
+			base:       view,
+			overwrites: []*discordgo.PermissionOverwrite{role("mods", send|react, 0), member("user", 0, send)},
+			roles:      []string{"mods"},
+			member:     "user",
+			want:       view | react,
+		},
+		{
+			name:       "member allow wins over role deny",
+			base:       view | send,
+			overwrites: []*discordgo.PermissionOverwrite{everyone(0, send), role("muted", 0, send), member("user", send, 0)},
+			roles:      []string{"muted"},
+			member:     "user",
+			want:       view | send,
+		},
+		{
+			name:       "other members' overwrites are ignored",
+			base:       view | send,
+			overwrites: []*discordgo.PermissionOverwrite{member("other", 0, send)},
+			member:     "user",
+			want:       view | send,
+		},
+		{
+			name:       "a member overwrite does not match a role with the same ID",
+			base:       view | send,
+			overwrites: []*discordgo.PermissionOverwrite{member("mods", 0, send)},
+			roles:      []string{"mods"},
+			want:       view | send,
+		},
+		{
+			name:       "administrators bypass overwrites",
+			base:       discordgo.PermissionAdministrator,
+			overwrites: []*discordgo.PermissionOverwrite{everyone(0, view|send), member("user", 0, send)},
+			member:     "user",
+			want:       discordgo.PermissionAll,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := applyOverwrites(tt.base, tt.overwrites, guildID, tt.roles, tt.member); got != tt.want {
+				t.Errorf("applyOverwrites = %b, want %b", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChannelNotes(t *testing.T) {
+	const guildID = "guild"
+	lockedDown := []*discordgo.PermissionOverwrite{{ID: guildID, Type: discordgo.PermissionOverwriteTypeRole, Deny: discordgo.PermissionSendMessages}}
+
+	tests := []struct {
+		name     string
+		channel  *discordgo.Channel
+		everyone int64
+		want     []string
+	}{
+		{
+			name:     "open channel",
+			channel:  &discordgo.Channel{ID: "1"},
+			everyone: defaultEveryonePermissions,
+		},
+		{
+			name:     "read-only for everyone",
+			channel:  &discordgo.Channel{ID: "1", PermissionOverwrites: lockedDown},
+			everyone: defaultEveryonePermissions,
+			want:     []string{"cannot send messages"},
+		},
+		{
+			name:     "read-only through the guild role",
+			channel:  &discordgo.Channel{ID: "1"},
+			everyone: discordgo.PermissionViewChannel | discordgo.PermissionAddReactions,
+			want:     []string{"cannot send messages"},
+		},
+		{
+			name: "hidden without reactions",
+			channel: &discordgo.Channel{ID: "1", PermissionOverwrites: []*discordgo.PermissionOverwrite{
+				{ID: guildID, Type: discordgo.PermissionOverwriteTypeRole, Deny: discordgo.PermissionViewChannel | discordgo.PermissionAddReactions},
+			}},
+			everyone: defaultEveryonePermissions,
+			want:     []string{"cannot add reactions", "cannot view"},
+		},
+		{
+			name: "member overwrites don't change what everyone can do",
+			channel: &discordgo.Channel{ID: "1", PermissionOverwrites: append(lockedDown,
+				&discordgo.PermissionOverwrite{ID: "user", Type: discordgo.PermissionOverwriteTypeMember, Allow: discordgo.PermissionSendMessages},
+			)},
+			everyone: defaultEveryonePermissions,
+			want:     []string{"cannot send messages"},
+		},
+		{
+			name:     "slowmode",
+			channel:  &discordgo.Channel{ID: "1", RateLimitPerUser: 30},
+			everyone: defaultEveryonePermissions,
+			want:     []string{"30s slowmode"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes := channelNotes(tt.channel, guildID, tt.everyone)
+			if len(notes) != len(tt.want) {
+				t.Fatalf("notes = %q, want %d mentioning %q", notes, len(tt.want), tt.want)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(notes[i], want) {
+					t.Errorf("note %d = %q, want it to mention %q", i, notes[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// SendReleaseUpdates announces Coming Soon games that just became free to the
+// servers whose /comingsoon mode asks to be told at release
+func (b *DiscordBot) SendReleaseUpdates(games []models.Game) error {
+	if len(games) == 0 {
+		return nil
+	}
+
+	serverConfigs, err := b.database.GetAllActiveServerConfigs()
+	if err != nil {
+		return fmt.Errorf("error getting server configs: %w", err)
+	}
+
+	released := models.NewGameCollection(games)
+	cycleID := time.Now().UTC().Format("20060102T150405.000000000Z")
+	var jobs []deliveryJob
+	for _, config := range serverConfigs {
+		if !config.AnnouncesReleases() {
+			continue
+		}
+		jobs = append(jobs, deliveryJob{
+			cycleID:   cycleID,
+			guildID:   config.GuildID,
+			channelID: config.ChannelID,
+			config:    config,
+			games:     released,
+			release:   true,
+		})
+	}
+
+	b.runDeliveryCycle(b.ctx, jobs)
+	return nil
+}
+
+// comingSoonModeDescriptions explains each /comingsoon mode to admins
+var comingSoonModeDescriptions = map[string]string{
+	database.ComingSoonAnnounce:    "Coming Soon games are posted in advance only.",
+	database.ComingSoonReleaseOnly: "Coming Soon games are not posted in advance; each is announced when it becomes free.",
+	database.ComingSoonBoth:        "Coming Soon games are posted in advance and announced again when they become free.",
+}
+
+// handleComingSoonCommand handles /comingsoon mode, choosing whether Coming
+// Soon games are posted in advance, at release or both
+func (b *DiscordBot) handleComingSoonCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	serverConfig, err := b.database.GetServerConfig(i.GuildID)
+	if err != nil {
+		b.respondToInteraction(s, i, "Error checking server configuration.", true)
+		return
+	}
+	if serverConfig == nil {
+		b.respondToInteraction(s, i, "This server is not configured yet. Use /setup first.", true)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 || len(options[0].Options) == 0 {
+		b.respondToInteraction(s, i, "Please choose a mode.", true)
+		return
+	}
+	mode := options[0].Options[0].StringValue()
+	description, ok := comingSoonModeDescriptions[mode]
+	if !ok {
+		b.respondToInteraction(s, i, "Unknown mode. Choose announce, release_only or both.", true)
+		return
+	}
+
+	if err := b.database.SetComingSoonMode(i.GuildID, mode); err != nil {
+		log.Printf("Error saving Coming Soon mode for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to save the setting. Please try again.", true)
+		return
+	}
+
+	b.respondToInteraction(s, i, fmt.Sprintf("Coming Soon mode set to **%s**. %s", mode, description), false)
+	log.Printf("Server %s set Coming Soon mode to %s", i.GuildID, mode)
+}
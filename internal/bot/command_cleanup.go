@@ -0,0 +1,99 @@
+package bot
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/database"
+)
+
+// CleanupCommandsMain runs the cleanup-commands subcommand: it deletes every
+// slash command the bot has registered, globally and in each of its guilds,
+// and returns the process exit code. The next start registers them afresh.
+func CleanupCommandsMain(args []string) int {
+	fs := flag.NewFlagSet("cleanup-commands", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+	session, err := discordgo.New("Bot " + cfg.Discord.Token)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Discord session: %v\n", err)
+		return 1
+	}
+	db, err := database.New(cfg.Database.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	guilds, err := CleanupCommands(session, db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Command cleanup incomplete: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Removed global slash commands and the commands of %d guilds\n", guilds)
+	return 0
+}
+
+// CleanupCommands removes the bot's global commands and its per-guild
+// commands in every guild it is in, through the REST API alone, and returns
+// the number of guilds cleaned. The recorded registration strategy is
+// cleared so the next start doesn't assume any commands exist.
+func CleanupCommands(session *discordgo.Session, db *database.Database) (int, error) {
+	user, err := session.User("@me")
+	if err != nil {
+		return 0, fmt.Errorf("error getting bot user: %w", err)
+	}
+	guildIDs, err := botGuildIDs(session)
+	if err != nil {
+		return 0, err
+	}
+
+	registrar := &commandRegistrar{session: session, appID: user.ID}
+	errs := []error{registrar.overwrite("", nil)}
+	for _, guildID := range guildIDs {
+		errs = append(errs, registrar.overwrite(guildID, nil))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return 0, err
+	}
+
+	if err := db.SetBotState(commandStrategyStateKey, ""); err != nil {
+		return 0, err
+	}
+	return len(guildIDs), nil
+}
+
+// botGuildIDs lists every guild the bot is in, a page at a time
+func botGuildIDs(session *discordgo.Session) ([]string, error) {
+	const pageSize = 200
+	var guildIDs []string
+	after := ""
+	for {
+		guilds, err := session.UserGuilds(pageSize, "", after, false)
+		if err != nil {
+			return nil, fmt.Errorf("error listing guilds: %w", err)
+		}
+		for _, guild := range guilds {
+			guildIDs = append(guildIDs, guild.ID)
+		}
+		if len(guilds) < pageSize {
+			return guildIDs, nil
+		}
+		after = guilds[len(guilds)-1].ID
+	}
+}
@@ -0,0 +1,729 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// Command registration strategies accepted by DISCORD_COMMAND_REGISTRATION.
+// Global commands suit large public instances; per-guild commands appear
+// instantly but do not scale past a few dozen guilds. Auto picks between them
+// using DISCORD_COMMAND_GUILD_THRESHOLD.
+const (
+	RegistrationGlobal = "global"
+	RegistrationGuild  = "guild"
+	RegistrationAuto   = "auto"
+)
+
+// registrationDev is the active strategy while DISCORD_DEV_GUILD_ID limits
+// commands to one development guild. It is applied as per-guild
+// registration, so the next normal start cleans up after it.
+const registrationDev = "dev"
+
+// commandStrategyStateKey stores the last fully applied strategy in bot_state
+const commandStrategyStateKey = "command_registration_strategy"
+
+// ownerCommandPermissions hides owner-only commands from regular members
+var ownerCommandPermissions int64 = discordgo.PermissionAdministrator
+
+// slashCommands returns the definitions of every slash command
+func slashCommands() []*discordgo.ApplicationCommand {
+	return []*discordgo.ApplicationCommand{
+		{
+			Name:        "setup",
+			Description: "Configure which channel to send free game notifications to",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionChannel,
+					Name:        "channel",
+					Description: "The channel to send notifications to",
+					Required:    true,
+					ChannelTypes: []discordgo.ChannelType{
+						discordgo.ChannelTypeGuildText,
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "webhook",
+					Description: "Post through this Discord webhook URL instead, using the channel as a fallback",
+					Required:    false,
+				},
+			},
+		},
+		{
+			Name:        "unsubscribe",
+			Description: "Stop free game notifications in this server or in your DMs",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "target",
+					Description: "What to unsubscribe (defaults to this server, or your DMs when used in DMs)",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "This server", Value: unsubscribeServer},
+						{Name: "My DMs", Value: unsubscribeMe},
+					},
+				},
+			},
+		},
+		{
+			Name:        "subscribe",
+			Description: "Get a DM when a new game becomes free",
+		},
+		{
+			Name:        "games",
+			Description: "Show current free games",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "view",
+					Description: "Page through one embed (default) or post every game",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "paged", Value: "paged"},
+						{Name: "all", Value: gamesViewAll},
+					},
+				},
+			},
+		},
+		{
+			Name:        "refresh",
+			Description: "Manually check for new games",
+		},
+		{
+			Name:        "status",
+			Description: "Show bot status and configuration",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "view",
+					Description: "What to show",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "overview", Value: "overview"},
+						{Name: "recent", Value: "recent"},
+					},
+				},
+			},
+		},
+		{
+			Name:        "nextcheck",
+			Description: "Show when the bot will next check for free games",
+		},
+		{
+			Name:        "privacy",
+			Description: "Show what data the bot stores about this server",
+		},
+		{
+			Name:        "impact",
+			Description: "Show what the bot announced to this server in the last 30 days",
+		},
+		{
+			Name:        "gameinfo",
+			Description: "Show details about a game",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "title",
+					Description:  "Game title",
+					Required:     true,
+					Autocomplete: true,
+				},
+			},
+		},
+		{
+			Name:        "compare",
+			Description: "Compare giveaways between two time periods",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "period1",
+					Description: "First period (e.g. \"this week\", \"last month\", \"2025-07\")",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "period2",
+					Description: "Second period (e.g. \"last week\", \"last 30 days\")",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "setdelay",
+			Description: "Set a pause between consecutive game announcements in this server",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "seconds",
+					Description: "Seconds to wait between announcements (0 to disable)",
+					Required:    true,
+					MinValue:    &minPostDelay,
+					MaxValue:    database.MaxPostDelaySeconds,
+				},
+			},
+		},
+		{
+			Name:        "setrole",
+			Description: "Choose a role to ping when new free games are announced",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "set",
+					Description: "Ping a role on new game announcements",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionRole,
+							Name:        "role",
+							Description: "The role to ping",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "none",
+					Description: "Stop pinging a role",
+				},
+			},
+		},
+		{
+			Name:        "textfallback",
+			Description: "Send plain-text announcements when the bot cannot post embeds",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "enabled",
+					Description: "Whether to fall back to plain text",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "markseen",
+			Description: "Mark the current giveaways as announced here without posting them",
+		},
+		{
+			Name:        "setreminders",
+			Description: "Post a last-chance reminder about 24 hours before a free game ends",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "state",
+					Description: "Whether to send expiry reminders",
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "on", Value: "on"},
+						{Name: "off", Value: "off"},
+					},
+				},
+			},
+		},
+		{
+			Name:        "comingsoon",
+			Description: "Choose when Coming Soon games are posted",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "mode",
+					Description: "Post Coming Soon games in advance, only once they are free, or both",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "value",
+							Description: "When to post Coming Soon games",
+							Required:    true,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "announce (in advance)", Value: database.ComingSoonAnnounce},
+								{Name: "release_only (when free)", Value: database.ComingSoonReleaseOnly},
+								{Name: "both (default)", Value: database.ComingSoonBoth},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:        "format",
+			Description: "Choose how game announcements look",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "style",
+					Description: "Rich embeds, plain store links or both",
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "embed (default)", Value: database.FormatEmbed},
+						{Name: "plain (store link with Discord's preview)", Value: database.FormatPlain},
+						{Name: "both", Value: database.FormatBoth},
+					},
+				},
+			},
+		},
+		{
+			Name:        "sources",
+			Description: "Choose which stores' giveaways are announced",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "stores",
+					Description: "Stores to announce games from",
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "all (default)", Value: sourcesAll},
+						{Name: "Epic Games Store only", Value: models.SourceEpic},
+						{Name: "GOG only", Value: models.SourceGOG},
+					},
+				},
+			},
+		},
+		{
+			Name:        "settings",
+			Description: "Apply or save a bundle of server settings",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "apply",
+					Description: "Apply a preset after reviewing what it changes",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:         discordgo.ApplicationCommandOptionString,
+							Name:         "preset",
+							Description:  "Built-in or saved preset name",
+							Required:     true,
+							Autocomplete: true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "save",
+					Description: "Save this server's settings as one of your presets",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "name",
+							Description: "Preset name: lowercase letters, digits and dashes",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "list",
+					Description: "List the built-in presets and your own",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "delete",
+					Description: "Delete one of your presets",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:         discordgo.ApplicationCommandOptionString,
+							Name:         "name",
+							Description:  "Preset name",
+							Required:     true,
+							Autocomplete: true,
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:        "settimezone",
+			Description: "Choose the timezone free periods are shown in",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "timezone",
+					Description: "IANA timezone name, e.g. Europe/Berlin or America/New_York (default UTC)",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "region",
+			Description: "Show or choose which Epic region's free games this server sees",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "locale",
+					Description: "Region to follow (leave empty to show the current one)",
+					Choices:     regionChoices(),
+				},
+			},
+		},
+		{
+			Name:        "customize",
+			Description: "Customize this server's game announcements",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "reminder",
+					Description: "Add a reminder to Free Now announcements, e.g. that claiming needs an Epic account",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "text",
+							Description: "Reminder text, or off to remove it",
+							Required:    true,
+							MaxLength:   database.MaxClaimReminderLength,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "policy",
+					Description: "Make announcements follow this server's posting rules",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "constraints",
+							Description: "Comma-separated: no-mentions, no-links, text-only; or off to remove the policy",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "banner",
+					Description: "Start announcements of several games with a banner showing all of them",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionBoolean,
+							Name:        "enabled",
+							Description: "Whether to post the banner",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "preview",
+					Description: "Preview an announcement with this server's settings and content policy",
+				},
+			},
+		},
+		{
+			Name:        "mute",
+			Description: "Stop the bot from referencing a game in this server",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "game",
+					Description: "Mute a game",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:         discordgo.ApplicationCommandOptionString,
+							Name:         "title",
+							Description:  "Game title",
+							Required:     true,
+							Autocomplete: true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "list",
+					Description: "List muted games",
+				},
+			},
+		},
+		{
+			Name:        "unmute",
+			Description: "Allow the bot to reference a muted game again",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "title",
+					Description:  "Game title",
+					Required:     true,
+					Autocomplete: true,
+				},
+			},
+		},
+		{
+			Name:                     "snapshot",
+			Description:              "Owner only: dump the games catalog to a JSON file",
+			DefaultMemberPermissions: &ownerCommandPermissions,
+		},
+		{
+			Name:                     "restore",
+			Description:              "Owner only: replace the games catalog from a JSON snapshot",
+			DefaultMemberPermissions: &ownerCommandPermissions,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "file",
+					Description: "Snapshot file name (e.g. games-20250717-120000.json)",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:                     "admin",
+			Description:              "Owner only: maintain the games catalog",
+			DefaultMemberPermissions: &ownerCommandPermissions,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "unlinked",
+					Description: "List games whose store link lookup needs a decision",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "linkgame",
+					Description: "Set the store link of a game",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "id",
+							Description: "Game ID from /admin unlinked",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "url",
+							Description: "Store page URL",
+							Required:    true,
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:        "help",
+			Description: "Show all available commands",
+		},
+	}
+}
+
+// commandSession is the subset of *discordgo.Session used to register commands
+type commandSession interface {
+	ApplicationCommandBulkOverwrite(appID string, guildID string, commands []*discordgo.ApplicationCommand, options ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error)
+}
+
+// chooseStrategy resolves the configured mode into global or guild registration
+func chooseStrategy(mode string, guildCount, threshold int) string {
+	switch mode {
+	case RegistrationGuild:
+		return RegistrationGuild
+	case RegistrationAuto:
+		if guildCount < threshold {
+			return RegistrationGuild
+		}
+		return RegistrationGlobal
+	default:
+		return RegistrationGlobal
+	}
+}
+
+// commandRegistrar applies a registration strategy. Every step is a bulk
+// overwrite, so re-running after a partial failure is safe.
+type commandRegistrar struct {
+	session  commandSession
+	appID    string
+	commands []*discordgo.ApplicationCommand
+}
+
+// apply registers the commands with strategy and removes duplicates left by
+// previous, the last strategy that was fully applied ("" when unknown). It
+// returns the guilds that now have per-guild commands and an error joining
+// every failed step; the caller must not record the strategy as applied when
+// an error is returned, so that cleanup is retried on the next start.
+func (r *commandRegistrar) apply(strategy, previous string, guildIDs []string) ([]string, error) {
+	var errs []error
+	var registered []string
+
+	switch strategy {
+	case RegistrationGuild:
+		for _, guildID := range guildIDs {
+			if err := r.overwrite(guildID, r.commands); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			registered = append(registered, guildID)
+		}
+		// Remove global commands only after guild commands exist so users are
+		// never left without commands; this also clears legacy global commands.
+		if previous != RegistrationGuild {
+			if err := r.overwrite("", nil); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	default:
+		if err := r.overwrite("", r.commands); err != nil {
+			// Keep per-guild commands when global registration failed
+			return nil, err
+		}
+		if previous == RegistrationGuild {
+			for _, guildID := range guildIDs {
+				if err := r.overwrite(guildID, nil); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	return registered, errors.Join(errs...)
+}
+
+// overwrite replaces the commands of a guild (or the global scope when
+// guildID is empty). A nil list removes all commands.
+func (r *commandRegistrar) overwrite(guildID string, commands []*discordgo.ApplicationCommand) error {
+	if commands == nil {
+		commands = []*discordgo.ApplicationCommand{}
+	}
+
+	if _, err := r.session.ApplicationCommandBulkOverwrite(r.appID, guildID, commands); err != nil {
+		scope := "global"
+		if guildID != "" {
+			scope = "guild " + guildID
+		}
+		return fmt.Errorf("error overwriting %s commands: %w", scope, err)
+	}
+	return nil
+}
+
+// commandState tracks the active registration strategy and which guilds
+// already have per-guild commands
+type commandState struct {
+	mu       sync.Mutex
+	strategy string
+	guilds   map[string]bool
+}
+
+// set records the active strategy and the guilds registered under it
+func (c *commandState) set(strategy string, guildIDs []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.strategy = strategy
+	c.guilds = make(map[string]bool, len(guildIDs))
+	for _, guildID := range guildIDs {
+		c.guilds[guildID] = true
+	}
+}
+
+// get returns the active strategy and the number of registered guilds
+func (c *commandState) get() (string, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.strategy, len(c.guilds)
+}
+
+// needsGuild reports whether guildID still needs per-guild commands and, if
+// so, reserves it so concurrent events don't register it twice
+func (c *commandState) needsGuild(guildID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.strategy != RegistrationGuild || c.guilds[guildID] {
+		return false
+	}
+	c.guilds[guildID] = true
+	return true
+}
+
+// forgetGuild releases a reservation after a failed registration
+func (c *commandState) forgetGuild(guildID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.guilds, guildID)
+}
+
+// registerSlashCommands registers all slash commands with Discord using the
+// configured strategy, switching between global and per-guild registration
+// when auto mode crosses the guild threshold
+func (b *DiscordBot) registerSlashCommands() error {
+	guildIDs := make([]string, 0, len(b.session.State.Guilds))
+	for _, guild := range b.session.State.Guilds {
+		guildIDs = append(guildIDs, guild.ID)
+	}
+
+	strategy := chooseStrategy(b.config.CommandRegistration, len(guildIDs), b.config.CommandGuildThreshold)
+	if b.config.DevGuildID != "" {
+		strategy, guildIDs = RegistrationGuild, []string{b.config.DevGuildID}
+	}
+	previous, err := b.database.GetBotState(commandStrategyStateKey)
+	if err != nil {
+		log.Printf("Error loading previous command registration strategy: %v", err)
+	}
+	if previous != "" && previous != strategy {
+		log.Printf("Switching command registration from %s to %s (%d guilds, threshold %d)",
+			previous, strategy, len(guildIDs), b.config.CommandGuildThreshold)
+	}
+
+	registrar := &commandRegistrar{
+		session:  b.session,
+		appID:    b.session.State.User.ID,
+		commands: slashCommands(),
+	}
+	registered, err := registrar.apply(strategy, previous, guildIDs)
+	if b.config.DevGuildID != "" {
+		// Guilds joining later get no commands
+		b.commands.set(registrationDev, registered)
+	} else {
+		b.commands.set(strategy, registered)
+	}
+	if err != nil {
+		return fmt.Errorf("command registration (%s) incomplete, will retry on next start: %w", strategy, err)
+	}
+
+	if err := b.database.SetBotState(commandStrategyStateKey, strategy); err != nil {
+		log.Printf("Error saving command registration strategy: %v", err)
+	}
+
+	if b.config.DevGuildID != "" {
+		log.Printf("Successfully registered %d slash commands in development guild %s only", len(registrar.commands), b.config.DevGuildID)
+		return nil
+	}
+	log.Printf("Successfully registered %d slash commands (%s)", len(registrar.commands), strategy)
+	return nil
+}
+
+// registerGuildCommands registers per-guild commands for a guild that joined
+// after startup. It does nothing under global registration.
+func (b *DiscordBot) registerGuildCommands(guildID string) {
+	if !b.commands.needsGuild(guildID) {
+		return
+	}
+
+	registrar := &commandRegistrar{
+		session:  b.session,
+		appID:    b.session.State.User.ID,
+		commands: slashCommands(),
+	}
+	if err := registrar.overwrite(guildID, registrar.commands); err != nil {
+		b.commands.forgetGuild(guildID)
+		log.Printf("Error registering commands for guild %s: %v", guildID, err)
+		return
+	}
+	log.Printf("Registered slash commands for guild %s", guildID)
+}
+
+// describeCommandStrategy renders the active registration strategy for /status
+func (b *DiscordBot) describeCommandStrategy() string {
+	strategy, guilds := b.commands.get()
+	var description string
+	switch strategy {
+	case RegistrationGuild:
+		description = fmt.Sprintf("Per-guild (%d guilds)", guilds)
+	case RegistrationGlobal:
+		description = "Global"
+	case registrationDev:
+		return fmt.Sprintf("Development guild %s only", b.config.DevGuildID)
+	default:
+		return "Not registered yet"
+	}
+	if b.config.CommandRegistration == RegistrationAuto {
+		description += fmt.Sprintf(" — auto, threshold %d", b.config.CommandGuildThreshold)
+	}
+	return description
+}
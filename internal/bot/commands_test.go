@@ -0,0 +1,269 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// fakeCommandSession keeps the commands registered per scope ("" for
+// global) the way Discord's bulk overwrite does, failing for the scopes in
+// fail
+type fakeCommandSession struct {
+	scopes map[string][]string
+	fail   map[string]bool
+	calls  []string
+}
+
+func (f *fakeCommandSession) ApplicationCommandBulkOverwrite(appID string, guildID string, commands []*discordgo.ApplicationCommand, options ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error) {
+	f.calls = append(f.calls, guildID)
+	if f.fail[guildID] {
+		return nil, errors.New("discord unavailable")
+	}
+	if len(commands) == 0 {
+		delete(f.scopes, guildID)
+		return commands, nil
+	}
+	names := make([]string, len(commands))
+	for i, command := range commands {
+		names[i] = command.Name
+	}
+	f.scopes[guildID] = names
+	return commands, nil
+}
+
+func TestChooseStrategy(t *testing.T) {
+	tests := []struct {
+		mode   string
+		guilds int
+		want   string
+	}{
+		{RegistrationGlobal, 1, RegistrationGlobal},
+		{RegistrationGuild, 500, RegistrationGuild},
+		{RegistrationAuto, 49, RegistrationGuild},
+		{RegistrationAuto, 50, RegistrationGlobal},
+		{"", 1, RegistrationGlobal},
+	}
+	for _, tt := range tests {
+		if got := chooseStrategy(tt.mode, tt.guilds, 50); got != tt.want {
+			t.Errorf("chooseStrategy(%q, %d, 50) = %q, want %q", tt.mode, tt.guilds, got, tt.want)
+		}
+	}
+}
+
+func TestCommandRegistrarTransitions(t *testing.T) {
+	commands := []*discordgo.ApplicationCommand{{Name: "games"}, {Name: "help"}}
+	registered := []string{"games", "help"}
+	old := []string{"games", "refresh"}
+	guilds := []string{"g1", "g2"}
+
+	tests := []struct {
+		name           string
+		start          map[string][]string
+		strategy       string
+		previous       string
+		fail           []string
+		want           map[string][]string
+		wantRegistered []string
+		wantCalls      int
+		wantErr        bool
+	}{
+		{
+			name:           "first start per guild",
+			start:          map[string][]string{},
+			strategy:       RegistrationGuild,
+			want:           map[string][]string{"g1": registered, "g2": registered},
+			wantRegistered: guilds,
+			wantCalls:      3,
+		},
+		{
+			name:      "global commands updated",
+			start:     map[string][]string{"": old},
+			strategy:  RegistrationGlobal,
+			previous:  RegistrationGlobal,
+			want:      map[string][]string{"": registered},
+			wantCalls: 1,
+		},
+		{
+			name:           "guild commands updated without touching global",
+			start:          map[string][]string{"g1": old, "g2": old},
+			strategy:       RegistrationGuild,
+			previous:       RegistrationGuild,
+			want:           map[string][]string{"g1": registered, "g2": registered},
+			wantRegistered: guilds,
+			wantCalls:      2,
+		},
+		{
+			name:      "guild to global removes guild duplicates",
+			start:     map[string][]string{"g1": old, "g2": old},
+			strategy:  RegistrationGlobal,
+			previous:  RegistrationGuild,
+			want:      map[string][]string{"": registered},
+			wantCalls: 3,
+		},
+		{
+			name:           "global to guild removes global commands",
+			start:          map[string][]string{"": old},
+			strategy:       RegistrationGuild,
+			previous:       RegistrationGlobal,
+			want:           map[string][]string{"g1": registered, "g2": registered},
+			wantRegistered: guilds,
+			wantCalls:      3,
+		},
+		{
+			name:      "cleanup of one guild fails",
+			start:     map[string][]string{"g1": old, "g2": old},
+			strategy:  RegistrationGlobal,
+			previous:  RegistrationGuild,
+			fail:      []string{"g2"},
+			want:      map[string][]string{"": registered, "g2": old},
+			wantCalls: 3,
+			wantErr:   true,
+		},
+		{
+			name:      "global registration fails",
+			start:     map[string][]string{"g1": old, "g2": old},
+			strategy:  RegistrationGlobal,
+			previous:  RegistrationGuild,
+			fail:      []string{""},
+			want:      map[string][]string{"g1": old, "g2": old},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:           "registration in one guild fails",
+			start:          map[string][]string{"": old},
+			strategy:       RegistrationGuild,
+			previous:       RegistrationGlobal,
+			fail:           []string{"g1"},
+			want:           map[string][]string{"g2": registered},
+			wantRegistered: []string{"g2"},
+			wantCalls:      3,
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &fakeCommandSession{scopes: tt.start, fail: make(map[string]bool)}
+			for _, scope := range tt.fail {
+				session.fail[scope] = true
+			}
+			registrar := &commandRegistrar{session: session, appID: "app", commands: commands}
+
+			got, err := registrar.apply(tt.strategy, tt.previous, guilds)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("apply error = %v, want error %v", err, tt.wantErr)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantRegistered) {
+				t.Errorf("registered guilds = %v, want %v", got, tt.wantRegistered)
+			}
+			if fmt.Sprint(session.scopes) != fmt.Sprint(tt.want) {
+				t.Errorf("commands = %v, want %v", session.scopes, tt.want)
+			}
+			if len(session.calls) != tt.wantCalls {
+				t.Errorf("made %d overwrites (%q), want %d", len(session.calls), session.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCommandRegistrarRetryFinishesCleanup(t *testing.T) {
+	commands := []*discordgo.ApplicationCommand{{Name: "games"}}
+	session := &fakeCommandSession{
+		scopes: map[string][]string{"g1": {"games"}, "g2": {"games"}},
+		fail:   map[string]bool{"g1": true},
+	}
+	registrar := &commandRegistrar{session: session, appID: "app", commands: commands}
+
+	// The failed run is not recorded, so the next start still sees guild
+	// registration as the previous strategy and retries the cleanup
+	if _, err := registrar.apply(RegistrationGlobal, RegistrationGuild, []string{"g1", "g2"}); err == nil {
+		t.Fatal("apply succeeded although removing g1's commands failed")
+	}
+	delete(session.fail, "g1")
+	for i := 0; i < 2; i++ {
+		if _, err := registrar.apply(RegistrationGlobal, RegistrationGuild, []string{"g1", "g2"}); err != nil {
+			t.Fatalf("retry %d: %v", i+1, err)
+		}
+	}
+
+	want := map[string][]string{"": {"games"}}
+	if fmt.Sprint(session.scopes) != fmt.Sprint(want) {
+		t.Errorf("commands = %v, want only the global ones", session.scopes)
+	}
+}
+
+func TestRegisterSlashCommandsSwitchesWithGuildCount(t *testing.T) {
+	b := newTestBot(t)
+	b.config.CommandRegistration = RegistrationAuto
+	b.config.CommandGuildThreshold = 3
+	discord := useFakeDiscord(t, b)
+	discord.fail = func(request fakeRequest) (int, string) {
+		// Bulk overwrites answer with the resulting command list
+		return http.StatusOK, "[]"
+	}
+	appID := b.session.State.User.ID
+
+	register := func(guildIDs ...string) {
+		t.Helper()
+		for _, guildID := range guildIDs {
+			if err := b.session.State.GuildAdd(&discordgo.Guild{ID: guildID}); err != nil {
+				t.Fatalf("GuildAdd: %v", err)
+			}
+		}
+		discord.mu.Lock()
+		discord.requests = nil
+		discord.mu.Unlock()
+		if err := b.registerSlashCommands(); err != nil {
+			t.Fatalf("registerSlashCommands: %v", err)
+		}
+	}
+	overwrites := func(path string) (added, removed int) {
+		for _, request := range discord.find("PUT", path) {
+			if request.Path != path {
+				continue
+			}
+			if len(request.List) > 0 {
+				added++
+			} else {
+				removed++
+			}
+		}
+		return added, removed
+	}
+
+	// Below the threshold every guild gets its own commands
+	register("1", "2")
+	if strategy, guilds := b.commands.get(); strategy != RegistrationGuild || guilds != 2 {
+		t.Errorf("strategy = %s with %d guilds, want guild with 2", strategy, guilds)
+	}
+	if added, _ := overwrites("applications/" + appID + "/guilds/1/commands"); added != 1 {
+		t.Errorf("guild 1 got commands %d times, want once", added)
+	}
+
+	// Crossing it moves the commands to the global scope and clears the guilds
+	register("3")
+	if strategy, _ := b.commands.get(); strategy != RegistrationGlobal {
+		t.Errorf("strategy = %s, want global", strategy)
+	}
+	if added, _ := overwrites("applications/" + appID + "/commands"); added != 1 {
+		t.Errorf("global commands set %d times, want once", added)
+	}
+	for _, guildID := range []string{"1", "2", "3"} {
+		if _, removed := overwrites("applications/" + appID + "/guilds/" + guildID + "/commands"); removed != 1 {
+			t.Errorf("guild %s commands removed %d times, want once", guildID, removed)
+		}
+	}
+	if state, _ := b.database.GetBotState(commandStrategyStateKey); state != RegistrationGlobal {
+		t.Errorf("recorded strategy %q, want global", state)
+	}
+
+	// Restarting with the same guilds only updates the global commands
+	register()
+	if n := len(discord.find("PUT", "applications/")); n != 1 {
+		t.Errorf("made %d overwrites on restart, want 1", n)
+	}
+}
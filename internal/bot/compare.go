@@ -0,0 +1,222 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// maxComparePeriod caps how far back a single /compare period can reach
+const maxComparePeriod = 366 * 24 * time.Hour
+
+// maxFieldLength is Discord's limit for an embed field value
+const maxFieldLength = 1024
+
+// period is a named, half-open time range [Start, End)
+type period struct {
+	Label string
+	Start time.Time
+	End   time.Time
+}
+
+// parsePeriod converts a friendly period string into a time range relative to now.
+// Supported forms: today, yesterday, this/last week, this/last month, this/last year,
+// "last N days", YYYY-MM, YYYY-MM-DD and YYYY-MM-DD..YYYY-MM-DD. Periods starting
+// before the oldest day games are kept for are rejected, since their counts
+// would be incomplete.
+func parsePeriod(input string, now time.Time) (*period, error) {
+	text := strings.ToLower(strings.Join(strings.Fields(input), " "))
+	if text == "" {
+		return nil, fmt.Errorf("period cannot be empty")
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// Weeks start on Monday
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	yearStart := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+
+	var p *period
+	switch text {
+	case "today":
+		p = &period{Start: today, End: today.AddDate(0, 0, 1)}
+	case "yesterday":
+		p = &period{Start: today.AddDate(0, 0, -1), End: today}
+	case "this week":
+		p = &period{Start: weekStart, End: weekStart.AddDate(0, 0, 7)}
+	case "last week":
+		p = &period{Start: weekStart.AddDate(0, 0, -7), End: weekStart}
+	case "this month":
+		p = &period{Start: monthStart, End: monthStart.AddDate(0, 1, 0)}
+	case "last month":
+		p = &period{Start: monthStart.AddDate(0, -1, 0), End: monthStart}
+	case "this year":
+		p = &period{Start: yearStart, End: yearStart.AddDate(1, 0, 0)}
+	case "last year":
+		p = &period{Start: yearStart.AddDate(-1, 0, 0), End: yearStart}
+	default:
+		parsed, err := parseExplicitPeriod(text, today)
+		if err != nil {
+			return nil, err
+		}
+		p = parsed
+	}
+
+	if !p.End.After(p.Start) {
+		return nil, fmt.Errorf("period %q ends before it starts", input)
+	}
+	if p.Start.After(now) {
+		return nil, fmt.Errorf("period %q is in the future", input)
+	}
+	// Games are deleted GameRetentionDays after they were last seen, so only
+	// the days since then are complete
+	if earliest := today.AddDate(0, 0, 1-database.GameRetentionDays); p.Start.Before(earliest) {
+		return nil, fmt.Errorf("period %q starts before %s; games are only kept for %d days", input, earliest.Format("Jan 2, 2006"), database.GameRetentionDays)
+	}
+	if p.End.Sub(p.Start) > maxComparePeriod {
+		return nil, fmt.Errorf("period %q is longer than one year", input)
+	}
+
+	if p.Label == "" {
+		p.Label = titleCase(text)
+	}
+	return p, nil
+}
+
+// parseExplicitPeriod handles "last N days" and ISO date based periods
+func parseExplicitPeriod(text string, today time.Time) (*period, error) {
+	if strings.HasPrefix(text, "last ") && strings.HasSuffix(text, " days") {
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(text, "last "), " days"))
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid day count in %q", text)
+		}
+		return &period{
+			Start: today.AddDate(0, 0, -n+1),
+			End:   today.AddDate(0, 0, 1),
+		}, nil
+	}
+
+	if from, to, ok := strings.Cut(text, ".."); ok {
+		start, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(from), today.Location())
+		if err != nil {
+			return nil, fmt.Errorf("invalid start date in %q (use YYYY-MM-DD)", text)
+		}
+		end, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(to), today.Location())
+		if err != nil {
+			return nil, fmt.Errorf("invalid end date in %q (use YYYY-MM-DD)", text)
+		}
+		return &period{
+			Label: fmt.Sprintf("%s – %s", start.Format("Jan 2, 2006"), end.Format("Jan 2, 2006")),
+			Start: start,
+			End:   end.AddDate(0, 0, 1),
+		}, nil
+	}
+
+	if day, err := time.ParseInLocation("2006-01-02", text, today.Location()); err == nil {
+		return &period{
+			Label: day.Format("Jan 2, 2006"),
+			Start: day,
+			End:   day.AddDate(0, 0, 1),
+		}, nil
+	}
+
+	if month, err := time.ParseInLocation("2006-01", text, today.Location()); err == nil {
+		return &period{
+			Label: month.Format("January 2006"),
+			Start: month,
+			End:   month.AddDate(0, 1, 0),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unrecognized period %q (try \"this week\", \"last month\", \"last 30 days\" or YYYY-MM)", text)
+}
+
+// titleCase capitalizes the first letter of each word
+func titleCase(text string) string {
+	words := strings.Fields(text)
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// formatPeriodSummary renders the games of a period as an embed field value
+func formatPeriodSummary(games []models.Game) string {
+	if len(games) == 0 {
+		return "**0 games**\nNo giveaways recorded."
+	}
+
+	noun := "games"
+	if len(games) == 1 {
+		noun = "game"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**%d %s**", len(games), noun)
+	for i, game := range games {
+		line := "\n• " + game.Title
+		if sb.Len()+len(line) > maxFieldLength-20 {
+			fmt.Fprintf(&sb, "\n…and %d more", len(games)-i)
+			break
+		}
+		sb.WriteString(line)
+	}
+	return sb.String()
+}
+
+// handleCompareCommand handles the /compare slash command
+func (b *DiscordBot) handleCompareCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options
+	if len(options) < 2 {
+		b.respondToInteraction(s, i, "Please specify two periods to compare.", true)
+		return
+	}
+
+	now := time.Now()
+	periods := make([]*period, 0, 2)
+	for _, option := range options[:2] {
+		p, err := parsePeriod(option.StringValue(), now)
+		if err != nil {
+			b.respondToInteraction(s, i, fmt.Sprintf("Invalid period: %v", err), true)
+			return
+		}
+		periods = append(periods, p)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:  "Giveaway Comparison",
+		Color:  b.branding().AccentColor,
+		Footer: brandFooter(b.branding()),
+	}
+
+	for _, p := range periods {
+		games, err := b.gameService.GetGameHistory(p.Start, p.End)
+		if err != nil {
+			log.Printf("Error getting game history for %s: %v", p.Label, err)
+			b.respondToInteraction(s, i, "Failed to load game history. Please try again.", true)
+			return
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("%s (%s – %s)", p.Label, p.Start.Format("Jan 2"), p.End.AddDate(0, 0, -1).Format("Jan 2")),
+			Value:  formatPeriodSummary(games),
+			Inline: true,
+		})
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to compare command: %v", err)
+	}
+}
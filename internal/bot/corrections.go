@@ -0,0 +1,198 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// correctionNote is added to announcements edited with corrected dates
+const correctionNote = "Corrected: the promotion dates changed after this was posted."
+
+// messageEditor edits messages the bot posted. *discordgo.Session
+// implements it; tests record the edits instead.
+type messageEditor interface {
+	ChannelMessageEditComplex(m *discordgo.MessageEdit, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	WebhookMessageEdit(webhookID, token, messageID string, data *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error)
+}
+
+// recordPostedMessage remembers an automatic announcement or reminder so it
+// can be corrected later. Replies to commands (cfg nil) are not recorded.
+func (b *DiscordBot) recordPostedMessage(cfg *database.ServerConfig, kind string, game models.Game, sent sentMessage, position, total int, mention string) {
+	if cfg == nil || sent.message == nil {
+		return
+	}
+	err := b.database.RecordPostedMessage(database.PostedMessage{
+		GuildID:   cfg.GuildID,
+		ChannelID: sent.message.ChannelID,
+		MessageID: sent.message.ID,
+		Webhook:   sent.webhook,
+		Plain:     sent.plain,
+		Kind:      kind,
+		Position:  position,
+		Total:     total,
+		Mention:   mention,
+		Game:      game,
+	})
+	if err != nil {
+		log.Printf("Error recording posted message for %s in guild %s: %v", game.Title, cfg.GuildID, err)
+	}
+}
+
+// CorrectPostedMessages edits the announcements and reminders queued for a
+// correction because the store changed their promotion's dates, those about
+// promotions ending soonest first. Each message is edited once; edits that
+// fail for a transient reason are retried on the next call, and messages that
+// were deleted or can no longer be reached are given up on.
+func (b *DiscordBot) CorrectPostedMessages(ctx context.Context) {
+	pending, err := b.database.GetPendingCorrections()
+	if err != nil {
+		log.Printf("Error getting pending corrections: %v", err)
+		return
+	}
+
+	configs := make(map[string]*database.ServerConfig)
+	corrected := 0
+	for _, posted := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+
+		cfg, ok := configs[posted.GuildID]
+		if !ok {
+			if cfg, err = b.database.GetServerConfig(posted.GuildID); err != nil {
+				log.Printf("Error getting server config of guild %s for corrections: %v", posted.GuildID, err)
+				continue
+			}
+			configs[posted.GuildID] = cfg
+		}
+		if cfg == nil {
+			// The server's settings are gone, so nothing can be rendered for it
+			b.clearCorrection(posted)
+			continue
+		}
+
+		if err := b.rateLimiter.WaitForChannel(ctx, posted.ChannelID); err != nil {
+			log.Printf("Rate limiter wait failed for channel %s: %v", posted.ChannelID, err)
+			return
+		}
+		err := b.editPostedMessage(posted, cfg)
+		if err != nil && !isMessageGone(err) {
+			log.Printf("Error correcting message %s about %s in channel %s, will retry: %v", posted.MessageID, posted.Game.Title, posted.ChannelID, err)
+			continue
+		}
+		if err != nil {
+			log.Printf("Message %s about %s in channel %s can no longer be corrected: %v", posted.MessageID, posted.Game.Title, posted.ChannelID, err)
+		} else {
+			corrected++
+		}
+		b.clearCorrection(posted)
+	}
+
+	if corrected > 0 {
+		log.Printf("Corrected the dates in %d posted messages", corrected)
+	}
+}
+
+// clearCorrection marks a posted message as no longer waiting for an edit
+func (b *DiscordBot) clearCorrection(posted database.PostedMessage) {
+	if err := b.database.ClearCorrectionPending(posted.ID); err != nil {
+		log.Printf("Error clearing pending correction of message %s: %v", posted.MessageID, err)
+	}
+}
+
+// editPostedMessage renders a posted message again from the stored game, the
+// way it was first posted, with the correction note added
+func (b *DiscordBot) editPostedMessage(posted database.PostedMessage, cfg *database.ServerConfig) error {
+	game := posted.Game
+	var embed *discordgo.MessageEmbed
+	switch {
+	case posted.Kind == database.NotificationExpiryReminder:
+		embed = expiryReminderEmbed(b.branding(), game, time.Now())
+	case posted.Kind == database.NotificationRelease:
+		embed = b.freeNowEmbed(game, posted.Position, posted.Total, cfg, true)
+	case game.Status == models.StatusComingSoon:
+		embed = b.comingSoonEmbed(game, posted.Position, posted.Total, cfg)
+	default:
+		embed = b.freeNowEmbed(game, posted.Position, posted.Total, cfg, false)
+	}
+
+	rendered := renderGameMessage(embed, game, cfg, posted.Mention)
+	message := rendered.rich
+	if posted.Plain {
+		message = rendered.plain
+	}
+	content, embeds := withCorrectionNote(message.Content, message.Embeds)
+	// An edit never pings, but the mention stays rendered as it was
+	allowedMentions := &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}}
+
+	if posted.Webhook {
+		id, token, ok := webhookCredentials(cfg.WebhookURL)
+		if !ok {
+			return errWebhookGone
+		}
+		return b.retryOnRateLimit("webhook "+id, func() error {
+			_, err := b.editor.WebhookMessageEdit(id, token, posted.MessageID, &discordgo.WebhookEdit{
+				Content:         &content,
+				Embeds:          &embeds,
+				AllowedMentions: allowedMentions,
+			}, discordgo.WithRetryOnRatelimit(false))
+			return err
+		})
+	}
+	return b.retryOnRateLimit("channel "+posted.ChannelID, func() error {
+		_, err := b.editor.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:              posted.MessageID,
+			Channel:         posted.ChannelID,
+			Content:         &content,
+			Embeds:          &embeds,
+			AllowedMentions: allowedMentions,
+			Flags:           message.Flags,
+		}, discordgo.WithRetryOnRatelimit(false))
+		return err
+	})
+}
+
+// withCorrectionNote adds correctionNote to the footer of the first embed, or
+// as small text below the content of messages without embeds
+func withCorrectionNote(content string, embeds []*discordgo.MessageEmbed) (string, []*discordgo.MessageEmbed) {
+	if len(embeds) == 0 {
+		return content + "\n-# " + correctionNote, []*discordgo.MessageEmbed{}
+	}
+
+	first := *embeds[0]
+	footer := &discordgo.MessageEmbedFooter{Text: correctionNote}
+	if first.Footer != nil {
+		footer.Text = first.Footer.Text + " • " + correctionNote
+		footer.IconURL = first.Footer.IconURL
+	}
+	first.Footer = footer
+	return content, append([]*discordgo.MessageEmbed{&first}, embeds[1:]...)
+}
+
+// errWebhookGone is returned for messages posted through a webhook the
+// server has since removed or replaced
+var errWebhookGone = errors.New("the webhook the message was posted with is no longer configured")
+
+// isMessageGone reports whether a message can't be edited anymore: it, its
+// channel or its webhook was deleted, or the bot lost access to it
+func isMessageGone(err error) bool {
+	if errors.Is(err, errWebhookGone) {
+		return true
+	}
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) || restErr.Message == nil {
+		return false
+	}
+	switch restErr.Message.Code {
+	case discordgo.ErrCodeUnknownMessage, discordgo.ErrCodeUnknownChannel, discordgo.ErrCodeUnknownWebhook,
+		discordgo.ErrCodeMissingAccess, discordgo.ErrCodeMissingPermissions, discordgo.ErrCodeCannotEditFromAnotherUser:
+		return true
+	}
+	return false
+}
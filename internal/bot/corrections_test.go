@@ -0,0 +1,202 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// recordingEditor records message edits instead of sending them to Discord
+type recordingEditor struct {
+	mu      sync.Mutex
+	edits   map[string]int
+	footers map[string]string
+	fail    map[string]error
+}
+
+func newRecordingEditor() *recordingEditor {
+	return &recordingEditor{edits: make(map[string]int), footers: make(map[string]string), fail: make(map[string]error)}
+}
+
+func (r *recordingEditor) record(messageID string, embeds *[]*discordgo.MessageEmbed) (*discordgo.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail[messageID]; err != nil {
+		return nil, err
+	}
+	r.edits[messageID]++
+	if embeds != nil && len(*embeds) > 0 && (*embeds)[0].Footer != nil {
+		r.footers[messageID] = (*embeds)[0].Footer.Text
+	}
+	return &discordgo.Message{ID: messageID}, nil
+}
+
+func (r *recordingEditor) ChannelMessageEditComplex(m *discordgo.MessageEdit, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return r.record(m.ID, m.Embeds)
+}
+
+func (r *recordingEditor) WebhookMessageEdit(webhookID, token, messageID string, data *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return r.record(messageID, data.Embeds)
+}
+
+// announce stores game and records it as posted in each guild, the way a
+// delivery does, returning the posted message IDs
+func announce(t *testing.T, b *DiscordBot, game models.Game, guilds ...string) []string {
+	t.Helper()
+	if _, err := b.gameService.SaveGames([]models.Game{game}); err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+	if err := b.database.MarkGamesNotified([]models.Game{game}); err != nil {
+		t.Fatalf("MarkGamesNotified: %v", err)
+	}
+
+	var messageIDs []string
+	for _, guildID := range guilds {
+		result, err := b.database.SaveServerConfig(guildID, "channel-"+guildID)
+		if err != nil {
+			t.Fatalf("SaveServerConfig: %v", err)
+		}
+		messageID := "message-" + guildID
+		sent := sentMessage{message: &discordgo.Message{ID: messageID, ChannelID: "channel-" + guildID}, webhook: result.Config.WebhookURL != ""}
+		b.recordPostedMessage(result.Config, database.NotificationAnnouncement, game, sent, 0, 1, "")
+		messageIDs = append(messageIDs, messageID)
+	}
+	return messageIDs
+}
+
+// correctEndDate saves a scrape reporting a new end date for game and queues
+// the corrections the way a game check does
+func correctEndDate(t *testing.T, b *DiscordBot, game models.Game, freeTo string) models.Game {
+	t.Helper()
+	corrected := game
+	corrected.FreeTo = freeTo
+	corrected.FreeToTime = time.Time{}
+	if _, err := b.gameService.SaveGames([]models.Game{corrected}); err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+	changes := models.DiffGames([]models.Game{game}, []models.Game{corrected}, time.Now())
+	b.gameService.CorrectAnnouncedDates(changes)
+	return corrected
+}
+
+// runningGame is a Free Now game whose promotion ends in a few days
+func runningGame(title string) models.Game {
+	now := time.Now()
+	return models.Game{
+		Title:    title,
+		Status:   models.StatusFreeNow,
+		FreeFrom: now.AddDate(0, 0, -2).Format("Jan 2"),
+		FreeTo:   now.AddDate(0, 0, 5).Format("Jan 2"),
+		StoreURL: "https://store.epicgames.com/p/" + strings.ToLower(title),
+	}
+}
+
+func TestCorrectPostedMessagesEditsEachMessageOnce(t *testing.T) {
+	b := newTestBot(t)
+	editor := newRecordingEditor()
+	b.editor = editor
+
+	if _, err := b.database.SaveServerConfig("guild-webhook", "channel-guild-webhook"); err != nil {
+		t.Fatalf("SaveServerConfig: %v", err)
+	}
+	if err := b.database.SetWebhookURL("guild-webhook", "https://discord.com/api/webhooks/123/token"); err != nil {
+		t.Fatalf("SetWebhookURL: %v", err)
+	}
+
+	game := runningGame("Corrected Game")
+	messageIDs := announce(t, b, game, "guild-a", "guild-b", "guild-webhook")
+	correctEndDate(t, b, game, time.Now().AddDate(0, 0, 4).Format("Jan 2"))
+
+	// Later game checks find nothing left to correct
+	for i := 0; i < 3; i++ {
+		b.CorrectPostedMessages(context.Background())
+	}
+
+	if len(editor.edits) != len(messageIDs) {
+		t.Errorf("edited %d messages, want %d", len(editor.edits), len(messageIDs))
+	}
+	for _, messageID := range messageIDs {
+		if got := editor.edits[messageID]; got != 1 {
+			t.Errorf("%s edited %d times, want once", messageID, got)
+		}
+		if footer := editor.footers[messageID]; !strings.Contains(footer, correctionNote) {
+			t.Errorf("%s footer = %q, want the correction note", messageID, footer)
+		}
+	}
+
+	pending, err := b.database.GetPendingCorrections()
+	if err != nil {
+		t.Fatalf("GetPendingCorrections: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("%d corrections still pending, want none", len(pending))
+	}
+}
+
+func TestCorrectPostedMessagesRetriesTransientFailures(t *testing.T) {
+	b := newTestBot(t)
+	editor := newRecordingEditor()
+	b.editor = editor
+
+	game := runningGame("Retried Game")
+	messageIDs := announce(t, b, game, "guild-a", "guild-b")
+	correctEndDate(t, b, game, time.Now().AddDate(0, 0, 4).Format("Jan 2"))
+
+	editor.fail["message-guild-a"] = fmt.Errorf("connection reset")
+	b.CorrectPostedMessages(context.Background())
+	delete(editor.fail, "message-guild-a")
+	b.CorrectPostedMessages(context.Background())
+	b.CorrectPostedMessages(context.Background())
+
+	for _, messageID := range messageIDs {
+		if got := editor.edits[messageID]; got != 1 {
+			t.Errorf("%s edited %d times, want once", messageID, got)
+		}
+	}
+}
+
+func TestCorrectPostedMessagesGivesUpOnDeletedMessages(t *testing.T) {
+	b := newTestBot(t)
+	editor := newRecordingEditor()
+	b.editor = editor
+
+	game := runningGame("Deleted Game")
+	announce(t, b, game, "guild-a")
+	correctEndDate(t, b, game, time.Now().AddDate(0, 0, 4).Format("Jan 2"))
+
+	editor.fail["message-guild-a"] = &discordgo.RESTError{Message: &discordgo.APIErrorMessage{Code: discordgo.ErrCodeUnknownMessage}}
+	b.CorrectPostedMessages(context.Background())
+
+	pending, err := b.database.GetPendingCorrections()
+	if err != nil {
+		t.Fatalf("GetPendingCorrections: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("%d corrections still pending for a deleted message, want none", len(pending))
+	}
+}
+
+func TestCorrectAnnouncedDatesKeepsGameAnnounced(t *testing.T) {
+	b := newTestBot(t)
+	b.editor = newRecordingEditor()
+
+	game := runningGame("Moved Game")
+	announce(t, b, game, "guild-a")
+	corrected := correctEndDate(t, b, game, time.Now().AddDate(0, 0, 4).Format("Jan 2"))
+
+	// The corrected promotion is the one already announced, not a new game
+	unnotified, err := b.gameService.GetUnnotifiedGames([]models.Game{corrected})
+	if err != nil {
+		t.Fatalf("GetUnnotifiedGames: %v", err)
+	}
+	if all := unnotified.All(); len(all) != 0 {
+		t.Errorf("GetUnnotifiedGames = %v after a date correction, want none", titles(all))
+	}
+}
@@ -0,0 +1,221 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+	"free-games-scrape/internal/security"
+)
+
+// mentionPattern matches user, role and channel mentions plus @everyone/@here
+var mentionPattern = regexp.MustCompile(`<@[!&]?\d+>|<#\d+>|@(everyone|here)`)
+
+// markdownLinkPattern matches Discord's [text](url) masked links
+var markdownLinkPattern = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+
+// urlPattern matches bare links
+var urlPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// stripLinks removes links from text for servers whose content policy
+// forbids them, keeping the text of masked links
+func stripLinks(text string) string {
+	text = markdownLinkPattern.ReplaceAllString(text, "$1")
+	text = urlPattern.ReplaceAllString(text, "")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// sanitizeClaimReminder cleans an admin-supplied reminder so it can be shown
+// in announcements without pinging anyone or breaking the embed layout
+func sanitizeClaimReminder(text string) string {
+	text = security.SanitizeInput(text)
+	text = mentionPattern.ReplaceAllString(text, "")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// claimReminder returns the reminder to attach to a game in a scheduled
+// announcement, without links under the no-links policy. Command lookups (cfg
+// nil), Coming Soon games and games from other stores get none.
+func claimReminder(cfg *database.ServerConfig, game models.Game) string {
+	if cfg == nil || cfg.ClaimReminder == "" {
+		return ""
+	}
+	if game.Status != models.StatusFreeNow || game.Source == models.SourceGOG {
+		return ""
+	}
+	if cfg.Policy().NoLinks {
+		return stripLinks(cfg.ClaimReminder)
+	}
+	return cfg.ClaimReminder
+}
+
+// handleCustomizeCommand handles /customize subcommands
+func (b *DiscordBot) handleCustomizeCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	serverConfig, err := b.database.GetServerConfig(i.GuildID)
+	if err != nil {
+		b.respondToInteraction(s, i, "Error checking server configuration.", true)
+		return
+	}
+	if serverConfig == nil {
+		b.respondToInteraction(s, i, "This server is not configured yet. Use /setup first.", true)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please choose what to customize.", true)
+		return
+	}
+
+	switch options[0].Name {
+	case "reminder":
+		b.handleCustomizeReminder(s, i, options[0].Options)
+	case "policy":
+		b.handleCustomizePolicy(s, i, serverConfig, options[0].Options)
+	case "banner":
+		b.handleCustomizeBanner(s, i, options[0].Options)
+	case "preview":
+		b.handleCustomizePreview(s, i, serverConfig)
+	}
+}
+
+// handleCustomizeReminder handles /customize reminder, setting the claim
+// reminder of Free Now announcements
+func (b *DiscordBot) handleCustomizeReminder(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please provide the reminder text, or `off` to remove it.", true)
+		return
+	}
+
+	reminder := sanitizeClaimReminder(options[0].StringValue())
+	if strings.EqualFold(reminder, "off") {
+		reminder = ""
+	} else if reminder == "" {
+		b.respondToInteraction(s, i, "Please provide the reminder text, or `off` to remove it.", true)
+		return
+	} else if utf8.RuneCountInString(reminder) > database.MaxClaimReminderLength {
+		b.respondToInteraction(s, i, fmt.Sprintf("The reminder can be at most %d characters.", database.MaxClaimReminderLength), true)
+		return
+	}
+
+	if err := b.database.SetClaimReminder(i.GuildID, reminder); err != nil {
+		log.Printf("Error saving claim reminder for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to save the reminder. Please try again.", true)
+		return
+	}
+
+	if reminder == "" {
+		b.respondToInteraction(s, i, "Free game announcements will no longer include a claim reminder.", false)
+	} else {
+		b.respondToInteraction(s, i, fmt.Sprintf("Free game announcements will now include:\n> %s", reminder), false)
+	}
+	log.Printf("Server %s set claim reminder (%d chars)", i.GuildID, utf8.RuneCountInString(reminder))
+}
+
+// policyDescriptions explains each content policy constraint to admins
+var policyDescriptions = map[string]string{
+	database.PolicyNoMentions: "no role pings",
+	database.PolicyNoLinks:    "no store links or links in the claim reminder",
+	database.PolicyTextOnly:   "text only, without embeds, images or link previews",
+}
+
+// describePolicy renders a stored content policy for people
+func describePolicy(policy string) string {
+	if policy == "" {
+		return "None"
+	}
+	var parts []string
+	for _, constraint := range strings.Split(policy, ",") {
+		parts = append(parts, fmt.Sprintf("`%s` (%s)", constraint, policyDescriptions[constraint]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// handleCustomizePolicy handles /customize policy, setting the constraints
+// every announcement in the server must follow
+func (b *DiscordBot) handleCustomizePolicy(s *discordgo.Session, i *discordgo.InteractionCreate, serverConfig *database.ServerConfig, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please list the constraints, or `off` to remove the policy.", true)
+		return
+	}
+
+	policy, err := database.ParseContentPolicy(options[0].StringValue())
+	if err != nil {
+		b.respondToInteraction(s, i, fmt.Sprintf("Invalid policy: %v.", err), true)
+		return
+	}
+	candidate := *serverConfig
+	candidate.ContentPolicy = policy
+	if err := candidate.PolicyConflict(); err != nil {
+		b.respondToInteraction(s, i, fmt.Sprintf("Can't set this policy: %v. Use `/setrole none` or `/format plain` first.", err), true)
+		return
+	}
+
+	if err := b.database.SetContentPolicy(i.GuildID, policy); err != nil {
+		log.Printf("Error saving content policy for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to save the policy. Please try again.", true)
+		return
+	}
+
+	if policy == "" {
+		b.respondToInteraction(s, i, "Announcements no longer follow a content policy.", false)
+	} else {
+		b.respondToInteraction(s, i, fmt.Sprintf("Announcements now follow this content policy: %s. Use `/customize preview` to see the result.", describePolicy(policy)), false)
+	}
+	log.Printf("Server %s set content policy to %q", i.GuildID, policy)
+}
+
+// handleCustomizePreview handles /customize preview, showing a current game
+// as this server's announcements render it, content policy included. The
+// preview is ephemeral and pings nobody.
+func (b *DiscordBot) handleCustomizePreview(s *discordgo.Session, i *discordgo.InteractionCreate, serverConfig *database.ServerConfig) {
+	games, err := b.gameService.GetActiveGames()
+	if err != nil {
+		log.Printf("Error getting games for preview: %v", err)
+		b.respondToInteraction(s, i, "Failed to load the current games. Please try again.", true)
+		return
+	}
+
+	var embed *discordgo.MessageEmbed
+	var game models.Game
+	switch {
+	case len(games.FreeNow) > 0:
+		game = games.FreeNow[0]
+		embed = b.freeNowEmbed(game, 0, len(games.FreeNow), serverConfig, false)
+	case len(games.ComingSoon) > 0:
+		game = games.ComingSoon[0]
+		embed = b.comingSoonEmbed(game, 0, len(games.ComingSoon), serverConfig)
+	default:
+		b.respondToInteraction(s, i, "There are no current games to preview.", true)
+		return
+	}
+
+	message := renderGameMessage(embed, game, serverConfig, serverConfig.RoleMention())
+	header := fmt.Sprintf("**Preview** of an announcement here (content policy: %s):", describePolicy(serverConfig.ContentPolicy))
+	content := header
+	if message.rich.Content != "" {
+		content += "\n" + message.rich.Content
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content:         content,
+			Embeds:          message.rich.Embeds,
+			AllowedMentions: &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}},
+			Flags:           discordgo.MessageFlagsEphemeral | message.rich.Flags,
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to customize preview: %v", err)
+	}
+}
@@ -0,0 +1,269 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// deliveryJob holds everything to be posted to one channel. A job is handled
+// by a single worker so messages within a channel keep their order.
+type deliveryJob struct {
+	cycleID   string
+	guildID   string
+	channelID string
+	config    *database.ServerConfig
+	games     *models.GameCollection
+	// release marks a post about Coming Soon games that just became free
+	release bool
+}
+
+// deliveryResult reports the outcome of a single delivery job
+type deliveryResult struct {
+	job       deliveryJob
+	decisions []models.DeliveryDecision
+	err       error
+}
+
+// deliver posts all jobs using a pool of workers. Channels are processed
+// concurrently while each channel's messages are sent sequentially; the rate
+// limiter inside the send path provides backpressure.
+func (b *DiscordBot) deliver(ctx context.Context, jobs []deliveryJob) []deliveryResult {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	results, busy := runDeliveryPool(jobs, b.config.DeliveryWorkers, func(job deliveryJob) deliveryResult {
+		return b.deliverToChannel(ctx, job)
+	})
+
+	elapsed := time.Since(start)
+	utilization := make([]float64, len(busy))
+	for w, d := range busy {
+		if elapsed > 0 {
+			utilization[w] = float64(d) / float64(elapsed)
+		}
+	}
+	b.metrics.SetDeliveryStats(elapsed, utilization)
+	b.rateLimiter.Evict()
+	b.metrics.SetRateLimiters(int64(b.rateLimiter.ActiveChannels()))
+	log.Printf("Delivered to %d channels with %d workers in %s", len(jobs), len(busy), elapsed.Round(time.Millisecond))
+	return results
+}
+
+// runDeliveryPool runs jobs on min(maxWorkers, distinct channels) workers and
+// returns their results with the time each worker spent busy. All jobs for a
+// channel go to one worker in their original order, so a channel never gets
+// two jobs at once and its messages keep their order.
+func runDeliveryPool(jobs []deliveryJob, maxWorkers int, send func(deliveryJob) deliveryResult) ([]deliveryResult, []time.Duration) {
+	var channels [][]deliveryJob
+	index := make(map[string]int)
+	for _, job := range jobs {
+		i, ok := index[job.channelID]
+		if !ok {
+			i = len(channels)
+			index[job.channelID] = i
+			channels = append(channels, nil)
+		}
+		channels[i] = append(channels[i], job)
+	}
+
+	workers := min(maxWorkers, len(channels))
+	if workers < 1 {
+		workers = 1
+	}
+
+	queue := make(chan []deliveryJob)
+	results := make(chan deliveryResult, len(jobs))
+	busy := make([]time.Duration, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for channelJobs := range queue {
+				for _, job := range channelJobs {
+					jobStart := time.Now()
+					results <- send(job)
+					busy[worker] += time.Since(jobStart)
+				}
+			}
+		}(w)
+	}
+
+	for _, channelJobs := range channels {
+		queue <- channelJobs
+	}
+	close(queue)
+	wg.Wait()
+	close(results)
+
+	collected := make([]deliveryResult, 0, len(jobs))
+	for result := range results {
+		collected = append(collected, result)
+	}
+	return collected, busy
+}
+
+// deliverToChannel runs the filter pipeline for the job's server, sends its
+// Free Now games followed by its Coming Soon games, and records a decision for
+// every game explaining whether it was delivered
+func (b *DiscordBot) deliverToChannel(ctx context.Context, job deliveryJob) deliveryResult {
+	result := deliveryResult{job: job}
+
+	filter := b.filterGames
+	if job.release {
+		filter = b.filterReleases
+	}
+	freeNow, skippedFreeNow := filter(job.config, job.games.FreeNow)
+	comingSoon, skippedComingSoon := filter(job.config, job.games.ComingSoon)
+
+	// Ping the server's announcement role once, on the first message sent
+	freeNowMention, comingSoonMention := job.config.RoleMention(), ""
+	if len(freeNow) == 0 {
+		freeNowMention, comingSoonMention = "", freeNowMention
+	}
+
+	// Servers opted into banners get a header showing every new game above
+	// their announcements, carrying the role ping
+	if !job.release && len(freeNow) >= 2 && b.bannersEnabled(job.config) {
+		if err := b.sendBannerHeader(ctx, job.channelID, job.config, freeNow, freeNowMention); err != nil {
+			log.Printf("Error sending the banner header to channel %s: %v", job.channelID, err)
+		} else {
+			freeNowMention = ""
+		}
+	}
+
+	sendFreeNow := b.sendFreeNowGames
+	if job.release {
+		sendFreeNow = b.sendReleasedGames
+	}
+	sentFreeNow, err := sendFreeNow(ctx, freeNow, job.channelID, job.config, freeNowMention)
+	sentComingSoon := 0
+	if err != nil {
+		result.err = fmt.Errorf("error sending Free Now games: %w", err)
+	} else if sentComingSoon, err = b.sendComingSoonGames(ctx, comingSoon, job.channelID, job.config, comingSoonMention); err != nil {
+		result.err = fmt.Errorf("error sending Coming Soon games: %w", err)
+	}
+
+	failureReason := models.SkipReasonNone
+	if result.err != nil {
+		failureReason = classifySendError(result.err)
+		b.deactivateUnreachableChannel(job, failureReason)
+	}
+
+	result.decisions = append(result.decisions, b.decide(job, job.games.FreeNow, skippedFreeNow, sentFreeNow, failureReason)...)
+	result.decisions = append(result.decisions, b.decide(job, job.games.ComingSoon, skippedComingSoon, sentComingSoon, failureReason)...)
+
+	// Remember what this server got so it is never announced there twice
+	if job.config != nil {
+		announced := append(append([]models.Game{}, freeNow[:sentFreeNow]...), comingSoon[:sentComingSoon]...)
+		if _, err := b.database.MarkNotificationsSent(job.guildID, database.NotificationAnnouncement, announced); err != nil {
+			log.Printf("Error recording announced games for guild %s: %v", job.guildID, err)
+		}
+		if job.release {
+			if _, err := b.database.MarkNotificationsSent(job.guildID, database.NotificationRelease, announced); err != nil {
+				log.Printf("Error recording released games for guild %s: %v", job.guildID, err)
+			}
+		}
+	}
+	return result
+}
+
+// deactivateUnreachableChannel stops announcing to a configured channel the
+// bot can no longer post in, instead of failing there every cycle. Running
+// /setup again reactivates it.
+func (b *DiscordBot) deactivateUnreachableChannel(job deliveryJob, reason models.SkipReason) {
+	if job.config == nil {
+		return
+	}
+	if reason != models.SkipReasonMissingPermissions && reason != models.SkipReasonChannelNotFound {
+		return
+	}
+
+	log.Printf("Channel %s in guild %s is unreachable (%s), deactivating its configuration", job.channelID, job.guildID, reason)
+	if err := b.database.DeactivateServerConfig(job.guildID, job.channelID); err != nil {
+		log.Printf("Error deactivating server config for guild %s: %v", job.guildID, err)
+	}
+}
+
+// decide builds delivery decisions for games: filtered games carry their filter
+// reason, the first sent games are delivered and the rest carry the send failure
+func (b *DiscordBot) decide(job deliveryJob, games []models.Game, skipped map[string]models.SkipReason, sent int, failureReason models.SkipReason) []models.DeliveryDecision {
+	decisions := make([]models.DeliveryDecision, 0, len(games))
+	now := time.Now()
+	accepted := 0
+
+	for _, game := range games {
+		decision := models.DeliveryDecision{
+			CycleID:   job.cycleID,
+			GuildID:   job.guildID,
+			ChannelID: job.channelID,
+			GameTitle: game.Title,
+			FreeTo:    game.FreeTo,
+			DecidedAt: now,
+		}
+		if job.config != nil {
+			decision.Policy = job.config.ContentPolicy
+		}
+
+		if reason, ok := skipped[database.NotificationKey(game)]; ok {
+			decision.Reason = reason
+		} else {
+			if accepted < sent {
+				decision.Delivered = true
+			} else {
+				decision.Reason = failureReason
+				if decision.Reason == models.SkipReasonNone {
+					decision.Reason = models.SkipReasonSendFailed
+				}
+			}
+			accepted++
+		}
+
+		decisions = append(decisions, decision)
+	}
+
+	return decisions
+}
+
+// backfillServer delivers the current games to a server that was just set
+// up. The delivery filters skip games already announced there, and the games
+// posted are recorded as announced, so the scheduled check does not repeat
+// them.
+func (b *DiscordBot) backfillServer(guildID string) {
+	config, err := b.database.GetServerConfig(guildID)
+	if err != nil {
+		log.Printf("Error loading server config for the backfill of guild %s: %v", guildID, err)
+		return
+	}
+	if config == nil {
+		return
+	}
+
+	games, err := b.gameService.GetActiveGames()
+	if err != nil {
+		log.Printf("Error getting games for the backfill of guild %s: %v", guildID, err)
+		return
+	}
+	games = b.withoutPending(games)
+	if len(games.FreeNow) == 0 && len(games.ComingSoon) == 0 {
+		return
+	}
+
+	b.runDeliveryCycle(b.ctx, []deliveryJob{{
+		cycleID:   time.Now().UTC().Format("20060102T150405.000000000Z"),
+		guildID:   config.GuildID,
+		channelID: config.ChannelID,
+		config:    config,
+		games:     games,
+	}})
+	log.Printf("Backfilled current games to guild %s", guildID)
+}
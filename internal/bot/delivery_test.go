@@ -0,0 +1,115 @@
+package bot
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"free-games-scrape/internal/models"
+)
+
+func TestRunDeliveryPoolKeepsChannelOrder(t *testing.T) {
+	const (
+		channels = 200
+		games    = 3
+		workers  = 8
+	)
+
+	// Each channel gets its games as separate jobs, interleaved with the other
+	// channels', so only the per-channel partitioning keeps them in order
+	var jobs []deliveryJob
+	for g := 0; g < games; g++ {
+		for c := 0; c < channels; c++ {
+			jobs = append(jobs, deliveryJob{
+				channelID: fmt.Sprintf("channel-%d", c),
+				games:     &models.GameCollection{FreeNow: []models.Game{{Title: fmt.Sprintf("game-%d", g)}}},
+			})
+		}
+	}
+
+	// The fake sender holds the first sends until every worker is busy, so
+	// the pool only finishes if it really sends to channels concurrently
+	var mu sync.Mutex
+	sent := make(map[string][]string)
+	inFlight := make(map[string]bool)
+	active, peak := 0, 0
+	allBusy := make(chan struct{})
+	var allBusyOnce sync.Once
+	send := func(job deliveryJob) deliveryResult {
+		mu.Lock()
+		if inFlight[job.channelID] {
+			t.Errorf("two jobs for %s ran at once", job.channelID)
+		}
+		inFlight[job.channelID] = true
+		active++
+		peak = max(peak, active)
+		if active == workers {
+			allBusyOnce.Do(func() { close(allBusy) })
+		}
+		mu.Unlock()
+
+		select {
+		case <-allBusy:
+		case <-time.After(10 * time.Second):
+			t.Errorf("never had %d sends in flight at once", workers)
+			allBusyOnce.Do(func() { close(allBusy) })
+		}
+
+		mu.Lock()
+		sent[job.channelID] = append(sent[job.channelID], job.games.FreeNow[0].Title)
+		inFlight[job.channelID] = false
+		active--
+		mu.Unlock()
+		return deliveryResult{job: job}
+	}
+
+	results, busy := runDeliveryPool(jobs, workers, send)
+
+	if len(results) != len(jobs) {
+		t.Errorf("got %d results, want %d", len(results), len(jobs))
+	}
+	if len(busy) != workers {
+		t.Errorf("ran %d workers, want %d", len(busy), workers)
+	}
+	if peak != workers {
+		t.Errorf("%d jobs ran at once, want %d", peak, workers)
+	}
+	for c := 0; c < channels; c++ {
+		channelID := fmt.Sprintf("channel-%d", c)
+		want := []string{"game-0", "game-1", "game-2"}
+		if got := sent[channelID]; fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("%s got %v, want %v", channelID, got, want)
+		}
+	}
+}
+
+func TestRunDeliveryPoolWorkerCount(t *testing.T) {
+	job := func(channelID string) deliveryJob {
+		return deliveryJob{channelID: channelID, games: &models.GameCollection{}}
+	}
+	send := func(job deliveryJob) deliveryResult { return deliveryResult{job: job} }
+
+	tests := []struct {
+		name       string
+		jobs       []deliveryJob
+		maxWorkers int
+		want       int
+	}{
+		{"fewer channels than the cap", []deliveryJob{job("a"), job("b"), job("c")}, 8, 3},
+		{"jobs sharing a channel", []deliveryJob{job("a"), job("a"), job("a"), job("b")}, 8, 2},
+		{"more channels than the cap", []deliveryJob{job("a"), job("b"), job("c"), job("d")}, 2, 2},
+		{"single channel", []deliveryJob{job("a")}, 8, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, busy := runDeliveryPool(tt.jobs, tt.maxWorkers, send)
+			if len(busy) != tt.want {
+				t.Errorf("ran %d workers, want %d", len(busy), tt.want)
+			}
+			if len(results) != len(tt.jobs) {
+				t.Errorf("got %d results, want %d", len(results), len(tt.jobs))
+			}
+		})
+	}
+}
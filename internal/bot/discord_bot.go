@@ -1,56 +1,92 @@
 package bot
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"free-games-scrape/internal/config"
 	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/logger"
+	"free-games-scrape/internal/metrics"
 	"free-games-scrape/internal/models"
+	"free-games-scrape/internal/ratelimit"
+	"free-games-scrape/internal/security"
 	"free-games-scrape/internal/service"
 )
 
 // DiscordBot handles Discord interactions
 type DiscordBot struct {
 	session     *discordgo.Session
+	editor      messageEditor
 	config      *config.DiscordConfig
 	channelID   string
 	gameService *service.GameService
 	database    *database.Database
+	logger      *logger.Logger
+	metrics     *metrics.Metrics
+	rateLimiter *ratelimit.DiscordRateLimiter
+	handlerSem  chan struct{}
+	// deliveryMu serializes delivery cycles, so a game is never sent to a
+	// server by two cycles at once
+	deliveryMu sync.Mutex
+
+	linkVerifier *linkVerifier
+	commands     commandState
+	schedule     scheduleState
+	impact       impactCache
+	pages        pageState
+	refreshes    refreshCooldowns
+	banners      bannerCache
+	ctx          context.Context
+	cancel       context.CancelFunc
 }
 
 // NewDiscordBot creates a new Discord bot instance
-func NewDiscordBot(cfg *config.DiscordConfig, gameService *service.GameService, db *database.Database) (*DiscordBot, error) {
+func NewDiscordBot(cfg *config.DiscordConfig, gameService *service.GameService, db *database.Database, appLogger *logger.Logger, appMetrics *metrics.Metrics, rateLimiter *ratelimit.DiscordRateLimiter) (*DiscordBot, error) {
 	session, err := discordgo.New("Bot " + cfg.Token)
 	if err != nil {
 		return nil, fmt.Errorf("error creating Discord session: %w", err)
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+
 	bot := &DiscordBot{
 		session:     session,
+		editor:      session,
 		config:      cfg,
 		channelID:   cfg.ChannelID,
 		gameService: gameService,
 		database:    db,
+		logger:      appLogger.WithComponent("discord"),
+		metrics:     appMetrics,
+		rateLimiter: rateLimiter,
+		handlerSem:  make(chan struct{}, cfg.MaxConcurrentHandlers),
+
+		linkVerifier: newLinkVerifier(),
+		ctx:          ctx,
+		cancel:       cancel,
 	}
 
-	// Set up event handlers
-	bot.setupEventHandlers()
-
 	return bot, nil
 }
 
-// Start opens the Discord connection
+// Start sets up the event handlers, opens the Discord connection and
+// registers the slash commands
 func (b *DiscordBot) Start() error {
-	err := b.session.Open()
-	if err != nil {
-		return fmt.Errorf("error opening Discord connection: %w", err)
+	b.setupEventHandlers()
+
+	if err := b.Connect(); err != nil {
+		return err
 	}
 	
 	// Register slash commands
-	err = b.registerSlashCommands()
+	err := b.registerSlashCommands()
 	if err != nil {
 		log.Printf("Error registering slash commands: %v", err)
 		// Don't fail startup, just log the error
@@ -60,28 +96,52 @@ func (b *DiscordBot) Start() error {
 	return nil
 }
 
+// Connect opens the Discord connection without handling any events or
+// registering commands, for one-off commands such as the smoke test
+func (b *DiscordBot) Connect() error {
+	if err := b.session.Open(); err != nil {
+		return fmt.Errorf("error opening Discord connection: %w", err)
+	}
+	return nil
+}
+
 // Stop closes the Discord connection
 func (b *DiscordBot) Stop() error {
 	log.Println("Shutting down Discord bot")
+	b.cancel()
 	return b.session.Close()
 }
 
 // setupEventHandlers configures Discord event handlers
 func (b *DiscordBot) setupEventHandlers() {
-	b.session.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
+	b.session.AddHandler(safeHandler(b, "ready", func(s *discordgo.Session, r *discordgo.Ready) {
 		log.Printf("Bot is ready! Logged in as: %v#%v", r.User.Username, r.User.Discriminator)
-	})
+	}))
 
-	b.session.AddHandler(func(s *discordgo.Session, g *discordgo.GuildCreate) {
-		log.Printf("Joined guild: %s (ID: %s)", g.Name, g.ID)
-		b.sendWelcomeMessage(s, g)
-	})
+	b.session.AddHandler(safeHandler(b, "guild_create", b.handleGuildCreate))
+
+	b.session.AddHandler(safeHandler(b, "guild_delete", func(s *discordgo.Session, g *discordgo.GuildDelete) {
+		// Unavailable means a Discord outage, not that the bot was removed
+		if g.Unavailable {
+			log.Printf("Guild %s became unavailable", g.ID)
+			return
+		}
+
+		log.Printf("Removed from guild: %s", g.ID)
+		b.metrics.IncrementServersLeft()
+		if err := b.database.DeactivateServerConfigByGuild(g.ID); err != nil {
+			log.Printf("Error deactivating server config for guild %s: %v", g.ID, err)
+		}
+		if err := b.database.ForgetGuild(g.ID); err != nil {
+			log.Printf("Error forgetting guild %s: %v", g.ID, err)
+		}
+	}))
 
 	// Add message handler for commands
-	b.session.AddHandler(b.messageHandler)
+	b.session.AddHandler(safeHandler(b, "message_create", b.messageHandler))
 	
 	// Add slash command handler
-	b.session.AddHandler(b.interactionHandler)
+	b.session.AddHandler(safeHandler(b, "interaction_create", b.interactionHandler))
 }
 
 // messageHandler handles incoming Discord messages
@@ -91,18 +151,22 @@ func (b *DiscordBot) messageHandler(s *discordgo.Session, m *discordgo.MessageCr
 		return
 	}
 
-	// Only respond in the configured channel
-	if m.ChannelID != b.channelID {
+	// Check for commands
+	content := strings.TrimSpace(m.Content)
+	if !strings.HasPrefix(content, "!") || !b.guildAllowed(m.GuildID) {
 		return
 	}
 
-	// Check for commands
-	content := strings.TrimSpace(m.Content)
-	if !strings.HasPrefix(content, "!") {
+	// Only respond in the legacy channel or this server's notification channel
+	if !b.acceptsPrefixCommands(m.GuildID, m.ChannelID) {
 		return
 	}
 
 	command := strings.ToLower(strings.Fields(content)[0])
+
+	reqLogger := b.requestLogger(command, m.GuildID, m.Author.ID)
+	defer reqLogger.done()
+	b.metrics.IncrementMessagesProcessed()
 	
 	switch command {
 	case "!games", "!freegames":
@@ -111,59 +175,110 @@ func (b *DiscordBot) messageHandler(s *discordgo.Session, m *discordgo.MessageCr
 		b.handleRefreshCommand(s, m)
 	case "!help":
 		b.handleHelpCommand(s, m)
+	default:
+		return
 	}
+	b.metrics.IncrementCommandsExecuted()
+}
+
+// acceptsPrefixCommands reports whether ! commands are answered in a channel
+func (b *DiscordBot) acceptsPrefixCommands(guildID, channelID string) bool {
+	if b.channelID != "" && channelID == b.channelID {
+		return true
+	}
+	if guildID == "" {
+		return false
+	}
+
+	serverConfig, err := b.database.GetServerConfig(guildID)
+	if err != nil {
+		log.Printf("Error loading server config for guild %s: %v", guildID, err)
+		return false
+	}
+	return serverConfig != nil && serverConfig.ChannelID == channelID
 }
 
 // handleGamesCommand shows current free games from database
 func (b *DiscordBot) handleGamesCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
 	games, err := b.gameService.GetActiveGames()
 	if err != nil {
-		b.SendErrorMessage(fmt.Sprintf("Failed to get games: %v", err))
+		b.SendErrorMessageTo(m.ChannelID, fmt.Sprintf("Failed to get games: %v", err))
 		return
 	}
 
 	if len(games.FreeNow) == 0 && len(games.ComingSoon) == 0 {
-		b.SendSimpleMessage("No free games currently available in the database.")
+		b.SendMessageTo(m.ChannelID, "No free games currently available in the database.")
 		return
 	}
 
-	if err := b.SendGameUpdates(games); err != nil {
-		b.SendErrorMessage(fmt.Sprintf("Failed to send game updates: %v", err))
+	if warning := b.staleDataWarning(); warning != "" {
+		b.SendMessageTo(m.ChannelID, warning)
+	}
+
+	if err := b.sendGamesToChannel(m.ChannelID, games); err != nil {
+		b.SendErrorMessageTo(m.ChannelID, fmt.Sprintf("Failed to send game updates: %v", err))
+	}
+}
+
+// staleDataWarning returns the notice to show before stored games that are
+// older than the refresh interval allows, or "" when they are current
+func (b *DiscordBot) staleDataWarning() string {
+	freshness, err := b.gameService.Freshness(time.Now())
+	if err != nil {
+		log.Printf("Error checking data freshness: %v", err)
+		return ""
 	}
+	return freshness.Warning()
 }
 
 // handleRefreshCommand manually triggers a refresh
 func (b *DiscordBot) handleRefreshCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
-	b.SendSimpleMessage("Refreshing games from Epic Games Store...")
+	if !b.allowPrefixRefresh(s, m) {
+		return
+	}
+
+	b.SendMessageTo(m.ChannelID, "Refreshing games from Epic Games Store...")
 	
-	if err := b.gameService.RefreshGames(); err != nil {
-		b.SendErrorMessage(fmt.Sprintf("Failed to refresh games: %v", err))
+	if err := b.gameService.RefreshGames(b.ctx); err != nil {
+		b.SendErrorMessageTo(m.ChannelID, refreshFailedMessage(err))
 		return
 	}
 
 	games, err := b.gameService.GetActiveGames()
 	if err != nil {
-		b.SendErrorMessage(fmt.Sprintf("Failed to get updated games: %v", err))
+		b.SendErrorMessageTo(m.ChannelID, fmt.Sprintf("Failed to get updated games: %v", err))
 		return
 	}
 
-	b.SendSimpleMessage("Games refreshed successfully!")
+	b.SendMessageTo(m.ChannelID, "Games refreshed successfully!")
 	
 	if len(games.FreeNow) > 0 || len(games.ComingSoon) > 0 {
-		if err := b.SendGameUpdates(games); err != nil {
-			b.SendErrorMessage(fmt.Sprintf("Failed to send game updates: %v", err))
+		if err := b.sendGamesToChannel(m.ChannelID, games); err != nil {
+			b.SendErrorMessageTo(m.ChannelID, fmt.Sprintf("Failed to send game updates: %v", err))
 		}
 	} else {
-		b.SendSimpleMessage("No free games found after refresh.")
+		b.SendMessageTo(m.ChannelID, "No free games found after refresh.")
+	}
+}
+
+// sendGamesToChannel posts a game collection to a single channel in reply to
+// a command, without per-server filters or role pings
+func (b *DiscordBot) sendGamesToChannel(channelID string, games *models.GameCollection) error {
+	if _, err := b.sendFreeNowGames(b.ctx, games.FreeNow, channelID, nil, ""); err != nil {
+		return err
 	}
+	if _, err := b.sendComingSoonGames(b.ctx, games.ComingSoon, channelID, nil, ""); err != nil {
+		return err
+	}
+	return nil
 }
 
 // handleHelpCommand shows available commands
 func (b *DiscordBot) handleHelpCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
 	embed := &discordgo.MessageEmbed{
-		Title:       "Free Games Bot Commands",
-		Description: "Available commands for the Epic Games Free Games Bot:",
-		Color:       0x0099ff,
+		Title:       fmt.Sprintf("%s Commands", b.branding().Name),
+		Description: fmt.Sprintf("Available commands for %s:", b.branding().Name),
+		Color:       b.branding().AccentColor,
 		Fields: []*discordgo.MessageEmbedField{
 			{
 				Name:   "!games or !freegames",
@@ -172,7 +287,7 @@ func (b *DiscordBot) handleHelpCommand(s *discordgo.Session, m *discordgo.Messag
 			},
 			{
 				Name:   "!refresh or !update",
-				Value:  "Manually refresh games from Epic Games Store",
+				Value:  "Manually refresh games from Epic Games Store (Manage Server)",
 				Inline: false,
 			},
 			{
@@ -181,245 +296,435 @@ func (b *DiscordBot) handleHelpCommand(s *discordgo.Session, m *discordgo.Messag
 				Inline: false,
 			},
 		},
-		Footer: &discordgo.MessageEmbedFooter{
-			Text: "Epic Games Store - Free Games Bot",
-		},
+		Footer: brandFooter(b.branding()),
 	}
 
-	_, err := s.ChannelMessageSendEmbed(b.channelID, embed)
+	_, err := s.ChannelMessageSendEmbed(m.ChannelID, embed)
 	if err != nil {
 		log.Printf("Error sending help message: %v", err)
 	}
 }
 
-// SendGameUpdates sends game updates to all configured Discord channels
-func (b *DiscordBot) SendGameUpdates(gameCollection *models.GameCollection) error {
+// Connected reports whether the Discord gateway session is up. It is false
+// before Start and while discordgo reconnects.
+func (b *DiscordBot) Connected() bool {
+	b.session.RLock()
+	defer b.session.RUnlock()
+	return b.session.DataReady
+}
+
+// SendGameUpdates sends game updates to all configured Discord channels.
+// Every server is offered all current games and gets the ones not yet
+// announced there, so servers configured later catch up on the current
+// giveaways. The legacy channel and DM subscribers only get newGames, the
+// games never announced anywhere. When ANNOUNCE_DELAY is set, new Free Now
+// games are held back until their store page is reachable and announced
+// separately, marked notified once that announcement went out. It returns
+// the new games announced now, which the caller marks notified.
+func (b *DiscordBot) SendGameUpdates(current, newGames *models.GameCollection) (*models.GameCollection, error) {
+	newGames, held := b.holdForLinkVerification(newGames)
+	b.announceAfterVerification(held)
+	current = b.withoutPending(current)
+
+	if len(current.FreeNow) == 0 && len(current.ComingSoon) == 0 &&
+		len(newGames.FreeNow) == 0 && len(newGames.ComingSoon) == 0 {
+		return newGames, nil
+	}
+	if err := b.deliverGameUpdates(current, newGames); err != nil {
+		return nil, err
+	}
+	return newGames, nil
+}
+
+// deliverGameUpdates posts the current games to every configured channel,
+// where the delivery filters skip those already announced, records the
+// delivery decisions and sends newGames to the legacy channel and subscribers
+func (b *DiscordBot) deliverGameUpdates(current, newGames *models.GameCollection) error {
 	// Get all active server configurations
 	serverConfigs, err := b.database.GetAllActiveServerConfigs()
 	if err != nil {
 		return fmt.Errorf("error getting server configs: %w", err)
 	}
 
+	// Stopping the bot cancels the cycle, including its rate limiter waits
+	ctx := b.ctx
+
 	// If no server configs and we have a legacy channel, use that
 	if len(serverConfigs) == 0 && b.channelID != "" {
-		if err := b.sendFreeNowGames(gameCollection.FreeNow, b.channelID); err != nil {
-			return fmt.Errorf("error sending Free Now games to legacy channel: %w", err)
+		if len(newGames.FreeNow) == 0 && len(newGames.ComingSoon) == 0 {
+			return nil
 		}
-		if err := b.sendComingSoonGames(gameCollection.ComingSoon, b.channelID); err != nil {
-			return fmt.Errorf("error sending Coming Soon games to legacy channel: %w", err)
+		job := deliveryJob{channelID: b.channelID, games: newGames}
+		if result := b.deliverToChannel(ctx, job); result.err != nil {
+			return fmt.Errorf("error sending games to legacy channel: %w", result.err)
 		}
+		b.deliverToSubscribers(ctx, newGames.FreeNow)
 		return nil
 	}
 
-	// Send to all configured channels
+	// Send to all configured channels, one worker per channel at a time
+	cycleID := time.Now().UTC().Format("20060102T150405.000000000Z")
+	jobs := make([]deliveryJob, 0, len(serverConfigs))
 	for _, config := range serverConfigs {
-		if err := b.sendFreeNowGames(gameCollection.FreeNow, config.ChannelID); err != nil {
-			log.Printf("Error sending Free Now games to channel %s: %v", config.ChannelID, err)
-			continue
-		}
-		if err := b.sendComingSoonGames(gameCollection.ComingSoon, config.ChannelID); err != nil {
-			log.Printf("Error sending Coming Soon games to channel %s: %v", config.ChannelID, err)
-			continue
-		}
+		jobs = append(jobs, deliveryJob{
+			cycleID:   cycleID,
+			guildID:   config.GuildID,
+			channelID: config.ChannelID,
+			config:    config,
+			games:     current,
+		})
 	}
 
+	b.runDeliveryCycle(ctx, jobs)
+
+	b.deliverToSubscribers(ctx, newGames.FreeNow)
 	return nil
 }
 
+// runDeliveryCycle warms up the images about to be posted, delivers jobs and
+// records the decisions made
+func (b *DiscordBot) runDeliveryCycle(ctx context.Context, jobs []deliveryJob) {
+	b.deliveryMu.Lock()
+	defer b.deliveryMu.Unlock()
+
+	b.warmUpImages(ctx, jobs)
+
+	var decisions []models.DeliveryDecision
+	for _, result := range b.deliver(ctx, jobs) {
+		if result.err != nil {
+			log.Printf("Error delivering games to channel %s: %v", result.job.channelID, result.err)
+		}
+		decisions = append(decisions, result.decisions...)
+	}
+
+	if len(decisions) > 0 {
+		if err := b.database.SaveDeliveryDecisions(decisions); err != nil {
+			log.Printf("Error recording delivery decisions: %v", err)
+		}
+	}
+}
+
 // sendFreeNowGames sends "Free Now" games to Discord with images displayed
-func (b *DiscordBot) sendFreeNowGames(games []models.Game, channelID string) error {
+// It returns how many games were sent before any error occurred.
+// mention, if set, is prefixed to the first message only.
+func (b *DiscordBot) sendFreeNowGames(ctx context.Context, games []models.Game, channelID string, cfg *database.ServerConfig, mention string) (int, error) {
+	return b.postFreeNowGames(ctx, games, channelID, cfg, mention, false)
+}
+
+// sendReleasedGames sends Coming Soon games that just became free, headed
+// "Now Available" rather than as new games
+func (b *DiscordBot) sendReleasedGames(ctx context.Context, games []models.Game, channelID string, cfg *database.ServerConfig, mention string) (int, error) {
+	return b.postFreeNowGames(ctx, games, channelID, cfg, mention, true)
+}
+
+// postFreeNowGames sends Free Now games one embed each, worded as releases of
+// earlier Coming Soon games when released is set
+func (b *DiscordBot) postFreeNowGames(ctx context.Context, games []models.Game, channelID string, cfg *database.ServerConfig, mention string, released bool) (int, error) {
 	if len(games) == 0 {
-		return nil
+		return 0, nil
 	}
 
 	// Send each game as a separate embed to display images properly
 	for i, game := range games {
-		embed := &discordgo.MessageEmbed{
-			Title:       fmt.Sprintf("Free Game Available Now! (%d/%d)", i+1, len(games)),
-			Description: fmt.Sprintf("**%s** is currently free on Epic Games Store!", game.Title),
-			Color:       0x00ff00, // Green color
-			Footer: &discordgo.MessageEmbedFooter{
-				Text: "Epic Games Store - Free Games Bot",
-			},
-		}
+		embed := b.freeNowEmbed(game, i, len(games), cfg, released)
 
-		// Add game image as the main embed image (this displays the actual image)
-		if game.ImageURL != "" {
-			embed.Image = &discordgo.MessageEmbedImage{
-				URL: game.ImageURL,
+		if i > 0 {
+			if err := pause(ctx, cfg.PostDelay()); err != nil {
+				return i, err
 			}
 		}
 
-		// Add game details as fields
-		if game.Status != "" {
-			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-				Name:   "Status",
-				Value:  game.Status,
-				Inline: true,
-			})
+		if err := b.rateLimiter.WaitForChannel(ctx, channelID); err != nil {
+			return i, fmt.Errorf("rate limiter wait failed: %w", err)
 		}
 
-		if game.FreeTo != "" {
-			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-				Name:   "Free Until",
-				Value:  game.FreeTo,
-				Inline: true,
-			})
+		if i > 0 {
+			mention = ""
 		}
-
-		_, err := b.session.ChannelMessageSendEmbed(channelID, embed)
+		sent, err := b.sendGameMessage(channelID, embed, game, cfg, mention)
 		if err != nil {
-			return fmt.Errorf("error sending Free Now message for %s: %w", game.Title, err)
+			return i, fmt.Errorf("error sending Free Now message for %s: %w", game.Title, err)
 		}
+		kind := database.NotificationAnnouncement
+		if released {
+			kind = database.NotificationRelease
+		}
+		b.recordPostedMessage(cfg, kind, game, sent, i, len(games), mention)
 	}
 
 	log.Printf("Sent %d Free Now games to Discord with images", len(games))
-	return nil
+	return len(games), nil
 }
 
 // sendComingSoonGames sends "Coming Soon" games to Discord with images displayed
-func (b *DiscordBot) sendComingSoonGames(games []models.Game, channelID string) error {
+// It returns how many games were sent before any error occurred.
+// mention, if set, is prefixed to the first message only.
+func (b *DiscordBot) sendComingSoonGames(ctx context.Context, games []models.Game, channelID string, cfg *database.ServerConfig, mention string) (int, error) {
 	if len(games) == 0 {
-		return nil
+		return 0, nil
 	}
 
 	// Send each game as a separate embed to display images properly
 	for i, game := range games {
-		embed := &discordgo.MessageEmbed{
-			Title:       fmt.Sprintf("Free Game Coming Soon! (%d/%d)", i+1, len(games)),
-			Description: fmt.Sprintf("**%s** will be free soon on Epic Games Store!", game.Title),
-			Color:       0x0099ff, // Blue color
-			Footer: &discordgo.MessageEmbedFooter{
-				Text: "Epic Games Store - Free Games Bot",
-			},
-		}
+		embed := b.comingSoonEmbed(game, i, len(games), cfg)
 
-		// Add game image as the main embed image (this displays the actual image)
-		if game.ImageURL != "" {
-			embed.Image = &discordgo.MessageEmbedImage{
-				URL: game.ImageURL,
+		if i > 0 {
+			if err := pause(ctx, cfg.PostDelay()); err != nil {
+				return i, err
 			}
 		}
 
-		// Add game details as fields
-		if game.Status != "" {
-			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-				Name:   "Status",
-				Value:  game.Status,
-				Inline: true,
-			})
+		if err := b.rateLimiter.WaitForChannel(ctx, channelID); err != nil {
+			return i, fmt.Errorf("rate limiter wait failed: %w", err)
 		}
 
-		if game.FreeFrom != "" && game.FreeTo != "" {
-			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-				Name:   "Free Period",
-				Value:  fmt.Sprintf("%s - %s", game.FreeFrom, game.FreeTo),
-				Inline: true,
-			})
-		} else if game.FreeFrom != "" {
-			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-				Name:   "Available From",
-				Value:  game.FreeFrom,
-				Inline: true,
-			})
-		} else if game.FreeTo != "" {
-			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-				Name:   "Available Until",
-				Value:  game.FreeTo,
-				Inline: true,
-			})
+		if i > 0 {
+			mention = ""
 		}
-
-		_, err := b.session.ChannelMessageSendEmbed(channelID, embed)
+		sent, err := b.sendGameMessage(channelID, embed, game, cfg, mention)
 		if err != nil {
-			return fmt.Errorf("error sending Coming Soon message for %s: %w", game.Title, err)
+			return i, fmt.Errorf("error sending Coming Soon message for %s: %w", game.Title, err)
 		}
+		b.recordPostedMessage(cfg, database.NotificationAnnouncement, game, sent, i, len(games), mention)
 	}
 
 	log.Printf("Sent %d Coming Soon games to Discord with images", len(games))
-	return nil
+	return len(games), nil
+}
+
+// freeNowEmbed builds the announcement embed of the index-th of total Free
+// Now games, worded as the release of an earlier Coming Soon game when
+// released is set
+func (b *DiscordBot) freeNowEmbed(game models.Game, index, total int, cfg *database.ServerConfig, released bool) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Free Game Available Now! (%d/%d)", index+1, total),
+		Description: fmt.Sprintf("**%s** is currently free on %s!", game.Title, game.SourceName()),
+		Color:       sourceColor(game, 0x00ff00), // Green color
+		Footer:      gameFooter(b.branding(), game),
+	}
+	if released {
+		embed.Title = fmt.Sprintf("Now Available! (%d/%d)", index+1, total)
+		embed.Description = fmt.Sprintf("**%s** is no longer coming soon - it is free on %s now!", game.Title, game.SourceName())
+	}
+
+	// Make the title link to the store page so the game can be claimed directly
+	embed.URL = game.StoreURL
+
+	// Add game image as the main embed image (this displays the actual image)
+	if game.ImageURL != "" {
+		embed.Image = &discordgo.MessageEmbedImage{
+			URL: game.ImageURL,
+		}
+	}
+
+	// Add game details as fields
+	if game.Status != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Status",
+			Value:  game.Status,
+			Inline: true,
+		})
+	}
+
+	if freeTo := promoTime(game.FreeToTime, game.FreeTo, cfg.Location()); freeTo != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Free Until",
+			Value:  freeTo,
+			Inline: true,
+		})
+	}
+
+	if reminder := claimReminder(cfg, game); reminder != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Before You Claim",
+			Value:  reminder,
+			Inline: false,
+		})
+	}
+
+	return embed
+}
+
+// comingSoonEmbed builds the announcement embed of the index-th of total
+// Coming Soon games
+func (b *DiscordBot) comingSoonEmbed(game models.Game, index, total int, cfg *database.ServerConfig) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Free Game Coming Soon! (%d/%d)", index+1, total),
+		Description: fmt.Sprintf("**%s** will be free soon on %s!", game.Title, game.SourceName()),
+		Color:       sourceColor(game, b.branding().AccentColor),
+		Footer:      gameFooter(b.branding(), game),
+	}
+
+	// Make the title link to the store page so the game can be claimed directly
+	embed.URL = game.StoreURL
+
+	// Add game image as the main embed image (this displays the actual image)
+	if game.ImageURL != "" {
+		embed.Image = &discordgo.MessageEmbedImage{
+			URL: game.ImageURL,
+		}
+	}
+
+	// Add game details as fields
+	if game.Status != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Status",
+			Value:  game.Status,
+			Inline: true,
+		})
+	}
+
+	freeFrom := promoTime(game.FreeFromTime, game.FreeFrom, cfg.Location())
+	freeTo := promoTime(game.FreeToTime, game.FreeTo, cfg.Location())
+	if freeFrom != "" && freeTo != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Free Period",
+			Value:  fmt.Sprintf("%s - %s", freeFrom, freeTo),
+			Inline: true,
+		})
+	} else if freeFrom != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Available From",
+			Value:  freeFrom,
+			Inline: true,
+		})
+	} else if freeTo != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Available Until",
+			Value:  freeTo,
+			Inline: true,
+		})
+	}
+
+	return embed
 }
 
 // SendSimpleMessage sends a simple text message to the configured channel
 func (b *DiscordBot) SendSimpleMessage(message string) error {
-	_, err := b.session.ChannelMessageSend(b.channelID, message)
+	return b.SendMessageTo(b.channelID, message)
+}
+
+// SendMessageTo sends a simple text message to a specific channel
+func (b *DiscordBot) SendMessageTo(channelID, message string) error {
+	if err := b.rateLimiter.WaitForChannel(b.ctx, channelID); err != nil {
+		return fmt.Errorf("rate limiter wait failed: %w", err)
+	}
+
+	_, err := b.session.ChannelMessageSend(channelID, message)
 	if err != nil {
 		return fmt.Errorf("error sending message: %w", err)
 	}
 	return nil
 }
 
-// SendErrorMessage sends an error message to the configured channel
+// SendErrorMessage sends an error message to the configured channel. It does
+// nothing when no legacy DISCORD_CHANNEL_ID is set.
 func (b *DiscordBot) SendErrorMessage(errorMsg string) error {
+	if b.channelID == "" {
+		return nil
+	}
+	return b.SendErrorMessageTo(b.channelID, errorMsg)
+}
+
+// SendErrorMessageTo sends an error message to a specific channel
+func (b *DiscordBot) SendErrorMessageTo(channelID, errorMsg string) error {
 	embed := &discordgo.MessageEmbed{
 		Title:       "Bot Error",
 		Description: errorMsg,
 		Color:       0xff0000, // Red color
-		Footer: &discordgo.MessageEmbedFooter{
-			Text: "Epic Games Store - Free Games Bot",
-		},
+		Footer:      brandFooter(b.branding()),
+	}
+
+	if err := b.rateLimiter.WaitForChannel(b.ctx, channelID); err != nil {
+		return fmt.Errorf("rate limiter wait failed: %w", err)
 	}
 
-	_, err := b.session.ChannelMessageSendEmbed(b.channelID, embed)
+	_, err := b.session.ChannelMessageSendEmbed(channelID, embed)
 	if err != nil {
 		return fmt.Errorf("error sending error message: %w", err)
 	}
 	return nil
 }
 
-// registerSlashCommands registers all slash commands with Discord
-func (b *DiscordBot) registerSlashCommands() error {
-	commands := []*discordgo.ApplicationCommand{
-		{
-			Name:        "setup",
-			Description: "Configure which channel to send free game notifications to",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionChannel,
-					Name:        "channel",
-					Description: "The channel to send notifications to",
-					Required:    true,
-					ChannelTypes: []discordgo.ChannelType{
-						discordgo.ChannelTypeGuildText,
-					},
-				},
-			},
-		},
-		{
-			Name:        "games",
-			Description: "Show current free games",
-		},
-		{
-			Name:        "refresh",
-			Description: "Manually check for new games",
-		},
-		{
-			Name:        "status",
-			Description: "Show bot status and configuration",
-		},
-		{
-			Name:        "help",
-			Description: "Show all available commands",
-		},
+// NotifyOwner sends a direct message to DISCORD_OWNER_ID, if configured
+func (b *DiscordBot) NotifyOwner(message string) error {
+	if b.config.OwnerID == "" {
+		return nil
 	}
 
-	for _, command := range commands {
-		_, err := b.session.ApplicationCommandCreate(b.session.State.User.ID, "", command)
-		if err != nil {
-			return fmt.Errorf("error creating command %s: %w", command.Name, err)
+	channel, err := b.session.UserChannelCreate(b.config.OwnerID)
+	if err != nil {
+		return fmt.Errorf("error opening DM with owner: %w", err)
+	}
+	return b.SendMessageTo(channel.ID, message)
+}
+
+// NotifyOperators sends an operational alert to the owner's DMs and the ops
+// channel, whichever are configured
+func (b *DiscordBot) NotifyOperators(message string) error {
+	var errs []error
+	if err := b.NotifyOwner(message); err != nil {
+		errs = append(errs, err)
+	}
+	if b.config.OpsChannelID != "" {
+		if err := b.SendMessageTo(b.config.OpsChannelID, message); err != nil {
+			errs = append(errs, fmt.Errorf("error posting to ops channel: %w", err))
 		}
 	}
+	return errors.Join(errs...)
+}
 
-	log.Printf("Successfully registered %d slash commands", len(commands))
-	return nil
+// describeLastRestart summarizes when this run started and whether the run
+// before it shut down cleanly
+func (b *DiscordBot) describeLastRestart() string {
+	restarts, err := b.database.GetRestarts(2)
+	if err != nil {
+		log.Printf("Error loading restart history: %v", err)
+		return ""
+	}
+	if len(restarts) == 0 {
+		return ""
+	}
+
+	description := fmt.Sprintf("<t:%d:R>", restarts[0].StartedAt.Unix())
+	if len(restarts) > 1 {
+		if restarts[1].CleanShutdown {
+			description += ", cleanly"
+		} else {
+			description += ", uncleanly"
+		}
+	}
+	return description
 }
 
-// interactionHandler handles slash command interactions
+// interactionHandler handles slash command and message component interactions
 func (b *DiscordBot) interactionHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if i.ApplicationCommandData().Name == "" {
+	// Servers outside GUILD_ALLOWLIST are being left; refuse their commands
+	if !b.guildAllowed(i.GuildID) {
+		if i.Type != discordgo.InteractionApplicationCommandAutocomplete {
+			b.respondToInteraction(s, i, unlistedGuildMessage(b.branding().Name), true)
+		}
+		return
+	}
+
+	if i.Type == discordgo.InteractionMessageComponent {
+		b.componentHandler(s, i)
+		return
+	}
+
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		b.autocompleteHandler(s, i)
+		return
+	}
+
+	if i.Type != discordgo.InteractionApplicationCommand || i.ApplicationCommandData().Name == "" {
 		return
 	}
 
+	reqLogger := b.requestLogger(i.ApplicationCommandData().Name, i.GuildID, interactionUserID(i))
+	defer reqLogger.done()
+	b.metrics.IncrementCommandsExecuted()
+
 	switch i.ApplicationCommandData().Name {
 	case "setup":
 		b.handleSetupCommand(s, i)
@@ -429,48 +734,146 @@ func (b *DiscordBot) interactionHandler(s *discordgo.Session, i *discordgo.Inter
 		b.handleRefreshSlashCommand(s, i)
 	case "status":
 		b.handleStatusCommand(s, i)
+	case "compare":
+		b.handleCompareCommand(s, i)
+	case "setdelay":
+		b.handleSetDelayCommand(s, i)
+	case "textfallback":
+		b.handleTextFallbackCommand(s, i)
+	case "setreminders":
+		b.handleSetRemindersCommand(s, i)
+	case "comingsoon":
+		b.handleComingSoonCommand(s, i)
+	case "format":
+		b.handleFormatCommand(s, i)
+	case "settimezone":
+		b.handleSetTimezoneCommand(s, i)
+	case "sources":
+		b.handleSourcesCommand(s, i)
+	case "settings":
+		b.handleSettingsCommand(s, i)
+	case "markseen":
+		b.handleMarkSeenCommand(s, i)
+	case "setrole":
+		b.handleSetRoleCommand(s, i)
+	case "unsubscribe":
+		b.handleUnsubscribe(s, i)
+	case "subscribe":
+		b.handleSubscribeCommand(s, i)
+	case "region":
+		b.handleRegionCommand(s, i)
+	case "customize":
+		b.handleCustomizeCommand(s, i)
+	case "mute":
+		b.handleMuteCommand(s, i)
+	case "unmute":
+		b.handleUnmuteCommand(s, i)
+	case "snapshot":
+		b.handleSnapshotCommand(s, i)
+	case "restore":
+		b.handleRestoreCommand(s, i)
+	case "admin":
+		b.handleAdminCommand(s, i)
+	case "nextcheck":
+		b.handleNextCheckCommand(s, i)
+	case "privacy":
+		b.handlePrivacyCommand(s, i)
+	case "impact":
+		b.handleImpactCommand(s, i)
+	case "gameinfo":
+		b.handleGameInfoCommand(s, i)
 	case "help":
 		b.handleHelpSlashCommand(s, i)
 	}
 }
 
+// componentHandler handles button clicks on bot messages
+func (b *DiscordBot) componentHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	customID := i.MessageComponentData().CustomID
+
+	reqLogger := b.requestLogger("component:"+strings.SplitN(customID, ":", 2)[0], i.GuildID, interactionUserID(i))
+	defer reqLogger.done()
+
+	switch {
+	case strings.HasPrefix(customID, muteButtonPrefix):
+		b.handleMuteComponent(s, i)
+	case strings.HasPrefix(customID, gamesPageButtonPrefix):
+		b.handleGamesPageComponent(s, i)
+	case strings.HasPrefix(customID, presetApplyButtonPrefix), customID == presetCancelButtonID:
+		b.handlePresetComponent(s, i)
+	}
+}
+
 // handleSetupCommand handles the /setup slash command
 func (b *DiscordBot) handleSetupCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	// Check if user has manage channels permission
-	permissions, err := s.UserChannelPermissions(i.Member.User.ID, i.ChannelID)
-	if err != nil {
-		b.respondToInteraction(s, i, "Error checking permissions.", true)
+	if !b.requireManageChannels(s, i) {
 		return
 	}
 
-	if permissions&discordgo.PermissionManageChannels == 0 {
-		b.respondToInteraction(s, i, "You need 'Manage Channels' permission to use this command.", true)
-		return
+	// Get the channel and optional webhook from the command options
+	var channelID, webhookURL string
+	webhookGiven := false
+	for _, option := range i.ApplicationCommandData().Options {
+		switch option.Name {
+		case "channel":
+			channelID = option.ChannelValue(s).ID
+		case "webhook":
+			webhookURL = option.StringValue()
+			webhookGiven = true
+		}
 	}
-
-	// Get the channel from the command options
-	options := i.ApplicationCommandData().Options
-	if len(options) == 0 {
+	if channelID == "" {
 		b.respondToInteraction(s, i, "Please specify a channel.", true)
 		return
 	}
-
-	channelID := options[0].ChannelValue(s).ID
+	if webhookURL != "" {
+		normalized, err := security.ValidateDiscordWebhookURL(webhookURL)
+		if err != nil {
+			b.respondToInteraction(s, i, fmt.Sprintf("Invalid webhook URL: %v. Copy it from Server Settings → Integrations → Webhooks.", err), true)
+			return
+		}
+		webhookURL = normalized
+	}
 	guildID := i.GuildID
 
 	// Save the server configuration
-	err = b.database.SaveServerConfig(guildID, channelID)
+	result, err := b.database.SaveServerConfig(guildID, channelID)
 	if err != nil {
 		log.Printf("Error saving server config: %v", err)
 		b.respondToInteraction(s, i, "Failed to save configuration. Please try again.", true)
 		return
 	}
+	// Re-running /setup without the webhook option keeps the stored webhook
+	if webhookGiven {
+		if err := b.database.SetWebhookURL(guildID, webhookURL); err != nil {
+			log.Printf("Error saving webhook for guild %s: %v", guildID, err)
+			b.respondToInteraction(s, i, "Failed to save configuration. Please try again.", true)
+			return
+		}
+	}
 
 	channelMention := fmt.Sprintf("<#%s>", channelID)
 	response := fmt.Sprintf("Successfully configured! I'll send free game notifications to %s", channelMention)
-	b.respondToInteraction(s, i, response, false)
+	if result.PreviousChannelID != "" {
+		response += fmt.Sprintf(" instead of <#%s>", result.PreviousChannelID)
+	}
+	if webhookURL != "" {
+		response += "\nAnnouncements are posted through your webhook, falling back to the channel if it fails. Webhook posts have no \"Mute this game\" button."
+	}
+	if result.Reactivated {
+		response += "\nNotifications are resumed with your previous settings."
+	}
+	if result.Created {
+		response += "\nThe current giveaways will be posted here in a moment."
+	}
+	response += formatChannelNotes(b.fetchChannelNotes(s, channelID, guildID))
+	// The webhook URL is a secret, so keep the reply to it private
+	b.respondToInteraction(s, i, response, webhookURL != "")
 	
 	log.Printf("Server %s configured to use channel %s", guildID, channelID)
+
+	// Post the current games right away rather than at the next check
+	go b.backfillServer(guildID)
 }
 
 // respondToInteraction sends a response to a slash command interaction
@@ -492,8 +895,15 @@ func (b *DiscordBot) respondToInteraction(s *discordgo.Session, i *discordgo.Int
 	}
 }
 
-// handleGamesSlashCommand handles the /games slash command
+// handleGamesSlashCommand handles the /games slash command. The default view
+// is a single paginated embed; view:all posts one message per game.
 func (b *DiscordBot) handleGamesSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 || options[0].StringValue() != gamesViewAll {
+		b.handlePaginatedGames(s, i)
+		return
+	}
+
 	// Defer the response since getting games might take time
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
@@ -514,13 +924,18 @@ func (b *DiscordBot) handleGamesSlashCommand(s *discordgo.Session, i *discordgo.
 		return
 	}
 
+	// The first follow-up replaces the deferred response, so the warning leads
+	if warning := b.staleDataWarning(); warning != "" {
+		b.followUpInteraction(s, i, warning)
+	}
+
 	// Send games to the current channel
-	if err := b.sendFreeNowGames(games.FreeNow, i.ChannelID); err != nil {
+	if _, err := b.sendFreeNowGames(b.ctx, games.FreeNow, i.ChannelID, nil, ""); err != nil {
 		b.followUpInteraction(s, i, fmt.Sprintf("Failed to send Free Now games: %v", err))
 		return
 	}
 	
-	if err := b.sendComingSoonGames(games.ComingSoon, i.ChannelID); err != nil {
+	if _, err := b.sendComingSoonGames(b.ctx, games.ComingSoon, i.ChannelID, nil, ""); err != nil {
 		b.followUpInteraction(s, i, fmt.Sprintf("Failed to send Coming Soon games: %v", err))
 		return
 	}
@@ -530,6 +945,10 @@ func (b *DiscordBot) handleGamesSlashCommand(s *discordgo.Session, i *discordgo.
 
 // handleRefreshSlashCommand handles the /refresh slash command
 func (b *DiscordBot) handleRefreshSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.allowSlashRefresh(s, i) {
+		return
+	}
+
 	// Defer the response since refreshing might take time
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
@@ -539,8 +958,8 @@ func (b *DiscordBot) handleRefreshSlashCommand(s *discordgo.Session, i *discordg
 		return
 	}
 
-	if err := b.gameService.RefreshGames(); err != nil {
-		b.followUpInteraction(s, i, fmt.Sprintf("Failed to refresh games: %v", err))
+	if err := b.gameService.RefreshGames(b.ctx); err != nil {
+		b.followUpInteraction(s, i, refreshFailedMessage(err))
 		return
 	}
 
@@ -556,12 +975,12 @@ func (b *DiscordBot) handleRefreshSlashCommand(s *discordgo.Session, i *discordg
 	}
 
 	// Send updated games to the current channel
-	if err := b.sendFreeNowGames(games.FreeNow, i.ChannelID); err != nil {
+	if _, err := b.sendFreeNowGames(b.ctx, games.FreeNow, i.ChannelID, nil, ""); err != nil {
 		b.followUpInteraction(s, i, fmt.Sprintf("Failed to send Free Now games: %v", err))
 		return
 	}
 	
-	if err := b.sendComingSoonGames(games.ComingSoon, i.ChannelID); err != nil {
+	if _, err := b.sendComingSoonGames(b.ctx, games.ComingSoon, i.ChannelID, nil, ""); err != nil {
 		b.followUpInteraction(s, i, fmt.Sprintf("Failed to send Coming Soon games: %v", err))
 		return
 	}
@@ -572,6 +991,15 @@ func (b *DiscordBot) handleRefreshSlashCommand(s *discordgo.Session, i *discordg
 // handleStatusCommand handles the /status slash command
 func (b *DiscordBot) handleStatusCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	guildID := i.GuildID
+	if guildID == "" {
+		b.handleDMStatus(s, i)
+		return
+	}
+
+	if options := i.ApplicationCommandData().Options; len(options) > 0 && options[0].StringValue() == "recent" {
+		b.handleStatusRecent(s, i)
+		return
+	}
 	
 	// Get server configuration
 	serverConfig, err := b.database.GetServerConfig(guildID)
@@ -582,7 +1010,7 @@ func (b *DiscordBot) handleStatusCommand(s *discordgo.Session, i *discordgo.Inte
 
 	embed := &discordgo.MessageEmbed{
 		Title: "Bot Status",
-		Color: 0x0099ff,
+		Color: b.branding().AccentColor,
 		Fields: []*discordgo.MessageEmbedField{
 			{
 				Name:   "Bot Status",
@@ -590,9 +1018,51 @@ func (b *DiscordBot) handleStatusCommand(s *discordgo.Session, i *discordgo.Inte
 				Inline: true,
 			},
 		},
-		Footer: &discordgo.MessageEmbedFooter{
-			Text: "Epic Games Store - Free Games Bot",
-		},
+		Footer: brandFooter(b.branding()),
+	}
+
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Command Registration",
+		Value:  b.describeCommandStrategy(),
+		Inline: true,
+	})
+
+	if schedule := b.describeSchedule(); schedule != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Game Checks",
+			Value:  schedule,
+			Inline: true,
+		})
+	}
+
+	if freshness, err := b.gameService.Freshness(time.Now()); err != nil {
+		log.Printf("Error checking data freshness: %v", err)
+	} else if !freshness.LastScrape.IsZero() {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Last Checked",
+			Value:  fmt.Sprintf("<t:%d:R>", freshness.LastScrape.Unix()),
+			Inline: true,
+		})
+	}
+
+	if lastRestart := b.describeLastRestart(); lastRestart != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Last Restart",
+			Value:  lastRestart,
+			Inline: true,
+		})
+	}
+
+	if pending := b.linkVerifier.list(); len(pending) > 0 {
+		var sb strings.Builder
+		for _, p := range pending {
+			sb.WriteString(fmt.Sprintf("• %s (announcing by <t:%d:t>)\n", p.Game.Title, p.Deadline.Unix()))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Pending Link Verification",
+			Value:  strings.TrimSuffix(sb.String(), "\n"),
+			Inline: false,
+		})
 	}
 
 	if serverConfig != nil {
@@ -602,6 +1072,106 @@ func (b *DiscordBot) handleStatusCommand(s *discordgo.Session, i *discordgo.Inte
 			Value:  channelMention,
 			Inline: true,
 		})
+
+		mentionRole := "None (use /setrole)"
+		if serverConfig.RoleID != "" {
+			mentionRole = serverConfig.RoleMention()
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Mention Role",
+			Value:  mentionRole,
+			Inline: true,
+		})
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Region",
+			Value:  describeRegion(b.guildRegion(serverConfig)),
+			Inline: true,
+		})
+
+		if serverConfig.ClaimReminder != "" {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:   "Claim Reminder",
+				Value:  serverConfig.ClaimReminder,
+				Inline: false,
+			})
+		}
+
+		expiryReminders := "Off"
+		if serverConfig.ExpiryReminders {
+			expiryReminders = "On"
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Expiry Reminders",
+			Value:  expiryReminders,
+			Inline: true,
+		})
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Coming Soon Mode",
+			Value:  serverConfig.ComingSoonMode,
+			Inline: true,
+		})
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Format",
+			Value:  serverConfig.MessageFormat,
+			Inline: true,
+		})
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Stores",
+			Value:  sourcesDescription(serverConfig.Sources),
+			Inline: true,
+		})
+
+		if serverConfig.ContentPolicy != "" {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:   "Content Policy",
+				Value:  describePolicy(serverConfig.ContentPolicy),
+				Inline: false,
+			})
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Timezone",
+			Value:  serverConfig.TimezoneName(),
+			Inline: true,
+		})
+
+		delivery := "Bot messages"
+		if serverConfig.WebhookURL != "" {
+			delivery = "Webhook (channel as fallback)"
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Delivery",
+			Value:  delivery,
+			Inline: true,
+		})
+
+		if serverConfig.NeedsAttention != "" {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:   "⚠️ Needs Attention",
+				Value:  attentionReason(serverConfig.NeedsAttention) + " Run /setup to choose a new channel.",
+				Inline: false,
+			})
+		}
+
+		if serverConfig.PostDelaySeconds > 0 {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:   "Announcement Delay",
+				Value:  fmt.Sprintf("%ds between games", serverConfig.PostDelaySeconds),
+				Inline: true,
+			})
+		}
+
+		if notes := b.fetchChannelNotes(s, serverConfig.ChannelID, guildID); len(notes) > 0 {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:   "Channel Notes",
+				Value:  strings.TrimPrefix(formatChannelNotes(notes), "\n"),
+				Inline: false,
+			})
+		}
 	} else {
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
 			Name:   "Notification Channel",
@@ -624,23 +1194,23 @@ func (b *DiscordBot) handleStatusCommand(s *discordgo.Session, i *discordgo.Inte
 // handleHelpSlashCommand handles the /help slash command
 func (b *DiscordBot) handleHelpSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	embed := &discordgo.MessageEmbed{
-		Title:       "Free Games Bot Commands",
-		Description: "Available slash commands for the Epic Games Free Games Bot:",
-		Color:       0x0099ff,
+		Title:       fmt.Sprintf("%s Commands", b.branding().Name),
+		Description: fmt.Sprintf("Available slash commands for %s:", b.branding().Name),
+		Color:       b.branding().AccentColor,
 		Fields: []*discordgo.MessageEmbedField{
 			{
-				Name:   "/setup <channel>",
-				Value:  "Configure which channel to send notifications to",
+				Name:   "/setup <channel> [webhook]",
+				Value:  "Configure which channel to send notifications to, optionally through a webhook",
 				Inline: false,
 			},
 			{
-				Name:   "/games",
-				Value:  "Show current free games",
+				Name:   "/games [view]",
+				Value:  "Page through current free games, or post them all with view:all",
 				Inline: false,
 			},
 			{
 				Name:   "/refresh",
-				Value:  "Manually check for new games",
+				Value:  "Manually check for new games (Manage Server)",
 				Inline: false,
 			},
 			{
@@ -648,15 +1218,113 @@ func (b *DiscordBot) handleHelpSlashCommand(s *discordgo.Session, i *discordgo.I
 				Value:  "Show bot status and configuration",
 				Inline: false,
 			},
+			{
+				Name:   "/nextcheck",
+				Value:  "Show when the bot will next check for free games",
+				Inline: false,
+			},
+			{
+				Name:   "/privacy",
+				Value:  "Show what data the bot stores about this server",
+				Inline: false,
+			},
+			{
+				Name:   "/impact",
+				Value:  "Show announcements, reminders and members reached in the last 30 days",
+				Inline: false,
+			},
+			{
+				Name:   "/gameinfo <title>",
+				Value:  "Show details about a game, with title suggestions while typing",
+				Inline: false,
+			},
+			{
+				Name:   "/compare <period1> <period2>",
+				Value:  "Compare giveaways between two periods (e.g. this week vs last week)",
+				Inline: false,
+			},
+			{
+				Name:   "/unsubscribe",
+				Value:  "Stop free game notifications in this server (Manage Channels)",
+				Inline: false,
+			},
+			{
+				Name:   "/subscribe · /unsubscribe target:me",
+				Value:  "Get (or stop) a DM when a new game becomes free",
+				Inline: false,
+			},
+			{
+				Name:   "/setdelay <seconds>",
+				Value:  "Pause between consecutive game announcements (Manage Channels)",
+				Inline: false,
+			},
+			{
+				Name:   "/setrole set <role> · /setrole none",
+				Value:  "Ping a role when new games are announced (Manage Channels)",
+				Inline: false,
+			},
+			{
+				Name:   "/region [locale]",
+				Value:  "Show or choose which Epic region's free games this server sees (Manage Channels to change)",
+				Inline: false,
+			},
+			{
+				Name:   "/customize reminder|policy|banner|preview",
+				Value:  "Add a claim reminder, set a content policy (no-mentions, no-links, text-only), start announcements with a banner and preview them (Manage Channels)",
+				Inline: false,
+			},
+			{
+				Name:   "/mute game <title> · /mute list · /unmute <title>",
+				Value:  "Stop the bot from referencing a game in this server (Manage Channels)",
+				Inline: false,
+			},
+			{
+				Name:   "/textfallback <enabled>",
+				Value:  "Post plain text when embeds aren't allowed in the channel (Manage Channels)",
+				Inline: false,
+			},
+			{
+				Name:   "/markseen",
+				Value:  "Mark the current giveaways as announced here without posting them (Manage Channels)",
+				Inline: false,
+			},
+			{
+				Name:   "/setreminders <on|off>",
+				Value:  "Post a last-chance reminder about 24 hours before a free game ends (Manage Channels)",
+				Inline: false,
+			},
+			{
+				Name:   "/comingsoon mode <announce|release_only|both>",
+				Value:  "Post Coming Soon games in advance, only when they become free, or both (Manage Channels)",
+				Inline: false,
+			},
+			{
+				Name:   "/format <embed|plain|both>",
+				Value:  "Post games as embeds, as plain store links, or both (Manage Channels)",
+				Inline: false,
+			},
+			{
+				Name:   "/sources <all|epic|gog>",
+				Value:  "Choose which stores' giveaways are announced; the default is all (Manage Channels)",
+				Inline: false,
+			},
+			{
+				Name:   "/settimezone <timezone>",
+				Value:  "Show free periods in an IANA timezone such as Europe/Berlin; the default is UTC (Manage Channels)",
+				Inline: false,
+			},
+			{
+				Name:   "/settings apply <preset> · save <name> · list · delete <name>",
+				Value:  "Apply a bundle of settings such as minimal, full-featured or family-friendly, or save this server's to reuse elsewhere (Manage Channels)",
+				Inline: false,
+			},
 			{
 				Name:   "/help",
 				Value:  "Show this help message",
 				Inline: false,
 			},
 		},
-		Footer: &discordgo.MessageEmbedFooter{
-			Text: "Epic Games Store - Free Games Bot",
-		},
+		Footer: brandFooter(b.branding()),
 	}
 
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -680,28 +1348,56 @@ func (b *DiscordBot) followUpInteraction(s *discordgo.Session, i *discordgo.Inte
 	}
 }
 
-// sendWelcomeMessage sends a welcome message when the bot joins a new guild
-func (b *DiscordBot) sendWelcomeMessage(s *discordgo.Session, g *discordgo.GuildCreate) {
-	// Find a suitable channel to send the welcome message
-	// Try to find a general channel, system channel, or the first text channel we can send to
-	var targetChannelID string
-	
+// welcomeJoinWindow bounds how long ago the bot may have joined an unrecorded
+// guild for it to still count as a new join rather than a guild that predates
+// the known_guilds table
+const welcomeJoinWindow = 10 * time.Minute
+
+// isNewGuildJoin distinguishes a genuine join from the GuildCreate events
+// discordgo fires for every existing guild on connect. Database errors are
+// treated as not new so a flaky database never causes welcome spam.
+func (b *DiscordBot) isNewGuildJoin(g *discordgo.GuildCreate) bool {
+	isNew, err := b.database.RecordGuild(g.ID, g.Name, g.MemberCount)
+	if err != nil {
+		log.Printf("Error recording guild %s: %v", g.ID, err)
+		return false
+	}
+	if !isNew {
+		return false
+	}
+
+	if !g.JoinedAt.IsZero() && time.Since(g.JoinedAt) > welcomeJoinWindow {
+		log.Printf("Recorded existing guild: %s (ID: %s)", g.Name, g.ID)
+		return false
+	}
+	return true
+}
+
+// welcomeChannel picks the channel to greet a guild in: the system channel
+// if it exists, otherwise the first text channel we can send messages to.
+// It returns "" when there is none.
+func welcomeChannel(s *discordgo.Session, g *discordgo.GuildCreate) string {
 	// First, try the system channel if it exists
 	if g.SystemChannelID != "" {
-		targetChannelID = g.SystemChannelID
-	} else {
-		// Find the first text channel we have permission to send messages to
-		for _, channel := range g.Channels {
-			if channel.Type == discordgo.ChannelTypeGuildText {
-				// Check if we can send messages to this channel
-				permissions, err := s.UserChannelPermissions(s.State.User.ID, channel.ID)
-				if err == nil && permissions&discordgo.PermissionSendMessages != 0 {
-					targetChannelID = channel.ID
-					break
-				}
+		return g.SystemChannelID
+	}
+
+	// Find the first text channel we have permission to send messages to
+	for _, channel := range g.Channels {
+		if channel.Type == discordgo.ChannelTypeGuildText {
+			// Check if we can send messages to this channel
+			permissions, err := s.UserChannelPermissions(s.State.User.ID, channel.ID)
+			if err == nil && permissions&discordgo.PermissionSendMessages != 0 {
+				return channel.ID
 			}
 		}
 	}
+	return ""
+}
+
+// sendWelcomeMessage sends a welcome message when the bot joins a new guild
+func (b *DiscordBot) sendWelcomeMessage(s *discordgo.Session, g *discordgo.GuildCreate) {
+	targetChannelID := welcomeChannel(s, g)
 	
 	// If we couldn't find a suitable channel, log and return
 	if targetChannelID == "" {
@@ -711,9 +1407,9 @@ func (b *DiscordBot) sendWelcomeMessage(s *discordgo.Session, g *discordgo.Guild
 	
 	// Create the welcome message embed
 	embed := &discordgo.MessageEmbed{
-		Title:       "Thanks for adding Free Games Bot!",
+		Title:       fmt.Sprintf("Thanks for adding %s!", b.branding().Name),
 		Description: "I'll help you stay updated on free games from Epic Games Store.",
-		Color:       0x0099ff,
+		Color:       b.branding().AccentColor,
 		Fields: []*discordgo.MessageEmbedField{
 			{
 				Name:   "Getting Started",
@@ -726,9 +1422,7 @@ func (b *DiscordBot) sendWelcomeMessage(s *discordgo.Session, g *discordgo.Guild
 				Inline: false,
 			},
 		},
-		Footer: &discordgo.MessageEmbedFooter{
-			Text: "Epic Games Store - Free Games Bot",
-		},
+		Footer: brandFooter(b.branding()),
 	}
 	
 	// Send the welcome message
@@ -0,0 +1,288 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/logger"
+	"free-games-scrape/internal/metrics"
+	"free-games-scrape/internal/models"
+	"free-games-scrape/internal/ratelimit"
+	"free-games-scrape/internal/service"
+)
+
+// newTestBot returns a bot over a private in-memory database with no Discord
+// session; tests set the parts of it they exercise
+func newTestBot(t *testing.T) *DiscordBot {
+	t.Helper()
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := database.New(fmt.Sprintf("file:%s?mode=memory&cache=shared", name))
+	if err != nil {
+		t.Fatalf("database.New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	rateLimiter := ratelimit.NewDiscordRateLimiter(0)
+	t.Cleanup(rateLimiter.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	appMetrics := metrics.New()
+	return &DiscordBot{
+		config:       &config.DiscordConfig{DeliveryWorkers: 4, MaxConcurrentHandlers: 4},
+		gameService:  service.NewGameService(db, appMetrics),
+		database:     db,
+		logger:       logger.NewWithOutput(logger.LevelError, "test", io.Discard),
+		metrics:      appMetrics,
+		rateLimiter:  rateLimiter,
+		handlerSem:   make(chan struct{}, 4),
+		linkVerifier: newLinkVerifier(),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+// fakeRequest is a REST call a session made to fakeDiscord, and when it
+// arrived. Bodies that are JSON arrays, like bulk command overwrites, go
+// to List; Files names the attachments of a multipart message.
+type fakeRequest struct {
+	Method string
+	Path   string
+	Body   map[string]interface{}
+	List   []interface{}
+	Files  []string
+	At     time.Time
+}
+
+// fakeDiscord stands in for Discord's REST API, recording every request and
+// answering each with an empty success, unless fail returns an HTTP status
+// and error body for it
+type fakeDiscord struct {
+	mu       sync.Mutex
+	requests []fakeRequest
+	fail     func(fakeRequest) (int, string)
+}
+
+// useFakeDiscord gives b a session whose REST calls go to a fakeDiscord. It
+// points discordgo's endpoints at a test server for the rest of the test.
+func useFakeDiscord(t *testing.T, b *DiscordBot) *fakeDiscord {
+	t.Helper()
+	fake := &fakeDiscord{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := fakeRequest{Method: r.Method, Path: strings.TrimPrefix(r.URL.Path, "/api/"), At: time.Now()}
+		if parts, err := r.MultipartReader(); err == nil {
+			// Messages with attachments carry their JSON in payload_json
+			for part, err := parts.NextPart(); err == nil; part, err = parts.NextPart() {
+				if part.FormName() == "payload_json" {
+					json.NewDecoder(part).Decode(&request.Body)
+				} else if part.FileName() != "" {
+					request.Files = append(request.Files, part.FileName())
+				}
+			}
+		} else if body, err := io.ReadAll(r.Body); err == nil && json.Unmarshal(body, &request.Body) != nil {
+			json.Unmarshal(body, &request.List)
+		}
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, request)
+		id := len(fake.requests)
+		fail := fake.fail
+		fake.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if fail != nil {
+			if status, body := fail(request); status != 0 {
+				w.WriteHeader(status)
+				io.WriteString(w, body)
+				return
+			}
+		}
+		fmt.Fprintf(w, `{"id": "%d"}`, id)
+	}))
+	t.Cleanup(server.Close)
+
+	endpoints := []*string{
+		&discordgo.EndpointAPI, &discordgo.EndpointChannels, &discordgo.EndpointUsers,
+		&discordgo.EndpointGuilds, &discordgo.EndpointWebhooks, &discordgo.EndpointApplications,
+	}
+	api := discordgo.EndpointAPI
+	saved := make([]string, len(endpoints))
+	for i, endpoint := range endpoints {
+		saved[i] = *endpoint
+		*endpoint = server.URL + "/api/" + strings.TrimPrefix(*endpoint, api)
+	}
+	t.Cleanup(func() {
+		for i, endpoint := range endpoints {
+			*endpoint = saved[i]
+		}
+	})
+
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("discordgo.New: %v", err)
+	}
+	session.State.User = &discordgo.User{ID: "100000000000000001"}
+	b.session = session
+	b.editor = session
+	return fake
+}
+
+// find returns the requests made with method to paths starting with prefix
+func (f *fakeDiscord) find(method, prefix string) []fakeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var found []fakeRequest
+	for _, request := range f.requests {
+		if request.Method == method && strings.HasPrefix(request.Path, prefix) {
+			found = append(found, request)
+		}
+	}
+	return found
+}
+
+func TestGuildCreateFloodWelcomesOnlyNewGuilds(t *testing.T) {
+	b := newTestBot(t)
+	discord := useFakeDiscord(t, b)
+
+	guild := func(id string, joinedAt time.Time) *discordgo.GuildCreate {
+		return &discordgo.GuildCreate{Guild: &discordgo.Guild{ID: id, Name: "Guild " + id, SystemChannelID: "channel-" + id, JoinedAt: joinedAt}}
+	}
+
+	// Guilds seen before the restart are already recorded
+	const known = 50
+	for i := 0; i < known; i++ {
+		if _, err := b.database.RecordGuild(fmt.Sprint(i), "", 0); err != nil {
+			t.Fatalf("RecordGuild: %v", err)
+		}
+	}
+
+	// On connecting, Discord sends a GuildCreate for every guild at once:
+	// the known ones, one joined long ago but never recorded, and one just
+	// joined, whose event also arrives twice
+	events := []*discordgo.GuildCreate{guild("old", time.Now().Add(-24*time.Hour)), guild("new", time.Now()), guild("new", time.Now())}
+	for i := 0; i < known; i++ {
+		events = append(events, guild(fmt.Sprint(i), time.Now().Add(-24*time.Hour)))
+	}
+	handler := safeHandler(b, "guild_create", b.handleGuildCreate)
+	var wg sync.WaitGroup
+	for _, event := range events {
+		wg.Add(1)
+		go func(event *discordgo.GuildCreate) {
+			defer wg.Done()
+			handler(b.session, event)
+		}(event)
+	}
+	wg.Wait()
+
+	welcomes := discord.find("POST", "channels/")
+	if len(welcomes) != 1 || welcomes[0].Path != "channels/channel-new/messages" {
+		t.Errorf("sent %d welcomes (%v), want one to the newly joined guild", len(welcomes), welcomes)
+	}
+	if got := b.metrics.GetServersJoined(); got != 1 {
+		t.Errorf("GetServersJoined = %d, want 1", got)
+	}
+
+	// The guild joined long ago is recorded, so it is not welcomed later
+	if isNew, err := b.database.RecordGuild("old", "", 0); err != nil || isNew {
+		t.Errorf("RecordGuild(old) = %v, %v, want it already recorded", isNew, err)
+	}
+}
+
+func TestSendsToOneChannelAreSpacedOut(t *testing.T) {
+	b := newTestBot(t)
+	discord := useFakeDiscord(t, b)
+
+	games := []models.Game{runningGame("One"), runningGame("Two"), runningGame("Three"), runningGame("Four")}
+	cfg := &database.ServerConfig{GuildID: "guild", ChannelID: "900"}
+	done := make(chan error, 1)
+	go func() {
+		_, err := b.postFreeNowGames(context.Background(), games, "900", cfg, "", false)
+		done <- err
+	}()
+	// Another channel is not held up by the first one's limit
+	if err := b.SendMessageTo("901", "other channel"); err != nil {
+		t.Fatalf("SendMessageTo: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("postFreeNowGames: %v", err)
+	}
+
+	// Channels allow 5 messages a second, one at a time
+	const minGap = 180 * time.Millisecond
+	sends := discord.find("POST", "channels/900/messages")
+	if len(sends) != len(games) {
+		t.Fatalf("made %d sends, want %d", len(sends), len(games))
+	}
+	for i := 1; i < len(sends); i++ {
+		if gap := sends[i].At.Sub(sends[i-1].At); gap < minGap {
+			t.Errorf("send %d came %v after the previous one, want at least %v", i, gap, minGap)
+		}
+	}
+
+	other := discord.find("POST", "channels/901/messages")
+	if len(other) != 1 {
+		t.Fatalf("made %d sends to the other channel, want 1", len(other))
+	}
+	if other[0].At.After(sends[1].At) {
+		t.Error("the other channel waited behind the first channel's sends")
+	}
+}
+
+func TestStopCancelsDeliveryCycle(t *testing.T) {
+	b := newTestBot(t)
+	discord := useFakeDiscord(t, b)
+	if _, err := b.database.SaveServerConfig("guild", "900"); err != nil {
+		t.Fatalf("SaveServerConfig: %v", err)
+	}
+
+	games := &models.GameCollection{FreeNow: []models.Game{runningGame("One"), runningGame("Two"), runningGame("Three"), runningGame("Four")}}
+	done := make(chan error, 1)
+	go func() { done <- b.deliverGameUpdates(games, games) }()
+
+	// Stop once the first game is out, while the rest wait on the rate limiter
+	deadline := time.Now().Add(5 * time.Second)
+	for len(discord.find("POST", "channels/900/messages")) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the first game was never sent")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	b.cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the delivery cycle kept running after the bot stopped")
+	}
+	if sends := discord.find("POST", "channels/900/messages"); len(sends) >= len(games.FreeNow) {
+		t.Errorf("made %d sends after stopping, want the cycle cut short", len(sends))
+	}
+}
+
+func TestFreeNowEmbedForRelease(t *testing.T) {
+	b := newTestBot(t)
+	game := runningGame("Flipped")
+
+	announced := b.freeNowEmbed(game, 0, 2, nil, false)
+	released := b.freeNowEmbed(game, 0, 2, nil, true)
+	if announced.Title != "Free Game Available Now! (1/2)" {
+		t.Errorf("announcement title = %q", announced.Title)
+	}
+	if released.Title != "Now Available! (1/2)" || !strings.Contains(released.Description, "no longer coming soon") {
+		t.Errorf("release embed = %q / %q, want the Now Available wording", released.Title, released.Description)
+	}
+	if released.URL != game.StoreURL {
+		t.Errorf("release embed URL = %q, want the store link", released.URL)
+	}
+}
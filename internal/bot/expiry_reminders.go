@@ -0,0 +1,145 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// ExpiryReminderWindow is how long before a Free Now game ends its "last
+// chance" reminder is posted
+const ExpiryReminderWindow = 24 * time.Hour
+
+// SendExpiryReminders posts a "last chance" reminder for each game to every
+// server with expiry reminders on. Each server gets at most one reminder per
+// game and promotion; sent reminders are recorded so restarts don't repeat them.
+func (b *DiscordBot) SendExpiryReminders(ctx context.Context, games []models.Game) {
+	if len(games) == 0 {
+		return
+	}
+
+	serverConfigs, err := b.database.GetAllActiveServerConfigs()
+	if err != nil {
+		log.Printf("Error getting server configs for expiry reminders: %v", err)
+		return
+	}
+
+	// If no server configs and we have a legacy channel, use that
+	if len(serverConfigs) == 0 && b.channelID != "" {
+		b.sendExpiryReminders(ctx, "", b.channelID, nil, games)
+		return
+	}
+
+	for _, cfg := range serverConfigs {
+		if !cfg.ExpiryReminders {
+			continue
+		}
+		accepted, _ := b.filterReminders(cfg, games)
+		b.sendExpiryReminders(ctx, cfg.GuildID, cfg.ChannelID, cfg, accepted)
+	}
+}
+
+// sendExpiryReminders posts the reminders a channel has not received yet
+func (b *DiscordBot) sendExpiryReminders(ctx context.Context, guildID, channelID string, cfg *database.ServerConfig, games []models.Game) {
+	for _, game := range games {
+		if ctx.Err() != nil {
+			return
+		}
+
+		sent, err := b.database.NotificationSent(guildID, database.NotificationExpiryReminder, game)
+		if err != nil {
+			log.Printf("Error checking expiry reminder for %s in guild %s: %v", game.Title, guildID, err)
+			continue
+		}
+		if sent {
+			continue
+		}
+
+		if err := b.rateLimiter.WaitForChannel(ctx, channelID); err != nil {
+			log.Printf("Rate limiter wait failed for channel %s: %v", channelID, err)
+			return
+		}
+		posted, err := b.sendGameMessage(channelID, expiryReminderEmbed(b.branding(), game, time.Now()), game, cfg, "")
+		if err != nil {
+			log.Printf("Error sending expiry reminder for %s to channel %s: %v", game.Title, channelID, err)
+			continue
+		}
+		b.recordPostedMessage(cfg, database.NotificationExpiryReminder, game, posted, 0, 1, "")
+
+		if err := b.database.RecordNotificationSent(guildID, database.NotificationExpiryReminder, game); err != nil {
+			log.Printf("Error recording expiry reminder for %s in guild %s: %v", game.Title, guildID, err)
+		}
+		log.Printf("Sent expiry reminder for %s to channel %s", game.Title, channelID)
+	}
+}
+
+// expiryReminderEmbed builds the "last chance" embed for a game ending soon
+func expiryReminderEmbed(branding config.BrandingConfig, game models.Game, now time.Time) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("⏰ Last chance to claim %s", game.Title),
+		URL:         game.StoreURL,
+		Description: fmt.Sprintf("**%s** is free on %s for %s. Claim it before <t:%d:f>!", game.Title, game.SourceName(), describeRemaining(game.FreeToTime.Sub(now)), game.FreeToTime.Unix()),
+		Color:       0xff9900, // Orange color
+		Footer:      gameFooter(branding, game),
+	}
+
+	if game.ImageURL != "" {
+		embed.Image = &discordgo.MessageEmbedImage{
+			URL: game.ImageURL,
+		}
+	}
+
+	return embed
+}
+
+// describeRemaining phrases the time left in a promotion, e.g. "23 more hours"
+func describeRemaining(d time.Duration) string {
+	hours := int(d / time.Hour)
+	if hours < 1 {
+		return "less than an hour"
+	}
+	return pluralize(hours, "more hour")
+}
+
+// handleSetRemindersCommand handles /setreminders on|off
+func (b *DiscordBot) handleSetRemindersCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	serverConfig, err := b.database.GetServerConfig(i.GuildID)
+	if err != nil {
+		b.respondToInteraction(s, i, "Error checking server configuration.", true)
+		return
+	}
+	if serverConfig == nil {
+		b.respondToInteraction(s, i, "This server is not configured yet. Use /setup first.", true)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please choose on or off.", true)
+		return
+	}
+	enabled := options[0].StringValue() == "on"
+
+	if err := b.database.SetExpiryReminders(i.GuildID, enabled); err != nil {
+		log.Printf("Error saving expiry reminders for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to save the setting. Please try again.", true)
+		return
+	}
+
+	if enabled {
+		b.respondToInteraction(s, i, fmt.Sprintf("Expiry reminders enabled: I'll post a last-chance reminder in <#%s> about %d hours before each free game ends.", serverConfig.ChannelID, int(ExpiryReminderWindow/time.Hour)), false)
+	} else {
+		b.respondToInteraction(s, i, "Expiry reminders disabled.", false)
+	}
+	log.Printf("Server %s set expiry reminders to %t", i.GuildID, enabled)
+}
@@ -0,0 +1,39 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+func TestExpiryRemindersFollowAnnouncements(t *testing.T) {
+	b := newTestBot(t)
+	discord := useFakeDiscord(t, b)
+	for guildID, reminders := range map[string]bool{"on": true, "off": false} {
+		if _, err := b.database.SaveServerConfig(guildID, "channel-"+guildID); err != nil {
+			t.Fatalf("SaveServerConfig: %v", err)
+		}
+		if err := b.database.SetExpiryReminders(guildID, reminders); err != nil {
+			t.Fatalf("SetExpiryReminders: %v", err)
+		}
+	}
+
+	game := runningGame("Ending")
+	game.FreeToTime = time.Now().Add(12 * time.Hour)
+	if _, err := b.database.MarkNotificationsSent("on", database.NotificationAnnouncement, []models.Game{game}); err != nil {
+		t.Fatalf("MarkNotificationsSent: %v", err)
+	}
+
+	// The announced game gets its reminder once, and only where reminders are on
+	for i := 0; i < 2; i++ {
+		b.SendExpiryReminders(b.ctx, []models.Game{game})
+	}
+	if sent := len(discord.find("POST", "channels/channel-on/messages")); sent != 1 {
+		t.Errorf("sent %d reminders of the announced game, want 1", sent)
+	}
+	if sent := len(discord.find("POST", "channels/channel-off/messages")); sent != 0 {
+		t.Errorf("sent %d reminders to a server with reminders off", sent)
+	}
+}
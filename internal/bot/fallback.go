@@ -0,0 +1,279 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// formatPlainGame renders a game as plain text for channels where the bot
+// cannot post embeds
+func formatPlainGame(game models.Game, location *time.Location) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**%s**", game.Title))
+	if game.Status != "" {
+		sb.WriteString(" — " + game.Status)
+	}
+	if freeFrom := promoTime(game.FreeFromTime, game.FreeFrom, location); freeFrom != "" && game.Status == models.StatusComingSoon {
+		sb.WriteString(fmt.Sprintf("\nFree from: %s", freeFrom))
+	}
+	if freeTo := promoTime(game.FreeToTime, game.FreeTo, location); freeTo != "" {
+		sb.WriteString(fmt.Sprintf("\nFree until: %s", freeTo))
+	}
+	if game.StoreURL != "" {
+		sb.WriteString("\n" + game.StoreURL)
+	}
+	return sb.String()
+}
+
+// formatGameLink renders a game as a one-line summary followed by the store
+// link, for guilds that chose the plain or both announcement format. Discord
+// shows its own preview of the link.
+func formatGameLink(game models.Game, location *time.Location) string {
+	var summary string
+	if game.Status == models.StatusComingSoon {
+		summary = fmt.Sprintf("**%s** will be free on %s", game.Title, game.SourceName())
+		if freeFrom := promoTime(game.FreeFromTime, game.FreeFrom, location); freeFrom != "" {
+			summary += " from " + freeFrom
+		}
+	} else {
+		summary = fmt.Sprintf("**%s** is free on %s", game.Title, game.SourceName())
+		if freeTo := promoTime(game.FreeToTime, game.FreeTo, location); freeTo != "" {
+			summary += " until " + freeTo
+		}
+	}
+	if game.StoreURL == "" {
+		return summary
+	}
+	return summary + "\n" + game.StoreURL
+}
+
+// promoTime renders a promotion boundary in the server's timezone followed by
+// a Discord timestamp, which each reader's client shows in their own
+// timezone. It falls back to the scraped display text when the exact time is
+// unknown.
+func promoTime(t time.Time, text string, location *time.Location) string {
+	if t.IsZero() {
+		return text
+	}
+	if location == nil {
+		location = time.UTC
+	}
+	return fmt.Sprintf("%s (<t:%d:f>)", t.In(location).Format("Jan 2 15:04 MST"), t.Unix())
+}
+
+// embedsAllowed reports whether the bot may post embeds in a channel
+// according to the state cache. Unknown permissions are assumed allowed so
+// the send itself decides.
+func (b *DiscordBot) embedsAllowed(channelID string) bool {
+	if b.session.State == nil || b.session.State.User == nil {
+		return true
+	}
+
+	permissions, err := b.session.State.UserChannelPermissions(b.session.State.User.ID, channelID)
+	if err != nil {
+		return true
+	}
+	return permissions&discordgo.PermissionEmbedLinks != 0
+}
+
+// isEmbedRejected reports whether a send error is what Discord returns when
+// an embed-only message is posted without the Embed Links permission
+func isEmbedRejected(err error) bool {
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) || restErr.Message == nil {
+		return false
+	}
+	switch restErr.Message.Code {
+	case discordgo.ErrCodeCannotSendEmptyMessage, discordgo.ErrCodeMissingPermissions:
+		return true
+	}
+	return false
+}
+
+// gameMessage is a game announcement rendered for one server: rich is
+// posted, and plain replaces it when fallback is set and the channel refuses
+// embeds
+type gameMessage struct {
+	rich     *discordgo.MessageSend
+	plain    *discordgo.MessageSend
+	fallback bool
+}
+
+// renderGameMessage renders a game embed as the server's announcement.
+// Automatic announcements (cfg set) get a "Mute this game" button and mention
+// is prefixed to the message. Guilds using the plain format get the summary
+// and store link instead of the embed, and the both format posts them above
+// it. The server's content policy is applied last: no-mentions drops the
+// mention, no-links drops the store link from the embed and text, and
+// text-only posts the text with Discord's link previews suppressed.
+func renderGameMessage(embed *discordgo.MessageEmbed, game models.Game, cfg *database.ServerConfig, mention string) gameMessage {
+	policy := cfg.Policy()
+	if policy.NoMentions {
+		mention = ""
+	}
+	if policy.NoLinks {
+		game.StoreURL = ""
+		withoutLink := *embed
+		withoutLink.URL = ""
+		embed = &withoutLink
+	}
+	fallback := cfg != nil && cfg.TextFallback
+
+	// Automatic announcements carry the admin "Mute this game" button
+	var components []discordgo.MessageComponent
+	if cfg != nil {
+		components = muteButton(game.Title)
+	}
+
+	// Only the announcement role may be pinged, never @everyone or users
+	allowedMentions := &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}}
+	if mention != "" && cfg != nil {
+		allowedMentions.Roles = []string{cfg.RoleID}
+	}
+
+	plainContent := formatPlainGame(game, cfg.Location())
+	if reminder := claimReminder(cfg, game); reminder != "" {
+		plainContent += "\n" + reminder
+	}
+	if mention != "" {
+		plainContent = mention + "\n" + plainContent
+	}
+	rich := &discordgo.MessageSend{
+		Content:         mention,
+		Embeds:          append([]*discordgo.MessageEmbed{embed}, galleryEmbeds(embed, game)...),
+		Components:      components,
+		AllowedMentions: allowedMentions,
+	}
+
+	format := database.FormatEmbed
+	if cfg != nil && cfg.MessageFormat != "" {
+		format = cfg.MessageFormat
+	}
+	if format == database.FormatPlain || format == database.FormatBoth {
+		linkContent := formatGameLink(game, cfg.Location())
+		if reminder := claimReminder(cfg, game); reminder != "" && format == database.FormatPlain {
+			linkContent += "\n" + reminder
+		}
+		if mention != "" {
+			linkContent = mention + "\n" + linkContent
+		}
+		rich.Content = linkContent
+		if format == database.FormatPlain {
+			rich.Embeds = nil
+			// Without embeds there is nothing for the text fallback to replace
+			fallback = false
+		}
+	}
+
+	plain := &discordgo.MessageSend{Content: plainContent, Components: components, AllowedMentions: allowedMentions}
+	if policy.TextOnly {
+		if rich.Embeds != nil {
+			rich = plain
+		}
+		rich.Flags = discordgo.MessageFlagsSuppressEmbeds
+		fallback = false
+	}
+	return gameMessage{rich: rich, plain: plain, fallback: fallback}
+}
+
+// sentMessage is a posted game message and how it was posted: through the
+// guild's webhook, and as the plain text fallback
+type sentMessage struct {
+	message *discordgo.Message
+	webhook bool
+	plain   bool
+}
+
+// sendGameMessage posts a game embed rendered by renderGameMessage. When the
+// guild opted into the text fallback and embeds are not allowed in the
+// channel, the game is posted as plain text instead so the announcement is
+// not lost. Discord 429s are retried by sendEmbedWithRetry. Guilds with a
+// webhook get the embeds through it, falling back to the channel if the
+// webhook fails.
+func (b *DiscordBot) sendGameMessage(channelID string, embed *discordgo.MessageEmbed, game models.Game, cfg *database.ServerConfig, mention string) (sentMessage, error) {
+	message := renderGameMessage(embed, game, cfg, mention)
+
+	// A configured webhook is preferred; the channel is the fallback
+	if cfg != nil && cfg.WebhookURL != "" {
+		sent, err := b.sendWebhookMessage(cfg.WebhookURL, message.rich)
+		if err == nil {
+			return sentMessage{message: sent, webhook: true}, nil
+		}
+		log.Printf("Webhook delivery failed for guild %s, falling back to channel %s: %v", cfg.GuildID, channelID, err)
+	}
+
+	if message.fallback && !b.embedsAllowed(channelID) {
+		sent, err := b.sendEmbedWithRetry(channelID, message.plain)
+		return sentMessage{message: sent, plain: true}, err
+	}
+
+	sent, err := b.sendEmbedWithRetry(channelID, message.rich)
+	if err != nil && message.fallback && isEmbedRejected(err) {
+		log.Printf("Embed rejected in channel %s, falling back to text for %s: %v", channelID, game.Title, err)
+		sent, err = b.sendEmbedWithRetry(channelID, message.plain)
+		return sentMessage{message: sent, plain: true}, err
+	}
+	return sentMessage{message: sent}, err
+}
+
+// galleryEmbeds returns image-only embeds for a game's extra images. Discord
+// merges embeds sharing the main embed's URL into one gallery, so games
+// without a store link or extra images get none.
+func galleryEmbeds(main *discordgo.MessageEmbed, game models.Game) []*discordgo.MessageEmbed {
+	if main.URL == "" {
+		return nil
+	}
+
+	var embeds []*discordgo.MessageEmbed
+	for _, image := range game.GalleryImages() {
+		embeds = append(embeds, &discordgo.MessageEmbed{
+			URL:   main.URL,
+			Image: &discordgo.MessageEmbedImage{URL: image},
+		})
+	}
+	return embeds
+}
+
+// handleTextFallbackCommand handles the /textfallback slash command
+func (b *DiscordBot) handleTextFallbackCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	serverConfig, err := b.database.GetServerConfig(i.GuildID)
+	if err != nil {
+		b.respondToInteraction(s, i, "Error checking server configuration.", true)
+		return
+	}
+	if serverConfig == nil {
+		b.respondToInteraction(s, i, "This server is not configured yet. Use /setup first.", true)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please specify whether the text fallback is enabled.", true)
+		return
+	}
+	enabled := options[0].BoolValue()
+
+	if err := b.database.SetTextFallback(i.GuildID, enabled); err != nil {
+		log.Printf("Error saving text fallback for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to save the setting. Please try again.", true)
+		return
+	}
+
+	if enabled {
+		b.respondToInteraction(s, i, "Text fallback enabled: if I can't post embeds in the notification channel, I'll send plain-text announcements instead.", false)
+	} else {
+		b.respondToInteraction(s, i, "Text fallback disabled: announcements are only sent as embeds.", false)
+	}
+	log.Printf("Server %s set text fallback to %t", i.GuildID, enabled)
+}
@@ -0,0 +1,162 @@
+package bot
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// rejectEmbeds makes fake Discord refuse messages with embeds the way it
+// does without the Embed Links permission, and accept plain ones
+func rejectEmbeds(request fakeRequest) (int, string) {
+	if embeds, _ := request.Body["embeds"].([]interface{}); len(embeds) > 0 {
+		return http.StatusForbidden, `{"code": 50013, "message": "Missing Permissions"}`
+	}
+	return 0, ""
+}
+
+func fallbackGame() models.Game {
+	return models.Game{
+		Title:    "Fallback Game",
+		Status:   models.StatusFreeNow,
+		FreeFrom: "Jul 17",
+		FreeTo:   "Jul 24",
+		StoreURL: "https://store.epicgames.com/p/fallback-game",
+	}
+}
+
+func TestSendGameMessageTextFallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		fallback  bool
+		reject    bool
+		wantPlain bool
+		wantErr   bool
+		wantSends int
+	}{
+		{name: "embeds allowed", fallback: true, wantSends: 1},
+		{name: "embeds rejected with fallback", fallback: true, reject: true, wantPlain: true, wantSends: 2},
+		{name: "embeds rejected without fallback", reject: true, wantErr: true, wantSends: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBot(t)
+			discord := useFakeDiscord(t, b)
+			if tt.reject {
+				discord.fail = rejectEmbeds
+			}
+
+			game := fallbackGame()
+			cfg := &database.ServerConfig{GuildID: "guild", ChannelID: "900", TextFallback: tt.fallback}
+			sent, err := b.sendGameMessage("900", &discordgo.MessageEmbed{Title: game.Title, URL: game.StoreURL}, game, cfg, "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sendGameMessage error = %v, want error %v", err, tt.wantErr)
+			}
+			if sent.plain != tt.wantPlain {
+				t.Errorf("sent as plain text = %v, want %v", sent.plain, tt.wantPlain)
+			}
+
+			sends := discord.find("POST", "channels/900/messages")
+			if len(sends) != tt.wantSends {
+				t.Fatalf("made %d sends, want %d", len(sends), tt.wantSends)
+			}
+			if !tt.wantPlain {
+				return
+			}
+			last := sends[len(sends)-1]
+			if embeds, _ := last.Body["embeds"].([]interface{}); len(embeds) != 0 {
+				t.Errorf("fallback message has %d embeds, want none", len(embeds))
+			}
+			content, _ := last.Body["content"].(string)
+			for _, want := range []string{game.Title, game.Status, "Free until: " + game.FreeTo, game.StoreURL} {
+				if !strings.Contains(content, want) {
+					t.Errorf("fallback message %q does not contain %q", content, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSendGameMessageSkipsEmbedsWithoutPermission(t *testing.T) {
+	b := newTestBot(t)
+	discord := useFakeDiscord(t, b)
+	discord.fail = rejectEmbeds
+
+	// The state cache shows the bot can send messages but not embed links
+	botID := b.session.State.User.ID
+	state := b.session.State
+	if err := state.GuildAdd(&discordgo.Guild{
+		ID:    "guild",
+		Roles: []*discordgo.Role{{ID: "guild", Permissions: discordgo.PermissionViewChannel | discordgo.PermissionSendMessages}},
+	}); err != nil {
+		t.Fatalf("GuildAdd: %v", err)
+	}
+	if err := state.ChannelAdd(&discordgo.Channel{ID: "900", GuildID: "guild", Type: discordgo.ChannelTypeGuildText}); err != nil {
+		t.Fatalf("ChannelAdd: %v", err)
+	}
+	if err := state.MemberAdd(&discordgo.Member{GuildID: "guild", User: &discordgo.User{ID: botID}}); err != nil {
+		t.Fatalf("MemberAdd: %v", err)
+	}
+
+	game := fallbackGame()
+	cfg := &database.ServerConfig{GuildID: "guild", ChannelID: "900", TextFallback: true}
+	sent, err := b.sendGameMessage("900", &discordgo.MessageEmbed{Title: game.Title, URL: game.StoreURL}, game, cfg, "")
+	if err != nil {
+		t.Fatalf("sendGameMessage: %v", err)
+	}
+	if !sent.plain {
+		t.Error("the game was not sent as plain text")
+	}
+	if sends := discord.find("POST", "channels/900/messages"); len(sends) != 1 {
+		t.Errorf("made %d sends, want only the plain one", len(sends))
+	}
+}
+
+func TestRenderGameMessageGallery(t *testing.T) {
+	bundle := fallbackGame()
+	bundle.ImageURL = "https://cdn.example/main.jpg"
+	bundle.Images = []string{bundle.ImageURL, "https://cdn.example/shot1.jpg", "https://cdn.example/shot2.jpg"}
+	single := fallbackGame()
+	single.ImageURL = "https://cdn.example/main.jpg"
+	single.Images = []string{single.ImageURL}
+
+	tests := []struct {
+		name        string
+		game        models.Game
+		noLinks     bool
+		wantGallery []string
+	}{
+		{name: "bundle", game: bundle, wantGallery: bundle.Images[1:]},
+		{name: "single image", game: single},
+		{name: "without a store link", game: bundle, noLinks: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &database.ServerConfig{GuildID: "guild", ChannelID: "900"}
+			if tt.noLinks {
+				cfg.ContentPolicy = database.PolicyNoLinks
+			}
+			embed := &discordgo.MessageEmbed{Title: tt.game.Title, URL: tt.game.StoreURL, Image: &discordgo.MessageEmbedImage{URL: tt.game.ImageURL}}
+			message := renderGameMessage(embed, tt.game, cfg, "")
+
+			embeds := message.rich.Embeds
+			if len(embeds) != 1+len(tt.wantGallery) {
+				t.Fatalf("rendered %d embeds, want the main one and %d gallery images", len(embeds), len(tt.wantGallery))
+			}
+			if embeds[0].Image == nil || embeds[0].Image.URL != tt.game.ImageURL {
+				t.Errorf("main embed image = %+v, want %s", embeds[0].Image, tt.game.ImageURL)
+			}
+			// Discord groups the gallery with the main embed by their shared URL
+			for i, image := range tt.wantGallery {
+				gallery := embeds[i+1]
+				if gallery.URL != embeds[0].URL || gallery.Image == nil || gallery.Image.URL != image {
+					t.Errorf("gallery embed %d = URL %s image %+v, want URL %s image %s", i, gallery.URL, gallery.Image, embeds[0].URL, image)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// gameFilter decides whether a game should be announced to a server. It
+// returns false together with the reason when the game must be skipped.
+type gameFilter func(cfg *database.ServerConfig, game models.Game) (bool, models.SkipReason)
+
+// deliveryFilters is the ordered filter pipeline applied to every game before
+// it is announced to a server. The first filter that rejects a game decides
+// the recorded skip reason. Filters needing per-server data load it here once
+// per server rather than once per game.
+func (b *DiscordBot) deliveryFilters(cfg *database.ServerConfig) []gameFilter {
+	return []gameFilter{
+		b.announcedFilter(cfg),
+		b.mutedFilter(cfg),
+		b.regionFilter(cfg),
+		sourceFilter,
+		comingSoonFilter,
+	}
+}
+
+// sourceFilter skips games from stores the server opted out of (/sources)
+func sourceFilter(cfg *database.ServerConfig, game models.Game) (bool, models.SkipReason) {
+	if !cfg.WantsSource(game.Source) {
+		return false, models.SkipReasonFilteredSource
+	}
+	return true, models.SkipReasonNone
+}
+
+// comingSoonFilter skips Coming Soon games for servers that chose to hear
+// about games only once they are free (/comingsoon)
+func comingSoonFilter(cfg *database.ServerConfig, game models.Game) (bool, models.SkipReason) {
+	if game.Status == models.StatusComingSoon && cfg.ComingSoonMode == database.ComingSoonReleaseOnly {
+		return false, models.SkipReasonComingSoonMode
+	}
+	return true, models.SkipReasonNone
+}
+
+// regionFilter skips games that are not free in the server's chosen region
+// (/region), or in EPIC_LOCALE when none was chosen
+func (b *DiscordBot) regionFilter(cfg *database.ServerConfig) gameFilter {
+	region := b.guildRegion(cfg)
+
+	return func(cfg *database.ServerConfig, game models.Game) (bool, models.SkipReason) {
+		if !game.AvailableIn(region) {
+			return false, models.SkipReasonRegion
+		}
+		return true, models.SkipReasonNone
+	}
+}
+
+// releaseFilters is the filter pipeline for release posts about Coming Soon
+// games that just became free. Servers in both mode were already told about
+// the game in advance, so only a repeated release post is skipped for them.
+func (b *DiscordBot) releaseFilters(cfg *database.ServerConfig) []gameFilter {
+	filters := []gameFilter{b.sentFilter(cfg, database.NotificationRelease)}
+	if cfg.ComingSoonMode == database.ComingSoonReleaseOnly {
+		filters = append(filters, b.announcedFilter(cfg))
+	}
+	return append(filters, b.mutedFilter(cfg), b.regionFilter(cfg), sourceFilter)
+}
+
+// reminderFilters is the filter pipeline for last-chance reminders. They are
+// about games the server was told about, so unlike announcements they are
+// not skipped once the game was announced.
+func (b *DiscordBot) reminderFilters(cfg *database.ServerConfig) []gameFilter {
+	return []gameFilter{b.mutedFilter(cfg), b.regionFilter(cfg), sourceFilter}
+}
+
+// announcedFilter skips games already announced to the server or marked
+// seen with /markseen
+func (b *DiscordBot) announcedFilter(cfg *database.ServerConfig) gameFilter {
+	return b.sentFilter(cfg, database.NotificationAnnouncement)
+}
+
+// sentFilter skips games the server already got a notification of kind for
+func (b *DiscordBot) sentFilter(cfg *database.ServerConfig, kind string) gameFilter {
+	sent, err := b.database.GetSentNotifications(cfg.GuildID, kind)
+	if err != nil {
+		log.Printf("Error loading %s notifications for guild %s: %v", kind, cfg.GuildID, err)
+	}
+
+	return func(cfg *database.ServerConfig, game models.Game) (bool, models.SkipReason) {
+		if sent[database.NotificationKey(game)] {
+			return false, models.SkipReasonRepeatPolicy
+		}
+		return true, models.SkipReasonNone
+	}
+}
+
+// mutedFilter skips games a server admin muted with /mute or the Mute button
+func (b *DiscordBot) mutedFilter(cfg *database.ServerConfig) gameFilter {
+	muted, err := b.database.GetMutedTitles(cfg.GuildID)
+	if err != nil {
+		log.Printf("Error loading muted games for guild %s: %v", cfg.GuildID, err)
+	}
+
+	return func(cfg *database.ServerConfig, game models.Game) (bool, models.SkipReason) {
+		if muted[game.Title] {
+			return false, models.SkipReasonMuted
+		}
+		return true, models.SkipReasonNone
+	}
+}
+
+// filterGames runs the delivery pipeline over a collection, returning the games
+// that should be sent and a decision for each game that was skipped
+func (b *DiscordBot) filterGames(cfg *database.ServerConfig, games []models.Game) ([]models.Game, map[string]models.SkipReason) {
+	var filters []gameFilter
+	if cfg != nil {
+		filters = b.deliveryFilters(cfg)
+	}
+	return applyFilters(cfg, filters, games)
+}
+
+// filterReleases runs the release pipeline over games that just became free
+func (b *DiscordBot) filterReleases(cfg *database.ServerConfig, games []models.Game) ([]models.Game, map[string]models.SkipReason) {
+	var filters []gameFilter
+	if cfg != nil {
+		filters = b.releaseFilters(cfg)
+	}
+	return applyFilters(cfg, filters, games)
+}
+
+// filterReminders runs the reminder pipeline over games ending soon
+func (b *DiscordBot) filterReminders(cfg *database.ServerConfig, games []models.Game) ([]models.Game, map[string]models.SkipReason) {
+	var filters []gameFilter
+	if cfg != nil {
+		filters = b.reminderFilters(cfg)
+	}
+	return applyFilters(cfg, filters, games)
+}
+
+// applyFilters splits games into those every filter accepts and the skip
+// reason of each rejected game, keyed by its database.NotificationKey
+func applyFilters(cfg *database.ServerConfig, filters []gameFilter, games []models.Game) ([]models.Game, map[string]models.SkipReason) {
+	accepted := make([]models.Game, 0, len(games))
+	skipped := make(map[string]models.SkipReason)
+
+	for _, game := range games {
+		allowed, reason := true, models.SkipReasonNone
+		if cfg != nil {
+			for _, filter := range filters {
+				if allowed, reason = filter(cfg, game); !allowed {
+					break
+				}
+			}
+		}
+
+		if allowed {
+			accepted = append(accepted, game)
+		} else {
+			skipped[database.NotificationKey(game)] = reason
+		}
+	}
+
+	return accepted, skipped
+}
+
+// classifySendError maps a Discord send error onto a skip reason
+func classifySendError(err error) models.SkipReason {
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) {
+		if restErr.Message != nil {
+			switch restErr.Message.Code {
+			case discordgo.ErrCodeMissingAccess, discordgo.ErrCodeMissingPermissions:
+				return models.SkipReasonMissingPermissions
+			case discordgo.ErrCodeUnknownChannel:
+				return models.SkipReasonChannelNotFound
+			}
+		}
+		if restErr.Response != nil {
+			switch restErr.Response.StatusCode {
+			case http.StatusTooManyRequests:
+				return models.SkipReasonRateLimited
+			case http.StatusForbidden:
+				return models.SkipReasonMissingPermissions
+			case http.StatusNotFound:
+				return models.SkipReasonChannelNotFound
+			}
+		}
+	}
+
+	var rateLimitErr *discordgo.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return models.SkipReasonRateLimited
+	}
+
+	return models.SkipReasonSendFailed
+}
+
+// handleStatusRecent handles /status view:recent, listing the decisions made
+// for this server during the last delivery cycle
+func (b *DiscordBot) handleStatusRecent(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	decisions, err := b.database.GetLatestDeliveryDecisions(i.GuildID)
+	if err != nil {
+		log.Printf("Error loading delivery decisions for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Error loading recent deliveries.", true)
+		return
+	}
+
+	if len(decisions) == 0 {
+		b.respondToInteraction(s, i, "No announcements have been attempted for this server yet.", true)
+		return
+	}
+
+	var sb strings.Builder
+	for _, decision := range decisions {
+		icon := "✅"
+		if !decision.Delivered {
+			icon = "⏭️"
+		}
+		line := fmt.Sprintf("%s **%s** — %s\n", icon, decision.GameTitle, decision.Reason.Description())
+		if sb.Len()+len(line) > maxFieldLength*4 {
+			sb.WriteString("…")
+			break
+		}
+		sb.WriteString(line)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Last Delivery Cycle",
+		Description: sb.String(),
+		Color:       b.branding().AccentColor,
+		Timestamp:   decisions[0].DecidedAt.Format(time.RFC3339),
+		Footer:      brandFooter(b.branding()),
+	}
+	if policy := decisions[0].Policy; policy != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Content Policy",
+			Value: "Announcements were rendered under: " + describePolicy(policy),
+		})
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to status recent command: %v", err)
+	}
+}
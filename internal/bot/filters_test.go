@@ -0,0 +1,331 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+func TestFiltersRecordTheirReason(t *testing.T) {
+	const guildID = "guild"
+	comingSoon := runningGame("Upcoming")
+	comingSoon.Status = models.StatusComingSoon
+	gog := runningGame("Drm Free")
+	gog.Source = models.SourceGOG
+	usOnly := runningGame("Us Only")
+	usOnly.Regions = []string{"en-US"}
+
+	tests := []struct {
+		name    string
+		game    models.Game
+		release bool
+		setup   func(db *database.Database, game models.Game) error
+		want    models.SkipReason
+	}{
+		{
+			name: "accepted",
+			game: runningGame("Fresh"),
+			want: models.SkipReasonNone,
+		},
+		{
+			name: "already announced",
+			game: runningGame("Announced"),
+			setup: func(db *database.Database, game models.Game) error {
+				_, err := db.MarkNotificationsSent(guildID, database.NotificationAnnouncement, []models.Game{game})
+				return err
+			},
+			want: models.SkipReasonRepeatPolicy,
+		},
+		{
+			name:    "release already posted",
+			game:    runningGame("Released"),
+			release: true,
+			setup: func(db *database.Database, game models.Game) error {
+				_, err := db.MarkNotificationsSent(guildID, database.NotificationRelease, []models.Game{game})
+				return err
+			},
+			want: models.SkipReasonRepeatPolicy,
+		},
+		{
+			name: "muted",
+			game: runningGame("Muted"),
+			setup: func(db *database.Database, game models.Game) error {
+				return db.MuteGame(guildID, game.Title, "admin")
+			},
+			want: models.SkipReasonMuted,
+		},
+		{
+			name: "other region",
+			game: usOnly,
+			setup: func(db *database.Database, game models.Game) error {
+				return db.SetRegion(guildID, "de-DE")
+			},
+			want: models.SkipReasonRegion,
+		},
+		{
+			name: "store opted out",
+			game: gog,
+			setup: func(db *database.Database, game models.Game) error {
+				return db.SetSources(guildID, models.SourceEpic)
+			},
+			want: models.SkipReasonFilteredSource,
+		},
+		{
+			name: "coming soon in release-only mode",
+			game: comingSoon,
+			setup: func(db *database.Database, game models.Game) error {
+				return db.SetComingSoonMode(guildID, database.ComingSoonReleaseOnly)
+			},
+			want: models.SkipReasonComingSoonMode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBot(t)
+			if _, err := b.database.SaveServerConfig(guildID, "channel"); err != nil {
+				t.Fatalf("SaveServerConfig: %v", err)
+			}
+			if tt.setup != nil {
+				if err := tt.setup(b.database, tt.game); err != nil {
+					t.Fatalf("setup: %v", err)
+				}
+			}
+			cfg, err := b.database.GetServerConfig(guildID)
+			if err != nil || cfg == nil {
+				t.Fatalf("GetServerConfig: %v, %v", cfg, err)
+			}
+
+			filter := b.filterGames
+			if tt.release {
+				filter = b.filterReleases
+			}
+			accepted, skipped := filter(cfg, []models.Game{tt.game})
+
+			if tt.want == models.SkipReasonNone {
+				if len(accepted) != 1 || len(skipped) != 0 {
+					t.Fatalf("accepted %v and skipped %v, want the game accepted", titles(accepted), skipped)
+				}
+				return
+			}
+			if len(accepted) != 0 {
+				t.Errorf("accepted %v, want the game skipped", titles(accepted))
+			}
+			if got := skipped[database.NotificationKey(tt.game)]; got != tt.want {
+				t.Errorf("skip reason = %q, want %q (skipped %v)", got, tt.want, skipped)
+			}
+		})
+	}
+}
+
+func TestDecideTellsPromotionsOfOneTitleApart(t *testing.T) {
+	b := newTestBot(t)
+	const guildID = "guild"
+	if _, err := b.database.SaveServerConfig(guildID, "channel"); err != nil {
+		t.Fatalf("SaveServerConfig: %v", err)
+	}
+
+	// The game was given away before and is free again until a new date
+	earlier := runningGame("Returning")
+	earlier.FreeTo = "Jan 2"
+	again := runningGame("Returning")
+	if _, err := b.database.MarkNotificationsSent(guildID, database.NotificationAnnouncement, []models.Game{earlier}); err != nil {
+		t.Fatalf("MarkNotificationsSent: %v", err)
+	}
+	cfg, err := b.database.GetServerConfig(guildID)
+	if err != nil || cfg == nil {
+		t.Fatalf("GetServerConfig: %v, %v", cfg, err)
+	}
+
+	games := []models.Game{earlier, again}
+	accepted, skipped := b.filterGames(cfg, games)
+	if len(accepted) != 1 || accepted[0].FreeTo != again.FreeTo {
+		t.Fatalf("accepted %v, want only the new promotion", accepted)
+	}
+
+	job := deliveryJob{cycleID: "cycle", guildID: guildID, channelID: cfg.ChannelID, config: cfg}
+	decisions := b.decide(job, games, skipped, len(accepted), models.SkipReasonNone)
+	if len(decisions) != 2 {
+		t.Fatalf("got %d decisions, want 2", len(decisions))
+	}
+	if decisions[0].Delivered || decisions[0].Reason != models.SkipReasonRepeatPolicy {
+		t.Errorf("earlier promotion: delivered %v, reason %q, want skipped as a repeat", decisions[0].Delivered, decisions[0].Reason)
+	}
+	if !decisions[1].Delivered {
+		t.Errorf("new promotion was not delivered: %q", decisions[1].Reason)
+	}
+}
+
+func TestMutedGameIsLeftOutOfEveryPost(t *testing.T) {
+	b := newTestBot(t)
+	discord := useFakeDiscord(t, b)
+	const guildID = "guild-muted"
+	if _, err := b.database.SaveServerConfig(guildID, "900"); err != nil {
+		t.Fatalf("SaveServerConfig: %v", err)
+	}
+	if err := b.database.SetExpiryReminders(guildID, true); err != nil {
+		t.Fatalf("SetExpiryReminders: %v", err)
+	}
+	if err := b.database.MuteGame(guildID, "Drama", "admin"); err != nil {
+		t.Fatalf("MuteGame: %v", err)
+	}
+	cfg, err := b.database.GetServerConfig(guildID)
+	if err != nil || cfg == nil {
+		t.Fatalf("GetServerConfig: %v, %v", cfg, err)
+	}
+
+	muted, calm := runningGame("Drama"), runningGame("Calm")
+	for _, game := range []*models.Game{&muted, &calm} {
+		game.FreeToTime = time.Now().Add(12 * time.Hour)
+	}
+	games := []models.Game{muted, calm}
+
+	// mentions reports whether a post to the channel names the muted game
+	mentionsMuted := func() bool {
+		for _, request := range discord.find("POST", "channels/900/messages") {
+			if strings.Contains(fmt.Sprint(request.Body), "Drama") {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("announcement", func(t *testing.T) {
+		result := b.deliverToChannel(b.ctx, deliveryJob{guildID: guildID, channelID: "900", config: cfg, games: &models.GameCollection{FreeNow: games}})
+		if result.err != nil {
+			t.Fatalf("deliverToChannel: %v", result.err)
+		}
+		if mentionsMuted() {
+			t.Error("the muted game was announced")
+		}
+		if len(result.decisions) != 2 || result.decisions[0].Delivered || result.decisions[0].Reason != models.SkipReasonMuted {
+			t.Errorf("decisions = %+v, want the muted game skipped as muted", result.decisions)
+		}
+		if !result.decisions[1].Delivered {
+			t.Errorf("the other game was not delivered: %q", result.decisions[1].Reason)
+		}
+	})
+
+	t.Run("release", func(t *testing.T) {
+		result := b.deliverToChannel(b.ctx, deliveryJob{guildID: guildID, channelID: "900", config: cfg, games: &models.GameCollection{FreeNow: games}, release: true})
+		if mentionsMuted() {
+			t.Error("the muted game got a release post")
+		}
+		if len(result.decisions) == 0 || result.decisions[0].Reason != models.SkipReasonMuted {
+			t.Errorf("decisions = %+v, want the muted game skipped as muted", result.decisions)
+		}
+	})
+
+	t.Run("expiry reminder", func(t *testing.T) {
+		before := len(discord.find("POST", "channels/900/messages"))
+		b.SendExpiryReminders(b.ctx, games)
+		if mentionsMuted() {
+			t.Error("the muted game got a last chance reminder")
+		}
+		if sent := len(discord.find("POST", "channels/900/messages")) - before; sent != 1 {
+			t.Errorf("sent %d reminders, want one for the other game", sent)
+		}
+	})
+}
+
+func TestClassifySendError(t *testing.T) {
+	// Responses captured from Discord for failed message sends
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   models.SkipReason
+	}{
+		{
+			name:   "missing permissions",
+			status: http.StatusForbidden,
+			body:   `{"message": "Missing Permissions", "code": 50013}`,
+			want:   models.SkipReasonMissingPermissions,
+		},
+		{
+			name:   "missing access",
+			status: http.StatusForbidden,
+			body:   `{"message": "Missing Access", "code": 50001}`,
+			want:   models.SkipReasonMissingPermissions,
+		},
+		{
+			name:   "forbidden without a code",
+			status: http.StatusForbidden,
+			body:   `<html><body>403 Forbidden</body></html>`,
+			want:   models.SkipReasonMissingPermissions,
+		},
+		{
+			name:   "unknown channel",
+			status: http.StatusNotFound,
+			body:   `{"message": "Unknown Channel", "code": 10003}`,
+			want:   models.SkipReasonChannelNotFound,
+		},
+		{
+			name:   "not found without a code",
+			status: http.StatusNotFound,
+			body:   `{"message": "404: Not Found", "code": 0}`,
+			want:   models.SkipReasonChannelNotFound,
+		},
+		{
+			name:   "rate limited",
+			status: http.StatusTooManyRequests,
+			body:   `{"message": "You are being rate limited.", "retry_after": 0.5, "global": false}`,
+			want:   models.SkipReasonRateLimited,
+		},
+		{
+			name:   "empty message",
+			status: http.StatusBadRequest,
+			body:   `{"message": "Cannot send an empty message", "code": 50006}`,
+			want:   models.SkipReasonSendFailed,
+		},
+		{
+			name:   "invalid form body",
+			status: http.StatusBadRequest,
+			body:   `{"message": "Invalid Form Body", "code": 50035, "errors": {"embeds": {"0": {"description": {"_errors": [{"code": "BASE_TYPE_MAX_LENGTH", "message": "Must be 4096 or fewer in length."}]}}}}}`,
+			want:   models.SkipReasonSendFailed,
+		},
+		{
+			name:   "server error",
+			status: http.StatusInternalServerError,
+			body:   `{"message": "500: Internal Server Error", "code": 0}`,
+			want:   models.SkipReasonSendFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBot(t)
+			discord := useFakeDiscord(t, b)
+			// Report a 429 as an error rather than waiting it out
+			b.session.ShouldRetryOnRateLimit = false
+			discord.fail = func(fakeRequest) (int, string) {
+				return tt.status, tt.body
+			}
+
+			_, err := b.session.ChannelMessageSend("900", "hello")
+			if err == nil {
+				t.Fatal("send succeeded, want the fixture's error")
+			}
+			if got := classifySendError(err); got != tt.want {
+				t.Errorf("classifySendError(%v) = %q, want %q", err, got, tt.want)
+			}
+			if got := classifySendError(fmt.Errorf("error sending message: %w", err)); got != tt.want {
+				t.Errorf("classifySendError of the wrapped error = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// A 429 can also surface as a REST error that carries only the status
+	limited := &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusTooManyRequests}}
+	if got := classifySendError(limited); got != models.SkipReasonRateLimited {
+		t.Errorf("classifySendError of a 429 REST error = %q, want %q", got, models.SkipReasonRateLimited)
+	}
+	if got := classifySendError(errors.New("connection reset by peer")); got != models.SkipReasonSendFailed {
+		t.Errorf("classifySendError of a transport error = %q, want %q", got, models.SkipReasonSendFailed)
+	}
+}
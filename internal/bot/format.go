@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+)
+
+// formatDescriptions explains each /format style to admins
+var formatDescriptions = map[string]string{
+	database.FormatEmbed: "Games are posted as rich embeds.",
+	database.FormatPlain: "Games are posted as a one-line summary and the store link, with Discord's own link preview.",
+	database.FormatBoth:  "Games are posted as a one-line summary and the store link above the embed.",
+}
+
+// handleFormatCommand handles /format, choosing how the server's game
+// announcements are rendered
+func (b *DiscordBot) handleFormatCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	serverConfig, err := b.database.GetServerConfig(i.GuildID)
+	if err != nil {
+		b.respondToInteraction(s, i, "Error checking server configuration.", true)
+		return
+	}
+	if serverConfig == nil {
+		b.respondToInteraction(s, i, "This server is not configured yet. Use /setup first.", true)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please choose a format.", true)
+		return
+	}
+	format := options[0].StringValue()
+	description, ok := formatDescriptions[format]
+	if !ok {
+		b.respondToInteraction(s, i, "Unknown format. Choose embed, plain or both.", true)
+		return
+	}
+
+	candidate := *serverConfig
+	candidate.MessageFormat = format
+	if err := candidate.PolicyConflict(); err != nil {
+		b.respondToInteraction(s, i, fmt.Sprintf("Can't switch to **%s**: %v. Change the content policy with `/customize policy` first.", format, err), true)
+		return
+	}
+
+	if err := b.database.SetMessageFormat(i.GuildID, format); err != nil {
+		log.Printf("Error saving message format for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to save the setting. Please try again.", true)
+		return
+	}
+
+	b.respondToInteraction(s, i, fmt.Sprintf("Announcement format set to **%s**. %s", format, description), false)
+	log.Printf("Server %s set message format to %s", i.GuildID, format)
+}
@@ -0,0 +1,78 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+func TestDeliverRendersMessageFormat(t *testing.T) {
+	tests := []struct {
+		format     string
+		wantEmbeds bool
+		wantLink   bool
+	}{
+		{format: "", wantEmbeds: true},
+		{format: database.FormatEmbed, wantEmbeds: true},
+		{format: database.FormatPlain, wantLink: true},
+		{format: database.FormatBoth, wantEmbeds: true, wantLink: true},
+	}
+	for _, tt := range tests {
+		name := tt.format
+		if name == "" {
+			name = "default"
+		}
+		t.Run(name, func(t *testing.T) {
+			b := newTestBot(t)
+			discord := useFakeDiscord(t, b)
+			const guildID = "guild"
+			if _, err := b.database.SaveServerConfig(guildID, "900"); err != nil {
+				t.Fatalf("SaveServerConfig: %v", err)
+			}
+			if err := b.database.SetMentionRole(guildID, "700"); err != nil {
+				t.Fatalf("SetMentionRole: %v", err)
+			}
+			if tt.format != "" {
+				if err := b.database.SetMessageFormat(guildID, tt.format); err != nil {
+					t.Fatalf("SetMessageFormat: %v", err)
+				}
+			}
+			cfg, err := b.database.GetServerConfig(guildID)
+			if err != nil || cfg == nil {
+				t.Fatalf("GetServerConfig: %v, %v", cfg, err)
+			}
+
+			game := runningGame("Formatted")
+			result := b.deliverToChannel(b.ctx, deliveryJob{guildID: guildID, channelID: "900", config: cfg, games: &models.GameCollection{FreeNow: []models.Game{game}}})
+			if result.err != nil {
+				t.Fatalf("deliverToChannel: %v", result.err)
+			}
+
+			var post *fakeRequest
+			for _, request := range discord.find("POST", "channels/900/messages") {
+				if strings.Contains(fmt.Sprint(request.Body), game.Title) {
+					post = &request
+				}
+			}
+			if post == nil {
+				t.Fatalf("no message about %s was posted", game.Title)
+			}
+
+			embeds, _ := post.Body["embeds"].([]interface{})
+			if got := len(embeds) > 0; got != tt.wantEmbeds {
+				t.Errorf("posted %d embeds, want embeds %v", len(embeds), tt.wantEmbeds)
+			}
+			content, _ := post.Body["content"].(string)
+			if !strings.HasPrefix(content, "<@&700>") {
+				t.Errorf("content %q does not start with the role mention", content)
+			}
+			wantSummary := "**" + game.Title + "** is free on"
+			if got := strings.Contains(content, wantSummary) && strings.Contains(content, game.StoreURL); got != tt.wantLink {
+				t.Errorf("content %q carries the summary and store link: %v, want %v", content, got, tt.wantLink)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/models"
+)
+
+// maxAutocompleteChoices is Discord's limit on autocomplete suggestions
+const maxAutocompleteChoices = 25
+
+// autocompleteHandler answers autocomplete requests for slash command options
+func (b *DiscordBot) autocompleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.ApplicationCommandData().Name {
+	case "gameinfo", "mute":
+		b.handleGameTitleAutocomplete(s, i)
+	case "unmute":
+		b.handleMutedTitleAutocomplete(s, i)
+	case "settings":
+		b.handlePresetAutocomplete(s, i)
+	}
+}
+
+// focusedValue returns what the user typed so far into the option being
+// autocompleted, looking inside subcommands
+func focusedValue(options []*discordgo.ApplicationCommandInteractionDataOption) string {
+	for _, option := range options {
+		if option.Focused {
+			return option.StringValue()
+		}
+		if option.Type == discordgo.ApplicationCommandOptionSubCommand {
+			if value := focusedValue(option.Options); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// handleGameTitleAutocomplete suggests stored game titles starting with what
+// the user typed so far
+func (b *DiscordBot) handleGameTitleAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	titles, err := b.database.SearchGamesByTitle(focusedValue(i.ApplicationCommandData().Options), maxAutocompleteChoices)
+	if err != nil {
+		log.Printf("Error searching game titles: %v", err)
+	}
+	b.respondWithTitleChoices(s, i, titles)
+}
+
+// handleMutedTitleAutocomplete suggests the server's muted game titles
+// starting with what the user typed so far
+func (b *DiscordBot) handleMutedTitleAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	prefix := strings.ToLower(focusedValue(i.ApplicationCommandData().Options))
+	muted, err := b.database.GetMutedGames(i.GuildID)
+	if err != nil {
+		log.Printf("Error loading muted games for guild %s: %v", i.GuildID, err)
+	}
+
+	var titles []string
+	for _, m := range muted {
+		if strings.HasPrefix(strings.ToLower(m.GameTitle), prefix) {
+			titles = append(titles, m.GameTitle)
+		}
+		if len(titles) == maxAutocompleteChoices {
+			break
+		}
+	}
+	b.respondWithTitleChoices(s, i, titles)
+}
+
+// respondWithTitleChoices answers an autocomplete request with game titles
+// or other names
+func (b *DiscordBot) respondWithTitleChoices(s *discordgo.Session, i *discordgo.InteractionCreate, titles []string) {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(titles))
+	for _, title := range titles {
+		// Choice names and values are limited to 100 characters
+		if len(title) > 100 {
+			continue
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: title, Value: title})
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to autocomplete: %v", err)
+	}
+}
+
+// handleGameInfoCommand handles /gameinfo, showing one stored game in detail
+func (b *DiscordBot) handleGameInfoCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please provide a game title.", true)
+		return
+	}
+	input := options[0].StringValue()
+
+	game, err := b.gameService.GetGameByTitle(input)
+	if err == nil && game == nil {
+		// Typed titles may differ in case from the stored one
+		var title string
+		if title, err = b.resolveGameTitle(input); err == nil && title != "" {
+			game, err = b.gameService.GetGameByTitle(title)
+		}
+	}
+	if err != nil {
+		log.Printf("Error loading game %q: %v", input, err)
+		b.respondToInteraction(s, i, "Failed to load the game. Please try again.", true)
+		return
+	}
+	if game == nil {
+		b.respondToInteraction(s, i, fmt.Sprintf("No game titled **%s** is known. Pick one of the suggestions while typing.", input), true)
+		return
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{b.gameInfoEmbed(*game, b.guildLocation(i.GuildID))},
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to gameinfo command: %v", err)
+	}
+}
+
+// gameInfoEmbed builds the detailed embed for a single game, showing its
+// free period in location
+func (b *DiscordBot) gameInfoEmbed(game models.Game, location *time.Location) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:  game.Title,
+		URL:    game.StoreURL,
+		Color:  b.branding().AccentColor,
+		Footer: gameFooter(b.branding(), game),
+	}
+	if game.Status == models.StatusFreeNow {
+		embed.Color = 0x00ff00 // Green color
+	}
+	embed.Color = sourceColor(game, embed.Color)
+
+	if game.ImageURL != "" {
+		embed.Image = &discordgo.MessageEmbedImage{
+			URL: game.ImageURL,
+		}
+	}
+
+	status := game.Status
+	if game.Status == models.StatusFreeNow && !game.IsActive() {
+		status = "Ended"
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Status",
+		Value:  status,
+		Inline: true,
+	})
+
+	freeFrom := promoTime(game.FreeFromTime, game.FreeFrom, location)
+	freeTo := promoTime(game.FreeToTime, game.FreeTo, location)
+	if freeFrom != "" || freeTo != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Free Period",
+			Value:  fmt.Sprintf("%s - %s", orUnknown(freeFrom), orUnknown(freeTo)),
+			Inline: true,
+		})
+	}
+
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Store",
+		Value:  game.SourceName(),
+		Inline: true,
+	})
+
+	if game.StoreURL != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Store Page",
+			Value: fmt.Sprintf("[Open on %s](%s)", game.SourceName(), game.StoreURL),
+		})
+	}
+
+	return embed
+}
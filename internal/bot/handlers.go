@@ -0,0 +1,65 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/logger"
+)
+
+// safeHandler wraps a discordgo event handler so that at most
+// MaxConcurrentHandlers run at once and a panic inside the handler is logged
+// and counted instead of crashing the process.
+func safeHandler[E any](b *DiscordBot, name string, handler func(*discordgo.Session, E)) func(*discordgo.Session, E) {
+	return func(s *discordgo.Session, event E) {
+		b.handlerSem <- struct{}{}
+		defer func() { <-b.handlerSem }()
+
+		defer func() {
+			if r := recover(); r != nil {
+				b.metrics.IncrementErrors()
+				b.logger.WithFields(map[string]interface{}{
+					"handler": name,
+					"panic":   fmt.Sprint(r),
+					"stack":   string(debug.Stack()),
+				}).Error("Recovered from panic in event handler")
+			}
+		}()
+
+		handler(s, event)
+	}
+}
+
+// commandLogger is a logger scoped to a single command invocation
+type commandLogger struct {
+	*logger.Logger
+	ctx   context.Context
+	start time.Time
+}
+
+// requestLogger assigns a request ID to a command invocation and logs its start.
+// Call done when the command finishes to log its duration under the same ID.
+func (b *DiscordBot) requestLogger(command, guildID, userID string) *commandLogger {
+	ctx := logger.ContextWithRequestID(b.ctx, logger.NewRequestID())
+	ctx = logger.ContextWithGuildID(ctx, guildID)
+	ctx = logger.ContextWithUserID(ctx, userID)
+	l := &commandLogger{
+		Logger: b.logger.WithContext(ctx).WithFields(map[string]interface{}{
+			"command": command,
+		}),
+		ctx:   ctx,
+		start: time.Now(),
+	}
+	l.Info("Handling command")
+	return l
+}
+
+// done logs the completion of the command
+func (l *commandLogger) done() {
+	l.WithFields(map[string]interface{}{
+		"duration_ms": time.Since(l.start).Milliseconds(),
+	}).Info("Command handled")
+}
@@ -0,0 +1,85 @@
+package bot
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/logger"
+)
+
+func TestSafeHandlerRecoversPanic(t *testing.T) {
+	b := newTestBot(t)
+	var logged bytes.Buffer
+	b.logger = logger.NewWithOutput(logger.LevelError, "production", &logged)
+
+	panicking := safeHandler(b, "message_create", func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		var fields []*discordgo.MessageEmbedField
+		_ = fields[0]
+	})
+	for i := 0; i < cap(b.handlerSem)+1; i++ {
+		panicking(nil, &discordgo.MessageCreate{})
+	}
+
+	if got := b.metrics.GetErrors(); got != int64(cap(b.handlerSem)+1) {
+		t.Errorf("GetErrors = %d, want %d", got, cap(b.handlerSem)+1)
+	}
+
+	var entry map[string]interface{}
+	if err := json.NewDecoder(&logged).Decode(&entry); err != nil {
+		t.Fatalf("decoding the panic log: %v", err)
+	}
+	if entry["handler"] != "message_create" {
+		t.Errorf("handler = %v, want message_create", entry["handler"])
+	}
+	if panicText, _ := entry["panic"].(string); !strings.Contains(panicText, "index out of range") {
+		t.Errorf("panic = %q, want the runtime error", panicText)
+	}
+	if stack, _ := entry["stack"].(string); !strings.Contains(stack, "TestSafeHandlerRecoversPanic") {
+		t.Errorf("stack does not lead back to the handler:\n%s", stack)
+	}
+
+	// Every panic released its slot, so handlers keep running afterwards
+	ran := false
+	safeHandler(b, "ready", func(s *discordgo.Session, r *discordgo.Ready) { ran = true })(nil, &discordgo.Ready{})
+	if !ran {
+		t.Error("a handler did not run after earlier handlers panicked")
+	}
+}
+
+func TestSafeHandlerBoundsConcurrency(t *testing.T) {
+	b := newTestBot(t)
+	limit := cap(b.handlerSem)
+
+	var running, peak atomic.Int32
+	handler := safeHandler(b, "message_create", func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		now := running.Add(1)
+		for {
+			seen := peak.Load()
+			if now <= seen || peak.CompareAndSwap(seen, now) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < limit*4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handler(nil, &discordgo.MessageCreate{})
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > int32(limit) {
+		t.Errorf("%d handlers ran at once, want at most %d", got, limit)
+	}
+}
@@ -0,0 +1,137 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+)
+
+// impactCacheTTL is how long a guild's /impact aggregates are reused
+const impactCacheTTL = time.Hour
+
+// impactPeriod is the window /impact covers. Delivery records are kept for
+// DeliveryDecisionRetentionDays, so a longer window would undercount.
+const impactPeriod = database.DeliveryDecisionRetentionDays * 24 * time.Hour
+
+// impactCache keeps each guild's aggregates for impactCacheTTL
+type impactCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedImpact
+}
+
+// cachedImpact is one guild's aggregates and when they were computed
+type cachedImpact struct {
+	impact     *database.GuildImpact
+	computedAt time.Time
+}
+
+// guildImpact returns a guild's aggregates over impactPeriod, from the cache
+// when they are younger than impactCacheTTL
+func (b *DiscordBot) guildImpact(guildID string) (*database.GuildImpact, time.Time, error) {
+	b.impact.mu.Lock()
+	defer b.impact.mu.Unlock()
+
+	now := time.Now()
+	if cached, ok := b.impact.entries[guildID]; ok && now.Sub(cached.computedAt) < impactCacheTTL {
+		return cached.impact, cached.computedAt, nil
+	}
+
+	impact, err := b.database.GetGuildImpact(guildID, now.Add(-impactPeriod))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if b.impact.entries == nil {
+		b.impact.entries = make(map[string]cachedImpact)
+	}
+	for id, cached := range b.impact.entries {
+		if now.Sub(cached.computedAt) >= impactCacheTTL {
+			delete(b.impact.entries, id)
+		}
+	}
+	b.impact.entries[guildID] = cachedImpact{impact: impact, computedAt: now}
+	return impact, now, nil
+}
+
+// handleImpactCommand handles /impact, summarizing what the bot delivered to
+// this server recently
+func (b *DiscordBot) handleImpactCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		b.respondToInteraction(s, i, "This command can only be used in a server.", true)
+		return
+	}
+
+	serverConfig, err := b.database.GetServerConfig(i.GuildID)
+	if err != nil {
+		b.respondToInteraction(s, i, "Error checking server configuration.", true)
+		return
+	}
+	if serverConfig == nil {
+		b.respondToInteraction(s, i, "This server is not configured yet. Use /setup first.", true)
+		return
+	}
+
+	impact, computedAt, err := b.guildImpact(i.GuildID)
+	if err != nil {
+		log.Printf("Error loading impact for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to load this server's stats. Please try again.", true)
+		return
+	}
+
+	days := int(impactPeriod / (24 * time.Hour))
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("Impact in the last %d days", days),
+		Color: b.branding().AccentColor,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Updated hourly",
+		},
+		Timestamp: computedAt.Format(time.RFC3339),
+	}
+
+	if !impact.HasHistory() {
+		embed.Description = fmt.Sprintf("Nothing has been announced in <#%s> in the last %d days yet. Stats appear after the next free game is posted.", serverConfig.ChannelID, days)
+	} else {
+		embed.Description = fmt.Sprintf("Announcements in <#%s>.", serverConfig.ChannelID)
+		embed.Fields = []*discordgo.MessageEmbedField{
+			{
+				Name:   "Announcements",
+				Value:  fmt.Sprintf("%s (%s)", pluralize(impact.Announcements, "post"), pluralize(impact.Games, "game")),
+				Inline: true,
+			},
+			{
+				Name:   "Last-Chance Reminders",
+				Value:  fmt.Sprintf("%d", impact.Reminders),
+				Inline: true,
+			},
+		}
+		if impact.MemberCount > 0 {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:   "Members Reached",
+				Value:  fmt.Sprintf("~%d", impact.MemberCount),
+				Inline: true,
+			})
+		}
+		if impact.Skipped > 0 {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:   "Skipped",
+				Value:  fmt.Sprintf("%s (see /status view:recent)", pluralize(impact.Skipped, "game")),
+				Inline: true,
+			})
+		}
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to impact command: %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package bot
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"free-games-scrape/internal/models"
+)
+
+// linkCheckInterval is how often a pending game's store page is polled
+const linkCheckInterval = 15 * time.Second
+
+// pendingAnnouncement is a Free Now game held back until its store page responds
+type pendingAnnouncement struct {
+	Game     models.Game
+	Detected time.Time
+	Deadline time.Time
+}
+
+// linkVerifier holds new Free Now games back for up to ANNOUNCE_DELAY, polling
+// their store pages so announcements don't point at links that still 404
+// while Epic's CDN catches up
+type linkVerifier struct {
+	client   *http.Client
+	interval time.Duration
+	mu       sync.Mutex
+	pending  map[string]*pendingAnnouncement
+}
+
+// newLinkVerifier creates a verifier with a short per-request timeout
+func newLinkVerifier() *linkVerifier {
+	return &linkVerifier{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		interval: linkCheckInterval,
+		pending:  make(map[string]*pendingAnnouncement),
+	}
+}
+
+// reachable reports whether url currently answers with 200 OK
+func (v *linkVerifier) reachable(ctx context.Context, url string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	return resp.StatusCode == http.StatusOK
+}
+
+// waitUntilReachable polls url until it returns 200, the deadline passes or ctx
+// is cancelled. It returns true only when the link was verified.
+func (v *linkVerifier) waitUntilReachable(ctx context.Context, url string, deadline time.Time) bool {
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	ticker := time.NewTicker(v.interval)
+	defer ticker.Stop()
+
+	for {
+		if v.reachable(ctx, url) {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// add records a game as pending link verification
+func (v *linkVerifier) add(game models.Game, delay time.Duration) *pendingAnnouncement {
+	now := time.Now()
+	pending := &pendingAnnouncement{Game: game, Detected: now, Deadline: now.Add(delay)}
+
+	v.mu.Lock()
+	v.pending[pendingKey(game)] = pending
+	v.mu.Unlock()
+
+	return pending
+}
+
+// pendingKey identifies a promotion the way the games table does
+func pendingKey(game models.Game) string {
+	return game.SourceKey() + "|" + game.FreeTo
+}
+
+// remove drops a game from the pending set
+func (v *linkVerifier) remove(game models.Game) {
+	v.mu.Lock()
+	delete(v.pending, pendingKey(game))
+	v.mu.Unlock()
+}
+
+// isPending reports whether a game is waiting for link verification
+func (v *linkVerifier) isPending(game models.Game) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	_, ok := v.pending[pendingKey(game)]
+	return ok
+}
+
+// list returns the games currently pending, oldest first
+func (v *linkVerifier) list() []pendingAnnouncement {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	result := make([]pendingAnnouncement, 0, len(v.pending))
+	for _, pending := range v.pending {
+		result = append(result, *pending)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Detected.Before(result[j].Detected)
+	})
+	return result
+}
+
+// holdForLinkVerification splits a collection into the games that can be
+// announced now and the Free Now games that must wait for their store page.
+// Coming Soon games and games without a store URL are never held back. Games
+// still held from an earlier check are left to that announcement.
+func (b *DiscordBot) holdForLinkVerification(games *models.GameCollection) (*models.GameCollection, []models.Game) {
+	if b.config.AnnounceDelay <= 0 {
+		return games, nil
+	}
+
+	now := &models.GameCollection{ComingSoon: games.ComingSoon}
+	var held []models.Game
+	for _, game := range games.FreeNow {
+		if b.linkVerifier.isPending(game) {
+			continue
+		}
+		if game.StoreURL == "" {
+			now.FreeNow = append(now.FreeNow, game)
+		} else {
+			held = append(held, game)
+		}
+	}
+	return now, held
+}
+
+// withoutPending drops the games still held for link verification, which are
+// announced once their store page is verified
+func (b *DiscordBot) withoutPending(games *models.GameCollection) *models.GameCollection {
+	ready := &models.GameCollection{ComingSoon: games.ComingSoon}
+	for _, game := range games.FreeNow {
+		if !b.linkVerifier.isPending(game) {
+			ready.FreeNow = append(ready.FreeNow, game)
+		}
+	}
+	return ready
+}
+
+// announceAfterVerification releases each held game once its store page
+// returns 200 or ANNOUNCE_DELAY expires, whichever comes first, and marks it
+// notified once delivered. A game still held at shutdown stays unnotified, so
+// it is announced after the restart.
+func (b *DiscordBot) announceAfterVerification(games []models.Game) {
+	for _, game := range games {
+		pending := b.linkVerifier.add(game, b.config.AnnounceDelay)
+		log.Printf("Holding %s for link verification (up to %s)", game.Title, b.config.AnnounceDelay)
+
+		go func(pending *pendingAnnouncement) {
+			game := pending.Game
+			verified := b.linkVerifier.waitUntilReachable(b.ctx, game.StoreURL, pending.Deadline)
+			// Stay pending until marked notified, so a check in between
+			// doesn't hold or announce the game again
+			defer b.linkVerifier.remove(game)
+
+			if b.ctx.Err() != nil {
+				log.Printf("Shutting down before announcing %s", game.Title)
+				return
+			}
+
+			if verified {
+				log.Printf("Store page for %s verified after %s", game.Title, time.Since(pending.Detected).Round(time.Second))
+			} else {
+				log.Printf("Store page for %s not verified within %s, announcing anyway", game.Title, b.config.AnnounceDelay)
+			}
+
+			collection := &models.GameCollection{FreeNow: []models.Game{game}}
+			if err := b.deliverGameUpdates(collection, collection); err != nil {
+				log.Printf("Error announcing %s after link verification: %v", game.Title, err)
+				return
+			}
+			if err := b.gameService.MarkGamesNotified(collection); err != nil {
+				log.Printf("Error marking %s notified after link verification: %v", game.Title, err)
+			}
+		}(pending)
+	}
+}
@@ -0,0 +1,106 @@
+package bot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/models"
+)
+
+// flippingStore answers 404 for the first misses requests and 200 after
+func flippingStore(t *testing.T, misses int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= misses {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestWaitUntilReachableAfterNotFound(t *testing.T) {
+	server, requests := flippingStore(t, 3)
+	v := newLinkVerifier()
+	v.interval = 5 * time.Millisecond
+
+	if !v.waitUntilReachable(context.Background(), server.URL, time.Now().Add(5*time.Second)) {
+		t.Fatal("waitUntilReachable = false, want true once the page answers 200")
+	}
+	if got := requests.Load(); got != 4 {
+		t.Errorf("store page polled %d times, want 4", got)
+	}
+}
+
+func TestWaitUntilReachableDeadline(t *testing.T) {
+	server, _ := flippingStore(t, 1<<30)
+	v := newLinkVerifier()
+	v.interval = 5 * time.Millisecond
+
+	start := time.Now()
+	if v.waitUntilReachable(context.Background(), server.URL, start.Add(50*time.Millisecond)) {
+		t.Fatal("waitUntilReachable = true for a page that never answers 200")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("waitUntilReachable returned after %v, want soon after the deadline", elapsed)
+	}
+}
+
+func TestHoldForLinkVerification(t *testing.T) {
+	b := &DiscordBot{
+		config:       &config.DiscordConfig{AnnounceDelay: time.Minute},
+		linkVerifier: newLinkVerifier(),
+	}
+	pending := models.Game{Title: "Pending", Status: models.StatusFreeNow, FreeTo: "Jan 1", StoreURL: "https://store.example/pending"}
+	b.linkVerifier.add(pending, time.Minute)
+
+	games := &models.GameCollection{
+		FreeNow: []models.Game{
+			{Title: "Linked", Status: models.StatusFreeNow, FreeTo: "Jan 1", StoreURL: "https://store.example/linked"},
+			{Title: "Unlinked", Status: models.StatusFreeNow, FreeTo: "Jan 1"},
+			pending,
+		},
+		ComingSoon: []models.Game{
+			{Title: "Later", Status: models.StatusComingSoon, StoreURL: "https://store.example/later"},
+		},
+	}
+	now, held := b.holdForLinkVerification(games)
+
+	if len(now.FreeNow) != 1 || now.FreeNow[0].Title != "Unlinked" {
+		t.Errorf("announced now = %v, want only Unlinked", titles(now.FreeNow))
+	}
+	if len(now.ComingSoon) != 1 {
+		t.Errorf("Coming Soon announced now = %v, want Later", titles(now.ComingSoon))
+	}
+	// A game already pending is left to its running announcement
+	if len(held) != 1 || held[0].Title != "Linked" {
+		t.Errorf("held = %v, want only Linked", titles(held))
+	}
+}
+
+func TestHoldForLinkVerificationDisabled(t *testing.T) {
+	b := &DiscordBot{config: &config.DiscordConfig{}, linkVerifier: newLinkVerifier()}
+	games := &models.GameCollection{
+		FreeNow: []models.Game{{Title: "Linked", StoreURL: "https://store.example/linked"}},
+	}
+	now, held := b.holdForLinkVerification(games)
+	if len(now.FreeNow) != 1 || len(held) != 0 {
+		t.Errorf("with no ANNOUNCE_DELAY got now=%v held=%v, want everything now", titles(now.FreeNow), titles(held))
+	}
+}
+
+func titles(games []models.Game) []string {
+	result := make([]string, len(games))
+	for i, game := range games {
+		result[i] = game.Title
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+)
+
+// handleMarkSeenCommand handles /markseen, recording every current giveaway
+// as announced in this server without posting anything, so only games that
+// appear later are announced
+func (b *DiscordBot) handleMarkSeenCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	serverConfig, err := b.database.GetServerConfig(i.GuildID)
+	if err != nil {
+		b.respondToInteraction(s, i, "Error checking server configuration.", true)
+		return
+	}
+	if serverConfig == nil {
+		b.respondToInteraction(s, i, "This server is not configured yet. Use /setup first.", true)
+		return
+	}
+
+	games, err := b.gameService.GetActiveGames()
+	if err != nil {
+		log.Printf("Error loading active games: %v", err)
+		b.respondToInteraction(s, i, "Failed to load the current games. Please try again.", true)
+		return
+	}
+
+	active := games.All()
+	marked, err := b.database.MarkNotificationsSent(i.GuildID, database.NotificationAnnouncement, active)
+	if err != nil {
+		log.Printf("Error marking games seen for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to mark the games. Please try again.", true)
+		return
+	}
+
+	switch {
+	case len(active) == 0:
+		b.respondToInteraction(s, i, "There are no current giveaways to mark.", true)
+	case marked == 0:
+		b.respondToInteraction(s, i, fmt.Sprintf("All %s were already marked as announced here.", pluralize(len(active), "current giveaway")), true)
+	default:
+		b.respondToInteraction(s, i, fmt.Sprintf("Marked %s as announced without posting. Only games that appear from now on will be announced here.", pluralize(marked, "giveaway")), true)
+	}
+	log.Printf("Server %s marked %d current games as seen", i.GuildID, marked)
+}
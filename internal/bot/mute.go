@@ -0,0 +1,178 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// muteButtonPrefix prefixes the custom ID of the "Mute this game" button; the
+// rest of the ID is the game title
+const muteButtonPrefix = "mute_game:"
+
+// maxCustomIDLength is Discord's limit for component custom IDs
+const maxCustomIDLength = 100
+
+// muteButton returns the admin "Mute this game" button row for an
+// announcement, or nil when the title does not fit in a custom ID
+func muteButton(title string) []discordgo.MessageComponent {
+	customID := muteButtonPrefix + title
+	if len(customID) > maxCustomIDLength {
+		return nil
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Mute this game",
+					Style:    discordgo.SecondaryButton,
+					CustomID: customID,
+					Emoji:    &discordgo.ComponentEmoji{Name: "🔇"},
+				},
+			},
+		},
+	}
+}
+
+// resolveGameTitle finds the stored title matching input case-insensitively
+func (b *DiscordBot) resolveGameTitle(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	games, err := b.database.GetAllGames()
+	if err != nil {
+		return "", err
+	}
+
+	for _, game := range games {
+		if strings.EqualFold(game.Title, input) {
+			return game.Title, nil
+		}
+	}
+	return "", nil
+}
+
+// muteGame records a mute and returns the user-facing confirmation
+func (b *DiscordBot) muteGame(guildID, title, userID string) (string, error) {
+	if err := b.database.MuteGame(guildID, title, userID); err != nil {
+		return "", err
+	}
+
+	log.Printf("Guild %s muted %s", guildID, title)
+	return fmt.Sprintf("Muted **%s**. I won't reference it again in this server; existing messages are left alone. Use `/unmute` to undo.", title), nil
+}
+
+// handleMuteComponent handles clicks on the "Mute this game" button
+func (b *DiscordBot) handleMuteComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	title := strings.TrimPrefix(i.MessageComponentData().CustomID, muteButtonPrefix)
+	response, err := b.muteGame(i.GuildID, title, interactionUserID(i))
+	if err != nil {
+		log.Printf("Error muting game for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to mute the game. Please try again.", true)
+		return
+	}
+	b.respondToInteraction(s, i, response, true)
+}
+
+// handleMuteCommand handles the /mute game and /mute list slash commands
+func (b *DiscordBot) handleMuteCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please choose a subcommand.", true)
+		return
+	}
+
+	switch options[0].Name {
+	case "list":
+		b.handleMuteList(s, i)
+	case "game":
+		if len(options[0].Options) == 0 {
+			b.respondToInteraction(s, i, "Please specify a game title.", true)
+			return
+		}
+
+		input := options[0].Options[0].StringValue()
+		title, err := b.resolveGameTitle(input)
+		if err != nil {
+			log.Printf("Error looking up game %q: %v", input, err)
+			b.respondToInteraction(s, i, "Error looking up the game.", true)
+			return
+		}
+		if title == "" {
+			b.respondToInteraction(s, i, fmt.Sprintf("I don't know a game called %q.", input), true)
+			return
+		}
+
+		response, err := b.muteGame(i.GuildID, title, interactionUserID(i))
+		if err != nil {
+			log.Printf("Error muting game for guild %s: %v", i.GuildID, err)
+			b.respondToInteraction(s, i, "Failed to mute the game. Please try again.", true)
+			return
+		}
+		b.respondToInteraction(s, i, response, true)
+	}
+}
+
+// handleMuteList lists the games muted in this server
+func (b *DiscordBot) handleMuteList(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	muted, err := b.database.GetMutedGames(i.GuildID)
+	if err != nil {
+		log.Printf("Error loading muted games for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Error loading muted games.", true)
+		return
+	}
+
+	if len(muted) == 0 {
+		b.respondToInteraction(s, i, "No games are muted in this server.", true)
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Muted games:\n")
+	for _, m := range muted {
+		line := fmt.Sprintf("• **%s** — muted <t:%d:R>\n", m.GameTitle, m.MutedAt.Unix())
+		if sb.Len()+len(line) > maxMessageLength-1 {
+			sb.WriteString("…")
+			break
+		}
+		sb.WriteString(line)
+	}
+	b.respondToInteraction(s, i, sb.String(), true)
+}
+
+// handleUnmuteCommand handles the /unmute slash command
+func (b *DiscordBot) handleUnmuteCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please specify a game title.", true)
+		return
+	}
+
+	title := strings.TrimSpace(options[0].StringValue())
+	removed, err := b.database.UnmuteGame(i.GuildID, title)
+	if err != nil {
+		log.Printf("Error unmuting game for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to unmute the game. Please try again.", true)
+		return
+	}
+	if !removed {
+		b.respondToInteraction(s, i, fmt.Sprintf("**%s** is not muted in this server.", title), true)
+		return
+	}
+
+	log.Printf("Guild %s unmuted %s", i.GuildID, title)
+	b.respondToInteraction(s, i, fmt.Sprintf("Unmuted **%s**.", title), true)
+}
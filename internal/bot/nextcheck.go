@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// scheduleState holds the periodic check schedule installed by the app.
+// nextCheck returns the zero time until the schedule has started.
+type scheduleState struct {
+	mu        sync.RWMutex
+	interval  time.Duration
+	nextCheck func() time.Time
+}
+
+// SetSchedule records the periodic check interval and the function that
+// reports the next scheduled game check
+func (b *DiscordBot) SetSchedule(interval time.Duration, nextCheck func() time.Time) {
+	b.schedule.mu.Lock()
+	defer b.schedule.mu.Unlock()
+	b.schedule.interval = interval
+	b.schedule.nextCheck = nextCheck
+}
+
+// nextScheduledCheck returns the next scheduled game check, or the zero time
+// when it is not known yet
+func (b *DiscordBot) nextScheduledCheck() time.Time {
+	b.schedule.mu.RLock()
+	source := b.schedule.nextCheck
+	b.schedule.mu.RUnlock()
+
+	if source == nil {
+		return time.Time{}
+	}
+	return source()
+}
+
+// describeSchedule summarizes the check interval and next check for /status,
+// or returns "" when no schedule has been installed
+func (b *DiscordBot) describeSchedule() string {
+	b.schedule.mu.RLock()
+	interval := b.schedule.interval
+	b.schedule.mu.RUnlock()
+
+	if interval == 0 {
+		return ""
+	}
+
+	description := fmt.Sprintf("Every %s", interval)
+	if next := b.nextScheduledCheck(); !next.IsZero() {
+		description += fmt.Sprintf("\nNext <t:%d:R>", next.Unix())
+	}
+	return description
+}
+
+// handleNextCheckCommand handles /nextcheck, showing when the bot will next
+// look for free games
+func (b *DiscordBot) handleNextCheckCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	var lines []string
+
+	next := b.nextScheduledCheck()
+	if next.IsZero() {
+		lines = append(lines, "The bot is running its startup check right now.")
+	} else {
+		lines = append(lines, fmt.Sprintf("Next check for free games: <t:%d:R> (<t:%d:f>).", next.Unix(), next.Unix()))
+	}
+
+	if lastScrape, success, _ := b.metrics.GetLastScrapeInfo(); !lastScrape.IsZero() {
+		result := "succeeded"
+		if !success {
+			result = "failed"
+		}
+		lines = append(lines, fmt.Sprintf("Last check <t:%d:R> %s.", lastScrape.Unix(), result))
+	}
+	if warning := b.staleDataWarning(); warning != "" {
+		lines = append(lines, warning)
+	}
+
+	b.respondToInteraction(s, i, strings.Join(lines, "\n"), true)
+}
@@ -0,0 +1,162 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"free-games-scrape/internal/models"
+)
+
+// maxMessageLength is Discord's limit for plain message content
+const maxMessageLength = 2000
+
+// formatChangeLine renders one scrape difference as a compact changelog line
+func formatChangeLine(change models.GameChange) string {
+	game := change.Game
+	switch change.Type {
+	case models.ChangeAdded:
+		return fmt.Sprintf("+ Added: %s (%s)", game.Title, describeWindow(game))
+	case models.ChangeChanged:
+		var details []string
+		if prev := change.Previous; prev != nil {
+			if prev.Status != game.Status {
+				details = append(details, fmt.Sprintf("status %s→%s", prev.Status, game.Status))
+			}
+			if prev.FreeFrom != game.FreeFrom {
+				details = append(details, fmt.Sprintf("start date %s→%s", orUnknown(prev.FreeFrom), orUnknown(game.FreeFrom)))
+			}
+			if prev.FreeTo != game.FreeTo {
+				details = append(details, fmt.Sprintf("end date %s→%s", orUnknown(prev.FreeTo), orUnknown(game.FreeTo)))
+			}
+		}
+		if len(details) == 0 {
+			return fmt.Sprintf("~ Changed: %s", game.Title)
+		}
+		return fmt.Sprintf("~ Changed: %s %s", game.Title, strings.Join(details, ", "))
+	case models.ChangeWithdrawn:
+		return fmt.Sprintf("- Withdrawn: %s", game.Title)
+	default:
+		return fmt.Sprintf("? %s: %s", change.Type, game.Title)
+	}
+}
+
+// describeWindow summarizes a game's status and promotion dates
+func describeWindow(game models.Game) string {
+	switch {
+	case game.Status == models.StatusFreeNow && game.FreeTo != "":
+		return fmt.Sprintf("%s until %s", game.Status, game.FreeTo)
+	case game.FreeFrom != "" && game.FreeTo != "":
+		return fmt.Sprintf("%s %s - %s", game.Status, game.FreeFrom, game.FreeTo)
+	default:
+		return game.Status
+	}
+}
+
+// orUnknown substitutes a placeholder for empty dates
+func orUnknown(value string) string {
+	if value == "" {
+		return "?"
+	}
+	return value
+}
+
+// formatEditLine renders a field-level edit of a stored game. Status and
+// start date edits are already covered by the scrape diff, so they get none.
+func formatEditLine(edit models.FieldChange) string {
+	switch edit.Field {
+	case models.FieldTitle:
+		return fmt.Sprintf("~ Renamed: %s", models.WordDiff(edit.OldValue, edit.NewValue))
+	case models.FieldPeriod:
+		return fmt.Sprintf("~ Edited: %s period %s", edit.GameTitle, models.WordDiff(edit.OldValue, edit.NewValue))
+	case models.FieldImageURL:
+		return fmt.Sprintf("~ Edited: %s image replaced", edit.GameTitle)
+	case models.FieldStoreURL:
+		return fmt.Sprintf("~ Edited: %s store link %s→%s", edit.GameTitle, orUnknown(edit.OldValue), edit.NewValue)
+	case models.FieldFreeTo:
+		return fmt.Sprintf("~ Corrected: %s end date %s→%s, posted announcements edited", edit.GameTitle, orUnknown(edit.OldValue), orUnknown(edit.NewValue))
+	default:
+		return ""
+	}
+}
+
+// changelogLines renders the scrape diff followed by the field-level edits. A
+// rename shows up in the diff as the old title withdrawn and the new one
+// added; both are left out in favor of the rename line.
+func changelogLines(changes []models.GameChange, edits []models.FieldChange) []string {
+	renamedFrom := make(map[string]bool)
+	renamedTo := make(map[string]bool)
+	for _, edit := range edits {
+		if edit.Field == models.FieldTitle {
+			renamedFrom[edit.OldValue] = true
+			renamedTo[edit.NewValue] = true
+		}
+	}
+
+	var lines []string
+	for _, change := range changes {
+		if (change.Type == models.ChangeAdded && renamedTo[change.Game.Title]) ||
+			(change.Type == models.ChangeWithdrawn && renamedFrom[change.Game.Title]) {
+			continue
+		}
+		lines = append(lines, formatChangeLine(change))
+	}
+	for _, edit := range edits {
+		if line := formatEditLine(edit); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// formatChangelog batches changelog lines into diff-highlighted code blocks
+// that each fit in one Discord message
+func formatChangelog(lines []string) []string {
+	const openFence, closeFence = "```diff\n", "```"
+
+	var messages []string
+	var sb strings.Builder
+	for _, text := range lines {
+		line := text + "\n"
+		if sb.Len() > 0 && sb.Len()+len(line)+len(closeFence) > maxMessageLength {
+			sb.WriteString(closeFence)
+			messages = append(messages, sb.String())
+			sb.Reset()
+		}
+		if sb.Len() == 0 {
+			sb.WriteString(openFence)
+		}
+		sb.WriteString(line)
+	}
+	if sb.Len() > 0 {
+		sb.WriteString(closeFence)
+		messages = append(messages, sb.String())
+	}
+	return messages
+}
+
+// SendOpsChangelog posts every difference detected in a scrape cycle, and the
+// field-level edits recorded while saving it, to the operator's
+// OPS_CHANNEL_ID as a single batched message. It does nothing when no ops
+// channel is configured or nothing changed.
+func (b *DiscordBot) SendOpsChangelog(changes []models.GameChange, edits []models.FieldChange) error {
+	if b.config.OpsChannelID == "" {
+		return nil
+	}
+	lines := changelogLines(changes, edits)
+	if len(lines) == 0 {
+		return nil
+	}
+
+	for _, message := range formatChangelog(lines) {
+		if err := b.rateLimiter.WaitForChannel(b.ctx, b.config.OpsChannelID); err != nil {
+			return fmt.Errorf("rate limiter wait for ops channel: %w", err)
+		}
+		if _, err := b.session.ChannelMessageSend(b.config.OpsChannelID, message); err != nil {
+			return fmt.Errorf("error sending ops changelog: %w", err)
+		}
+	}
+
+	log.Printf("Posted %d scrape changes to ops channel", len(lines))
+	return nil
+}
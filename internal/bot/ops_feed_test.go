@@ -0,0 +1,72 @@
+package bot
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"free-games-scrape/internal/models"
+)
+
+func TestFormatEditLine(t *testing.T) {
+	tests := []struct {
+		name string
+		edit models.FieldChange
+		want string
+	}{
+		{
+			name: "rename",
+			edit: models.FieldChange{GameTitle: "Control Ultimate Edition", Field: models.FieldTitle, OldValue: "Control", NewValue: "Control Ultimate Edition"},
+			want: "~ Renamed: Control {+Ultimate Edition+}",
+		},
+		{
+			name: "period",
+			edit: models.FieldChange{GameTitle: "Control", Field: models.FieldPeriod, OldValue: "Free Jul 24 - Jul 31", NewValue: "Free Jul 24 - Jul 31 at 11:00 AM"},
+			want: "~ Edited: Control period Free Jul 24 - Jul 31 {+at 11:00 AM+}",
+		},
+		{
+			name: "image",
+			edit: models.FieldChange{GameTitle: "Control", Field: models.FieldImageURL, OldValue: "https://cdn.example.com/a.jpg", NewValue: "https://cdn.example.com/b.jpg"},
+			want: "~ Edited: Control image replaced",
+		},
+		{
+			name: "store link",
+			edit: models.FieldChange{GameTitle: "Control", Field: models.FieldStoreURL, NewValue: "https://store.epicgames.com/p/control"},
+			want: "~ Edited: Control store link ?→https://store.epicgames.com/p/control",
+		},
+		{
+			name: "end date correction",
+			edit: models.FieldChange{GameTitle: "Control", Field: models.FieldFreeTo, OldValue: "Jul 31", NewValue: "Aug 1"},
+			want: "~ Corrected: Control end date Jul 31→Aug 1, posted announcements edited",
+		},
+		// Already reported by the scrape diff
+		{name: "status", edit: models.FieldChange{GameTitle: "Control", Field: models.FieldStatus, OldValue: models.StatusComingSoon, NewValue: models.StatusFreeNow}},
+		{name: "start date", edit: models.FieldChange{GameTitle: "Control", Field: models.FieldFreeFrom, OldValue: "Jul 24", NewValue: "Jul 25"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatEditLine(tt.edit); got != tt.want {
+				t.Errorf("formatEditLine = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangelogLinesFoldsRenames(t *testing.T) {
+	changes := []models.GameChange{
+		{Type: models.ChangeWithdrawn, Game: models.Game{Title: "Control"}},
+		{Type: models.ChangeAdded, Game: models.Game{Title: "Control Ultimate Edition", Status: models.StatusFreeNow, FreeTo: "Jul 31"}},
+		{Type: models.ChangeAdded, Game: models.Game{Title: "New Game", Status: models.StatusFreeNow, FreeTo: "Jul 31"}},
+	}
+	edits := []models.FieldChange{
+		{GameTitle: "Control Ultimate Edition", Field: models.FieldTitle, OldValue: "Control", NewValue: "Control Ultimate Edition"},
+	}
+
+	want := []string{
+		"+ Added: New Game (Free Now until Jul 31)",
+		"~ Renamed: Control {+Ultimate Edition+}",
+	}
+	if got := changelogLines(changes, edits); !reflect.DeepEqual(got, want) {
+		t.Errorf("changelogLines =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
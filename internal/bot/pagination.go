@@ -0,0 +1,165 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/models"
+)
+
+// gamesPageButtonPrefix prefixes the custom ID of the /games Previous and
+// Next buttons; the rest is "<interaction ID>:<prev|next>"
+const gamesPageButtonPrefix = "games_page:"
+
+// gamesPageTTL is how long a paginated /games message can be paged before
+// its buttons are disabled
+const gamesPageTTL = 5 * time.Minute
+
+// gamesViewAll is the /games view option that posts one message per game
+const gamesViewAll = "all"
+
+// pageState keeps the paginated /games messages, keyed by the ID of the
+// interaction that created them
+type pageState struct {
+	mu      sync.Mutex
+	entries map[string]*gamePages
+}
+
+// gamePages is one paginated /games message
+type gamePages struct {
+	games    []models.Game
+	index    int
+	warning  string
+	location *time.Location
+}
+
+// handlePaginatedGames answers /games with a single embed paged with buttons.
+// After gamesPageTTL the state is dropped and the buttons are disabled.
+func (b *DiscordBot) handlePaginatedGames(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	games, err := b.gameService.GetActiveGames()
+	if err != nil {
+		b.respondToInteraction(s, i, fmt.Sprintf("Failed to get games: %v", err), true)
+		return
+	}
+
+	pages := &gamePages{
+		games:    append(append([]models.Game{}, games.FreeNow...), games.ComingSoon...),
+		warning:  b.staleDataWarning(),
+		location: b.guildLocation(i.GuildID),
+	}
+	if len(pages.games) == 0 {
+		b.respondToInteraction(s, i, "No free games currently available in the database.", false)
+		return
+	}
+
+	b.pages.mu.Lock()
+	if b.pages.entries == nil {
+		b.pages.entries = make(map[string]*gamePages)
+	}
+	b.pages.entries[i.ID] = pages
+	b.pages.mu.Unlock()
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: b.gamesPageData(i.ID, pages, true),
+	})
+	if err != nil {
+		log.Printf("Error responding to games command: %v", err)
+		b.dropGamePages(i.ID)
+		return
+	}
+
+	interaction := i.Interaction
+	time.AfterFunc(gamesPageTTL, func() {
+		b.dropGamePages(interaction.ID)
+		disabled := gamesPageButtons(interaction.ID, pages, false)
+		if _, err := s.InteractionResponseEdit(interaction, &discordgo.WebhookEdit{Components: &disabled}); err != nil {
+			log.Printf("Error disabling games page buttons: %v", err)
+		}
+	})
+}
+
+// handleGamesPageComponent handles the /games Previous and Next buttons
+func (b *DiscordBot) handleGamesPageComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	rest := strings.TrimPrefix(i.MessageComponentData().CustomID, gamesPageButtonPrefix)
+	id, direction, _ := strings.Cut(rest, ":")
+
+	b.pages.mu.Lock()
+	pages, ok := b.pages.entries[id]
+	if ok {
+		switch direction {
+		case "prev":
+			if pages.index > 0 {
+				pages.index--
+			}
+		case "next":
+			if pages.index < len(pages.games)-1 {
+				pages.index++
+			}
+		}
+	}
+	var data *discordgo.InteractionResponseData
+	if ok {
+		data = b.gamesPageData(id, pages, true)
+	}
+	b.pages.mu.Unlock()
+
+	if !ok {
+		b.respondToInteraction(s, i, "This list has expired. Run /games again.", true)
+		return
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: data,
+	})
+	if err != nil {
+		log.Printf("Error updating games page: %v", err)
+	}
+}
+
+// dropGamePages forgets a paginated /games message
+func (b *DiscordBot) dropGamePages(id string) {
+	b.pages.mu.Lock()
+	defer b.pages.mu.Unlock()
+	delete(b.pages.entries, id)
+}
+
+// gamesPageData renders the current page of a paginated /games message
+func (b *DiscordBot) gamesPageData(id string, pages *gamePages, enabled bool) *discordgo.InteractionResponseData {
+	embed := b.gameInfoEmbed(pages.games[pages.index], pages.location)
+	embed.Description = fmt.Sprintf("Game %d of %d", pages.index+1, len(pages.games))
+
+	return &discordgo.InteractionResponseData{
+		Content:    pages.warning,
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: gamesPageButtons(id, pages, enabled),
+	}
+}
+
+// gamesPageButtons returns the Previous/Next row, with each button disabled
+// at the ends of the list or when paging is no longer possible
+func gamesPageButtons(id string, pages *gamePages, enabled bool) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Previous",
+					Style:    discordgo.SecondaryButton,
+					CustomID: gamesPageButtonPrefix + id + ":prev",
+					Disabled: !enabled || pages.index == 0,
+				},
+				discordgo.Button{
+					Label:    "Next",
+					Style:    discordgo.SecondaryButton,
+					CustomID: gamesPageButtonPrefix + id + ":next",
+					Disabled: !enabled || pages.index == len(pages.games)-1,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,272 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+)
+
+// presetApplyButtonPrefix prefixes the custom ID of the button confirming
+// /settings apply; the rest of the ID is the preset name
+const presetApplyButtonPrefix = "settings_apply:"
+
+// presetCancelButtonID is the custom ID of the button dismissing /settings apply
+const presetCancelButtonID = "settings_cancel"
+
+// resolvePreset finds a built-in preset, or one userID saved, called name
+func (b *DiscordBot) resolvePreset(userID, name string) (*database.Preset, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if preset := database.BuiltinPreset(name); preset != nil {
+		return preset, nil
+	}
+	return b.database.GetUserPreset(userID, name)
+}
+
+// presetDiff renders the changes applying a preset would make
+func presetDiff(changes []database.SettingChange) string {
+	var lines []string
+	for _, change := range changes {
+		lines = append(lines, fmt.Sprintf("• **%s**: %s → %s", change.Setting.Label, change.Setting.Display(change.OldValue), change.Setting.Display(change.NewValue)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// handleSettingsCommand handles /settings apply, save, list and delete
+func (b *DiscordBot) handleSettingsCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please choose a subcommand.", true)
+		return
+	}
+	var name string
+	if len(options[0].Options) > 0 {
+		name = options[0].Options[0].StringValue()
+	}
+
+	switch options[0].Name {
+	case "apply":
+		b.handleSettingsApply(s, i, name)
+	case "save":
+		b.handleSettingsSave(s, i, name)
+	case "list":
+		b.handleSettingsList(s, i)
+	case "delete":
+		b.handleSettingsDelete(s, i, name)
+	}
+}
+
+// handleSettingsApply shows what a preset would change, with buttons to
+// confirm or cancel
+func (b *DiscordBot) handleSettingsApply(s *discordgo.Session, i *discordgo.InteractionCreate, name string) {
+	serverConfig, err := b.database.GetServerConfig(i.GuildID)
+	if err != nil {
+		b.respondToInteraction(s, i, "Error checking server configuration.", true)
+		return
+	}
+	if serverConfig == nil {
+		b.respondToInteraction(s, i, "This server is not configured yet. Use /setup first.", true)
+		return
+	}
+
+	preset, err := b.resolvePreset(interactionUserID(i), name)
+	if err != nil {
+		log.Printf("Error loading preset %q: %v", name, err)
+		b.respondToInteraction(s, i, "Failed to load the preset. Please try again.", true)
+		return
+	}
+	if preset == nil {
+		b.respondToInteraction(s, i, fmt.Sprintf("No preset called %q. Use `/settings list` to see the presets you can apply.", name), true)
+		return
+	}
+
+	if err := database.PresetPolicyConflict(serverConfig, preset); err != nil {
+		b.respondToInteraction(s, i, fmt.Sprintf("Can't apply **%s**: %v. Change the content policy with `/customize policy` first.", preset.Name, err), true)
+		return
+	}
+
+	changes := database.DiffPreset(serverConfig, preset)
+	if len(changes) == 0 {
+		b.respondToInteraction(s, i, fmt.Sprintf("This server's settings already match **%s**.", preset.Name), true)
+		return
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("Applying **%s** would change:\n%s", preset.Name, presetDiff(changes)),
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Label:    "Apply",
+							Style:    discordgo.PrimaryButton,
+							CustomID: presetApplyButtonPrefix + preset.Name,
+						},
+						discordgo.Button{
+							Label:    "Cancel",
+							Style:    discordgo.SecondaryButton,
+							CustomID: presetCancelButtonID,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to settings apply: %v", err)
+	}
+}
+
+// handlePresetComponent handles the Apply and Cancel buttons of /settings apply
+func (b *DiscordBot) handlePresetComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	content := "Cancelled; no settings were changed."
+	if customID := i.MessageComponentData().CustomID; customID != presetCancelButtonID {
+		content = b.applyPreset(i, strings.TrimPrefix(customID, presetApplyButtonPrefix))
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+	if err != nil {
+		log.Printf("Error updating settings apply message: %v", err)
+	}
+}
+
+// applyPreset applies a confirmed preset and returns the user-facing result
+func (b *DiscordBot) applyPreset(i *discordgo.InteractionCreate, name string) string {
+	preset, err := b.resolvePreset(interactionUserID(i), name)
+	if err != nil {
+		log.Printf("Error loading preset %q: %v", name, err)
+		return "Failed to load the preset. Please try again."
+	}
+	if preset == nil {
+		return fmt.Sprintf("The preset %q no longer exists; no settings were changed.", name)
+	}
+	serverConfig, err := b.database.GetServerConfig(i.GuildID)
+	if err != nil || serverConfig == nil {
+		return "Error checking server configuration; no settings were changed."
+	}
+	if err := database.PresetPolicyConflict(serverConfig, preset); err != nil {
+		return fmt.Sprintf("Can't apply **%s**: %v. No settings were changed.", preset.Name, err)
+	}
+
+	if err := b.database.ApplyPreset(i.GuildID, preset); err != nil {
+		log.Printf("Error applying preset %s to guild %s: %v", preset.Name, i.GuildID, err)
+		return "Failed to apply the preset; no settings were changed. Please try again."
+	}
+	return fmt.Sprintf("Applied **%s**. Use `/status` to review the settings.", preset.Name)
+}
+
+// handleSettingsSave saves the server's current settings as a preset of the
+// invoking user
+func (b *DiscordBot) handleSettingsSave(s *discordgo.Session, i *discordgo.InteractionCreate, name string) {
+	serverConfig, err := b.database.GetServerConfig(i.GuildID)
+	if err != nil {
+		b.respondToInteraction(s, i, "Error checking server configuration.", true)
+		return
+	}
+	if serverConfig == nil {
+		b.respondToInteraction(s, i, "This server is not configured yet. Use /setup first.", true)
+		return
+	}
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	if err := database.ValidatePresetName(name); err != nil {
+		b.respondToInteraction(s, i, fmt.Sprintf("Can't save the preset: %v.", err), true)
+		return
+	}
+	if err := b.database.SaveUserPreset(interactionUserID(i), name, database.CurrentSettings(serverConfig)); err != nil {
+		log.Printf("Error saving preset %s: %v", name, err)
+		b.respondToInteraction(s, i, fmt.Sprintf("Failed to save the preset: %v.", err), true)
+		return
+	}
+
+	b.respondToInteraction(s, i, fmt.Sprintf("Saved this server's settings as **%s**. Use `/settings apply preset:%s` in your other servers.", name, name), true)
+	log.Printf("User %s saved preset %s from guild %s", interactionUserID(i), name, i.GuildID)
+}
+
+// handleSettingsList lists the built-in presets and the invoking user's own
+func (b *DiscordBot) handleSettingsList(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	var builtin []string
+	for _, preset := range database.BuiltinPresets() {
+		builtin = append(builtin, "`"+preset.Name+"`")
+	}
+	content := "**Built-in presets:** " + strings.Join(builtin, ", ")
+
+	names, err := b.database.GetUserPresetNames(interactionUserID(i))
+	if err != nil {
+		log.Printf("Error listing presets: %v", err)
+		b.respondToInteraction(s, i, "Failed to load your presets. Please try again.", true)
+		return
+	}
+	if len(names) == 0 {
+		content += "\n**Your presets:** none yet. Save one with `/settings save`."
+	} else {
+		content += "\n**Your presets:** `" + strings.Join(names, "`, `") + "`"
+	}
+	b.respondToInteraction(s, i, content, true)
+}
+
+// handleSettingsDelete deletes one of the invoking user's presets
+func (b *DiscordBot) handleSettingsDelete(s *discordgo.Session, i *discordgo.InteractionCreate, name string) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if database.BuiltinPreset(name) != nil {
+		b.respondToInteraction(s, i, "Built-in presets can't be deleted.", true)
+		return
+	}
+
+	deleted, err := b.database.DeleteUserPreset(interactionUserID(i), name)
+	if err != nil {
+		log.Printf("Error deleting preset %s: %v", name, err)
+		b.respondToInteraction(s, i, "Failed to delete the preset. Please try again.", true)
+		return
+	}
+	if !deleted {
+		b.respondToInteraction(s, i, fmt.Sprintf("You have no preset called %q.", name), true)
+		return
+	}
+	b.respondToInteraction(s, i, fmt.Sprintf("Deleted **%s**.", name), true)
+}
+
+// handlePresetAutocomplete suggests the built-in presets and the user's own
+// starting with what the user typed so far
+func (b *DiscordBot) handlePresetAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options
+	prefix := strings.ToLower(focusedValue(options))
+
+	var candidates []string
+	if len(options) > 0 && options[0].Name == "apply" {
+		for _, preset := range database.BuiltinPresets() {
+			candidates = append(candidates, preset.Name)
+		}
+	}
+	names, err := b.database.GetUserPresetNames(interactionUserID(i))
+	if err != nil {
+		log.Printf("Error listing presets: %v", err)
+	}
+	candidates = append(candidates, names...)
+
+	var matches []string
+	for _, name := range candidates {
+		if strings.HasPrefix(name, prefix) {
+			matches = append(matches, name)
+		}
+	}
+	b.respondWithTitleChoices(s, i, matches)
+}
@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+)
+
+// handlePrivacyCommand handles /privacy, listing what the bot stores about
+// this server with live record counts. The disclosure is generated from the
+// database's data category registry, the same source as the web /privacy page.
+func (b *DiscordBot) handlePrivacyCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		b.respondToInteraction(s, i, "Use /privacy in a server to see what the bot stores about it.", true)
+		return
+	}
+
+	guildData, err := b.database.GetGuildData(i.GuildID)
+	if err != nil {
+		log.Printf("Error loading privacy data for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to load the data summary. Please try again.", true)
+		return
+	}
+
+	description := "The bot stores only what it needs to send free game notifications. It does not store message content."
+	if b.config.PrivacyURL != "" {
+		description += fmt.Sprintf("\n\nFull privacy notice: %s", b.config.PrivacyURL)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "What this bot stores about this server",
+		Description: description,
+		Color:       b.branding().AccentColor,
+	}
+
+	for _, data := range guildData {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%s (%s)", data.Name, pluralize(data.Rows, "record")),
+			Value: fmt.Sprintf("%s\n*Retention:* %s", data.Description, data.Retention),
+		})
+	}
+
+	var global []string
+	for _, category := range database.DataCategories() {
+		if !category.PerGuild() {
+			global = append(global, fmt.Sprintf("• **%s** — %s", category.Name, category.Retention))
+		}
+	}
+	if len(global) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Not tied to any server",
+			Value: strings.Join(global, "\n"),
+		})
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to privacy command: %v", err)
+	}
+}
+
+// pluralize formats a count with a singular or plural noun
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,170 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// bot_state keys tracking channel reconciliation progress
+const (
+	reconcileCursorStateKey    = "channel_reconcile_cursor"
+	reconcileCompletedStateKey = "channel_reconcile_completed_at"
+)
+
+// ReconcileChannels checks that each active config's channel still exists in
+// its guild, flagging configs whose channel was deleted, moved to another
+// guild or became inaccessible. A full pass runs every
+// CHANNEL_RECONCILE_INTERVAL; each call makes at most CHANNEL_RECONCILE_BUDGET
+// API requests and later calls resume where the previous one stopped.
+func (b *DiscordBot) ReconcileChannels(ctx context.Context) {
+	cursor, err := b.database.GetBotState(reconcileCursorStateKey)
+	if err != nil {
+		log.Printf("Error loading channel reconcile cursor: %v", err)
+		return
+	}
+	if cursor == "" && !b.reconcileDue(time.Now()) {
+		return
+	}
+
+	budget := b.config.ReconcileBudget
+	configs, err := b.database.GetActiveServerConfigsAfter(cursor, budget)
+	if err != nil {
+		log.Printf("Error loading server configs for channel reconciliation: %v", err)
+		return
+	}
+
+	calls, checked := 0, 0
+	for _, cfg := range configs {
+		// A check may also DM the guild owner, which takes two more requests
+		if ctx.Err() != nil || (calls > 0 && calls+3 > budget) {
+			break
+		}
+		if err := b.rateLimiter.WaitForChannel(ctx, cfg.ChannelID); err != nil {
+			log.Printf("Rate limiter wait failed for channel %s: %v", cfg.ChannelID, err)
+			break
+		}
+
+		calls += b.reconcileChannel(ctx, cfg)
+		checked++
+		if err := b.database.SetBotState(reconcileCursorStateKey, cfg.GuildID); err != nil {
+			log.Printf("Error saving channel reconcile cursor: %v", err)
+			return
+		}
+	}
+
+	if checked < len(configs) || len(configs) == budget {
+		log.Printf("Channel reconciliation checked %d configs, resuming later", checked)
+		return
+	}
+
+	if err := b.database.SetBotState(reconcileCursorStateKey, ""); err != nil {
+		log.Printf("Error resetting channel reconcile cursor: %v", err)
+	}
+	if err := b.database.SetBotState(reconcileCompletedStateKey, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		log.Printf("Error recording channel reconciliation: %v", err)
+	}
+	log.Printf("Channel reconciliation pass complete")
+}
+
+// reconcileDue reports whether a new reconciliation pass should start at now
+func (b *DiscordBot) reconcileDue(now time.Time) bool {
+	value, err := b.database.GetBotState(reconcileCompletedStateKey)
+	if err != nil {
+		log.Printf("Error loading last channel reconciliation: %v", err)
+		return false
+	}
+	if value == "" {
+		return true
+	}
+	completed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Printf("Invalid last channel reconciliation %q: %v", value, err)
+		return true
+	}
+	return now.Sub(completed) >= b.config.ReconcileInterval
+}
+
+// reconcileChannel checks one config's channel and updates its attention flag,
+// returning the number of API requests made. Transient errors leave the flag
+// as it is until the next pass.
+func (b *DiscordBot) reconcileChannel(ctx context.Context, cfg *database.ServerConfig) int {
+	calls := 1
+	reason := ""
+	channel, err := b.session.Channel(cfg.ChannelID, discordgo.WithContext(ctx))
+	if err != nil {
+		switch classifySendError(err) {
+		case models.SkipReasonChannelNotFound:
+			reason = database.AttentionChannelDeleted
+		case models.SkipReasonMissingPermissions:
+			reason = database.AttentionChannelInaccessible
+		default:
+			log.Printf("Could not check channel %s of guild %s: %v", cfg.ChannelID, cfg.GuildID, err)
+			return calls
+		}
+	} else if channel.GuildID != cfg.GuildID {
+		reason = database.AttentionChannelMoved
+	}
+	if reason == database.AttentionChannelInaccessible && cfg.WebhookURL != "" {
+		// Webhook guilds need not let the bot see the channel
+		reason = ""
+	}
+
+	if reason == cfg.NeedsAttention {
+		return calls
+	}
+	if err := b.database.SetNeedsAttention(cfg.GuildID, reason); err != nil {
+		log.Printf("Error updating attention flag for guild %s: %v", cfg.GuildID, err)
+		return calls
+	}
+	if reason == "" {
+		log.Printf("Channel %s of guild %s is reachable again", cfg.ChannelID, cfg.GuildID)
+		return calls
+	}
+
+	log.Printf("Flagged guild %s: %s (channel %s)", cfg.GuildID, reason, cfg.ChannelID)
+	calls += 2
+	if err := b.notifyGuildOwner(cfg.GuildID, reason); err != nil {
+		log.Printf("Error notifying owner of guild %s: %v", cfg.GuildID, err)
+	}
+	return calls
+}
+
+// notifyGuildOwner DMs the owner of a guild the bot has in its state cache
+// that the guild's config needs attention
+func (b *DiscordBot) notifyGuildOwner(guildID, reason string) error {
+	guild, err := b.session.State.Guild(guildID)
+	if err != nil {
+		return fmt.Errorf("guild not in state: %w", err)
+	}
+	channel, err := b.session.UserChannelCreate(guild.OwnerID)
+	if err != nil {
+		return fmt.Errorf("error opening DM with guild owner: %w", err)
+	}
+	return b.SendMessageTo(channel.ID, attentionNotice(guild.Name, reason))
+}
+
+// attentionReason describes a needs_attention reason for admins
+func attentionReason(reason string) string {
+	switch reason {
+	case database.AttentionChannelDeleted:
+		return "The announcement channel no longer exists."
+	case database.AttentionChannelMoved:
+		return "The announcement channel now belongs to a different server."
+	case database.AttentionChannelInaccessible:
+		return "The bot can no longer see the announcement channel."
+	default:
+		return reason
+	}
+}
+
+// attentionNotice is the DM sent to a guild owner when their config is flagged
+func attentionNotice(guildName, reason string) string {
+	return fmt.Sprintf("⚠️ Free game announcements in **%s** need attention: %s Run `/setup` with a channel the bot can post in to keep receiving them.",
+		guildName, attentionReason(reason))
+}
@@ -0,0 +1,158 @@
+package bot
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+)
+
+// channelResponses answers channel lookups on the fake Discord API: each
+// channel ID maps to the guild it belongs to, or to an error status
+type channelResponses map[string]string
+
+func (c channelResponses) respond(request fakeRequest) (int, string) {
+	if request.Method != "GET" || !strings.HasPrefix(request.Path, "channels/") {
+		return 0, ""
+	}
+	channelID := strings.TrimPrefix(request.Path, "channels/")
+	switch answer := c[channelID]; answer {
+	case "404":
+		return http.StatusNotFound, `{"code": 10003, "message": "Unknown Channel"}`
+	case "403":
+		return http.StatusForbidden, `{"code": 50001, "message": "Missing Access"}`
+	case "500":
+		return http.StatusInternalServerError, `{"message": "Internal Server Error"}`
+	default:
+		return http.StatusOK, fmt.Sprintf(`{"id": "%s", "guild_id": "%s"}`, channelID, answer)
+	}
+}
+
+// addGuilds sets up an active config per guild, each announcing in the
+// channel with the guild's ID plus "0", with the guild in the state cache
+func addGuilds(t *testing.T, b *DiscordBot, guildIDs ...string) {
+	t.Helper()
+	for _, guildID := range guildIDs {
+		if _, err := b.database.SaveServerConfig(guildID, guildID+"0"); err != nil {
+			t.Fatalf("SaveServerConfig: %v", err)
+		}
+		if err := b.session.State.GuildAdd(&discordgo.Guild{ID: guildID, Name: "Guild " + guildID, OwnerID: "owner" + guildID}); err != nil {
+			t.Fatalf("GuildAdd: %v", err)
+		}
+	}
+}
+
+func TestReconcileChannelsFlagsMissingChannels(t *testing.T) {
+	b := newTestBot(t)
+	b.config.ReconcileBudget = 100
+	b.config.ReconcileInterval = 7 * 24 * time.Hour
+	discord := useFakeDiscord(t, b)
+	addGuilds(t, b, "1", "2", "3", "4", "5", "6")
+	if err := b.database.SetWebhookURL("6", "https://discord.com/api/webhooks/1/token"); err != nil {
+		t.Fatalf("SetWebhookURL: %v", err)
+	}
+	discord.fail = channelResponses{
+		"10": "1",   // still there
+		"20": "404", // deleted
+		"30": "9",   // moved to another guild
+		"40": "403", // hidden from the bot
+		"50": "500", // Discord trouble
+		"60": "403", // hidden, but posted to through a webhook
+	}.respond
+
+	b.ReconcileChannels(b.ctx)
+
+	want := map[string]string{
+		"1": "",
+		"2": database.AttentionChannelDeleted,
+		"3": database.AttentionChannelMoved,
+		"4": database.AttentionChannelInaccessible,
+		"5": "",
+		"6": "",
+	}
+	for guildID, reason := range want {
+		cfg, err := b.database.GetServerConfig(guildID)
+		if err != nil || cfg == nil {
+			t.Fatalf("GetServerConfig(%s): %v, %v", guildID, cfg, err)
+		}
+		if cfg.NeedsAttention != reason {
+			t.Errorf("guild %s needs attention %q, want %q", guildID, cfg.NeedsAttention, reason)
+		}
+	}
+
+	// Only the owners of flagged guilds are told
+	var owners []string
+	for _, request := range discord.find("POST", "users/@me/channels") {
+		owners = append(owners, fmt.Sprint(request.Body["recipient_id"]))
+	}
+	if got := strings.Join(owners, ","); got != "owner2,owner3,owner4" {
+		t.Errorf("DMed owners %s, want owner2,owner3,owner4", got)
+	}
+
+	if cursor, _ := b.database.GetBotState(reconcileCursorStateKey); cursor != "" {
+		t.Errorf("cursor = %q after a full pass, want it reset", cursor)
+	}
+	if completed, _ := b.database.GetBotState(reconcileCompletedStateKey); completed == "" {
+		t.Error("the pass was not recorded as complete")
+	}
+
+	// Flags don't repeat, and a channel that comes back clears its flag
+	discord.mu.Lock()
+	discord.requests = nil
+	discord.mu.Unlock()
+	discord.fail = channelResponses{"10": "1", "20": "404", "30": "3", "40": "403", "50": "5", "60": "6"}.respond
+	if err := b.database.SetBotState(reconcileCompletedStateKey, ""); err != nil {
+		t.Fatalf("SetBotState: %v", err)
+	}
+	b.ReconcileChannels(b.ctx)
+
+	if n := len(discord.find("POST", "users/@me/channels")); n != 0 {
+		t.Errorf("DMed %d owners again, want none", n)
+	}
+	if cfg, _ := b.database.GetServerConfig("3"); cfg.NeedsAttention != "" {
+		t.Errorf("guild 3 still needs attention %q after its channel came back", cfg.NeedsAttention)
+	}
+}
+
+func TestReconcileChannelsResumesWithinBudget(t *testing.T) {
+	b := newTestBot(t)
+	b.config.ReconcileBudget = 4
+	b.config.ReconcileInterval = 7 * 24 * time.Hour
+	discord := useFakeDiscord(t, b)
+	addGuilds(t, b, "1", "2", "3")
+	discord.fail = channelResponses{"10": "404", "20": "404", "30": "404"}.respond
+
+	// Every check flags its guild, taking three requests, so each run
+	// fits only one of them in its budget of four
+	for run := 1; run <= 3; run++ {
+		before := len(discord.requests)
+		b.ReconcileChannels(b.ctx)
+		if calls := len(discord.requests) - before; calls > b.config.ReconcileBudget {
+			t.Errorf("run %d made %d requests, over the budget of %d", run, calls, b.config.ReconcileBudget)
+		}
+		cursor, _ := b.database.GetBotState(reconcileCursorStateKey)
+		if want := fmt.Sprint(run); run < 3 && cursor != want {
+			t.Errorf("cursor after run %d = %q, want %q", run, cursor, want)
+		}
+	}
+
+	for _, channelID := range []string{"10", "20", "30"} {
+		if n := len(discord.find("GET", "channels/"+channelID)); n != 1 {
+			t.Errorf("channel %s checked %d times, want once", channelID, n)
+		}
+	}
+	if completed, _ := b.database.GetBotState(reconcileCompletedStateKey); completed == "" {
+		t.Error("the pass was not recorded as complete")
+	}
+
+	// The next pass waits for the interval
+	before := len(discord.requests)
+	b.ReconcileChannels(b.ctx)
+	if calls := len(discord.requests) - before; calls != 0 {
+		t.Errorf("made %d requests before the next pass was due", calls)
+	}
+}
@@ -0,0 +1,181 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/models"
+)
+
+// refreshCooldowns remembers when each guild last started a manual refresh,
+// so !refresh and /refresh can't queue up scrapes
+type refreshCooldowns struct {
+	mu      sync.Mutex
+	lastRun map[string]time.Time
+}
+
+// startManualRefresh records a manual refresh for key unless one started
+// within the cooldown, in which case it returns the time left to wait
+func (b *DiscordBot) startManualRefresh(key string, now time.Time) (time.Duration, bool) {
+	b.refreshes.mu.Lock()
+	defer b.refreshes.mu.Unlock()
+
+	if wait := b.refreshes.lastRun[key].Add(b.config.RefreshCooldown).Sub(now); wait > 0 {
+		return wait, false
+	}
+	if b.refreshes.lastRun == nil {
+		b.refreshes.lastRun = make(map[string]time.Time)
+	}
+	// Drop expired entries so the map stays as small as the active guilds
+	for k, last := range b.refreshes.lastRun {
+		if now.Sub(last) >= b.config.RefreshCooldown {
+			delete(b.refreshes.lastRun, k)
+		}
+	}
+	b.refreshes.lastRun[key] = now
+	return 0, true
+}
+
+// refreshCooldownKey keys the cooldown by guild, falling back to the channel
+// for commands outside a guild
+func refreshCooldownKey(guildID, channelID string) string {
+	if guildID != "" {
+		return guildID
+	}
+	return "channel:" + channelID
+}
+
+// canRefresh reports whether a member may trigger a manual refresh: they
+// need Manage Server in the channel or the configured admin role
+func (b *DiscordBot) canRefresh(s *discordgo.Session, userID, channelID string, roles []string) (bool, error) {
+	if b.config.AdminRoleID != "" && slices.Contains(roles, b.config.AdminRoleID) {
+		return true, nil
+	}
+	permissions, err := s.UserChannelPermissions(userID, channelID)
+	if err != nil {
+		return false, err
+	}
+	return permissions&discordgo.PermissionManageGuild != 0, nil
+}
+
+// refreshDeniedMessage explains who may refresh
+func (b *DiscordBot) refreshDeniedMessage() string {
+	if b.config.AdminRoleID != "" {
+		return fmt.Sprintf("You need 'Manage Server' permission or the <@&%s> role to refresh games.", b.config.AdminRoleID)
+	}
+	return "You need 'Manage Server' permission to refresh games."
+}
+
+// refreshCooldownMessage tells the user how long until the next manual refresh
+func refreshCooldownMessage(wait time.Duration) string {
+	return fmt.Sprintf("Games were refreshed recently; you can refresh again in %s. Showing the stored games instead.", formatWait(wait))
+}
+
+// refreshFailedMessage explains why a manual refresh failed, pointing to the
+// stored games on hosts that can't scrape
+func refreshFailedMessage(err error) string {
+	if errors.Is(err, models.ErrScraperUnavailable) {
+		return "Scraping is disabled on this host, so games can't be refreshed. Use /games to see the stored games."
+	}
+	return fmt.Sprintf("Failed to refresh games: %v", err)
+}
+
+// formatWait renders a wait in whole minutes, rounded up
+func formatWait(wait time.Duration) string {
+	if wait < time.Minute {
+		return "less than a minute"
+	}
+	minutes := int((wait + time.Minute - 1) / time.Minute)
+	if minutes == 1 {
+		return "1 minute"
+	}
+	return fmt.Sprintf("%d minutes", minutes)
+}
+
+// allowPrefixRefresh checks the permission and cooldown of !refresh,
+// answering in the channel when the refresh can't run
+func (b *DiscordBot) allowPrefixRefresh(s *discordgo.Session, m *discordgo.MessageCreate) bool {
+	var roles []string
+	if m.Member != nil {
+		roles = m.Member.Roles
+	}
+	allowed, err := b.canRefresh(s, m.Author.ID, m.ChannelID, roles)
+	if err != nil {
+		log.Printf("Error checking refresh permission of %s: %v", m.Author.ID, err)
+		b.SendErrorMessageTo(m.ChannelID, "Error checking permissions.")
+		return false
+	}
+	if !allowed {
+		b.SendMessageTo(m.ChannelID, b.refreshDeniedMessage())
+		return false
+	}
+
+	if wait, ok := b.startManualRefresh(refreshCooldownKey(m.GuildID, m.ChannelID), time.Now()); !ok {
+		b.SendMessageTo(m.ChannelID, refreshCooldownMessage(wait))
+		b.handleGamesCommand(s, m)
+		return false
+	}
+	return true
+}
+
+// allowSlashRefresh checks the permission and cooldown of /refresh,
+// answering ephemerally when the refresh can't run
+func (b *DiscordBot) allowSlashRefresh(s *discordgo.Session, i *discordgo.InteractionCreate) bool {
+	if i.Member == nil || i.Member.User == nil {
+		b.respondToInteraction(s, i, "This command can only be used in a server.", true)
+		return false
+	}
+	allowed, err := b.canRefresh(s, i.Member.User.ID, i.ChannelID, i.Member.Roles)
+	if err != nil {
+		log.Printf("Error checking refresh permission of %s: %v", i.Member.User.ID, err)
+		b.respondToInteraction(s, i, "Error checking permissions.", true)
+		return false
+	}
+	if !allowed {
+		b.respondToInteraction(s, i, b.refreshDeniedMessage(), true)
+		return false
+	}
+
+	wait, ok := b.startManualRefresh(refreshCooldownKey(i.GuildID, i.ChannelID), time.Now())
+	if ok {
+		return true
+	}
+	b.respondToInteraction(s, i, refreshCooldownMessage(wait), true)
+
+	games, err := b.gameService.GetActiveGames()
+	if err != nil {
+		log.Printf("Error getting stored games: %v", err)
+		return false
+	}
+	b.followUpStoredGames(s, i, games)
+	return false
+}
+
+// followUpStoredGames shows the stored games to the member who ran /refresh
+// only, so a refresh on cooldown doesn't repost them in the channel
+func (b *DiscordBot) followUpStoredGames(s *discordgo.Session, i *discordgo.InteractionCreate, games *models.GameCollection) {
+	var embeds []*discordgo.MessageEmbed
+	for index, game := range games.FreeNow {
+		embeds = append(embeds, b.freeNowEmbed(game, index, len(games.FreeNow), nil, false))
+	}
+	for index, game := range games.ComingSoon {
+		embeds = append(embeds, b.comingSoonEmbed(game, index, len(games.ComingSoon), nil))
+	}
+
+	for start := 0; start < len(embeds); start += maxEmbedsPerMessage {
+		end := min(start+maxEmbedsPerMessage, len(embeds))
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Embeds: embeds[start:end],
+			Flags:  discordgo.MessageFlagsEphemeral,
+		})
+		if err != nil {
+			log.Printf("Error sending stored games: %v", err)
+			return
+		}
+	}
+}
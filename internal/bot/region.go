@@ -0,0 +1,112 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// regionDefaultChoice is the /region value that reverts to EPIC_LOCALE
+const regionDefaultChoice = "default"
+
+// regionChoices lists every supported locale as a /region choice
+func regionChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := []*discordgo.ApplicationCommandOptionChoice{
+		{Name: "Bot default", Value: regionDefaultChoice},
+	}
+	for _, locale := range models.SupportedLocales {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  fmt.Sprintf("%s (%s)", locale.Name, locale.Code),
+			Value: locale.Code,
+		})
+	}
+	return choices
+}
+
+// guildRegion returns the locale a server sees games for
+func (b *DiscordBot) guildRegion(cfg *database.ServerConfig) string {
+	if cfg != nil && cfg.Region != "" {
+		return cfg.Region
+	}
+	return b.config.DefaultRegion
+}
+
+// isScrapedRegion reports whether locale is one of EPIC_LOCALES
+func (b *DiscordBot) isScrapedRegion(locale string) bool {
+	for _, region := range b.config.Regions {
+		if strings.EqualFold(region, locale) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeRegion renders a locale code with its country name
+func describeRegion(code string) string {
+	if locale, ok := models.LookupLocale(code); ok {
+		return fmt.Sprintf("%s (%s)", locale.Name, locale.Code)
+	}
+	return code
+}
+
+// handleRegionCommand handles /region, showing the server's region or, with a
+// locale, changing it
+func (b *DiscordBot) handleRegionCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	serverConfig, err := b.database.GetServerConfig(i.GuildID)
+	if err != nil {
+		b.respondToInteraction(s, i, "Error checking server configuration.", true)
+		return
+	}
+	if serverConfig == nil {
+		b.respondToInteraction(s, i, "This server is not configured yet. Use /setup first.", true)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		current := describeRegion(b.guildRegion(serverConfig))
+		if serverConfig.Region == "" {
+			current += " (bot default)"
+		}
+		b.respondToInteraction(s, i, fmt.Sprintf("This server is shown free games for %s.", current), true)
+		return
+	}
+
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	locale := options[0].StringValue()
+	if locale == regionDefaultChoice {
+		locale = ""
+	} else {
+		info, ok := models.LookupLocale(locale)
+		if !ok {
+			b.respondToInteraction(s, i, "That region isn't supported.", true)
+			return
+		}
+		locale = info.Code
+		if !b.isScrapedRegion(locale) {
+			b.respondToInteraction(s, i, fmt.Sprintf("This bot only checks these regions: %s. Ask the bot operator to add %s to EPIC_LOCALES.",
+				strings.Join(b.config.Regions, ", "), locale), true)
+			return
+		}
+	}
+
+	if err := b.database.SetRegion(i.GuildID, locale); err != nil {
+		log.Printf("Error saving region for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to save the region. Please try again.", true)
+		return
+	}
+
+	if locale == "" {
+		b.respondToInteraction(s, i, fmt.Sprintf("This server will now see free games for the bot default, %s.", describeRegion(b.config.DefaultRegion)), false)
+	} else {
+		b.respondToInteraction(s, i, fmt.Sprintf("This server will now see free games for %s.", describeRegion(locale)), false)
+	}
+	log.Printf("Server %s set region to %q", i.GuildID, locale)
+}
@@ -0,0 +1,82 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxRetryAfter is the longest Retry-After a send waits out. Longer waits
+// (e.g. a global ban) fail the send instead of stalling the delivery cycle.
+const maxRetryAfter = time.Minute
+
+// sendEmbedWithRetry posts a message, waiting out Discord 429 responses and
+// retrying up to the configured DISCORD_MAX_RETRIES times. discordgo's own
+// unbounded rate-limit retry is disabled for these sends so the limit holds.
+func (b *DiscordBot) sendEmbedWithRetry(channelID string, data *discordgo.MessageSend) (*discordgo.Message, error) {
+	var message *discordgo.Message
+	err := b.retryOnRateLimit("channel "+channelID, func() error {
+		var err error
+		message, err = b.session.ChannelMessageSendComplex(channelID, data, discordgo.WithRetryOnRatelimit(false))
+		return err
+	})
+	return message, err
+}
+
+// retryOnRateLimit calls send until it succeeds, fails with something other
+// than a 429 or runs out of retries. target names the destination in logs.
+func (b *DiscordBot) retryOnRateLimit(target string, send func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := send()
+		if err == nil {
+			return nil
+		}
+
+		wait, limited := retryAfter(err)
+		if !limited || attempt >= b.config.MaxRetries {
+			return err
+		}
+		if wait > maxRetryAfter {
+			return fmt.Errorf("rate limited for %s: %w", wait, err)
+		}
+
+		log.Printf("Rate limited sending to %s, retrying in %s (attempt %d/%d)", target, wait, attempt+1, b.config.MaxRetries)
+		timer := time.NewTimer(wait)
+		select {
+		case <-b.ctx.Done():
+			timer.Stop()
+			return b.ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryAfter reports whether err is a Discord 429 and how long to wait
+// before retrying
+func retryAfter(err error) (time.Duration, bool) {
+	var rateLimitErr *discordgo.RateLimitError
+	if errors.As(err, &rateLimitErr) && rateLimitErr.TooManyRequests != nil {
+		return rateLimitErr.RetryAfter, true
+	}
+
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) || restErr.Response == nil || restErr.Response.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	var body discordgo.TooManyRequests
+	if json.Unmarshal(restErr.ResponseBody, &body) == nil && body.RetryAfter > 0 {
+		return body.RetryAfter, true
+	}
+	// The header is in seconds, possibly fractional
+	if seconds, err := strconv.ParseFloat(restErr.Response.Header.Get("Retry-After"), 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+	return time.Second, true
+}
@@ -0,0 +1,183 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/security"
+)
+
+// minPostDelay is the lower bound of the /setdelay seconds option
+var minPostDelay float64 = 0
+
+// requireManageChannels checks that the invoking member may change this
+// server's bot settings, responding with an error when they may not
+func (b *DiscordBot) requireManageChannels(s *discordgo.Session, i *discordgo.InteractionCreate) bool {
+	if i.Member == nil || i.Member.User == nil {
+		b.respondToInteraction(s, i, "This command can only be used in a server.", true)
+		return false
+	}
+
+	permissions, err := s.UserChannelPermissions(i.Member.User.ID, i.ChannelID)
+	if err != nil {
+		b.respondToInteraction(s, i, "Error checking permissions.", true)
+		return false
+	}
+
+	if permissions&discordgo.PermissionManageChannels == 0 {
+		b.respondToInteraction(s, i, "You need 'Manage Channels' permission to use this command.", true)
+		return false
+	}
+
+	return true
+}
+
+// pause waits for d, returning early with the context error if ctx is done
+func pause(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// handleSetDelayCommand handles the /setdelay slash command
+func (b *DiscordBot) handleSetDelayCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	serverConfig, err := b.database.GetServerConfig(i.GuildID)
+	if err != nil {
+		b.respondToInteraction(s, i, "Error checking server configuration.", true)
+		return
+	}
+	if serverConfig == nil {
+		b.respondToInteraction(s, i, "This server is not configured yet. Use /setup first.", true)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please specify a delay in seconds.", true)
+		return
+	}
+
+	seconds := int(options[0].IntValue())
+	if seconds < 0 || seconds > database.MaxPostDelaySeconds {
+		b.respondToInteraction(s, i, fmt.Sprintf("The delay must be between 0 and %d seconds.", database.MaxPostDelaySeconds), true)
+		return
+	}
+
+	if err := b.database.SetPostDelay(i.GuildID, seconds); err != nil {
+		log.Printf("Error saving post delay for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to save the delay. Please try again.", true)
+		return
+	}
+
+	if seconds == 0 {
+		b.respondToInteraction(s, i, "Games will now be announced back to back.", false)
+	} else {
+		b.respondToInteraction(s, i, fmt.Sprintf("Games will now be announced %d seconds apart.", seconds), false)
+	}
+	log.Printf("Server %s set post delay to %ds", i.GuildID, seconds)
+}
+
+// handleSetRoleCommand handles /setrole set <role> and /setrole none
+func (b *DiscordBot) handleSetRoleCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	serverConfig, err := b.database.GetServerConfig(i.GuildID)
+	if err != nil {
+		b.respondToInteraction(s, i, "Error checking server configuration.", true)
+		return
+	}
+	if serverConfig == nil {
+		b.respondToInteraction(s, i, "This server is not configured yet. Use /setup first.", true)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please choose a subcommand.", true)
+		return
+	}
+
+	roleID := ""
+	if options[0].Name == "set" {
+		if len(options[0].Options) == 0 {
+			b.respondToInteraction(s, i, "Please specify a role.", true)
+			return
+		}
+		roleID, _ = options[0].Options[0].Value.(string)
+		if err := security.ValidateDiscordID(roleID); err != nil {
+			b.respondToInteraction(s, i, "That doesn't look like a valid role.", true)
+			return
+		}
+		if roleID == i.GuildID {
+			b.respondToInteraction(s, i, "Pinging @everyone isn't supported; please pick a dedicated role.", true)
+			return
+		}
+	}
+
+	candidate := *serverConfig
+	candidate.RoleID = roleID
+	if err := candidate.PolicyConflict(); err != nil {
+		b.respondToInteraction(s, i, fmt.Sprintf("Can't set an announcement role: %v. Change the content policy with `/customize policy` first.", err), true)
+		return
+	}
+
+	if err := b.database.SetMentionRole(i.GuildID, roleID); err != nil {
+		log.Printf("Error saving mention role for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to save the role. Please try again.", true)
+		return
+	}
+
+	if roleID == "" {
+		b.respondToInteraction(s, i, "New game announcements will no longer ping a role.", false)
+	} else {
+		b.respondToInteraction(s, i, fmt.Sprintf("New game announcements will ping <@&%s>.", roleID), false)
+	}
+	log.Printf("Server %s set mention role to %q", i.GuildID, roleID)
+}
+
+// handleUnsubscribeCommand handles /unsubscribe, stopping announcements in
+// this server. Settings are kept so /setup can resume them later.
+func (b *DiscordBot) handleUnsubscribeCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	serverConfig, err := b.database.GetServerConfig(i.GuildID)
+	if err != nil {
+		b.respondToInteraction(s, i, "Error checking server configuration.", true)
+		return
+	}
+	if serverConfig == nil {
+		b.respondToInteraction(s, i, "This server isn't receiving free game notifications. Use /setup to subscribe.", true)
+		return
+	}
+
+	if err := b.database.DeactivateServerConfig(i.GuildID, serverConfig.ChannelID); err != nil {
+		log.Printf("Error unsubscribing guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to unsubscribe. Please try again.", true)
+		return
+	}
+
+	b.respondToInteraction(s, i, fmt.Sprintf("Unsubscribed. I'll stop sending free game notifications to <#%s>. Use /setup to subscribe again; your other settings are kept.", serverConfig.ChannelID), true)
+	log.Printf("Server %s unsubscribed from notifications", i.GuildID)
+}
@@ -0,0 +1,41 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/models"
+)
+
+// smokeTestLabel heads smoke test announcements so nobody mistakes them for
+// real ones
+const smokeTestLabel = "🧪 **Deployment smoke test** - not a real announcement; it will be deleted shortly."
+
+// SendSmokeTestAnnouncement posts a clearly labeled announcement of game to
+// channelID and returns the message ID so it can be deleted afterwards
+func (b *DiscordBot) SendSmokeTestAnnouncement(ctx context.Context, channelID string, game models.Game) (string, error) {
+	if err := b.rateLimiter.WaitForChannel(ctx, channelID); err != nil {
+		return "", fmt.Errorf("rate limiter wait failed: %w", err)
+	}
+
+	message, err := b.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:         smokeTestLabel,
+		Embeds:          []*discordgo.MessageEmbed{b.gameInfoEmbed(game, time.UTC)},
+		AllowedMentions: &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}},
+	}, discordgo.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("error sending smoke test announcement: %w", err)
+	}
+	return message.ID, nil
+}
+
+// DeleteSmokeTestAnnouncement removes a message posted by
+// SendSmokeTestAnnouncement
+func (b *DiscordBot) DeleteSmokeTestAnnouncement(ctx context.Context, channelID, messageID string) error {
+	if err := b.session.ChannelMessageDelete(channelID, messageID, discordgo.WithContext(ctx)); err != nil {
+		return fmt.Errorf("error deleting smoke test announcement: %w", err)
+	}
+	return nil
+}
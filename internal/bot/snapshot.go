@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// interactionUserID returns the ID of the user who triggered an interaction
+// in either a guild or a DM context
+func interactionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+// isOwner reports whether the interaction was triggered by the configured bot owner
+func (b *DiscordBot) isOwner(i *discordgo.InteractionCreate) bool {
+	return b.config.OwnerID != "" && interactionUserID(i) == b.config.OwnerID
+}
+
+// snapshotPath resolves a snapshot file name inside the snapshot directory,
+// rejecting anything that could escape it
+func (b *DiscordBot) snapshotPath(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || filepath.Base(name) != name || !strings.HasSuffix(name, ".json") {
+		return "", fmt.Errorf("invalid snapshot file name %q", name)
+	}
+	return filepath.Join(b.config.SnapshotDir, name), nil
+}
+
+// handleSnapshotCommand handles the owner-only /snapshot slash command
+func (b *DiscordBot) handleSnapshotCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.isOwner(i) {
+		b.respondToInteraction(s, i, "This command is restricted to the bot owner.", true)
+		return
+	}
+
+	if err := os.MkdirAll(b.config.SnapshotDir, 0o755); err != nil {
+		log.Printf("Error creating snapshot directory: %v", err)
+		b.respondToInteraction(s, i, "Failed to create snapshot directory.", true)
+		return
+	}
+
+	name := fmt.Sprintf("games-%s.json", time.Now().UTC().Format("20060102-150405"))
+	path, err := b.snapshotPath(name)
+	if err != nil {
+		b.respondToInteraction(s, i, err.Error(), true)
+		return
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		log.Printf("Error creating snapshot file: %v", err)
+		b.respondToInteraction(s, i, "Failed to create snapshot file.", true)
+		return
+	}
+	defer file.Close()
+
+	count, err := b.gameService.WriteSnapshot(file)
+	if err != nil {
+		log.Printf("Error writing snapshot: %v", err)
+		os.Remove(path)
+		b.respondToInteraction(s, i, "Failed to write snapshot.", true)
+		return
+	}
+
+	log.Printf("Wrote snapshot of %d games to %s", count, path)
+	b.respondToInteraction(s, i, fmt.Sprintf("Saved %d games to `%s`.", count, name), true)
+}
+
+// handleRestoreCommand handles the owner-only /restore slash command
+func (b *DiscordBot) handleRestoreCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.isOwner(i) {
+		b.respondToInteraction(s, i, "This command is restricted to the bot owner.", true)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please specify a snapshot file.", true)
+		return
+	}
+
+	path, err := b.snapshotPath(options[0].StringValue())
+	if err != nil {
+		b.respondToInteraction(s, i, err.Error(), true)
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		b.respondToInteraction(s, i, fmt.Sprintf("Snapshot `%s` not found.", filepath.Base(path)), true)
+		return
+	}
+	defer file.Close()
+
+	count, err := b.gameService.RestoreSnapshot(file)
+	if err != nil {
+		log.Printf("Error restoring snapshot %s: %v", path, err)
+		b.respondToInteraction(s, i, fmt.Sprintf("Restore failed, catalog unchanged: %v", err), true)
+		return
+	}
+
+	log.Printf("Restored %d games from %s", count, path)
+	b.respondToInteraction(s, i, fmt.Sprintf("Restored %d games from `%s`.", count, filepath.Base(path)), true)
+}
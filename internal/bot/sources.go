@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/models"
+)
+
+// sourcesAll is the /sources choice for announcing every store, stored as an
+// empty list
+const sourcesAll = "all"
+
+// gogColor is the embed color of GOG giveaways, GOG's brand purple
+const gogColor = 0x86328a
+
+// sourceColor returns the embed color for a game: GOG giveaways are purple,
+// other games keep color
+func sourceColor(game models.Game, color int) int {
+	if game.Source == models.SourceGOG {
+		return gogColor
+	}
+	return color
+}
+
+// sourcesDescription renders a server's stored sources list for /status
+func sourcesDescription(sources string) string {
+	switch sources {
+	case "":
+		return "All"
+	case models.SourceEpic:
+		return "Epic Games Store"
+	case models.SourceGOG:
+		return "GOG"
+	default:
+		return sources
+	}
+}
+
+// handleSourcesCommand handles /sources, choosing which stores' giveaways
+// the server is told about
+func (b *DiscordBot) handleSourcesCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	serverConfig, err := b.database.GetServerConfig(i.GuildID)
+	if err != nil {
+		b.respondToInteraction(s, i, "Error checking server configuration.", true)
+		return
+	}
+	if serverConfig == nil {
+		b.respondToInteraction(s, i, "This server is not configured yet. Use /setup first.", true)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please choose the stores to announce.", true)
+		return
+	}
+	var sources string
+	switch choice := options[0].StringValue(); choice {
+	case sourcesAll:
+	case models.SourceEpic, models.SourceGOG:
+		sources = choice
+	default:
+		b.respondToInteraction(s, i, "Unknown choice. Choose all, epic or gog.", true)
+		return
+	}
+
+	if err := b.database.SetSources(i.GuildID, sources); err != nil {
+		log.Printf("Error saving sources for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to save the setting. Please try again.", true)
+		return
+	}
+
+	b.respondToInteraction(s, i, fmt.Sprintf("Announcing giveaways from: **%s**.", sourcesDescription(sources)), false)
+	log.Printf("Server %s set sources to %q", i.GuildID, sources)
+}
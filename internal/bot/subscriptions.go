@@ -0,0 +1,183 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/models"
+)
+
+// maxDMFailures is how many DMs in a row may fail (closed DMs, blocked bot)
+// before a user's subscription is deactivated
+const maxDMFailures = 3
+
+// Targets of /unsubscribe
+const (
+	unsubscribeServer = "server"
+	unsubscribeMe     = "me"
+)
+
+// handleSubscribeCommand handles /subscribe, signing the user up for free game DMs
+func (b *DiscordBot) handleSubscribeCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := interactionUserID(i)
+	if userID == "" {
+		b.respondToInteraction(s, i, "Could not identify your account.", true)
+		return
+	}
+
+	alreadySubscribed, err := b.database.SubscribeUser(userID)
+	if err != nil {
+		log.Printf("Error subscribing user %s: %v", userID, err)
+		b.respondToInteraction(s, i, "Failed to subscribe. Please try again.", true)
+		return
+	}
+
+	if alreadySubscribed {
+		b.respondToInteraction(s, i, "You're already subscribed. I'll DM you when a new game becomes free.", true)
+		return
+	}
+	b.respondToInteraction(s, i, "Subscribed! I'll DM you when a new game becomes free. Make sure you allow DMs from this bot. Use `/unsubscribe target:me` to stop.", true)
+	log.Printf("User %s subscribed to DM notifications", userID)
+}
+
+// handleUnsubscribe routes /unsubscribe to the server or the user's DM
+// subscription. Without a target it unsubscribes the server when used in a
+// server and the user when used in DMs.
+func (b *DiscordBot) handleUnsubscribe(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	target := unsubscribeServer
+	if i.GuildID == "" {
+		target = unsubscribeMe
+	}
+	if options := i.ApplicationCommandData().Options; len(options) > 0 {
+		target = options[0].StringValue()
+	}
+
+	if target == unsubscribeMe {
+		b.handleUserUnsubscribeCommand(s, i)
+		return
+	}
+	if i.GuildID == "" {
+		b.respondToInteraction(s, i, "Use this in a server to stop its notifications.", true)
+		return
+	}
+	b.handleUnsubscribeCommand(s, i)
+}
+
+// handleUserUnsubscribeCommand stops free game DMs for the user
+func (b *DiscordBot) handleUserUnsubscribeCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := interactionUserID(i)
+	wasSubscribed, err := b.database.UnsubscribeUser(userID)
+	if err != nil {
+		log.Printf("Error unsubscribing user %s: %v", userID, err)
+		b.respondToInteraction(s, i, "Failed to unsubscribe. Please try again.", true)
+		return
+	}
+
+	if !wasSubscribed {
+		b.respondToInteraction(s, i, "You aren't subscribed to free game DMs. Use /subscribe to sign up.", true)
+		return
+	}
+	b.respondToInteraction(s, i, "Unsubscribed. I won't DM you about free games anymore.", true)
+	log.Printf("User %s unsubscribed from DM notifications", userID)
+}
+
+// handleDMStatus handles /status used in DMs, showing the user's subscription
+func (b *DiscordBot) handleDMStatus(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	sub, err := b.database.GetUserSubscription(interactionUserID(i))
+	if err != nil {
+		log.Printf("Error loading subscription: %v", err)
+		b.respondToInteraction(s, i, "Error checking your subscription.", true)
+		return
+	}
+
+	switch {
+	case sub == nil:
+		b.respondToInteraction(s, i, "You aren't subscribed to free game DMs. Use /subscribe to sign up.", true)
+	case !sub.Active:
+		b.respondToInteraction(s, i, "Your subscription was paused because my DMs to you kept failing. Allow DMs from this bot, then use /subscribe to resume.", true)
+	default:
+		b.respondToInteraction(s, i, fmt.Sprintf("You're subscribed to free game DMs (since <t:%d:D>). Use `/unsubscribe target:me` to stop.", sub.CreatedAt.Unix()), true)
+	}
+}
+
+// deliverToSubscribers DMs new Free Now games to every subscribed user. Users
+// get games for the bot's default region. Each DM channel goes through the
+// rate limiter like any other channel.
+func (b *DiscordBot) deliverToSubscribers(ctx context.Context, games []models.Game) {
+	var available []models.Game
+	for _, game := range games {
+		if game.AvailableIn(b.config.DefaultRegion) {
+			available = append(available, game)
+		}
+	}
+	if len(available) == 0 {
+		return
+	}
+
+	subscribers, err := b.database.GetActiveSubscribers()
+	if err != nil {
+		log.Printf("Error loading DM subscribers: %v", err)
+		return
+	}
+
+	sent := 0
+	for _, userID := range subscribers {
+		if ctx.Err() != nil {
+			return
+		}
+
+		err := b.sendSubscriberDM(ctx, userID, available)
+		if err == nil {
+			sent++
+			if err := b.database.ResetSubscriptionFailures(userID); err != nil {
+				log.Printf("Error resetting DM failures for user %s: %v", userID, err)
+			}
+			continue
+		}
+
+		log.Printf("Error sending DM to user %s: %v", userID, err)
+		if !isDMClosed(err) {
+			continue
+		}
+		deactivated, err := b.database.RecordSubscriptionFailure(userID, maxDMFailures)
+		if err != nil {
+			log.Printf("Error recording DM failure for user %s: %v", userID, err)
+		} else if deactivated {
+			log.Printf("Deactivated DM subscription for user %s after %d failed DMs", userID, maxDMFailures)
+		}
+	}
+
+	if len(subscribers) > 0 {
+		log.Printf("Sent Free Now DMs to %d of %d subscribers", sent, len(subscribers))
+	}
+}
+
+// sendSubscriberDM opens a DM channel with a user and sends the games
+func (b *DiscordBot) sendSubscriberDM(ctx context.Context, userID string, games []models.Game) error {
+	channel, err := b.session.UserChannelCreate(userID)
+	if err != nil {
+		return fmt.Errorf("error opening DM: %w", err)
+	}
+
+	if _, err := b.sendFreeNowGames(ctx, games, channel.ID, nil, ""); err != nil {
+		return err
+	}
+	return nil
+}
+
+// isDMClosed reports whether a send error means the user does not accept DMs
+// from the bot
+func isDMClosed(err error) bool {
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) {
+		return false
+	}
+	if restErr.Message != nil && restErr.Message.Code == discordgo.ErrCodeCannotSendMessagesToThisUser {
+		return true
+	}
+	return restErr.Response != nil && restErr.Response.StatusCode == http.StatusForbidden
+}
@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+)
+
+// guildLocation returns the timezone a guild chose for displayed times, or
+// UTC outside guilds and when none is set
+func (b *DiscordBot) guildLocation(guildID string) *time.Location {
+	if guildID == "" {
+		return time.UTC
+	}
+	serverConfig, err := b.database.GetServerConfig(guildID)
+	if err != nil {
+		log.Printf("Error loading server config for guild %s: %v", guildID, err)
+	}
+	return serverConfig.Location()
+}
+
+// handleSetTimezoneCommand handles /settimezone, choosing the IANA timezone
+// the server's free periods are shown in
+func (b *DiscordBot) handleSetTimezoneCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.requireManageChannels(s, i) {
+		return
+	}
+
+	serverConfig, err := b.database.GetServerConfig(i.GuildID)
+	if err != nil {
+		b.respondToInteraction(s, i, "Error checking server configuration.", true)
+		return
+	}
+	if serverConfig == nil {
+		b.respondToInteraction(s, i, "This server is not configured yet. Use /setup first.", true)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		b.respondToInteraction(s, i, "Please give a timezone, e.g. Europe/Berlin.", true)
+		return
+	}
+	name := strings.TrimSpace(options[0].StringValue())
+	location, err := database.LoadTimezone(name)
+	if err != nil {
+		b.respondToInteraction(s, i, fmt.Sprintf("**%s** is not a known timezone. Use an IANA name such as Europe/Berlin, America/New_York or UTC.", name), true)
+		return
+	}
+
+	if err := b.database.SetTimezone(i.GuildID, location.String()); err != nil {
+		log.Printf("Error saving timezone for guild %s: %v", i.GuildID, err)
+		b.respondToInteraction(s, i, "Failed to save the setting. Please try again.", true)
+		return
+	}
+
+	b.respondToInteraction(s, i, fmt.Sprintf("Free periods will be shown in **%s** (currently %s), alongside a timestamp each reader sees in their own timezone.",
+		location.String(), time.Now().In(location).Format("Jan 2 15:04 MST")), false)
+	log.Printf("Server %s set timezone to %s", i.GuildID, location.String())
+}
@@ -0,0 +1,136 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// Image warm-up outcomes recorded in the delivery stats
+const (
+	warmupDisabled  = "disabled"
+	warmupSkipped   = "skipped"
+	warmupSucceeded = "succeeded"
+	warmupFailed    = "failed"
+	warmupTimedOut  = "timed_out"
+)
+
+// maxEmbedsPerMessage is the most embeds Discord accepts in one message
+const maxEmbedsPerMessage = 10
+
+// warmUpImages posts the images of the games about to be announced to the
+// operator's warm-up channel, so Discord's media proxy has fetched them before
+// the servers see their announcements. It never holds delivery back longer
+// than IMAGE_WARMUP_TIMEOUT; a warm-up that fails or times out is logged and
+// delivery goes ahead.
+func (b *DiscordBot) warmUpImages(ctx context.Context, jobs []deliveryJob) {
+	start := time.Now()
+	result := b.runImageWarmup(ctx, jobs)
+	elapsed := time.Since(start)
+	b.metrics.SetImageWarmup(result, elapsed)
+	if result != warmupDisabled {
+		log.Printf("Image warm-up %s in %s", result, elapsed.Round(time.Millisecond))
+	}
+}
+
+// runImageWarmup performs the warm-up and returns its outcome
+func (b *DiscordBot) runImageWarmup(ctx context.Context, jobs []deliveryJob) string {
+	if b.config.ImageWarmupChannelID == "" {
+		return warmupDisabled
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, b.config.ImageWarmupTimeout)
+	defer cancel()
+
+	done := make(chan string, 1)
+	go func() {
+		urls := warmupImageURLs(b.gamesToDeliver(jobs))
+		if len(urls) == 0 {
+			done <- warmupSkipped
+			return
+		}
+		if err := b.postWarmupImages(ctx, urls); err != nil {
+			log.Printf("Image warm-up failed: %v", err)
+			done <- warmupFailed
+			return
+		}
+		done <- warmupSucceeded
+	}()
+
+	select {
+	case result := <-done:
+		return result
+	case <-ctx.Done():
+		return warmupTimedOut
+	}
+}
+
+// gamesToDeliver returns the games the delivery filters let through for at
+// least one job, so games already announced everywhere are not warmed up
+// again every cycle
+func (b *DiscordBot) gamesToDeliver(jobs []deliveryJob) []models.Game {
+	var games []models.Game
+	seen := make(map[string]bool)
+	for _, job := range jobs {
+		if job.games == nil {
+			continue
+		}
+		filter := b.filterGames
+		if job.release {
+			filter = b.filterReleases
+		}
+		accepted, _ := filter(job.config, job.games.All())
+		for _, game := range accepted {
+			if key := database.NotificationKey(game); !seen[key] {
+				seen[key] = true
+				games = append(games, game)
+			}
+		}
+	}
+	return games
+}
+
+// postWarmupImages posts the images as image-only embeds, up to ten per message
+func (b *DiscordBot) postWarmupImages(ctx context.Context, urls []string) error {
+	channelID := b.config.ImageWarmupChannelID
+	for start := 0; start < len(urls); start += maxEmbedsPerMessage {
+		end := min(start+maxEmbedsPerMessage, len(urls))
+		embeds := make([]*discordgo.MessageEmbed, 0, end-start)
+		for _, url := range urls[start:end] {
+			embeds = append(embeds, &discordgo.MessageEmbed{Image: &discordgo.MessageEmbedImage{URL: url}})
+		}
+
+		if err := b.rateLimiter.WaitForChannel(ctx, channelID); err != nil {
+			return fmt.Errorf("rate limiter wait failed: %w", err)
+		}
+		if _, err := b.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Embeds: embeds}, discordgo.WithContext(ctx)); err != nil {
+			return fmt.Errorf("failed to post warm-up images: %w", err)
+		}
+	}
+	return nil
+}
+
+// warmupImageURLs returns the distinct images of the games, main images first
+// as they are what an announcement shows
+func warmupImageURLs(games []models.Game) []string {
+	seen := make(map[string]bool)
+	var main, gallery []string
+	for _, game := range games {
+		if game.ImageURL != "" && !seen[game.ImageURL] {
+			seen[game.ImageURL] = true
+			main = append(main, game.ImageURL)
+		}
+		for _, image := range game.GalleryImages() {
+			if !seen[image] {
+				seen[image] = true
+				gallery = append(gallery, image)
+			}
+		}
+	}
+	return append(main, gallery...)
+}
@@ -0,0 +1,176 @@
+package bot
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// warmupJob sets up a guild announcing in channel 900 and returns a job
+// delivering games to it
+func warmupJob(t *testing.T, b *DiscordBot, games ...models.Game) deliveryJob {
+	t.Helper()
+	const guildID = "guild"
+	if _, err := b.database.SaveServerConfig(guildID, "900"); err != nil {
+		t.Fatalf("SaveServerConfig: %v", err)
+	}
+	cfg, err := b.database.GetServerConfig(guildID)
+	if err != nil || cfg == nil {
+		t.Fatalf("GetServerConfig: %v, %v", cfg, err)
+	}
+	return deliveryJob{cycleID: "cycle", guildID: guildID, channelID: "900", config: cfg, games: &models.GameCollection{FreeNow: games}}
+}
+
+// imageGame is a running game with a main image and gallery images
+func imageGame(title string, gallery int) models.Game {
+	game := runningGame(title)
+	game.ImageURL = fmt.Sprintf("https://cdn.example/%s/main.jpg", strings.ToLower(title))
+	game.Images = []string{game.ImageURL}
+	for i := 0; i < gallery; i++ {
+		game.Images = append(game.Images, fmt.Sprintf("https://cdn.example/%s/%d.jpg", strings.ToLower(title), i))
+	}
+	return game
+}
+
+func TestWarmUpPostsImagesBeforeDelivery(t *testing.T) {
+	b := newTestBot(t)
+	b.config.ImageWarmupChannelID = "800"
+	b.config.ImageWarmupTimeout = 5 * time.Second
+	discord := useFakeDiscord(t, b)
+
+	// Eleven images need two warm-up messages
+	games := []models.Game{imageGame("First", 5), imageGame("Second", 4)}
+	b.runDeliveryCycle(b.ctx, []deliveryJob{warmupJob(t, b, games...)})
+
+	var order []string
+	var warmed []string
+	for _, request := range discord.find("POST", "channels/") {
+		order = append(order, strings.TrimSuffix(strings.TrimPrefix(request.Path, "channels/"), "/messages"))
+		if request.Path != "channels/800/messages" {
+			continue
+		}
+		embeds, _ := request.Body["embeds"].([]interface{})
+		for _, embed := range embeds {
+			image, _ := embed.(map[string]interface{})["image"].(map[string]interface{})
+			warmed = append(warmed, fmt.Sprint(image["url"]))
+		}
+	}
+
+	if len(order) < 3 || order[0] != "800" || order[1] != "800" {
+		t.Fatalf("posted to channels %v, want two warm-up messages first", order)
+	}
+	for _, channelID := range order[2:] {
+		if channelID != "900" {
+			t.Errorf("posted to channels %v, want every warm-up message before the announcements", order)
+			break
+		}
+	}
+	want := []string{games[0].ImageURL, games[1].ImageURL}
+	want = append(want, games[0].GalleryImages()...)
+	want = append(want, games[1].GalleryImages()...)
+	if fmt.Sprint(warmed) != fmt.Sprint(want) {
+		t.Errorf("warmed up %v, want main images first: %v", warmed, want)
+	}
+	if result, _ := b.metrics.GetImageWarmup(); result != warmupSucceeded {
+		t.Errorf("warm-up result = %q, want %q", result, warmupSucceeded)
+	}
+}
+
+func TestWarmUpNeverBlocksDelivery(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+	tests := []struct {
+		name      string
+		channelID string
+		announced bool
+		respond   func(release <-chan struct{}) func(fakeRequest) (int, string)
+		want      string
+		wantPosts int
+	}{
+		{
+			name: "disabled",
+			want: warmupDisabled,
+		},
+		{
+			name:      "nothing new to warm up",
+			channelID: "800",
+			announced: true,
+			want:      warmupSkipped,
+		},
+		{
+			name:      "warm-up channel refuses",
+			channelID: "800",
+			respond: func(<-chan struct{}) func(fakeRequest) (int, string) {
+				return func(request fakeRequest) (int, string) {
+					if request.Path == "channels/800/messages" {
+						return http.StatusForbidden, `{"code": 50013, "message": "Missing Permissions"}`
+					}
+					return 0, ""
+				}
+			},
+			want:      warmupFailed,
+			wantPosts: 1,
+		},
+		{
+			name:      "warm-up channel hangs",
+			channelID: "800",
+			respond: func(release <-chan struct{}) func(fakeRequest) (int, string) {
+				return func(request fakeRequest) (int, string) {
+					if request.Path == "channels/800/messages" {
+						<-release
+					}
+					return 0, ""
+				}
+			},
+			want:      warmupTimedOut,
+			wantPosts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBot(t)
+			b.config.ImageWarmupChannelID = tt.channelID
+			b.config.ImageWarmupTimeout = timeout
+			discord := useFakeDiscord(t, b)
+			// Runs before the fake server closes, which waits for its handlers
+			release := make(chan struct{})
+			t.Cleanup(func() { close(release) })
+			if tt.respond != nil {
+				discord.fail = tt.respond(release)
+			}
+
+			game := imageGame("Warm", 1)
+			job := warmupJob(t, b, game)
+			if tt.announced {
+				if _, err := b.database.MarkNotificationsSent(job.guildID, database.NotificationAnnouncement, []models.Game{game}); err != nil {
+					t.Fatalf("MarkNotificationsSent: %v", err)
+				}
+			}
+
+			b.runDeliveryCycle(b.ctx, []deliveryJob{job})
+
+			result, elapsed := b.metrics.GetImageWarmup()
+			if result != tt.want {
+				t.Errorf("warm-up result = %q, want %q", result, tt.want)
+			}
+			if elapsed > timeout+time.Second {
+				t.Errorf("warm-up took %s, over its %s timeout", elapsed, timeout)
+			}
+			if n := len(discord.find("POST", "channels/800/messages")); n != tt.wantPosts {
+				t.Errorf("made %d warm-up posts, want %d", n, tt.wantPosts)
+			}
+
+			announcements := len(discord.find("POST", "channels/900/messages"))
+			if tt.announced && announcements != 0 {
+				t.Errorf("announced %d times, want the announced game left out", announcements)
+			}
+			if !tt.announced && announcements == 0 {
+				t.Error("the game was not announced after the warm-up")
+			}
+		})
+	}
+}
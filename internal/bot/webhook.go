@@ -0,0 +1,47 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// webhookURLPrefix is the form security.ValidateDiscordWebhookURL normalizes
+// webhook URLs to
+const webhookURLPrefix = "https://discord.com/api/webhooks/"
+
+// webhookCredentials splits a normalized webhook URL into its ID and token
+func webhookCredentials(webhookURL string) (id, token string, ok bool) {
+	rest, found := strings.CutPrefix(webhookURL, webhookURLPrefix)
+	if !found {
+		return "", "", false
+	}
+	id, token, ok = strings.Cut(rest, "/")
+	return id, token, ok && id != "" && token != ""
+}
+
+// sendWebhookMessage posts a message through a guild's webhook and returns
+// it. Webhooks the bot did not create cannot carry buttons, so components are
+// dropped; the webhook's own name and avatar are kept.
+func (b *DiscordBot) sendWebhookMessage(webhookURL string, data *discordgo.MessageSend) (*discordgo.Message, error) {
+	id, token, ok := webhookCredentials(webhookURL)
+	if !ok {
+		return nil, fmt.Errorf("malformed webhook URL")
+	}
+
+	params := &discordgo.WebhookParams{
+		Content:         data.Content,
+		Embeds:          data.Embeds,
+		Components:      []discordgo.MessageComponent{},
+		AllowedMentions: data.AllowedMentions,
+		Flags:           data.Flags,
+	}
+	var message *discordgo.Message
+	err := b.retryOnRateLimit("webhook "+id, func() error {
+		var err error
+		message, err = b.session.WebhookExecute(id, token, true, params, discordgo.WithRetryOnRatelimit(false))
+		return err
+	})
+	return message, err
+}
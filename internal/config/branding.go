@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Branding defaults, matching the bot's original hardcoded strings
+const (
+	DefaultBrandingName        = "Free Games Bot"
+	DefaultBrandingFooter      = "Epic Games Store - Free Games Bot"
+	DefaultBrandingAccentColor = 0x0099ff
+)
+
+// Branding length limits. Discord allows longer footers, but these keep
+// embeds and page headers readable.
+const (
+	maxBrandingNameLength    = 64
+	maxBrandingFooterLength  = 256
+	maxBrandingLogoURLLength = 512
+)
+
+// BrandingConfig holds the names, logo and accent color a self-hosted
+// instance shows in embeds and web pages
+type BrandingConfig struct {
+	// Name replaces "Free Games Bot" in titles and per-game footers
+	Name string
+	// Footer is the footer of embeds that are not about a single game
+	Footer string
+	// LogoURL is shown as the embed footer icon and web page logo, if set
+	LogoURL string
+	// AccentColor is the embed color of informational messages
+	AccentColor int
+}
+
+// loadBranding reads the BRANDING_* variables, falling back to the defaults
+func loadBranding() (BrandingConfig, error) {
+	branding := BrandingConfig{
+		Name:        getEnvOrDefault("BRANDING_NAME", DefaultBrandingName),
+		Footer:      getEnvOrDefault("BRANDING_FOOTER", DefaultBrandingFooter),
+		LogoURL:     strings.TrimSpace(os.Getenv("BRANDING_LOGO_URL")),
+		AccentColor: DefaultBrandingAccentColor,
+	}
+
+	if value := strings.TrimSpace(os.Getenv("BRANDING_ACCENT_COLOR")); value != "" {
+		color, err := parseHexColor(value)
+		if err != nil {
+			return BrandingConfig{}, fmt.Errorf("invalid BRANDING_ACCENT_COLOR: %w", err)
+		}
+		branding.AccentColor = color
+	}
+
+	return branding, nil
+}
+
+// parseHexColor parses an RGB color such as "#0099ff" or "0099ff"
+func parseHexColor(value string) (int, error) {
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) != 6 {
+		return 0, fmt.Errorf("%q is not a 6-digit hex color", value)
+	}
+	color, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a 6-digit hex color", value)
+	}
+	return int(color), nil
+}
+
+// HexColor returns the accent color as "#rrggbb" for CSS
+func (b BrandingConfig) HexColor() string {
+	return fmt.Sprintf("#%06x", b.AccentColor)
+}
+
+// HasCustomAccent reports whether an accent color other than the default was
+// configured. The web pages keep their own palette unless it was.
+func (b BrandingConfig) HasCustomAccent() bool {
+	return b.AccentColor != DefaultBrandingAccentColor
+}
+
+// validate checks the branding lengths and that the logo is an absolute
+// http(s) URL
+func (b BrandingConfig) validate() error {
+	if b.Name == "" {
+		return fmt.Errorf("branding name cannot be empty")
+	}
+	if utf8.RuneCountInString(b.Name) > maxBrandingNameLength {
+		return fmt.Errorf("branding name must be at most %d characters", maxBrandingNameLength)
+	}
+	if b.Footer == "" {
+		return fmt.Errorf("branding footer cannot be empty")
+	}
+	if utf8.RuneCountInString(b.Footer) > maxBrandingFooterLength {
+		return fmt.Errorf("branding footer must be at most %d characters", maxBrandingFooterLength)
+	}
+	if b.AccentColor < 0 || b.AccentColor > 0xffffff {
+		return fmt.Errorf("branding accent color must be an RGB color")
+	}
+
+	if b.LogoURL == "" {
+		return nil
+	}
+	if len(b.LogoURL) > maxBrandingLogoURLLength {
+		return fmt.Errorf("branding logo URL must be at most %d characters", maxBrandingLogoURLLength)
+	}
+	logo, err := url.Parse(b.LogoURL)
+	if err != nil || (logo.Scheme != "https" && logo.Scheme != "http") || logo.Host == "" {
+		return fmt.Errorf("branding logo URL must be an absolute http(s) URL")
+	}
+	return nil
+}
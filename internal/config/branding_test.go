@@ -0,0 +1,100 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadBranding(t *testing.T) {
+	t.Setenv("BRANDING_NAME", "Loot Herald")
+	t.Setenv("BRANDING_FOOTER", "Loot Herald by the Guild")
+	t.Setenv("BRANDING_LOGO_URL", " https://example.com/herald.png ")
+	t.Setenv("BRANDING_ACCENT_COLOR", "#AA3377")
+
+	branding, err := loadBranding()
+	if err != nil {
+		t.Fatalf("loadBranding: %v", err)
+	}
+	want := BrandingConfig{Name: "Loot Herald", Footer: "Loot Herald by the Guild", LogoURL: "https://example.com/herald.png", AccentColor: 0xaa3377}
+	if branding != want {
+		t.Errorf("loadBranding = %+v, want %+v", branding, want)
+	}
+	if branding.HexColor() != "#aa3377" || !branding.HasCustomAccent() {
+		t.Errorf("HexColor = %q, HasCustomAccent = %v, want #aa3377 and true", branding.HexColor(), branding.HasCustomAccent())
+	}
+}
+
+func TestLoadBrandingDefaults(t *testing.T) {
+	for _, key := range []string{"BRANDING_NAME", "BRANDING_FOOTER", "BRANDING_LOGO_URL", "BRANDING_ACCENT_COLOR"} {
+		t.Setenv(key, "")
+	}
+
+	branding, err := loadBranding()
+	if err != nil {
+		t.Fatalf("loadBranding: %v", err)
+	}
+	want := BrandingConfig{Name: DefaultBrandingName, Footer: DefaultBrandingFooter, AccentColor: DefaultBrandingAccentColor}
+	if branding != want {
+		t.Errorf("loadBranding = %+v, want the defaults %+v", branding, want)
+	}
+	if branding.HasCustomAccent() {
+		t.Error("HasCustomAccent = true for the default color")
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{value: "#0099ff", want: 0x0099ff},
+		{value: "0099FF", want: 0x0099ff},
+		{value: "#000000", want: 0},
+		{value: "#fff", wantErr: true},
+		{value: "#0099ff00", wantErr: true},
+		{value: "#gg99ff", wantErr: true},
+		{value: "blue", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseHexColor(tt.value)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseHexColor(%q) = %#x, %v, want %#x, error %v", tt.value, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBrandingValidate(t *testing.T) {
+	valid := BrandingConfig{Name: DefaultBrandingName, Footer: DefaultBrandingFooter, AccentColor: DefaultBrandingAccentColor}
+	longLogo := "https://example.com/" + strings.Repeat("a", maxBrandingLogoURLLength)
+	tests := []struct {
+		name    string
+		change  func(b *BrandingConfig)
+		wantErr bool
+	}{
+		{name: "defaults", change: func(b *BrandingConfig) {}},
+		{name: "https logo", change: func(b *BrandingConfig) { b.LogoURL = "https://example.com/logo.png" }},
+		{name: "http logo", change: func(b *BrandingConfig) { b.LogoURL = "http://example.com/logo.png" }},
+		{name: "name at the limit", change: func(b *BrandingConfig) { b.Name = strings.Repeat("é", maxBrandingNameLength) }},
+		{name: "empty name", change: func(b *BrandingConfig) { b.Name = "" }, wantErr: true},
+		{name: "long name", change: func(b *BrandingConfig) { b.Name = strings.Repeat("a", maxBrandingNameLength+1) }, wantErr: true},
+		{name: "empty footer", change: func(b *BrandingConfig) { b.Footer = "" }, wantErr: true},
+		{name: "long footer", change: func(b *BrandingConfig) { b.Footer = strings.Repeat("a", maxBrandingFooterLength+1) }, wantErr: true},
+		{name: "color out of range", change: func(b *BrandingConfig) { b.AccentColor = 0x1000000 }, wantErr: true},
+		{name: "relative logo", change: func(b *BrandingConfig) { b.LogoURL = "/logo.png" }, wantErr: true},
+		{name: "javascript logo", change: func(b *BrandingConfig) { b.LogoURL = "javascript:alert(1)" }, wantErr: true},
+		{name: "data logo", change: func(b *BrandingConfig) { b.LogoURL = "data:image/png;base64,AAAA" }, wantErr: true},
+		{name: "long logo", change: func(b *BrandingConfig) { b.LogoURL = longLogo }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			branding := valid
+			tt.change(&branding)
+			if err := branding.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate(%+v) = %v, want error %v", branding, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,12 +1,16 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"runtime"
 	"strconv"
 	"strings"
 	"time"
+
+	"free-games-scrape/internal/models"
+	"free-games-scrape/internal/security"
 )
 
 // Config holds all configuration for the application
@@ -18,25 +22,62 @@ type Config struct {
 	App      AppConfig
 }
 
+// MaxImageWarmupTimeout caps how long image warm-up may hold announcements back
+const MaxImageWarmupTimeout = 5 * time.Second
+
 // DiscordConfig holds Discord-specific configuration
 type DiscordConfig struct {
-	Token           string
-	ClientID        string
-	ChannelID       string
-	MaxRetries      int
-	RetryDelay      time.Duration
-	CommandTimeout  time.Duration
-	RateLimitBuffer time.Duration
+	Token                 string
+	ClientID              string
+	ChannelID             string
+	MaxRetries            int
+	RetryDelay            time.Duration
+	CommandTimeout        time.Duration
+	RateLimitBuffer       time.Duration
+	RateLimitIdleTimeout  time.Duration
+	MaxConcurrentHandlers int
+	DeliveryWorkers       int
+	OwnerID               string
+	SnapshotDir           string
+	AnnounceDelay         time.Duration
+	OpsChannelID          string
+	ImageWarmupChannelID  string
+	ImageWarmupTimeout    time.Duration
+	CommandRegistration   string
+	CommandGuildThreshold int
+	DevGuildID            string
+	AdminRoleID           string
+	GuildAllowlist        []string
+	RefreshCooldown       time.Duration
+	DefaultRegion         string
+	Regions               []string
+	PrivacyURL            string
+	Branding              BrandingConfig
+	ReconcileInterval     time.Duration
+	ReconcileBudget       int
+	// BannerImages lets servers opt into a composed banner of the current
+	// games' covers above their announcements
+	BannerImages bool
 }
 
 // ScraperConfig holds scraper-specific configuration
 type ScraperConfig struct {
-	ChromePath   string
-	UserAgent    string
-	Timeout      time.Duration
-	MaxRetries   int
-	RetryDelay   time.Duration
-	RequestDelay time.Duration
+	Enabled       bool
+	Mode          string
+	ChromePath    string
+	PromotionsURL string
+	Locale        string
+	Locales       []string
+	GOGEnabled    bool
+	GOGCatalogURL string
+	UserAgent     string
+	Timeout       time.Duration
+	MaxRetries    int
+	RetryDelay    time.Duration
+	RequestDelay  time.Duration
+	// StoreURLBackfill looks up store links of stored games without one
+	StoreURLBackfill         bool
+	StoreURLLookupsPerMinute int
 }
 
 // DatabaseConfig holds database-specific configuration
@@ -54,36 +95,29 @@ type WebConfig struct {
 	WriteTimeout   time.Duration
 	IdleTimeout    time.Duration
 	MaxHeaderBytes int
+	AdminToken     string
+	PublicURL      string
+	Branding       BrandingConfig
 }
 
 // AppConfig holds application-level configuration
 type AppConfig struct {
-	Environment     string
-	LogLevel        string
-	RefreshInterval time.Duration
-	GracefulTimeout time.Duration
+	Environment        string
+	LogLevel           string
+	RefreshInterval    time.Duration
+	GracefulTimeout    time.Duration
+	CrashLoopThreshold int
+	CrashLoopBackoff   time.Duration
 }
 
-// Load loads configuration from environment variables with validation
+// Load loads configuration from environment variables with structural
+// validation. The error lists every invalid setting, one per line.
 func Load() (*Config, error) {
 	// Discord configuration
 	token := strings.TrimSpace(os.Getenv("DISCORD_BOT_TOKEN"))
-	if token == "" {
-		return nil, fmt.Errorf("DISCORD_BOT_TOKEN environment variable is required")
-	}
-
 	clientID := strings.TrimSpace(os.Getenv("DISCORD_CLIENT_ID"))
-	if clientID == "" {
-		return nil, fmt.Errorf("DISCORD_CLIENT_ID environment variable is required for bot verification")
-	}
-
 	channelID := strings.TrimSpace(os.Getenv("DISCORD_CHANNEL_ID"))
 
-	// Validate token format (basic check)
-	if len(token) < 50 || !strings.Contains(token, ".") {
-		return nil, fmt.Errorf("invalid Discord bot token format")
-	}
-
 	// Scraper configuration
 	chromePath := os.Getenv("CHROME_PATH")
 	if chromePath == "" {
@@ -92,6 +126,8 @@ func Load() (*Config, error) {
 
 	userAgent := getEnvOrDefault("USER_AGENT", "Mozilla/5.0 (compatible; FreeGamesBotScraper/2.0; +https://github.com/yourusername/free-games-bot)")
 
+	locale, locales := parseLocales(getEnvOrDefault("EPIC_LOCALE", models.DefaultLocale), os.Getenv("EPIC_LOCALES"))
+
 	// Database configuration
 	dbPath := getEnvOrDefault("DATABASE_PATH", "games.db")
 
@@ -100,6 +136,17 @@ func Load() (*Config, error) {
 	if !strings.HasPrefix(webPort, ":") {
 		webPort = ":" + webPort
 	}
+	publicURL := strings.TrimRight(strings.TrimSpace(os.Getenv("WEB_PUBLIC_URL")), "/")
+	privacyURL := ""
+	if publicURL != "" {
+		privacyURL = publicURL + "/privacy"
+	}
+
+	// Branding configuration, shared by the bot and the web server
+	branding, err := loadBranding()
+	if err != nil {
+		return nil, err
+	}
 
 	// App configuration
 	environment := getEnvOrDefault("ENVIRONMENT", "production")
@@ -107,21 +154,53 @@ func Load() (*Config, error) {
 
 	config := &Config{
 		Discord: DiscordConfig{
-			Token:           token,
-			ClientID:        clientID,
-			ChannelID:       channelID,
-			MaxRetries:      getEnvInt("DISCORD_MAX_RETRIES", 3),
-			RetryDelay:      getEnvDuration("DISCORD_RETRY_DELAY", 5*time.Second),
-			CommandTimeout:  getEnvDuration("DISCORD_COMMAND_TIMEOUT", 30*time.Second),
-			RateLimitBuffer: getEnvDuration("DISCORD_RATE_LIMIT_BUFFER", 1*time.Second),
+			Token:                 token,
+			ClientID:              clientID,
+			ChannelID:             channelID,
+			MaxRetries:            getEnvInt("DISCORD_MAX_RETRIES", 3),
+			RetryDelay:            getEnvDuration("DISCORD_RETRY_DELAY", 5*time.Second),
+			CommandTimeout:        getEnvDuration("DISCORD_COMMAND_TIMEOUT", 30*time.Second),
+			RateLimitBuffer:       getEnvDuration("DISCORD_RATE_LIMIT_BUFFER", 1*time.Second),
+			RateLimitIdleTimeout:  getEnvDuration("DISCORD_RATE_LIMIT_IDLE_TIMEOUT", 10*time.Minute),
+			MaxConcurrentHandlers: getEnvInt("DISCORD_MAX_CONCURRENT_HANDLERS", 10),
+			DeliveryWorkers:       getEnvInt("DISCORD_DELIVERY_WORKERS", 8),
+			OwnerID:               strings.TrimSpace(os.Getenv("DISCORD_OWNER_ID")),
+			SnapshotDir:           getEnvOrDefault("SNAPSHOT_DIR", "snapshots"),
+			AnnounceDelay:         getEnvDuration("ANNOUNCE_DELAY", 0),
+			OpsChannelID:          strings.TrimSpace(os.Getenv("OPS_CHANNEL_ID")),
+			ImageWarmupChannelID:  strings.TrimSpace(os.Getenv("IMAGE_WARMUP_CHANNEL_ID")),
+			ImageWarmupTimeout:    getEnvDuration("IMAGE_WARMUP_TIMEOUT", 2*time.Second),
+			CommandRegistration:   strings.ToLower(getEnvOrDefault("DISCORD_COMMAND_REGISTRATION", "global")),
+			CommandGuildThreshold: getEnvInt("DISCORD_COMMAND_GUILD_THRESHOLD", 50),
+			DevGuildID:            strings.TrimSpace(os.Getenv("DISCORD_DEV_GUILD_ID")),
+			AdminRoleID:           strings.TrimSpace(os.Getenv("DISCORD_ADMIN_ROLE_ID")),
+			GuildAllowlist:        parseIDList(os.Getenv("GUILD_ALLOWLIST")),
+			RefreshCooldown:       getEnvDuration("MANUAL_REFRESH_COOLDOWN", 10*time.Minute),
+			DefaultRegion:         locale,
+			Regions:               locales,
+			PrivacyURL:            privacyURL,
+			Branding:              branding,
+			ReconcileInterval:     getEnvDuration("CHANNEL_RECONCILE_INTERVAL", 7*24*time.Hour),
+			ReconcileBudget:       getEnvInt("CHANNEL_RECONCILE_BUDGET", 100),
+			BannerImages:          getEnvBool("BANNER_IMAGES", false),
 		},
 		Scraper: ScraperConfig{
-			ChromePath:   chromePath,
-			UserAgent:    userAgent,
-			Timeout:      getEnvDuration("SCRAPER_TIMEOUT", 90*time.Second),
-			MaxRetries:   getEnvInt("SCRAPER_MAX_RETRIES", 3),
-			RetryDelay:   getEnvDuration("SCRAPER_RETRY_DELAY", 5*time.Second),
-			RequestDelay: getEnvDuration("SCRAPER_REQUEST_DELAY", 2*time.Second),
+			Enabled:       getEnvBool("SCRAPING_ENABLED", true),
+			Mode:          strings.ToLower(getEnvOrDefault("SCRAPER_MODE", "auto")),
+			ChromePath:    chromePath,
+			PromotionsURL: strings.TrimSpace(os.Getenv("EPIC_PROMOTIONS_URL")),
+			Locale:        locale,
+			Locales:       locales,
+			GOGEnabled:    getEnvBool("GOG_ENABLED", false),
+			GOGCatalogURL: strings.TrimSpace(os.Getenv("GOG_CATALOG_URL")),
+			UserAgent:     userAgent,
+			Timeout:       getEnvDuration("SCRAPER_TIMEOUT", 90*time.Second),
+			MaxRetries:    getEnvInt("SCRAPER_MAX_RETRIES", 3),
+			RetryDelay:    getEnvDuration("SCRAPER_RETRY_DELAY", 5*time.Second),
+			RequestDelay:  getEnvDuration("SCRAPER_REQUEST_DELAY", 2*time.Second),
+
+			StoreURLBackfill:         getEnvBool("STORE_URL_BACKFILL", false),
+			StoreURLLookupsPerMinute: getEnvInt("STORE_URL_LOOKUPS_PER_MINUTE", 4),
 		},
 		Database: DatabaseConfig{
 			Path:              dbPath,
@@ -135,43 +214,137 @@ func Load() (*Config, error) {
 			WriteTimeout:   getEnvDuration("WEB_WRITE_TIMEOUT", 10*time.Second),
 			IdleTimeout:    getEnvDuration("WEB_IDLE_TIMEOUT", 60*time.Second),
 			MaxHeaderBytes: getEnvInt("WEB_MAX_HEADER_BYTES", 1<<20), // 1MB
+			AdminToken:     strings.TrimSpace(os.Getenv("WEB_ADMIN_TOKEN")),
+			PublicURL:      publicURL,
+			Branding:       branding,
 		},
 		App: AppConfig{
-			Environment:     environment,
-			LogLevel:        logLevel,
-			RefreshInterval: getEnvDuration("REFRESH_INTERVAL", 6*time.Hour),
-			GracefulTimeout: getEnvDuration("GRACEFUL_TIMEOUT", 30*time.Second),
+			Environment:        environment,
+			LogLevel:           logLevel,
+			RefreshInterval:    getEnvDuration("REFRESH_INTERVAL", 6*time.Hour),
+			GracefulTimeout:    getEnvDuration("GRACEFUL_TIMEOUT", 30*time.Second),
+			CrashLoopThreshold: getEnvInt("CRASH_LOOP_THRESHOLD", 3),
+			CrashLoopBackoff:   getEnvDuration("CRASH_LOOP_BACKOFF", 5*time.Minute),
 		},
 	}
 
-	// Validate configuration
+	// Validate configuration, reporting every problem at once
 	if err := config.Validate(); err != nil {
-		return nil, fmt.Errorf("configuration validation failed: %w", err)
+		return nil, fmt.Errorf("configuration validation failed:\n%w", err)
 	}
 
 	return config, nil
 }
 
-// Validate validates the configuration
+// FieldError is a structural problem with one configuration value, named by
+// the environment variable that sets it
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// Validate validates the configuration, joining every problem ValidateAll
+// finds into one error
 func (c *Config) Validate() error {
-	if c.Discord.Token == "" {
-		return fmt.Errorf("discord token is required")
-	}
+	return errors.Join(c.ValidateAll()...)
+}
 
-	if c.Discord.ClientID == "" {
-		return fmt.Errorf("discord client ID is required")
+// ValidateAll checks the configuration's structure and returns every problem
+// found rather than only the first. Whether the host can actually run it
+// (Chrome, the web port, the database directory) is left to
+// CheckEnvironment.
+func (c *Config) ValidateAll() []error {
+	var errs []error
+	check := func(ok bool, field, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+		}
+	}
+	checkID := func(id, field string) {
+		if id != "" {
+			check(security.ValidateDiscordID(id) == nil, field, "%q is not a Discord ID", id)
+		}
 	}
 
-
-	if c.Scraper.ChromePath == "" {
-		return fmt.Errorf("chrome path not found - please install Chrome/Chromium or set CHROME_PATH")
+	// Discord
+	check(c.Discord.Token != "", "DISCORD_BOT_TOKEN", "is required")
+	if c.Discord.Token != "" {
+		check(len(c.Discord.Token) >= 50 && strings.Contains(c.Discord.Token, "."), "DISCORD_BOT_TOKEN", "invalid Discord bot token format")
+	}
+	check(c.Discord.ClientID != "", "DISCORD_CLIENT_ID", "is required for bot verification")
+	checkID(c.Discord.ClientID, "DISCORD_CLIENT_ID")
+	checkID(c.Discord.ChannelID, "DISCORD_CHANNEL_ID")
+	checkID(c.Discord.OwnerID, "DISCORD_OWNER_ID")
+	checkID(c.Discord.OpsChannelID, "OPS_CHANNEL_ID")
+	checkID(c.Discord.ImageWarmupChannelID, "IMAGE_WARMUP_CHANNEL_ID")
+	checkID(c.Discord.DevGuildID, "DISCORD_DEV_GUILD_ID")
+	checkID(c.Discord.AdminRoleID, "DISCORD_ADMIN_ROLE_ID")
+	for _, guildID := range c.Discord.GuildAllowlist {
+		checkID(guildID, "GUILD_ALLOWLIST")
+	}
+	check(c.Discord.MaxRetries >= 0, "DISCORD_MAX_RETRIES", "cannot be negative")
+	check(c.Discord.RetryDelay > 0, "DISCORD_RETRY_DELAY", "must be positive")
+	check(c.Discord.CommandTimeout > 0, "DISCORD_COMMAND_TIMEOUT", "must be positive")
+	check(c.Discord.RateLimitBuffer >= 0, "DISCORD_RATE_LIMIT_BUFFER", "cannot be negative")
+	check(c.Discord.RateLimitIdleTimeout > 0, "DISCORD_RATE_LIMIT_IDLE_TIMEOUT", "must be positive")
+	check(c.Discord.MaxConcurrentHandlers >= 1, "DISCORD_MAX_CONCURRENT_HANDLERS", "must be at least 1")
+	check(c.Discord.DeliveryWorkers >= 1, "DISCORD_DELIVERY_WORKERS", "must be at least 1")
+	switch c.Discord.CommandRegistration {
+	case "global", "guild", "auto":
+	default:
+		check(false, "DISCORD_COMMAND_REGISTRATION", "invalid mode %q (expected global, guild or auto)", c.Discord.CommandRegistration)
+	}
+	check(c.Discord.CommandGuildThreshold >= 1, "DISCORD_COMMAND_GUILD_THRESHOLD", "must be at least 1")
+	check(c.Discord.ReconcileInterval >= time.Hour, "CHANNEL_RECONCILE_INTERVAL", "must be at least 1h")
+	check(c.Discord.ReconcileBudget >= 1, "CHANNEL_RECONCILE_BUDGET", "must be at least 1")
+	check(c.Discord.AnnounceDelay >= 0, "ANNOUNCE_DELAY", "cannot be negative")
+	check(c.Discord.RefreshCooldown >= 0, "MANUAL_REFRESH_COOLDOWN", "cannot be negative")
+	check(c.Discord.AnnounceDelay < c.App.RefreshInterval, "ANNOUNCE_DELAY", "must be shorter than the refresh interval")
+	check(c.Discord.ImageWarmupTimeout > 0 && c.Discord.ImageWarmupTimeout <= MaxImageWarmupTimeout, "IMAGE_WARMUP_TIMEOUT",
+		"must be between 0 and %s", MaxImageWarmupTimeout)
+	if err := c.Discord.Branding.validate(); err != nil {
+		errs = append(errs, err)
 	}
 
-	if c.App.RefreshInterval < time.Hour {
-		return fmt.Errorf("refresh interval must be at least 1 hour to respect Epic Games' servers")
+	// Scraper
+	switch c.Scraper.Mode {
+	case "auto", "api", "chrome":
+	default:
+		check(false, "SCRAPER_MODE", "invalid mode %q (expected auto, api or chrome)", c.Scraper.Mode)
+	}
+	for _, locale := range c.Scraper.Locales {
+		_, ok := models.LookupLocale(locale)
+		check(ok, "EPIC_LOCALES", "unsupported Epic locale %q", locale)
+	}
+	check(c.Scraper.Timeout > 0, "SCRAPER_TIMEOUT", "must be positive")
+	check(c.Scraper.MaxRetries >= 0, "SCRAPER_MAX_RETRIES", "cannot be negative")
+	check(c.Scraper.RetryDelay > 0, "SCRAPER_RETRY_DELAY", "must be positive")
+	check(c.Scraper.RequestDelay >= 0, "SCRAPER_REQUEST_DELAY", "cannot be negative")
+	if c.Scraper.StoreURLBackfill {
+		check(c.Scraper.StoreURLLookupsPerMinute >= 1 && c.Scraper.StoreURLLookupsPerMinute <= 30, "STORE_URL_LOOKUPS_PER_MINUTE", "must be between 1 and 30")
 	}
 
-	return nil
+	// Database
+	check(c.Database.Path != "", "DATABASE_PATH", "cannot be empty")
+
+	// Web
+	port, err := strconv.Atoi(strings.TrimPrefix(c.Web.Port, ":"))
+	check(err == nil && port >= 1 && port <= 65535, "WEB_PORT", "%q is not a port between 1 and 65535", strings.TrimPrefix(c.Web.Port, ":"))
+	check(c.Web.ReadTimeout > 0, "WEB_READ_TIMEOUT", "must be positive")
+	check(c.Web.WriteTimeout > 0, "WEB_WRITE_TIMEOUT", "must be positive")
+	check(c.Web.IdleTimeout > 0, "WEB_IDLE_TIMEOUT", "must be positive")
+
+	// App
+	check(c.App.RefreshInterval >= time.Hour, "REFRESH_INTERVAL", "must be at least 1 hour to respect Epic Games' servers")
+	check(c.App.GracefulTimeout > 0, "GRACEFUL_TIMEOUT", "must be positive")
+	check(c.App.CrashLoopThreshold >= 1, "CRASH_LOOP_THRESHOLD", "must be at least 1")
+	check(c.App.CrashLoopBackoff >= 0, "CRASH_LOOP_BACKOFF", "cannot be negative")
+
+	return errs
 }
 
 // IsDevelopment returns true if running in development mode
@@ -185,6 +358,41 @@ func (c *Config) IsProduction() bool {
 }
 
 // Helper functions
+// parseLocales canonicalizes EPIC_LOCALE and the comma-separated EPIC_LOCALES
+// list, making sure the default locale is scraped first
+func parseLocales(defaultLocale, list string) (string, []string) {
+	canonical := func(code string) string {
+		if locale, ok := models.LookupLocale(code); ok {
+			return locale.Code
+		}
+		return strings.TrimSpace(code)
+	}
+
+	defaultLocale = canonical(defaultLocale)
+	locales := []string{defaultLocale}
+	seen := map[string]bool{defaultLocale: true}
+	for _, code := range strings.Split(list, ",") {
+		code = canonical(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		locales = append(locales, code)
+	}
+	return defaultLocale, locales
+}
+
+// parseIDList splits a comma-separated list of Discord IDs, dropping blanks
+func parseIDList(list string) []string {
+	var ids []string
+	for _, id := range strings.Split(list, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
 		return value
@@ -201,6 +409,15 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -212,6 +429,15 @@ func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 
 // findChromePath attempts to find Chrome/Chromium executable
 func findChromePath() string {
+	if candidates := ChromeCandidates(); len(candidates) > 0 {
+		return candidates[0]
+	}
+	return ""
+}
+
+// ChromeCandidates returns the well-known Chrome/Chromium install locations
+// that exist on this machine, most preferred first
+func ChromeCandidates() []string {
 	var paths []string
 
 	switch runtime.GOOS {
@@ -236,12 +462,12 @@ func findChromePath() string {
 		}
 	}
 
+	var found []string
 	for _, path := range paths {
 		if _, err := os.Stat(path); err == nil {
-			return path
+			found = append(found, path)
 		}
 	}
 
-	return ""
+	return found
 }
-
@@ -0,0 +1,220 @@
+package config
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testToken    = "MTIzNDU2Nzg5MDEyMzQ1Njc4.GAbCdE.abcdefghijklmnopqrstuvwxyz0123456789"
+	testClientID = "123456789012345678"
+)
+
+// loadValid loads the default configuration with the required settings set
+func loadValid(t *testing.T) *Config {
+	t.Helper()
+	t.Setenv("DISCORD_BOT_TOKEN", testToken)
+	t.Setenv("DISCORD_CLIENT_ID", testClientID)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return cfg
+}
+
+func TestValidateAllRules(t *testing.T) {
+	tests := []struct {
+		field  string
+		change func(c *Config)
+	}{
+		// Discord
+		{"DISCORD_BOT_TOKEN", func(c *Config) { c.Discord.Token = "" }},
+		{"DISCORD_BOT_TOKEN", func(c *Config) { c.Discord.Token = "too-short" }},
+		{"DISCORD_BOT_TOKEN", func(c *Config) { c.Discord.Token = strings.Repeat("a", 60) }},
+		{"DISCORD_CLIENT_ID", func(c *Config) { c.Discord.ClientID = "" }},
+		{"DISCORD_CLIENT_ID", func(c *Config) { c.Discord.ClientID = "not-an-id" }},
+		{"DISCORD_CHANNEL_ID", func(c *Config) { c.Discord.ChannelID = "#general" }},
+		{"DISCORD_OWNER_ID", func(c *Config) { c.Discord.OwnerID = "owner" }},
+		{"OPS_CHANNEL_ID", func(c *Config) { c.Discord.OpsChannelID = "123" }},
+		{"IMAGE_WARMUP_CHANNEL_ID", func(c *Config) { c.Discord.ImageWarmupChannelID = "warmup" }},
+		{"DISCORD_DEV_GUILD_ID", func(c *Config) { c.Discord.DevGuildID = "dev" }},
+		{"DISCORD_ADMIN_ROLE_ID", func(c *Config) { c.Discord.AdminRoleID = "@admin" }},
+		{"GUILD_ALLOWLIST", func(c *Config) { c.Discord.GuildAllowlist = []string{testClientID, "guild"} }},
+		{"DISCORD_MAX_RETRIES", func(c *Config) { c.Discord.MaxRetries = -1 }},
+		{"DISCORD_RETRY_DELAY", func(c *Config) { c.Discord.RetryDelay = 0 }},
+		{"DISCORD_COMMAND_TIMEOUT", func(c *Config) { c.Discord.CommandTimeout = 0 }},
+		{"DISCORD_RATE_LIMIT_BUFFER", func(c *Config) { c.Discord.RateLimitBuffer = -time.Second }},
+		{"DISCORD_RATE_LIMIT_IDLE_TIMEOUT", func(c *Config) { c.Discord.RateLimitIdleTimeout = 0 }},
+		{"DISCORD_MAX_CONCURRENT_HANDLERS", func(c *Config) { c.Discord.MaxConcurrentHandlers = 0 }},
+		{"DISCORD_DELIVERY_WORKERS", func(c *Config) { c.Discord.DeliveryWorkers = 0 }},
+		{"DISCORD_COMMAND_REGISTRATION", func(c *Config) { c.Discord.CommandRegistration = "everywhere" }},
+		{"DISCORD_COMMAND_GUILD_THRESHOLD", func(c *Config) { c.Discord.CommandGuildThreshold = 0 }},
+		{"CHANNEL_RECONCILE_INTERVAL", func(c *Config) { c.Discord.ReconcileInterval = 30 * time.Minute }},
+		{"CHANNEL_RECONCILE_BUDGET", func(c *Config) { c.Discord.ReconcileBudget = 0 }},
+		{"ANNOUNCE_DELAY", func(c *Config) { c.Discord.AnnounceDelay = -time.Second }},
+		{"ANNOUNCE_DELAY", func(c *Config) { c.Discord.AnnounceDelay = c.App.RefreshInterval }},
+		{"MANUAL_REFRESH_COOLDOWN", func(c *Config) { c.Discord.RefreshCooldown = -time.Second }},
+		{"IMAGE_WARMUP_TIMEOUT", func(c *Config) { c.Discord.ImageWarmupTimeout = 0 }},
+		{"IMAGE_WARMUP_TIMEOUT", func(c *Config) { c.Discord.ImageWarmupTimeout = MaxImageWarmupTimeout + time.Second }},
+
+		// Scraper
+		{"SCRAPER_MODE", func(c *Config) { c.Scraper.Mode = "headless" }},
+		{"EPIC_LOCALES", func(c *Config) { c.Scraper.Locales = append(c.Scraper.Locales, "xx-XX") }},
+		{"SCRAPER_TIMEOUT", func(c *Config) { c.Scraper.Timeout = 0 }},
+		{"SCRAPER_MAX_RETRIES", func(c *Config) { c.Scraper.MaxRetries = -1 }},
+		{"SCRAPER_RETRY_DELAY", func(c *Config) { c.Scraper.RetryDelay = 0 }},
+		{"SCRAPER_REQUEST_DELAY", func(c *Config) { c.Scraper.RequestDelay = -time.Second }},
+		{"STORE_URL_LOOKUPS_PER_MINUTE", func(c *Config) { c.Scraper.StoreURLBackfill = true; c.Scraper.StoreURLLookupsPerMinute = 0 }},
+		{"STORE_URL_LOOKUPS_PER_MINUTE", func(c *Config) { c.Scraper.StoreURLBackfill = true; c.Scraper.StoreURLLookupsPerMinute = 31 }},
+
+		// Database
+		{"DATABASE_PATH", func(c *Config) { c.Database.Path = "" }},
+
+		// Web
+		{"WEB_PORT", func(c *Config) { c.Web.Port = ":http" }},
+		{"WEB_PORT", func(c *Config) { c.Web.Port = ":0" }},
+		{"WEB_PORT", func(c *Config) { c.Web.Port = ":65536" }},
+		{"WEB_READ_TIMEOUT", func(c *Config) { c.Web.ReadTimeout = 0 }},
+		{"WEB_WRITE_TIMEOUT", func(c *Config) { c.Web.WriteTimeout = 0 }},
+		{"WEB_IDLE_TIMEOUT", func(c *Config) { c.Web.IdleTimeout = 0 }},
+
+		// App
+		{"REFRESH_INTERVAL", func(c *Config) { c.App.RefreshInterval = 30 * time.Minute; c.Discord.AnnounceDelay = 0 }},
+		{"GRACEFUL_TIMEOUT", func(c *Config) { c.App.GracefulTimeout = 0 }},
+		{"CRASH_LOOP_THRESHOLD", func(c *Config) { c.App.CrashLoopThreshold = 0 }},
+		{"CRASH_LOOP_BACKOFF", func(c *Config) { c.App.CrashLoopBackoff = -time.Second }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			cfg := loadValid(t)
+			tt.change(cfg)
+
+			errs := cfg.ValidateAll()
+			if len(errs) != 1 {
+				t.Fatalf("ValidateAll = %v, want one %s error", errs, tt.field)
+			}
+			var fieldErr *FieldError
+			if !errors.As(errs[0], &fieldErr) || fieldErr.Field != tt.field {
+				t.Errorf("ValidateAll = %v, want a FieldError for %s", errs[0], tt.field)
+			}
+		})
+	}
+}
+
+func TestValidateAllAcceptsValidSettings(t *testing.T) {
+	cfg := loadValid(t)
+	cfg.Discord.ChannelID = testClientID
+	cfg.Discord.GuildAllowlist = []string{testClientID, "876543210987654321"}
+	cfg.Discord.CommandRegistration = "auto"
+	cfg.Discord.ImageWarmupTimeout = MaxImageWarmupTimeout
+	cfg.Scraper.Mode = "api"
+	cfg.Scraper.StoreURLBackfill = true
+	cfg.Scraper.StoreURLLookupsPerMinute = 30
+	cfg.Web.Port = ":65535"
+
+	if errs := cfg.ValidateAll(); len(errs) != 0 {
+		t.Errorf("ValidateAll = %v, want no errors", errs)
+	}
+}
+
+func TestValidateAllReportsEveryError(t *testing.T) {
+	cfg := loadValid(t)
+	cfg.Discord.Token = ""
+	cfg.Discord.RetryDelay = 0
+	cfg.Web.Port = ":0"
+	cfg.Discord.Branding.Name = ""
+
+	errs := cfg.ValidateAll()
+	if len(errs) != 4 {
+		t.Errorf("ValidateAll = %v, want all 4 errors", errs)
+	}
+
+	err := cfg.Validate()
+	for _, want := range []string{"DISCORD_BOT_TOKEN: is required", "DISCORD_RETRY_DELAY: must be positive", "WEB_PORT:", "branding name"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate = %v, want it to mention %q", err, want)
+		}
+	}
+}
+
+func TestLoadReportsInvalidSettings(t *testing.T) {
+	t.Setenv("DISCORD_BOT_TOKEN", testToken)
+	t.Setenv("DISCORD_CLIENT_ID", testClientID)
+	t.Setenv("GUILD_ALLOWLIST", testClientID+", not-a-guild")
+	t.Setenv("WEB_PORT", "70000")
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("Load accepted invalid settings")
+	}
+	for _, want := range []string{"GUILD_ALLOWLIST", "WEB_PORT"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Load error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func TestCheckEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	chrome := filepath.Join(dir, "chrome")
+	if err := os.WriteFile(chrome, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	notExecutable := filepath.Join(dir, "chrome.txt")
+	if err := os.WriteFile(notExecutable, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A port that is taken for the duration of the test
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	takenPort := ":" + port
+
+	tests := []struct {
+		name      string
+		change    func(c *Config)
+		subsystem string
+		fatal     bool
+	}{
+		{name: "ready", change: func(c *Config) {}},
+		{name: "api mode needs no chrome", change: func(c *Config) { c.Scraper.Mode = "api"; c.Scraper.ChromePath = "" }},
+		{name: "scraping disabled needs no chrome", change: func(c *Config) { c.Scraper.Enabled = false; c.Scraper.ChromePath = "" }},
+		{name: "chrome missing", change: func(c *Config) { c.Scraper.ChromePath = filepath.Join(dir, "missing") }, subsystem: SubsystemScraper},
+		{name: "chrome not executable", change: func(c *Config) { c.Scraper.ChromePath = notExecutable }, subsystem: SubsystemScraper},
+		{name: "port taken", change: func(c *Config) { c.Web.Port = takenPort }, subsystem: SubsystemWeb},
+		{name: "database directory missing", change: func(c *Config) { c.Database.Path = filepath.Join(dir, "missing", "games.db") }, subsystem: SubsystemDatabase, fatal: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Scraper:  ScraperConfig{Enabled: true, Mode: "auto", ChromePath: chrome},
+				Database: DatabaseConfig{Path: filepath.Join(dir, "games.db")},
+				Web:      WebConfig{Port: ":0"},
+			}
+			tt.change(cfg)
+
+			issues := cfg.CheckEnvironment()
+			if tt.subsystem == "" {
+				if len(issues) != 0 {
+					t.Errorf("CheckEnvironment = %v, want no issues", issues)
+				}
+				return
+			}
+			if len(issues) != 1 || issues[0].Subsystem != tt.subsystem || issues[0].Fatal != tt.fatal {
+				t.Errorf("CheckEnvironment = %+v, want one %s issue with fatal %v", issues, tt.subsystem, tt.fatal)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Subsystems an EnvironmentIssue can affect
+const (
+	SubsystemScraper  = "scraper"
+	SubsystemWeb      = "web"
+	SubsystemDatabase = "database"
+)
+
+// EnvironmentIssue is a problem with the host found by CheckEnvironment.
+// Fatal issues mean the bot cannot run; the others leave a subsystem degraded.
+type EnvironmentIssue struct {
+	Subsystem string
+	Fatal     bool
+	Err       error
+}
+
+func (i EnvironmentIssue) Error() string {
+	return i.Subsystem + ": " + i.Err.Error()
+}
+
+// CheckEnvironment checks that the host can run a structurally valid
+// configuration: that Chrome is executable, the web port is free and the
+// database directory is writable. It is run once at startup, after Load.
+func (c *Config) CheckEnvironment() []EnvironmentIssue {
+	var issues []EnvironmentIssue
+
+	if c.Scraper.Enabled && c.Scraper.Mode != "api" {
+		if err := checkExecutable(c.Scraper.ChromePath); err != nil {
+			// Auto mode still has the promotions API without Chrome; chrome
+			// mode starts without scraping and serves the stored games
+			issues = append(issues, EnvironmentIssue{
+				Subsystem: SubsystemScraper,
+				Err:       fmt.Errorf("%w - install Chrome/Chromium, set CHROME_PATH, use SCRAPER_MODE=api or set SCRAPING_ENABLED=false", err),
+			})
+		}
+	}
+
+	if listener, err := net.Listen("tcp", c.Web.Port); err != nil {
+		issues = append(issues, EnvironmentIssue{
+			Subsystem: SubsystemWeb,
+			Err:       fmt.Errorf("WEB_PORT %s is not available: %w", c.Web.Port, err),
+		})
+	} else {
+		listener.Close()
+	}
+
+	if err := checkWritableDir(filepath.Dir(c.Database.Path)); err != nil {
+		issues = append(issues, EnvironmentIssue{
+			Subsystem: SubsystemDatabase,
+			Fatal:     true,
+			Err:       fmt.Errorf("DATABASE_PATH directory is not writable: %w", err),
+		})
+	}
+
+	return issues
+}
+
+// checkExecutable reports whether path is an executable file
+func checkExecutable(path string) error {
+	if path == "" {
+		return fmt.Errorf("chrome path not found")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("chrome not found at %s: %w", path, err)
+	}
+	// Windows has no executable bit
+	if info.IsDir() || (runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0) {
+		return fmt.Errorf("chrome at %s is not executable", path)
+	}
+	return nil
+}
+
+// checkWritableDir creates and removes a file in dir
+func checkWritableDir(dir string) error {
+	file, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return err
+	}
+	name := file.Name()
+	file.Close()
+	return os.Remove(name)
+}
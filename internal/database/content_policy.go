@@ -0,0 +1,125 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Content policy constraints chosen with /customize policy, for servers whose
+// posting rules the announcements must follow. A server's policy is stored as
+// a comma-separated list of them, empty when it has none.
+const (
+	// PolicyNoMentions never pings a role, @here or @everyone
+	PolicyNoMentions = "no-mentions"
+	// PolicyNoLinks leaves store links and links in reminders out
+	PolicyNoLinks = "no-links"
+	// PolicyTextOnly posts text without embeds, images or link previews
+	PolicyTextOnly = "text-only"
+)
+
+// PolicyOff is the /customize policy value that clears the policy
+const PolicyOff = "off"
+
+// policyConstraints lists the constraints in the order they are stored
+var policyConstraints = []string{PolicyNoMentions, PolicyNoLinks, PolicyTextOnly}
+
+// ContentPolicy is a server's parsed content policy
+type ContentPolicy struct {
+	NoMentions bool
+	NoLinks    bool
+	TextOnly   bool
+}
+
+// Policy returns the server's content policy; servers without one, and
+// command lookups (c nil), have none
+func (c *ServerConfig) Policy() ContentPolicy {
+	var policy ContentPolicy
+	if c == nil {
+		return policy
+	}
+	for _, constraint := range strings.Split(c.ContentPolicy, ",") {
+		switch constraint {
+		case PolicyNoMentions:
+			policy.NoMentions = true
+		case PolicyNoLinks:
+			policy.NoLinks = true
+		case PolicyTextOnly:
+			policy.TextOnly = true
+		}
+	}
+	return policy
+}
+
+// ParseContentPolicy parses a list of constraints separated by commas or
+// spaces into its stored form. "off" clears the policy and can't be combined
+// with constraints.
+func ParseContentPolicy(value string) (string, error) {
+	fields := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	if len(fields) == 0 {
+		return "", fmt.Errorf("no constraints given")
+	}
+
+	chosen := make(map[string]bool)
+	for _, field := range fields {
+		switch field {
+		case PolicyOff:
+			if len(fields) > 1 {
+				return "", fmt.Errorf("%s can't be combined with other constraints", PolicyOff)
+			}
+			return "", nil
+		case PolicyNoMentions, PolicyNoLinks, PolicyTextOnly:
+			chosen[field] = true
+		default:
+			return "", fmt.Errorf("unknown constraint %q (expected %s, or %s)", field, strings.Join(policyConstraints, ", "), PolicyOff)
+		}
+	}
+
+	var constraints []string
+	for _, constraint := range policyConstraints {
+		if chosen[constraint] {
+			constraints = append(constraints, constraint)
+		}
+	}
+	return strings.Join(constraints, ","), nil
+}
+
+// PolicyConflict reports a setting that contradicts the server's content
+// policy: an announcement role under no-mentions, or a format with embeds
+// under text-only. The settings commands refuse changes that would cause one.
+func (c *ServerConfig) PolicyConflict() error {
+	policy := c.Policy()
+	if policy.NoMentions && c.RoleID != "" {
+		return fmt.Errorf("%s can't be combined with an announcement role", PolicyNoMentions)
+	}
+	if policy.TextOnly && c.MessageFormat != FormatPlain {
+		format := c.MessageFormat
+		if format == "" {
+			format = FormatEmbed
+		}
+		return fmt.Errorf("%s needs the %s format, not %s", PolicyTextOnly, FormatPlain, format)
+	}
+	return nil
+}
+
+// PresetPolicyConflict reports the conflict with the content policy that
+// applying preset to config would cause, if any
+func PresetPolicyConflict(config *ServerConfig, preset *Preset) error {
+	candidate := *config
+	for _, change := range DiffPreset(config, preset) {
+		switch change.Setting.Key {
+		case "message_format":
+			candidate.MessageFormat = change.NewValue
+		case "content_policy":
+			candidate.ContentPolicy = change.NewValue
+		}
+	}
+	return candidate.PolicyConflict()
+}
+
+// SetContentPolicy stores a guild's content policy, as returned by
+// ParseContentPolicy
+func (d *Database) SetContentPolicy(guildID, policy string) error {
+	return d.updateServerSetting(guildID, "content_policy", policy)
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseContentPolicy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr string
+	}{
+		{value: "no-mentions", want: "no-mentions"},
+		{value: "text-only, no-links no-mentions", want: "no-mentions,no-links,text-only"},
+		{value: "NO-LINKS", want: "no-links"},
+		{value: "no-links,no-links", want: "no-links"},
+		{value: "off", want: ""},
+		{value: " Off ", want: ""},
+		{value: "off,no-links", wantErr: "can't be combined"},
+		{value: "no-links off", wantErr: "can't be combined"},
+		{value: "off off", wantErr: "can't be combined"},
+		{value: "", wantErr: "no constraints"},
+		{value: " , ", wantErr: "no constraints"},
+		{value: "no-pings", wantErr: `unknown constraint "no-pings"`},
+		{value: "no-links,embeds-only", wantErr: `unknown constraint "embeds-only"`},
+	}
+	for _, tt := range tests {
+		got, err := ParseContentPolicy(tt.value)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseContentPolicy(%q) error = %v, want it to mention %q", tt.value, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseContentPolicy(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestPolicyConflict(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ServerConfig
+		wantErr string
+	}{
+		{name: "no policy with a role", config: ServerConfig{RoleID: "1", MessageFormat: FormatEmbed}},
+		{name: "no-mentions without a role", config: ServerConfig{ContentPolicy: PolicyNoMentions}},
+		{name: "no-mentions with a role", config: ServerConfig{ContentPolicy: PolicyNoMentions, RoleID: "1"}, wantErr: "announcement role"},
+		{name: "no-links with embeds", config: ServerConfig{ContentPolicy: PolicyNoLinks, MessageFormat: FormatEmbed}},
+		{name: "text-only in plain format", config: ServerConfig{ContentPolicy: PolicyTextOnly, MessageFormat: FormatPlain}},
+		{name: "text-only in embed format", config: ServerConfig{ContentPolicy: PolicyTextOnly, MessageFormat: FormatEmbed}, wantErr: "not embed"},
+		{name: "text-only in both format", config: ServerConfig{ContentPolicy: PolicyTextOnly, MessageFormat: FormatBoth}, wantErr: "not both"},
+		{name: "text-only in the default format", config: ServerConfig{ContentPolicy: PolicyTextOnly}, wantErr: "not embed"},
+		{name: "every constraint satisfied", config: ServerConfig{ContentPolicy: "no-mentions,no-links,text-only", MessageFormat: FormatPlain}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.PolicyConflict()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("PolicyConflict = %v, want none", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("PolicyConflict = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPresetPolicyConflict(t *testing.T) {
+	textOnly := ServerConfig{ContentPolicy: PolicyTextOnly, MessageFormat: FormatPlain}
+	embed := ServerConfig{MessageFormat: FormatEmbed}
+
+	tests := []struct {
+		name    string
+		config  ServerConfig
+		preset  *Preset
+		wantErr bool
+	}{
+		{name: "plain preset under text-only", config: textOnly, preset: BuiltinPreset("minimal")},
+		{name: "embed preset under text-only", config: textOnly, preset: BuiltinPreset("family-friendly"), wantErr: true},
+		{name: "both preset under text-only", config: textOnly, preset: BuiltinPreset("full-featured"), wantErr: true},
+		{
+			name:    "preset turning on text-only keeps embeds",
+			config:  embed,
+			preset:  &Preset{Name: "quiet", Settings: map[string]string{"content_policy": PolicyTextOnly}},
+			wantErr: true,
+		},
+		{
+			name:   "preset turning on text-only with the plain format",
+			config: embed,
+			preset: &Preset{Name: "quiet", Settings: map[string]string{"content_policy": PolicyTextOnly, "message_format": FormatPlain}},
+		},
+		{
+			name:   "preset clearing the policy allows embeds",
+			config: textOnly,
+			preset: &Preset{Name: "rich", Settings: map[string]string{"content_policy": "", "message_format": FormatBoth}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			err := PresetPolicyConflict(&config, tt.preset)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PresetPolicyConflict = %v, want error %v", err, tt.wantErr)
+			}
+			if config != tt.config {
+				t.Errorf("PresetPolicyConflict changed the config to %+v", config)
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -12,10 +14,191 @@ import (
 
 // ServerConfig represents a Discord server configuration
 type ServerConfig struct {
-	GuildID   string `json:"guild_id"`
-	ChannelID string `json:"channel_id"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	GuildID          string `json:"guild_id"`
+	ChannelID        string `json:"channel_id"`
+	CreatedAt        string `json:"created_at"`
+	UpdatedAt        string `json:"updated_at"`
+	PostDelaySeconds int    `json:"post_delay_seconds"`
+	TextFallback     bool   `json:"text_fallback"`
+	RoleID           string `json:"role_id,omitempty"`
+	Region           string `json:"region,omitempty"`
+	ClaimReminder    string `json:"claim_reminder,omitempty"`
+	ExpiryReminders  bool   `json:"expiry_reminders"`
+	NeedsAttention   string `json:"needs_attention,omitempty"`
+	WebhookURL       string `json:"-"`
+	ComingSoonMode   string `json:"comingsoon_mode"`
+	MessageFormat    string `json:"message_format"`
+	Timezone         string `json:"timezone,omitempty"`
+	Sources          string `json:"sources,omitempty"`
+	ContentPolicy    string `json:"content_policy,omitempty"`
+	BannerImages     bool   `json:"banner_images"`
+}
+
+// Coming Soon modes chosen with /comingsoon
+const (
+	// ComingSoonAnnounce posts Coming Soon games in advance only
+	ComingSoonAnnounce = "announce"
+	// ComingSoonReleaseOnly skips Coming Soon posts and announces games when
+	// they become free instead
+	ComingSoonReleaseOnly = "release_only"
+	// ComingSoonBoth posts Coming Soon games in advance and again at release,
+	// the default
+	ComingSoonBoth = "both"
+)
+
+// Announcement formats chosen with /format
+const (
+	// FormatEmbed posts each game as a rich embed, the default
+	FormatEmbed = "embed"
+	// FormatPlain posts a one-line summary and the store link, leaving the
+	// preview to Discord
+	FormatPlain = "plain"
+	// FormatBoth posts the summary and store link above the embed
+	FormatBoth = "both"
+)
+
+// AnnouncesReleases reports whether the server is told when a Coming Soon
+// game becomes free
+func (c *ServerConfig) AnnouncesReleases() bool {
+	return c != nil && (c.ComingSoonMode == ComingSoonReleaseOnly || c.ComingSoonMode == ComingSoonBoth)
+}
+
+// MaxClaimReminderLength caps the per-guild claim reminder, in characters
+const MaxClaimReminderLength = 200
+
+// MaxPostDelaySeconds caps the per-guild delay between consecutive announcements
+const MaxPostDelaySeconds = 30
+
+// PostDelay returns the pause to leave between consecutive game embeds
+func (c *ServerConfig) PostDelay() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return time.Duration(c.PostDelaySeconds) * time.Second
+}
+
+// RoleMention returns the mention for the configured announcement role, or ""
+// when there is none or the content policy forbids mentions
+func (c *ServerConfig) RoleMention() string {
+	if c == nil || c.RoleID == "" || c.Policy().NoMentions {
+		return ""
+	}
+	return "<@&" + c.RoleID + ">"
+}
+
+// WantsSource reports whether the server announces games from a store
+// (models.Source*). Servers that did not choose with /sources get every
+// store; a game without a source is from the Epic Games Store.
+func (c *ServerConfig) WantsSource(source string) bool {
+	if c == nil || c.Sources == "" {
+		return true
+	}
+	if source == "" {
+		source = models.SourceEpic
+	}
+	for _, wanted := range strings.Split(c.Sources, ",") {
+		if wanted == source {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultTimezone is used for displayed times when a server has not chosen one
+const DefaultTimezone = "UTC"
+
+// TimezoneName returns the server's IANA timezone, or DefaultTimezone when
+// none is set
+func (c *ServerConfig) TimezoneName() string {
+	if c == nil || c.Timezone == "" {
+		return DefaultTimezone
+	}
+	return c.Timezone
+}
+
+// Location returns the server's timezone, falling back to UTC when none is
+// set or the stored name no longer loads
+func (c *ServerConfig) Location() *time.Location {
+	if c == nil || c.Timezone == "" {
+		return time.UTC
+	}
+	location, err := LoadTimezone(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// LoadTimezone loads an IANA timezone name such as "Europe/Berlin". "Local"
+// is rejected because it depends on the host the bot runs on.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	return location, nil
+}
+
+// serverConfigColumns is the column list scanned by scanServerConfig
+const serverConfigColumns = "guild_id, channel_id, created_at, updated_at, COALESCE(post_delay_seconds, 0), COALESCE(text_fallback, 0), COALESCE(role_id, ''), COALESCE(region, ''), COALESCE(claim_reminder, ''), COALESCE(expiry_reminders, 1), COALESCE(needs_attention, ''), COALESCE(webhook_url, ''), COALESCE(comingsoon_mode, 'both'), COALESCE(message_format, 'embed'), COALESCE(timezone, ''), COALESCE(sources, ''), COALESCE(content_policy, ''), COALESCE(banner_images, 0)"
+
+// scanServerConfig scans a row selected with serverConfigColumns into config
+func scanServerConfig(row rowScanner, config *ServerConfig) error {
+	return row.Scan(&config.GuildID, &config.ChannelID, &config.CreatedAt, &config.UpdatedAt, &config.PostDelaySeconds, &config.TextFallback, &config.RoleID, &config.Region, &config.ClaimReminder, &config.ExpiryReminders, &config.NeedsAttention, &config.WebhookURL, &config.ComingSoonMode, &config.MessageFormat, &config.Timezone, &config.Sources, &config.ContentPolicy, &config.BannerImages)
+}
+
+// gameColumns is the column list scanned by scanGame
+const gameColumns = "id, title, image_url, status, free_from, free_to, COALESCE(store_url, ''), COALESCE(source, ''), COALESCE(regions, ''), COALESCE(images, ''), COALESCE(period, ''), COALESCE(free_from_at, ''), COALESCE(free_to_at, '')"
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanGame scans a row selected with gameColumns into game
+func scanGame(row rowScanner, game *models.Game) error {
+	var regions, images, freeFromAt, freeToAt string
+	if err := row.Scan(&game.ID, &game.Title, &game.ImageURL, &game.Status, &game.FreeFrom, &game.FreeTo, &game.StoreURL, &game.Source, &regions, &images, &game.Period, &freeFromAt, &freeToAt); err != nil {
+		return err
+	}
+	if regions != "" {
+		game.Regions = strings.Split(regions, ",")
+	}
+	if images != "" {
+		game.Images = strings.Split(images, "\n")
+	}
+	game.FreeFromTime = parseStoredTime(freeFromAt)
+	game.FreeToTime = parseStoredTime(freeToAt)
+	game.ParseDates(time.Now())
+	return nil
+}
+
+// storedTimeLayout is the UTC layout of free_from_at and free_to_at
+const storedTimeLayout = "2006-01-02 15:04:05"
+
+// formatStoredTime formats t for a nullable time column, storing NULL for the
+// zero time
+func formatStoredTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(storedTimeLayout)
+}
+
+// parseStoredTime parses a value written by formatStoredTime, returning the
+// zero time for NULL or malformed values
+func parseStoredTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	t, err := time.ParseInLocation(storedTimeLayout, value, time.UTC)
+	if err != nil {
+		return time.Time{}
+	}
+	return t.Local()
 }
 
 // Database handles SQLite operations
@@ -36,18 +219,233 @@ func New(dbPath string) (*Database, error) {
 		return nil, fmt.Errorf("failed to create tables: %w", err)
 	}
 
+	if err := database.ensureColumn("games", "store_url", "TEXT"); err != nil {
+		return nil, fmt.Errorf("failed to migrate games table: %w", err)
+	}
+
+	if err := database.ensureColumn("games", "source", "TEXT"); err != nil {
+		return nil, fmt.Errorf("failed to migrate games table: %w", err)
+	}
+
+	if err := database.ensureColumn("games", "regions", "TEXT"); err != nil {
+		return nil, fmt.Errorf("failed to migrate games table: %w", err)
+	}
+
+	if err := database.ensureColumn("games", "images", "TEXT"); err != nil {
+		return nil, fmt.Errorf("failed to migrate games table: %w", err)
+	}
+
+	// Raw period text and the parsed free window (UTC)
+	for _, column := range []string{"period", "free_from_at", "free_to_at"} {
+		if err := database.ensureColumn("games", column, "TEXT"); err != nil {
+			return nil, fmt.Errorf("failed to migrate games table: %w", err)
+		}
+	}
+
+	// Outcome and time of the last store URL backfill lookup
+	for _, column := range []string{"store_url_lookup", "store_url_lookup_at"} {
+		if err := database.ensureColumn("games", column, "TEXT"); err != nil {
+			return nil, fmt.Errorf("failed to migrate games table: %w", err)
+		}
+	}
+
+	if err := database.ensureNotifiedColumn(); err != nil {
+		return nil, fmt.Errorf("failed to migrate games table: %w", err)
+	}
+
+	if err := database.migrateGamesSourceKey(); err != nil {
+		return nil, err
+	}
+
 	if err := database.createServerConfigTable(); err != nil {
 		return nil, fmt.Errorf("failed to create server config table: %w", err)
 	}
 
+	if err := database.ensureColumn("server_configs", "post_delay_seconds", "INTEGER DEFAULT 0"); err != nil {
+		return nil, fmt.Errorf("failed to migrate server_configs table: %w", err)
+	}
+
+	if err := database.ensureColumn("server_configs", "text_fallback", "INTEGER DEFAULT 0"); err != nil {
+		return nil, fmt.Errorf("failed to migrate server_configs table: %w", err)
+	}
+
+	if err := database.ensureColumn("server_configs", "role_id", "TEXT"); err != nil {
+		return nil, fmt.Errorf("failed to migrate server_configs table: %w", err)
+	}
+
+	if err := database.ensureColumn("server_configs", "region", "TEXT"); err != nil {
+		return nil, fmt.Errorf("failed to migrate server_configs table: %w", err)
+	}
+
+	if err := database.ensureColumn("server_configs", "claim_reminder", "TEXT"); err != nil {
+		return nil, fmt.Errorf("failed to migrate server_configs table: %w", err)
+	}
+
+	if err := database.ensureColumn("server_configs", "expiry_reminders", "INTEGER DEFAULT 1"); err != nil {
+		return nil, fmt.Errorf("failed to migrate server_configs table: %w", err)
+	}
+
+	if err := database.ensureColumn("server_configs", "needs_attention", "TEXT"); err != nil {
+		return nil, fmt.Errorf("failed to migrate server_configs table: %w", err)
+	}
+
+	if err := database.ensureColumn("server_configs", "webhook_url", "TEXT"); err != nil {
+		return nil, fmt.Errorf("failed to migrate server_configs table: %w", err)
+	}
+
+	if err := database.ensureColumn("server_configs", "comingsoon_mode", "TEXT DEFAULT 'both'"); err != nil {
+		return nil, fmt.Errorf("failed to migrate server_configs table: %w", err)
+	}
+
+	if err := database.ensureColumn("server_configs", "message_format", "TEXT DEFAULT 'embed'"); err != nil {
+		return nil, fmt.Errorf("failed to migrate server_configs table: %w", err)
+	}
+
+	if err := database.ensureColumn("server_configs", "timezone", "TEXT"); err != nil {
+		return nil, fmt.Errorf("failed to migrate server_configs table: %w", err)
+	}
+
+	if err := database.ensureColumn("server_configs", "sources", "TEXT"); err != nil {
+		return nil, fmt.Errorf("failed to migrate server_configs table: %w", err)
+	}
+
+	if err := database.ensureColumn("server_configs", "content_policy", "TEXT"); err != nil {
+		return nil, fmt.Errorf("failed to migrate server_configs table: %w", err)
+	}
+
+	if err := database.ensureColumn("server_configs", "banner_images", "INTEGER DEFAULT 0"); err != nil {
+		return nil, fmt.Errorf("failed to migrate server_configs table: %w", err)
+	}
+
+	if err := database.createDeliveryDecisionsTable(); err != nil {
+		return nil, fmt.Errorf("failed to create delivery decisions table: %w", err)
+	}
+
+	if err := database.ensureColumn("delivery_decisions", "policy", "TEXT"); err != nil {
+		return nil, fmt.Errorf("failed to migrate delivery_decisions table: %w", err)
+	}
+
+	if err := database.createBotStateTable(); err != nil {
+		return nil, fmt.Errorf("failed to create bot state table: %w", err)
+	}
+
+	if err := database.createMutedGamesTable(); err != nil {
+		return nil, fmt.Errorf("failed to create muted games table: %w", err)
+	}
+
+	if err := database.createKnownGuildsTable(); err != nil {
+		return nil, fmt.Errorf("failed to create known guilds table: %w", err)
+	}
+
+	if err := database.ensureColumn("known_guilds", "member_count", "INTEGER DEFAULT 0"); err != nil {
+		return nil, fmt.Errorf("failed to migrate known_guilds table: %w", err)
+	}
+
+	if err := database.createRestartsTable(); err != nil {
+		return nil, fmt.Errorf("failed to create restarts table: %w", err)
+	}
+
+	if err := database.createUserSubscriptionsTable(); err != nil {
+		return nil, fmt.Errorf("failed to create user subscriptions table: %w", err)
+	}
+
+	if err := database.createNotificationsSentTable(); err != nil {
+		return nil, fmt.Errorf("failed to create notifications sent table: %w", err)
+	}
+
+	if err := database.backfillAnnouncements(); err != nil {
+		return nil, err
+	}
+
+	if err := database.migrateComingSoonDefault(); err != nil {
+		return nil, err
+	}
+
+	if err := database.createGameChangesTable(); err != nil {
+		return nil, fmt.Errorf("failed to create game changes table: %w", err)
+	}
+
+	if err := database.createPostedMessagesTable(); err != nil {
+		return nil, fmt.Errorf("failed to create posted messages table: %w", err)
+	}
+
+	if err := database.createScrapeHistoryTable(); err != nil {
+		return nil, fmt.Errorf("failed to create scrape history table: %w", err)
+	}
+
+	if err := database.ensureColumn("scrape_history", "failure_class", "TEXT"); err != nil {
+		return nil, fmt.Errorf("failed to migrate scrape_history table: %w", err)
+	}
+
+	if err := database.createSettingsPresetsTable(); err != nil {
+		return nil, fmt.Errorf("failed to create settings presets table: %w", err)
+	}
+
 	return database, nil
 }
 
+// Ping checks that the database can still be reached and queried
+func (d *Database) Ping(ctx context.Context) error {
+	var one int
+	if err := d.db.QueryRowContext(ctx, `SELECT 1`).Scan(&one); err != nil {
+		return fmt.Errorf("database ping failed: %w", err)
+	}
+	return nil
+}
+
 // Close closes the database connection
 func (d *Database) Close() error {
 	return d.db.Close()
 }
 
+// ensureColumn adds a column to a table if it does not exist yet
+func (d *Database) ensureColumn(table, column, definition string) error {
+	exists, err := d.hasColumn(table, column)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	if _, err := d.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
+
+	log.Printf("Added column %s to %s table", column, table)
+	return nil
+}
+
+// hasColumn reports whether a table has a column
+func (d *Database) hasColumn(table, column string) (bool, error) {
+	rows, err := d.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			columnType string
+			notNull    int
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return false, fmt.Errorf("failed to scan column info: %w", err)
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to read column info: %w", err)
+	}
+	return false, nil
+}
+
 // createTables creates the necessary database tables
 func (d *Database) createTables() error {
 	// First check if the table exists
@@ -58,6 +456,10 @@ func (d *Database) createTables() error {
 		// Table exists, check if we need to migrate
 		var hasUniqueConstraint bool
 		err = d.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='index' AND name='idx_games_title_free_to'").Scan(&hasUniqueConstraint)
+		if err == nil && !hasUniqueConstraint {
+			// Tables keyed by source have no separate unique index
+			hasUniqueConstraint, err = d.gamesKeyedBySource()
+		}
 		
 		if err == nil && !hasUniqueConstraint {
 			// Need to migrate the table structure
@@ -107,7 +509,8 @@ func (d *Database) createTables() error {
 		}
 	}
 	
-	// Create table if it doesn't exist or if there was an error checking
+	// Create table if it doesn't exist or if there was an error checking.
+	// The other columns are added by New.
 	query := `
 	CREATE TABLE IF NOT EXISTS games (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -119,69 +522,264 @@ func (d *Database) createTables() error {
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		last_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
-		UNIQUE(title, free_to)
+		source TEXT NOT NULL DEFAULT 'epic',
+		` + gamesKey + `
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_games_status ON games(status);
 	CREATE INDEX IF NOT EXISTS idx_games_title ON games(title);
 	CREATE INDEX IF NOT EXISTS idx_games_last_seen ON games(last_seen);
-	CREATE UNIQUE INDEX IF NOT EXISTS idx_games_title_free_to ON games(title, free_to);
 	`
 
 	_, err = d.db.Exec(query)
 	return err
 }
 
-// SaveGames saves or updates games in the database
-func (d *Database) SaveGames(games []models.Game) error {
+// gamesKey is the unique key of games: a store's promotion of a title, told
+// apart from later promotions of it by the end date
+const gamesKey = "UNIQUE(source, title, free_to)"
+
+// gamesKeyedBySource reports whether the games table has gamesKey. Tables
+// created before it are keyed by title and end date alone.
+func (d *Database) gamesKeyedBySource() (bool, error) {
+	var schema string
+	if err := d.db.QueryRow("SELECT sql FROM sqlite_master WHERE type='table' AND name='games'").Scan(&schema); err != nil {
+		return false, fmt.Errorf("failed to inspect games table: %w", err)
+	}
+	return strings.Contains(schema, gamesKey), nil
+}
+
+// migrateGamesSourceKey rebuilds a games table keyed by title and end date
+// with gamesKey, so a game given away by two stores gets a row for each.
+// Games without a source are from the Epic Games Store. It runs after every
+// column has been added.
+func (d *Database) migrateGamesSourceKey() error {
+	keyed, err := d.gamesKeyedBySource()
+	if err != nil || keyed {
+		return err
+	}
+
+	log.Println("Migrating games table to key games by source...")
 	tx, err := d.db.Begin()
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	_, err = tx.Exec(`
+		CREATE TABLE games_new (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			title TEXT NOT NULL,
+			image_url TEXT,
+			status TEXT NOT NULL,
+			free_from TEXT,
+			free_to TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			last_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+			source TEXT NOT NULL DEFAULT 'epic',
+			store_url TEXT,
+			regions TEXT,
+			images TEXT,
+			period TEXT,
+			free_from_at TEXT,
+			free_to_at TEXT,
+			store_url_lookup TEXT,
+			store_url_lookup_at TEXT,
+			notified INTEGER DEFAULT 0,
+			` + gamesKey + `
+		);
+
+		INSERT INTO games_new
+			(id, title, image_url, status, free_from, free_to, created_at, updated_at, last_seen, source, store_url, regions, images,
+			period, free_from_at, free_to_at, store_url_lookup, store_url_lookup_at, notified)
+		SELECT
+			id, title, image_url, status, free_from, free_to, created_at, updated_at, last_seen, COALESCE(NULLIF(source, ''), 'epic'), store_url, regions, images,
+			period, free_from_at, free_to_at, store_url_lookup, store_url_lookup_at, notified
+		FROM games;
+
+		DROP TABLE games;
+		ALTER TABLE games_new RENAME TO games;
+
+		CREATE INDEX IF NOT EXISTS idx_games_status ON games(status);
+		CREATE INDEX IF NOT EXISTS idx_games_title ON games(title);
+		CREATE INDEX IF NOT EXISTS idx_games_last_seen ON games(last_seen);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to migrate games table: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Println("Successfully migrated games table")
+	return nil
+}
+
+// SaveGames saves or updates games in the database, recording and returning
+// the field-level edits of games that were already stored
+func (d *Database) SaveGames(games []models.Game) ([]models.FieldChange, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	// First, mark all games as not seen in this update
 	_, err = tx.Exec(`UPDATE games SET last_seen = datetime('now', '-1 day') WHERE 1=1`)
 	if err != nil {
-		return fmt.Errorf("failed to mark games as not seen: %w", err)
+		return nil, fmt.Errorf("failed to mark games as not seen: %w", err)
 	}
 
 	// Now insert or update each game
-	// We'll use title AND free_to as a composite key to handle cases where the same game becomes free again
+	// We'll use source, title AND free_to as a composite key to handle cases where the same game becomes free again
 	stmt, err := tx.Prepare(`
-		INSERT INTO games (title, image_url, status, free_from, free_to, updated_at, last_seen)
-		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		ON CONFLICT(title, free_to) DO UPDATE SET
+		INSERT INTO games (title, image_url, status, free_from, free_to, store_url, source, regions, images, period, free_from_at, free_to_at, updated_at, last_seen)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		ON CONFLICT(source, title, free_to) DO UPDATE SET
 			image_url = excluded.image_url,
 			status = excluded.status,
 			free_from = excluded.free_from,
+			store_url = COALESCE(NULLIF(excluded.store_url, ''), games.store_url),
+			regions = COALESCE(NULLIF(excluded.regions, ''), games.regions),
+			images = excluded.images,
+			period = COALESCE(NULLIF(excluded.period, ''), games.period),
+			free_from_at = COALESCE(excluded.free_from_at, games.free_from_at),
+			free_to_at = COALESCE(excluded.free_to_at, games.free_to_at),
 			updated_at = CURRENT_TIMESTAMP,
 			last_seen = CURRENT_TIMESTAMP
 	`)
 	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
+		return nil, fmt.Errorf("failed to prepare statement: %w", err)
 	}
 	defer stmt.Close()
 
+	scrapedKeys := make(map[string]bool, len(games))
 	for _, game := range games {
-		_, err := stmt.Exec(game.Title, game.ImageURL, game.Status, game.FreeFrom, game.FreeTo)
+		scrapedKeys[game.SourceKey()] = true
+	}
+
+	now := time.Now()
+	var changes []models.FieldChange
+	for _, game := range games {
+		gameChanges, err := detectFieldChanges(tx, game, scrapedKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = stmt.Exec(game.Title, game.ImageURL, game.Status, game.FreeFrom, game.FreeTo, game.StoreURL, game.StoreSource(), strings.Join(game.Regions, ","), strings.Join(game.Images, "\n"),
+			game.Period, formatStoredTime(game.FreeFromTime), formatStoredTime(game.FreeToTime))
 		if err != nil {
-			return fmt.Errorf("failed to save game %s: %w", game.Title, err)
+			return nil, fmt.Errorf("failed to save game %s: %w", game.Title, err)
+		}
+
+		if len(gameChanges) > 0 {
+			if err := recordFieldChanges(tx, game, gameChanges, now); err != nil {
+				return nil, err
+			}
+			changes = append(changes, gameChanges...)
 		}
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	log.Printf("Saved %d games to database", len(games))
+	return changes, nil
+}
+
+// ensureNotifiedColumn adds games.notified. Games already stored when the
+// column is added were handled by the earlier new-game detection, so they are
+// marked as notified rather than announced again.
+func (d *Database) ensureNotifiedColumn() error {
+	exists, err := d.hasColumn("games", "notified")
+	if err != nil || exists {
+		return err
+	}
+
+	if err := d.ensureColumn("games", "notified", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if _, err := d.db.Exec(`UPDATE games SET notified = 1`); err != nil {
+		return fmt.Errorf("failed to mark existing games as notified: %w", err)
+	}
 	return nil
 }
 
+// GetUnnotifiedGames returns the games that have not been announced yet. A
+// game counts as announced once MarkGamesNotified recorded it, however often
+// it disappears from and reappears in later scrapes while its row is kept.
+func (d *Database) GetUnnotifiedGames(games []models.Game) ([]models.Game, error) {
+	stmt, err := d.db.Prepare(`SELECT notified FROM games WHERE source = ? AND title = ? AND free_to = ?`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	var unnotified []models.Game
+	for _, game := range games {
+		var notified bool
+		err := stmt.QueryRow(game.StoreSource(), game.Title, game.FreeTo).Scan(&notified)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to check notified flag for %s: %w", game.Title, err)
+		}
+		if !notified {
+			unnotified = append(unnotified, game)
+		}
+	}
+	return unnotified, nil
+}
+
+// MarkGamesNotified records that games have been announced, so later scrapes
+// do not treat them as new
+func (d *Database) MarkGamesNotified(games []models.Game) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`UPDATE games SET notified = 1 WHERE source = ? AND title = ? AND free_to = ?`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, game := range games {
+		if _, err := stmt.Exec(game.StoreSource(), game.Title, game.FreeTo); err != nil {
+			return fmt.Errorf("failed to mark %s as notified: %w", game.Title, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetActiveGamesVersion returns a value that changes whenever the result of
+// GetActiveGames may have changed: the number of active games and the latest
+// time one of them was saved
+func (d *Database) GetActiveGamesVersion() (string, error) {
+	var count int
+	var latest sql.NullString
+	err := d.db.QueryRow(`
+		SELECT COUNT(*), MAX(updated_at)
+		FROM games
+		WHERE status IN ('Free Now', 'Coming Soon')
+		AND last_seen > datetime('now', '-7 days')
+	`).Scan(&count, &latest)
+	if err != nil {
+		return "", fmt.Errorf("failed to get active games version: %w", err)
+	}
+	return fmt.Sprintf("%d/%s", count, latest.String), nil
+}
+
 // GetActiveGames returns all currently active games
 func (d *Database) GetActiveGames() ([]models.Game, error) {
 	query := `
-		SELECT title, image_url, status, free_from, free_to
+		SELECT ` + gameColumns + `
 		FROM games
 		WHERE status IN ('Free Now', 'Coming Soon')
 		AND last_seen > datetime('now', '-7 days')
@@ -203,7 +801,7 @@ func (d *Database) GetActiveGames() ([]models.Game, error) {
 	var games []models.Game
 	for rows.Next() {
 		var game models.Game
-		err := rows.Scan(&game.Title, &game.ImageURL, &game.Status, &game.FreeFrom, &game.FreeTo)
+		err := scanGame(rows, &game)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan game: %w", err)
 		}
@@ -213,10 +811,40 @@ func (d *Database) GetActiveGames() ([]models.Game, error) {
 	return games, nil
 }
 
+// GetGamesEndingBetween returns active Free Now games whose promotion ends
+// after from and no later than to
+func (d *Database) GetGamesEndingBetween(from, to time.Time) ([]models.Game, error) {
+	query := `
+		SELECT ` + gameColumns + `
+		FROM games
+		WHERE status = 'Free Now'
+		AND last_seen > datetime('now', '-7 days')
+		AND free_to_at > ? AND free_to_at <= ?
+		ORDER BY free_to_at, title
+	`
+
+	rows, err := d.db.Query(query, formatStoredTime(from), formatStoredTime(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ending games: %w", err)
+	}
+	defer rows.Close()
+
+	var games []models.Game
+	for rows.Next() {
+		var game models.Game
+		if err := scanGame(rows, &game); err != nil {
+			return nil, fmt.Errorf("failed to scan game: %w", err)
+		}
+		games = append(games, game)
+	}
+
+	return games, rows.Err()
+}
+
 // GetNewGames returns games that are new since the last check
 func (d *Database) GetNewGames(since time.Time) ([]models.Game, error) {
 	query := `
-		SELECT title, image_url, status, free_from, free_to
+		SELECT ` + gameColumns + `
 		FROM games
 		WHERE created_at > ?
 		AND status IN ('Free Now', 'Coming Soon')
@@ -238,7 +866,37 @@ func (d *Database) GetNewGames(since time.Time) ([]models.Game, error) {
 	var games []models.Game
 	for rows.Next() {
 		var game models.Game
-		err := rows.Scan(&game.Title, &game.ImageURL, &game.Status, &game.FreeFrom, &game.FreeTo)
+		err := scanGame(rows, &game)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan game: %w", err)
+		}
+		games = append(games, game)
+	}
+
+	return games, nil
+}
+
+// GetGameHistory returns games first seen within the given time range
+func (d *Database) GetGameHistory(start, end time.Time) ([]models.Game, error) {
+	query := `
+		SELECT ` + gameColumns + `
+		FROM games
+		WHERE created_at >= ? AND created_at < ?
+		ORDER BY created_at, title
+	`
+
+	rows, err := d.db.Query(query,
+		start.UTC().Format("2006-01-02 15:04:05"),
+		end.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query game history: %w", err)
+	}
+	defer rows.Close()
+
+	var games []models.Game
+	for rows.Next() {
+		var game models.Game
+		err := scanGame(rows, &game)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan game: %w", err)
 		}
@@ -248,11 +906,117 @@ func (d *Database) GetNewGames(since time.Time) ([]models.Game, error) {
 	return games, nil
 }
 
-// CleanupOldGames removes games that haven't been seen for more than 30 days
+// GetAllGames returns every game in the catalog regardless of status or age
+func (d *Database) GetAllGames() ([]models.Game, error) {
+	query := `
+		SELECT ` + gameColumns + `
+		FROM games
+		ORDER BY created_at, title
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query games: %w", err)
+	}
+	defer rows.Close()
+
+	var games []models.Game
+	for rows.Next() {
+		var game models.Game
+		err := scanGame(rows, &game)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan game: %w", err)
+		}
+		games = append(games, game)
+	}
+
+	return games, nil
+}
+
+// GetSnapshotGames returns every game in the catalog, like GetAllGames, with
+// whether it was announced
+func (d *Database) GetSnapshotGames() ([]models.SnapshotGame, error) {
+	rows, err := d.db.Query(`
+		SELECT COALESCE(notified, 0), ` + gameColumns + `
+		FROM games
+		ORDER BY created_at, title
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query games: %w", err)
+	}
+	defer rows.Close()
+
+	var games []models.SnapshotGame
+	for rows.Next() {
+		var notified bool
+		var game models.SnapshotGame
+		if err := scanGame(prefixScanner{row: rows, prefix: []interface{}{&notified}}, &game.Game); err != nil {
+			return nil, fmt.Errorf("failed to scan game: %w", err)
+		}
+		game.Notified = &notified
+		game.FreeFromAt = exportTime(game.FreeFromTime)
+		game.FreeToAt = exportTime(game.FreeToTime)
+		games = append(games, game)
+	}
+
+	return games, rows.Err()
+}
+
+// ReplaceAllGames replaces the whole games catalog in a single transaction.
+// Games keep the announced flag of the snapshot; those of snapshots without
+// one are marked as announced, so restoring never posts the catalog again.
+// Promotion windows missing from old snapshots are parsed from the display
+// dates.
+func (d *Database) ReplaceAllGames(games []models.SnapshotGame) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM games`); err != nil {
+		return fmt.Errorf("failed to clear games: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO games (title, image_url, status, free_from, free_to, store_url, source, regions, images, period, free_from_at, free_to_at, notified)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, game := range games {
+		notified := game.Notified == nil || *game.Notified
+		if game.FreeFromAt != nil {
+			game.FreeFromTime = *game.FreeFromAt
+		}
+		if game.FreeToAt != nil {
+			game.FreeToTime = *game.FreeToAt
+		}
+		game.ParseDates(now)
+		if _, err := stmt.Exec(game.Title, game.ImageURL, game.Status, game.FreeFrom, game.FreeTo, game.StoreURL, game.StoreSource(), strings.Join(game.Regions, ","), strings.Join(game.Images, "\n"),
+			game.Period, formatStoredTime(game.FreeFromTime), formatStoredTime(game.FreeToTime), notified); err != nil {
+			return fmt.Errorf("failed to restore game %s: %w", game.Title, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("Restored %d games to database", len(games))
+	return nil
+}
+
+// CleanupOldGames removes games that haven't been seen for more than
+// GameRetentionDays
 func (d *Database) CleanupOldGames() error {
-	query := `DELETE FROM games WHERE last_seen < datetime('now', '-30 days')`
+	query := `DELETE FROM games WHERE last_seen < datetime('now', ?)`
 	
-	result, err := d.db.Exec(query)
+	result, err := d.db.Exec(query, fmt.Sprintf("-%d days", GameRetentionDays))
 	if err != nil {
 		return fmt.Errorf("failed to cleanup old games: %w", err)
 	}
@@ -268,16 +1032,14 @@ func (d *Database) CleanupOldGames() error {
 // GetGameByTitle retrieves a specific game by title
 func (d *Database) GetGameByTitle(title string) (*models.Game, error) {
 	query := `
-		SELECT title, image_url, status, free_from, free_to
+		SELECT ` + gameColumns + `
 		FROM games
 		WHERE title = ?
 		LIMIT 1
 	`
 
 	var game models.Game
-	err := d.db.QueryRow(query, title).Scan(
-		&game.Title, &game.ImageURL, &game.Status, &game.FreeFrom, &game.FreeTo,
-	)
+	err := scanGame(d.db.QueryRow(query, title), &game)
 	
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -289,6 +1051,32 @@ func (d *Database) GetGameByTitle(title string) (*models.Game, error) {
 	return &game, nil
 }
 
+// SearchGamesByTitle returns up to limit distinct stored titles starting with
+// prefix, matched case-insensitively and sorted alphabetically
+func (d *Database) SearchGamesByTitle(prefix string, limit int) ([]string, error) {
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix)
+	rows, err := d.db.Query(`
+		SELECT DISTINCT title FROM games
+		WHERE title LIKE ? ESCAPE '\'
+		ORDER BY title COLLATE NOCASE
+		LIMIT ?
+	`, escaped+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search games by title: %w", err)
+	}
+	defer rows.Close()
+
+	var titles []string
+	for rows.Next() {
+		var title string
+		if err := rows.Scan(&title); err != nil {
+			return nil, fmt.Errorf("failed to scan game title: %w", err)
+		}
+		titles = append(titles, title)
+	}
+	return titles, rows.Err()
+}
+
 // GetServerCount returns the total number of configured servers
 func (d *Database) GetServerCount() (int, error) {
 	query := `SELECT COUNT(*) FROM server_configs WHERE active = 1`
@@ -305,7 +1093,7 @@ func (d *Database) GetServerCount() (int, error) {
 // GetAllActiveServerConfigs returns all active server configurations
 func (d *Database) GetAllActiveServerConfigs() ([]*ServerConfig, error) {
 	query := `
-		SELECT guild_id, channel_id, created_at, updated_at
+		SELECT ` + serverConfigColumns + `
 		FROM server_configs 
 		WHERE active = 1
 		ORDER BY created_at
@@ -320,7 +1108,7 @@ func (d *Database) GetAllActiveServerConfigs() ([]*ServerConfig, error) {
 	var configs []*ServerConfig
 	for rows.Next() {
 		var config ServerConfig
-		err := rows.Scan(&config.GuildID, &config.ChannelID, &config.CreatedAt, &config.UpdatedAt)
+		err := scanServerConfig(rows, &config)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan server config: %w", err)
 		}
@@ -333,16 +1121,14 @@ func (d *Database) GetAllActiveServerConfigs() ([]*ServerConfig, error) {
 // GetServerConfig retrieves server configuration by guild ID
 func (d *Database) GetServerConfig(guildID string) (*ServerConfig, error) {
 	query := `
-		SELECT guild_id, channel_id, created_at, updated_at
+		SELECT ` + serverConfigColumns + `
 		FROM server_configs 
 		WHERE guild_id = ? AND active = 1
 		LIMIT 1
 	`
 	
 	var config ServerConfig
-	err := d.db.QueryRow(query, guildID).Scan(
-		&config.GuildID, &config.ChannelID, &config.CreatedAt, &config.UpdatedAt,
-	)
+	err := scanServerConfig(d.db.QueryRow(query, guildID), &config)
 	
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -354,19 +1140,191 @@ func (d *Database) GetServerConfig(guildID string) (*ServerConfig, error) {
 	return &config, nil
 }
 
-// SaveServerConfig saves or updates server configuration
-func (d *Database) SaveServerConfig(guildID, channelID string) error {
+// ServerConfigSaveResult describes what SaveServerConfig changed
+type ServerConfigSaveResult struct {
+	// Config is the configuration as stored after the save
+	Config *ServerConfig
+	// Created is true when the guild had never been configured before
+	Created bool
+	// Reactivated is true when a previously deactivated config was resumed
+	Reactivated bool
+	// PreviousChannelID is the channel notifications went to before, when the
+	// save moved them to a different channel
+	PreviousChannelID string
+}
+
+// SaveServerConfig points a guild's notifications at channelID and activates
+// them. Re-running setup only updates the channel, so every other per-guild
+// setting survives.
+func (d *Database) SaveServerConfig(guildID, channelID string) (*ServerConfigSaveResult, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := &ServerConfigSaveResult{}
+	var previousChannelID string
+	var wasActive bool
+	err = tx.QueryRow(`SELECT channel_id, COALESCE(active, 0) FROM server_configs WHERE guild_id = ?`, guildID).Scan(&previousChannelID, &wasActive)
+	switch {
+	case err == sql.ErrNoRows:
+		result.Created = true
+	case err != nil:
+		return nil, fmt.Errorf("failed to load existing server config: %w", err)
+	default:
+		result.Reactivated = !wasActive
+		if previousChannelID != channelID {
+			result.PreviousChannelID = previousChannelID
+		}
+	}
+
 	query := `
-		INSERT OR REPLACE INTO server_configs (guild_id, channel_id, updated_at)
-		VALUES (?, ?, CURRENT_TIMESTAMP)
+		INSERT INTO server_configs (guild_id, channel_id, active, comingsoon_mode, updated_at)
+		VALUES (?, ?, 1, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(guild_id) DO UPDATE SET
+			channel_id = excluded.channel_id,
+			active = 1,
+			needs_attention = NULL,
+			updated_at = CURRENT_TIMESTAMP
 	`
-	
-	_, err := d.db.Exec(query, guildID, channelID)
-	if err != nil {
-		return fmt.Errorf("failed to save server config: %w", err)
+	if _, err := tx.Exec(query, guildID, channelID, ComingSoonBoth); err != nil {
+		return nil, fmt.Errorf("failed to save server config: %w", err)
 	}
-	
+
+	var config ServerConfig
+	row := tx.QueryRow(`SELECT `+serverConfigColumns+` FROM server_configs WHERE guild_id = ?`, guildID)
+	if err := scanServerConfig(row, &config); err != nil {
+		return nil, fmt.Errorf("failed to read saved server config: %w", err)
+	}
+	result.Config = &config
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	log.Printf("Saved server config for guild %s, channel %s", guildID, channelID)
+	return result, nil
+}
+
+// SetPostDelay stores the delay between consecutive announcements for a guild
+func (d *Database) SetPostDelay(guildID string, seconds int) error {
+	return d.updateServerSetting(guildID, "post_delay_seconds", seconds)
+}
+
+// SetTextFallback stores whether a guild wants plain-text announcements when embeds are not allowed
+func (d *Database) SetTextFallback(guildID string, enabled bool) error {
+	return d.updateServerSetting(guildID, "text_fallback", enabled)
+}
+
+// SetExpiryReminders stores whether a guild gets "last chance" reminders
+// before Free Now games end
+func (d *Database) SetExpiryReminders(guildID string, enabled bool) error {
+	return d.updateServerSetting(guildID, "expiry_reminders", enabled)
+}
+
+// SetWebhookURL stores the Discord webhook announcements are posted through;
+// an empty url clears it so the bot posts in the channel itself
+func (d *Database) SetWebhookURL(guildID, url string) error {
+	var value interface{}
+	if url != "" {
+		value = url
+	}
+	return d.updateServerSetting(guildID, "webhook_url", value)
+}
+
+// SetComingSoonMode stores how a guild is told about Coming Soon games, one
+// of the ComingSoon* modes
+func (d *Database) SetComingSoonMode(guildID, mode string) error {
+	return d.updateServerSetting(guildID, "comingsoon_mode", mode)
+}
+
+// SetMessageFormat stores how a guild's announcements are rendered, one of
+// the Format* formats
+func (d *Database) SetMessageFormat(guildID, format string) error {
+	return d.updateServerSetting(guildID, "message_format", format)
+}
+
+// SetTimezone stores the IANA timezone a guild's displayed times use
+func (d *Database) SetTimezone(guildID, timezone string) error {
+	return d.updateServerSetting(guildID, "timezone", timezone)
+}
+
+// SetSources stores the comma-separated stores a guild announces games from;
+// empty means every store
+func (d *Database) SetSources(guildID, sources string) error {
+	return d.updateServerSetting(guildID, "sources", sources)
+}
+
+// comingSoonDefaultKey is set in bot_state once servers on the old default
+// Coming Soon mode were moved to the new one
+const comingSoonDefaultKey = "comingsoon_default_both"
+
+// migrateComingSoonDefault runs once per database. The default mode used to
+// be announce, which never posted the moment a Coming Soon game became free;
+// servers still on it are moved to both.
+func (d *Database) migrateComingSoonDefault() error {
+	done, err := d.GetBotState(comingSoonDefaultKey)
+	if err != nil || done != "" {
+		return err
+	}
+
+	result, err := d.db.Exec(`UPDATE server_configs SET comingsoon_mode = ? WHERE comingsoon_mode IS NULL OR comingsoon_mode = ?`, ComingSoonBoth, ComingSoonAnnounce)
+	if err != nil {
+		return fmt.Errorf("failed to migrate Coming Soon modes: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		log.Printf("Moved %d servers to the %s Coming Soon mode", rows, ComingSoonBoth)
+	}
+	return d.SetBotState(comingSoonDefaultKey, "1")
+}
+
+// SetMentionRole stores the role pinged on new game announcements; an empty
+// roleID clears it
+func (d *Database) SetMentionRole(guildID, roleID string) error {
+	var value interface{}
+	if roleID != "" {
+		value = roleID
+	}
+	return d.updateServerSetting(guildID, "role_id", value)
+}
+
+// SetRegion stores the Epic locale whose games a guild is shown; an empty
+// locale reverts to EPIC_LOCALE
+func (d *Database) SetRegion(guildID, locale string) error {
+	var value interface{}
+	if locale != "" {
+		value = locale
+	}
+	return d.updateServerSetting(guildID, "region", value)
+}
+
+// SetBannerImages stores whether a guild's announcements start with a banner
+// of the current games' covers
+func (d *Database) SetBannerImages(guildID string, enabled bool) error {
+	return d.updateServerSetting(guildID, "banner_images", enabled)
+}
+
+// SetClaimReminder stores the reminder appended to Free Now announcements; an
+// empty reminder turns it off
+func (d *Database) SetClaimReminder(guildID, reminder string) error {
+	var value interface{}
+	if reminder != "" {
+		value = reminder
+	}
+	return d.updateServerSetting(guildID, "claim_reminder", value)
+}
+
+// updateServerSetting sets one column of an active server config
+func (d *Database) updateServerSetting(guildID, column string, value interface{}) error {
+	query := fmt.Sprintf(`UPDATE server_configs SET %s = ?, updated_at = CURRENT_TIMESTAMP WHERE guild_id = ? AND active = 1`, column)
+	result, err := d.db.Exec(query, value, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", column, err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("no active server config for guild %s", guildID)
+	}
 	return nil
 }
 
@@ -382,6 +1340,21 @@ func (d *Database) DeactivateServerConfig(guildID, channelID string) error {
 	return nil
 }
 
+// DeactivateServerConfigByGuild deactivates every configuration of a guild,
+// e.g. after the bot was removed from it. Running /setup again reactivates it.
+func (d *Database) DeactivateServerConfigByGuild(guildID string) error {
+	query := `UPDATE server_configs SET active = 0, updated_at = CURRENT_TIMESTAMP WHERE guild_id = ? AND active = 1`
+	result, err := d.db.Exec(query, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate server config: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		log.Printf("Deactivated server config for guild %s", guildID)
+	}
+	return nil
+}
+
 // createServerConfigTable creates the server_configs table
 func (d *Database) createServerConfigTable() error {
 	query := `
@@ -405,4 +1378,167 @@ func (d *Database) createServerConfigTable() error {
 
 	log.Println("Server configs table created/verified")
 	return nil
-}
\ No newline at end of file
+}
+
+// createBotStateTable creates the bot_state key/value table used to persist
+// instance-wide bookkeeping across restarts
+func (d *Database) createBotStateTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS bot_state (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	`
+
+	if _, err := d.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create bot_state table: %w", err)
+	}
+
+	log.Println("Bot state table created/verified")
+	return nil
+}
+
+// GetBotState returns the value stored under key, or "" if it is unset
+func (d *Database) GetBotState(key string) (string, error) {
+	var value string
+	err := d.db.QueryRow(`SELECT value FROM bot_state WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get bot state %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// SetBotState stores value under key
+func (d *Database) SetBotState(key, value string) error {
+	query := `
+		INSERT INTO bot_state (key, value, updated_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = CURRENT_TIMESTAMP
+	`
+	if _, err := d.db.Exec(query, key, value); err != nil {
+		return fmt.Errorf("failed to set bot state %s: %w", key, err)
+	}
+	return nil
+}
+
+// lastScrapeStateKey is the bot_state key holding the last successful scrape
+const lastScrapeStateKey = "last_successful_scrape"
+
+// SetLastScrapeTime stores when games were last scraped and saved
+func (d *Database) SetLastScrapeTime(at time.Time) error {
+	return d.SetBotState(lastScrapeStateKey, at.UTC().Format(time.RFC3339))
+}
+
+// GetLastScrapeTime returns when games were last scraped and saved, or the
+// zero time if that was never recorded
+func (d *Database) GetLastScrapeTime() (time.Time, error) {
+	value, err := d.GetBotState(lastScrapeStateKey)
+	if err != nil || value == "" {
+		return time.Time{}, err
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid last scrape time %q: %w", value, err)
+	}
+	return at, nil
+}
+
+// createDeliveryDecisionsTable creates the delivery_decisions table
+func (d *Database) createDeliveryDecisionsTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS delivery_decisions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		cycle_id TEXT NOT NULL,
+		guild_id TEXT NOT NULL,
+		channel_id TEXT NOT NULL,
+		game_title TEXT NOT NULL,
+		free_to TEXT,
+		delivered INTEGER NOT NULL,
+		reason TEXT,
+		decided_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_delivery_decisions_guild ON delivery_decisions(guild_id, decided_at);
+	`
+
+	if _, err := d.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create delivery_decisions table: %w", err)
+	}
+	return nil
+}
+
+// SaveDeliveryDecisions records the per-guild outcome of a delivery cycle and
+// prunes decisions older than DeliveryDecisionRetentionDays
+func (d *Database) SaveDeliveryDecisions(decisions []models.DeliveryDecision) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO delivery_decisions (cycle_id, guild_id, channel_id, game_title, free_to, delivered, reason, policy, decided_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, decision := range decisions {
+		_, err := stmt.Exec(decision.CycleID, decision.GuildID, decision.ChannelID, decision.GameTitle,
+			decision.FreeTo, decision.Delivered, string(decision.Reason), decision.Policy,
+			decision.DecidedAt.UTC().Format("2006-01-02 15:04:05"))
+		if err != nil {
+			return fmt.Errorf("failed to save delivery decision for %s: %w", decision.GameTitle, err)
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM delivery_decisions WHERE decided_at < datetime('now', ?)`, fmt.Sprintf("-%d days", DeliveryDecisionRetentionDays)); err != nil {
+		return fmt.Errorf("failed to prune delivery decisions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetLatestDeliveryDecisions returns the decisions from the most recent delivery cycle for a guild
+func (d *Database) GetLatestDeliveryDecisions(guildID string) ([]models.DeliveryDecision, error) {
+	query := `
+		SELECT cycle_id, guild_id, channel_id, game_title, free_to, delivered, reason, COALESCE(policy, ''), decided_at
+		FROM delivery_decisions
+		WHERE guild_id = ? AND cycle_id = (
+			SELECT cycle_id FROM delivery_decisions WHERE guild_id = ? ORDER BY decided_at DESC, id DESC LIMIT 1
+		)
+		ORDER BY id
+	`
+
+	rows, err := d.db.Query(query, guildID, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query delivery decisions: %w", err)
+	}
+	defer rows.Close()
+
+	var decisions []models.DeliveryDecision
+	for rows.Next() {
+		var decision models.DeliveryDecision
+		var reason sql.NullString
+		var freeTo sql.NullString
+		err := rows.Scan(&decision.CycleID, &decision.GuildID, &decision.ChannelID, &decision.GameTitle,
+			&freeTo, &decision.Delivered, &reason, &decision.Policy, &decision.DecidedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delivery decision: %w", err)
+		}
+		decision.FreeTo = freeTo.String
+		decision.Reason = models.SkipReason(reason.String)
+		decisions = append(decisions, decision)
+	}
+
+	return decisions, nil
+}
@@ -0,0 +1,334 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"free-games-scrape/internal/models"
+)
+
+// newTestDB opens a private in-memory database that is closed with the test
+func newTestDB(t *testing.T) *Database {
+	t.Helper()
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := New(fmt.Sprintf("file:%s?mode=memory&cache=shared", name))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSaveServerConfigKeepsSettings(t *testing.T) {
+	db := newTestDB(t)
+
+	first, err := db.SaveServerConfig("guild", "channel-1")
+	if err != nil {
+		t.Fatalf("SaveServerConfig: %v", err)
+	}
+	if !first.Created || first.Reactivated || first.PreviousChannelID != "" {
+		t.Errorf("first save = %+v, want only Created", first)
+	}
+
+	if err := db.SetPostDelay("guild", 30); err != nil {
+		t.Fatalf("SetPostDelay: %v", err)
+	}
+	if err := db.SetMentionRole("guild", "role"); err != nil {
+		t.Fatalf("SetMentionRole: %v", err)
+	}
+	if err := db.SetWebhookURL("guild", "https://discord.com/api/webhooks/1/token"); err != nil {
+		t.Fatalf("SetWebhookURL: %v", err)
+	}
+	if err := db.SetComingSoonMode("guild", ComingSoonReleaseOnly); err != nil {
+		t.Fatalf("SetComingSoonMode: %v", err)
+	}
+
+	second, err := db.SaveServerConfig("guild", "channel-2")
+	if err != nil {
+		t.Fatalf("SaveServerConfig: %v", err)
+	}
+	if second.Created || second.Reactivated || second.PreviousChannelID != "channel-1" {
+		t.Errorf("second save = %+v, want PreviousChannelID channel-1 only", second)
+	}
+
+	config := second.Config
+	if config.ChannelID != "channel-2" {
+		t.Errorf("ChannelID = %q, want channel-2", config.ChannelID)
+	}
+	if config.PostDelaySeconds != 30 {
+		t.Errorf("PostDelaySeconds = %d, want 30", config.PostDelaySeconds)
+	}
+	if config.RoleID != "role" {
+		t.Errorf("RoleID = %q, want role", config.RoleID)
+	}
+	if config.WebhookURL != "https://discord.com/api/webhooks/1/token" {
+		t.Errorf("WebhookURL = %q, want it kept", config.WebhookURL)
+	}
+	if config.ComingSoonMode != ComingSoonReleaseOnly {
+		t.Errorf("ComingSoonMode = %q, want %q", config.ComingSoonMode, ComingSoonReleaseOnly)
+	}
+}
+
+func TestSaveServerConfigReactivates(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, err := db.SaveServerConfig("guild", "channel"); err != nil {
+		t.Fatalf("SaveServerConfig: %v", err)
+	}
+	if err := db.SetPostDelay("guild", 10); err != nil {
+		t.Fatalf("SetPostDelay: %v", err)
+	}
+	if err := db.DeactivateServerConfigByGuild("guild"); err != nil {
+		t.Fatalf("DeactivateServerConfigByGuild: %v", err)
+	}
+
+	result, err := db.SaveServerConfig("guild", "channel")
+	if err != nil {
+		t.Fatalf("SaveServerConfig: %v", err)
+	}
+	if !result.Reactivated || result.Created {
+		t.Errorf("save = %+v, want Reactivated", result)
+	}
+	if result.Config.PostDelaySeconds != 10 {
+		t.Errorf("PostDelaySeconds = %d, want 10 after reactivation", result.Config.PostDelaySeconds)
+	}
+}
+
+func TestSaveGamesKeepsPromotionTimes(t *testing.T) {
+	db := newTestDB(t)
+
+	// A giveaway across New Year with an exact end time, as the API reports it
+	from := time.Date(2025, time.December, 26, 16, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.January, 2, 16, 0, 0, 0, time.UTC)
+	game := models.Game{Title: "New Year Game", Status: models.StatusFreeNow, FreeFrom: "Dec 26", FreeTo: "Jan 2", FreeFromTime: from, FreeToTime: to}
+	undated := models.Game{Title: "Undated Game", Status: models.StatusFreeNow}
+	if _, err := db.SaveGames([]models.Game{game, undated}); err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+
+	stored := make(map[string]models.Game)
+	games, err := db.GetActiveGames()
+	if err != nil {
+		t.Fatalf("GetActiveGames: %v", err)
+	}
+	for _, g := range games {
+		stored[g.Title] = g
+	}
+
+	got := stored["New Year Game"]
+	if !got.FreeFromTime.Equal(from) || !got.FreeToTime.Equal(to) {
+		t.Errorf("stored window = %v - %v, want %v - %v", got.FreeFromTime, got.FreeToTime, from, to)
+	}
+	if !got.IsActiveAt(to.Add(-time.Minute)) || got.IsActiveAt(to) {
+		t.Errorf("stored game is not active until exactly %v", to)
+	}
+
+	// Games without dates keep zero times rather than a guessed year
+	if got := stored["Undated Game"]; !got.FreeFromTime.IsZero() || !got.FreeToTime.IsZero() {
+		t.Errorf("undated game window = %v - %v, want zero times", got.FreeFromTime, got.FreeToTime)
+	}
+
+	ending, err := db.GetGamesEndingBetween(to.Add(-time.Hour), to)
+	if err != nil {
+		t.Fatalf("GetGamesEndingBetween: %v", err)
+	}
+	if len(ending) != 1 || ending[0].Title != "New Year Game" {
+		t.Errorf("GetGamesEndingBetween = %v, want New Year Game", ending)
+	}
+}
+
+func TestSaveGamesKeepsImages(t *testing.T) {
+	db := newTestDB(t)
+
+	bundle := models.Game{
+		Title:    "Bundle Of Games",
+		Status:   models.StatusFreeNow,
+		FreeTo:   "Jul 17",
+		ImageURL: "https://cdn.example/bundle/wide.jpg",
+		Images:   []string{"https://cdn.example/bundle/wide.jpg", "https://cdn.example/bundle/shot1.jpg", "https://cdn.example/bundle/shot2.jpg"},
+	}
+	single := models.Game{Title: "Single Game", Status: models.StatusFreeNow, FreeTo: "Jul 17", ImageURL: "https://cdn.example/single.jpg"}
+	if _, err := db.SaveGames([]models.Game{bundle, single}); err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+
+	games, err := db.GetActiveGames()
+	if err != nil {
+		t.Fatalf("GetActiveGames: %v", err)
+	}
+	stored := make(map[string]models.Game)
+	for _, g := range games {
+		stored[g.Title] = g
+	}
+	if got := stored[bundle.Title].Images; fmt.Sprint(got) != fmt.Sprint(bundle.Images) {
+		t.Errorf("stored images = %v, want %v", got, bundle.Images)
+	}
+	if got := stored[single.Title]; got.Images != nil || got.ImageURL != single.ImageURL {
+		t.Errorf("single game stored with image %s and images %v, want only its image", got.ImageURL, got.Images)
+	}
+}
+
+func TestNotifiedSurvivesPartialScrape(t *testing.T) {
+	db := newTestDB(t)
+
+	announced := models.Game{Title: "Announced Game", Status: models.StatusFreeNow, FreeFrom: "Jul 17", FreeTo: "Jul 24"}
+	pending := models.Game{Title: "Pending Game", Status: models.StatusComingSoon, FreeFrom: "Jul 24", FreeTo: "Jul 31"}
+	full := []models.Game{announced, pending}
+
+	if _, err := db.SaveGames(full); err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+	if err := db.MarkGamesNotified([]models.Game{announced}); err != nil {
+		t.Fatalf("MarkGamesNotified: %v", err)
+	}
+
+	// A partial scrape misses both games, then the next one finds them again
+	if _, err := db.SaveGames([]models.Game{{Title: "Other Game", Status: models.StatusFreeNow, FreeFrom: "Jul 17", FreeTo: "Jul 24"}}); err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+	if _, err := db.SaveGames(full); err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+
+	unnotified, err := db.GetUnnotifiedGames(full)
+	if err != nil {
+		t.Fatalf("GetUnnotifiedGames: %v", err)
+	}
+	if len(unnotified) != 1 || unnotified[0].Title != "Pending Game" {
+		t.Errorf("GetUnnotifiedGames = %v, want only the never announced Pending Game", unnotified)
+	}
+}
+
+func TestSnapshotKeepsNotified(t *testing.T) {
+	db := newTestDB(t)
+
+	announced := models.Game{Title: "Announced Game", Status: models.StatusFreeNow, FreeFrom: "Jul 17", FreeTo: "Jul 24"}
+	pending := models.Game{Title: "Pending Game", Status: models.StatusFreeNow, FreeFrom: "Jul 17", FreeTo: "Jul 24"}
+	if _, err := db.SaveGames([]models.Game{announced, pending}); err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+	if err := db.MarkGamesNotified([]models.Game{announced}); err != nil {
+		t.Fatalf("MarkGamesNotified: %v", err)
+	}
+
+	snapshot, err := db.GetSnapshotGames()
+	if err != nil {
+		t.Fatalf("GetSnapshotGames: %v", err)
+	}
+	// Snapshots written before the flag was recorded count as announced
+	legacy := models.SnapshotGame{Game: models.Game{Title: "Legacy Game", Status: models.StatusFreeNow, FreeFrom: "Jul 17", FreeTo: "Jul 24"}}
+	if err := db.ReplaceAllGames(append(snapshot, legacy)); err != nil {
+		t.Fatalf("ReplaceAllGames: %v", err)
+	}
+
+	unnotified, err := db.GetUnnotifiedGames([]models.Game{announced, pending, legacy.Game})
+	if err != nil {
+		t.Fatalf("GetUnnotifiedGames: %v", err)
+	}
+	if len(unnotified) != 1 || unnotified[0].Title != "Pending Game" {
+		t.Errorf("GetUnnotifiedGames after restore = %v, want only Pending Game", unnotified)
+	}
+}
+
+func TestSaveGamesRecordsFieldChanges(t *testing.T) {
+	db := newTestDB(t)
+
+	original := models.Game{
+		Title:    "Control",
+		ImageURL: "https://cdn1.epicgames.com/offer/control.jpg?token=abc",
+		Status:   models.StatusComingSoon,
+		FreeFrom: "Jul 24",
+		FreeTo:   "Jul 31",
+		StoreURL: "https://store.epicgames.com/p/control",
+		Period:   "Free Jul 24 - Jul 31",
+	}
+	if edits, err := db.SaveGames([]models.Game{original}); err != nil || len(edits) != 0 {
+		t.Fatalf("first SaveGames = %v, %v, want no edits", edits, err)
+	}
+
+	// The CDN rotating the image token is not an edit
+	rotated := original
+	rotated.ImageURL = "https://cdn1.epicgames.com/offer/control.jpg?token=xyz"
+	if edits, err := db.SaveGames([]models.Game{rotated}); err != nil || len(edits) != 0 {
+		t.Fatalf("SaveGames with a rotated image token = %v, %v, want no edits", edits, err)
+	}
+
+	edited := rotated
+	edited.ImageURL = "https://cdn1.epicgames.com/offer/control-key-art.jpg"
+	edited.Status = models.StatusFreeNow
+	edited.StoreURL = "https://store.epicgames.com/p/control-ultimate"
+	edited.Period = "Free Now - Jul 31 at 11:00 AM"
+	edits, err := db.SaveGames([]models.Game{edited})
+	if err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+	wantFields := map[string][2]string{
+		models.FieldImageURL: {rotated.ImageURL, edited.ImageURL},
+		models.FieldStatus:   {models.StatusComingSoon, models.StatusFreeNow},
+		models.FieldStoreURL: {original.StoreURL, edited.StoreURL},
+		models.FieldPeriod:   {original.Period, edited.Period},
+	}
+	if len(edits) != len(wantFields) {
+		t.Errorf("SaveGames edits = %+v, want %d", edits, len(wantFields))
+	}
+
+	games, err := db.GetActiveGames()
+	if err != nil || len(games) != 1 {
+		t.Fatalf("GetActiveGames = %v, %v, want the one game", games, err)
+	}
+	title, history, err := db.GetGameChanges(games[0].ID)
+	if err != nil {
+		t.Fatalf("GetGameChanges: %v", err)
+	}
+	if title != "Control" {
+		t.Errorf("GetGameChanges title = %q, want Control", title)
+	}
+	if len(history) != len(wantFields) {
+		t.Fatalf("GetGameChanges = %+v, want %d changes", history, len(wantFields))
+	}
+	for _, change := range history {
+		want, ok := wantFields[change.Field]
+		if !ok || change.OldValue != want[0] || change.NewValue != want[1] || change.GameID != games[0].ID {
+			t.Errorf("recorded change %+v is not one of %v", change, wantFields)
+		}
+	}
+
+	if title, history, err := db.GetGameChanges(games[0].ID + 100); err != nil || title != "" || len(history) != 0 {
+		t.Errorf("GetGameChanges of an unknown game = %q, %v, %v, want nothing", title, history, err)
+	}
+}
+
+func TestSaveGamesRecordsRenames(t *testing.T) {
+	db := newTestDB(t)
+
+	original := models.Game{Title: "Control", Status: models.StatusFreeNow, FreeFrom: "Jul 24", FreeTo: "Jul 31", StoreURL: "https://store.epicgames.com/p/control"}
+	other := models.Game{Title: "Other Game", Status: models.StatusFreeNow, FreeFrom: "Jul 24", FreeTo: "Jul 31", StoreURL: "https://store.epicgames.com/p/other"}
+	if _, err := db.SaveGames([]models.Game{original, other}); err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+
+	renamed := original
+	renamed.Title = "Control Ultimate Edition"
+	edits, err := db.SaveGames([]models.Game{renamed, other})
+	if err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+	want := models.FieldChange{GameTitle: renamed.Title, Field: models.FieldTitle, OldValue: "Control", NewValue: renamed.Title}
+	if len(edits) != 1 || edits[0].Field != want.Field || edits[0].OldValue != want.OldValue || edits[0].NewValue != want.NewValue {
+		t.Errorf("SaveGames edits = %+v, want the rename %+v", edits, want)
+	}
+
+	// A new game sharing the store link of a game still being scraped is
+	// not a rename of it
+	bundle := other
+	bundle.Title = "Other Game Bundle"
+	edits, err = db.SaveGames([]models.Game{renamed, other, bundle})
+	if err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+	if len(edits) != 0 {
+		t.Errorf("SaveGames edits = %+v, want none for a game added next to the original", edits)
+	}
+}
@@ -0,0 +1,154 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"free-games-scrape/internal/models"
+)
+
+// GameChangeRetentionDays is how long field-level game edits are kept
+const GameChangeRetentionDays = 30
+
+// createGameChangesTable creates the game_changes table, the field-level edit
+// history of stored games
+func (d *Database) createGameChangesTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS game_changes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		game_id INTEGER NOT NULL,
+		game_title TEXT NOT NULL,
+		field TEXT NOT NULL,
+		old_value TEXT,
+		new_value TEXT,
+		detected_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_game_changes_game_id ON game_changes(game_id);
+	CREATE INDEX IF NOT EXISTS idx_game_changes_detected_at ON game_changes(detected_at);
+	`
+
+	if _, err := d.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create game_changes table: %w", err)
+	}
+
+	log.Println("Game changes table created/verified")
+	return nil
+}
+
+// detectFieldChanges compares a scraped game with its stored row before it is
+// saved. A game with no stored row whose store, store URL and promotion end
+// match a stored game under another title is recorded as a title edit of that
+// game, unless that game was scraped too (scrapedKeys holds the SourceKey of
+// every scraped game). Game IDs are filled in after saving.
+func detectFieldChanges(tx *sql.Tx, game models.Game, scrapedKeys map[string]bool) ([]models.FieldChange, error) {
+	var stored models.Game
+	err := tx.QueryRow(`
+		SELECT image_url, status, free_from, COALESCE(store_url, ''), COALESCE(period, '')
+		FROM games WHERE source = ? AND title = ? AND free_to = ?
+	`, game.StoreSource(), game.Title, game.FreeTo).Scan(&stored.ImageURL, &stored.Status, &stored.FreeFrom, &stored.StoreURL, &stored.Period)
+	if err == nil {
+		return models.DiffFields(stored, game), nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to load stored game %s: %w", game.Title, err)
+	}
+
+	if game.StoreURL == "" {
+		return nil, nil
+	}
+	rows, err := tx.Query(`
+		SELECT title FROM games
+		WHERE source = ? AND store_url = ? AND free_to = ? AND title != ?
+	`, game.StoreSource(), game.StoreURL, game.FreeTo, game.Title)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up renamed game %s: %w", game.Title, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var oldTitle string
+		if err := rows.Scan(&oldTitle); err != nil {
+			return nil, fmt.Errorf("failed to scan renamed game: %w", err)
+		}
+		renamed := models.Game{Title: oldTitle, Source: game.Source}
+		if !scrapedKeys[renamed.SourceKey()] {
+			return []models.FieldChange{{GameTitle: game.Title, Field: models.FieldTitle, OldValue: oldTitle, NewValue: game.Title}}, nil
+		}
+	}
+	return nil, rows.Err()
+}
+
+// recordFieldChanges stores the changes detected for a saved game
+func recordFieldChanges(tx *sql.Tx, game models.Game, changes []models.FieldChange, now time.Time) error {
+	var gameID int64
+	if err := tx.QueryRow(`SELECT id FROM games WHERE source = ? AND title = ? AND free_to = ?`, game.StoreSource(), game.Title, game.FreeTo).Scan(&gameID); err != nil {
+		return fmt.Errorf("failed to load id of %s: %w", game.Title, err)
+	}
+
+	for i := range changes {
+		changes[i].GameID = gameID
+		changes[i].DetectedAt = now
+		_, err := tx.Exec(`
+			INSERT INTO game_changes (game_id, game_title, field, old_value, new_value, detected_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, gameID, game.Title, changes[i].Field, changes[i].OldValue, changes[i].NewValue, now.UTC().Format(storedTimeLayout))
+		if err != nil {
+			return fmt.Errorf("failed to record %s change of %s: %w", changes[i].Field, game.Title, err)
+		}
+	}
+	return nil
+}
+
+// GetGameChanges returns the recorded edits of a game, oldest first, and the
+// game's title. The title is empty when no game has the ID.
+func (d *Database) GetGameChanges(gameID int64) (string, []models.FieldChange, error) {
+	var title string
+	err := d.db.QueryRow(`SELECT title FROM games WHERE id = ?`, gameID).Scan(&title)
+	if err == sql.ErrNoRows {
+		return "", nil, nil
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get game %d: %w", gameID, err)
+	}
+
+	rows, err := d.db.Query(`
+		SELECT game_id, game_title, field, COALESCE(old_value, ''), COALESCE(new_value, ''), detected_at
+		FROM game_changes
+		WHERE game_id = ?
+		ORDER BY detected_at, id
+	`, gameID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to query game changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []models.FieldChange
+	for rows.Next() {
+		var change models.FieldChange
+		if err := rows.Scan(&change.GameID, &change.GameTitle, &change.Field, &change.OldValue, &change.NewValue, &change.DetectedAt); err != nil {
+			return "", nil, fmt.Errorf("failed to scan game change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return "", nil, fmt.Errorf("failed to iterate game changes: %w", err)
+	}
+	return title, changes, nil
+}
+
+// CleanupOldGameChanges removes edits recorded more than
+// GameChangeRetentionDays ago
+func (d *Database) CleanupOldGameChanges() error {
+	result, err := d.db.Exec(`DELETE FROM game_changes WHERE detected_at < datetime('now', ?)`, fmt.Sprintf("-%d days", GameChangeRetentionDays))
+	if err != nil {
+		return fmt.Errorf("failed to cleanup game changes: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		log.Printf("Cleaned up %d old game change records", rows)
+	}
+	return nil
+}
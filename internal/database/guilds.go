@@ -0,0 +1,70 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// createKnownGuildsTable creates the known_guilds table, which remembers every
+// guild the bot has seen so restarts are not mistaken for new joins. Guilds
+// that already have an active server config are seeded as known.
+func (d *Database) createKnownGuildsTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS known_guilds (
+		guild_id TEXT PRIMARY KEY,
+		name TEXT,
+		first_seen_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		last_seen_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	`
+
+	if _, err := d.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create known_guilds table: %w", err)
+	}
+
+	seed := `
+		INSERT OR IGNORE INTO known_guilds (guild_id)
+		SELECT DISTINCT guild_id FROM server_configs WHERE active = 1
+	`
+	if _, err := d.db.Exec(seed); err != nil {
+		return fmt.Errorf("failed to seed known_guilds: %w", err)
+	}
+
+	log.Println("Known guilds table created/verified")
+	return nil
+}
+
+// RecordGuild marks a guild as seen and stores its member count, reporting
+// whether it had never been recorded before. Concurrent calls for the same
+// guild report new at most once.
+func (d *Database) RecordGuild(guildID, name string, memberCount int) (bool, error) {
+	now := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	result, err := d.db.Exec(`
+		INSERT OR IGNORE INTO known_guilds (guild_id, name, member_count, first_seen_at, last_seen_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, guildID, name, memberCount, now, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to record guild: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows > 0 {
+		return true, nil
+	}
+
+	if _, err := d.db.Exec(`UPDATE known_guilds SET name = ?, member_count = ?, last_seen_at = ? WHERE guild_id = ?`, name, memberCount, now, guildID); err != nil {
+		return false, fmt.Errorf("failed to update known guild: %w", err)
+	}
+	return false, nil
+}
+
+// ForgetGuild removes a guild from known_guilds so that being invited back
+// counts as a new join
+func (d *Database) ForgetGuild(guildID string) error {
+	if _, err := d.db.Exec(`DELETE FROM known_guilds WHERE guild_id = ?`, guildID); err != nil {
+		return fmt.Errorf("failed to forget guild: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// GuildImpact aggregates what the bot delivered to one guild over a period
+type GuildImpact struct {
+	// Announcements counts games posted to the guild, Games the distinct titles
+	Announcements int
+	Games         int
+	// Skipped counts games filtered out or that failed to send
+	Skipped int
+	// Reminders counts "last chance" reminders posted
+	Reminders int
+	// MemberCount is the guild's size when the bot last connected, 0 if unknown
+	MemberCount int
+}
+
+// HasHistory reports whether anything was sent to the guild in the period
+func (g GuildImpact) HasHistory() bool {
+	return g.Announcements > 0 || g.Reminders > 0
+}
+
+// GetGuildImpact aggregates a guild's delivery records since the given time.
+// Every query is limited to the guild through an index (delivery_decisions
+// guild/time index, notifications_sent and known_guilds primary keys), so the
+// cost does not grow with the number of servers.
+func (d *Database) GetGuildImpact(guildID string, since time.Time) (*GuildImpact, error) {
+	var impact GuildImpact
+	sinceText := since.UTC().Format("2006-01-02 15:04:05")
+
+	err := d.db.QueryRow(`
+		SELECT
+			COALESCE(SUM(delivered), 0),
+			COUNT(DISTINCT CASE WHEN delivered = 1 THEN game_title END),
+			COALESCE(SUM(1 - delivered), 0)
+		FROM delivery_decisions
+		WHERE guild_id = ? AND decided_at >= ?
+	`, guildID, sinceText).Scan(&impact.Announcements, &impact.Games, &impact.Skipped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate delivery decisions: %w", err)
+	}
+
+	err = d.db.QueryRow(`
+		SELECT COUNT(*) FROM notifications_sent
+		WHERE guild_id = ? AND kind = ? AND sent_at >= ?
+	`, guildID, NotificationExpiryReminder, sinceText).Scan(&impact.Reminders)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reminders: %w", err)
+	}
+
+	err = d.db.QueryRow(`
+		SELECT COALESCE(MAX(member_count), 0) FROM known_guilds WHERE guild_id = ?
+	`, guildID).Scan(&impact.MemberCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member count: %w", err)
+	}
+
+	return &impact, nil
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// MutedGame is a game a server admin asked the bot to stop referencing
+type MutedGame struct {
+	GuildID   string    `json:"guild_id"`
+	GameTitle string    `json:"game_title"`
+	MutedBy   string    `json:"muted_by"`
+	MutedAt   time.Time `json:"muted_at"`
+}
+
+// createMutedGamesTable creates the muted_games table
+func (d *Database) createMutedGamesTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS muted_games (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		guild_id TEXT NOT NULL,
+		game_title TEXT NOT NULL,
+		muted_by TEXT,
+		muted_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(guild_id, game_title)
+	);
+	`
+
+	if _, err := d.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create muted_games table: %w", err)
+	}
+
+	log.Println("Muted games table created/verified")
+	return nil
+}
+
+// MuteGame records a per-guild mute. Muting an already muted game is a no-op.
+func (d *Database) MuteGame(guildID, gameTitle, mutedBy string) error {
+	query := `
+		INSERT INTO muted_games (guild_id, game_title, muted_by, muted_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(guild_id, game_title) DO NOTHING
+	`
+	now := time.Now().UTC().Format("2006-01-02 15:04:05")
+	if _, err := d.db.Exec(query, guildID, gameTitle, mutedBy, now); err != nil {
+		return fmt.Errorf("failed to mute game: %w", err)
+	}
+	return nil
+}
+
+// UnmuteGame removes a per-guild mute, reporting whether the game was muted
+func (d *Database) UnmuteGame(guildID, gameTitle string) (bool, error) {
+	result, err := d.db.Exec(`DELETE FROM muted_games WHERE guild_id = ? AND game_title = ? COLLATE NOCASE`, guildID, gameTitle)
+	if err != nil {
+		return false, fmt.Errorf("failed to unmute game: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// GetMutedGames returns the games muted in a guild, most recent first
+func (d *Database) GetMutedGames(guildID string) ([]MutedGame, error) {
+	rows, err := d.db.Query(`
+		SELECT guild_id, game_title, COALESCE(muted_by, ''), muted_at
+		FROM muted_games
+		WHERE guild_id = ?
+		ORDER BY muted_at DESC
+	`, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query muted games: %w", err)
+	}
+	defer rows.Close()
+
+	var muted []MutedGame
+	for rows.Next() {
+		var m MutedGame
+		if err := rows.Scan(&m.GuildID, &m.GameTitle, &m.MutedBy, &m.MutedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan muted game: %w", err)
+		}
+		muted = append(muted, m)
+	}
+	return muted, rows.Err()
+}
+
+// GetMutedTitles returns the muted game titles of a guild as a set
+func (d *Database) GetMutedTitles(guildID string) (map[string]bool, error) {
+	muted, err := d.GetMutedGames(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make(map[string]bool, len(muted))
+	for _, m := range muted {
+		titles[m.GameTitle] = true
+	}
+	return titles, nil
+}
@@ -0,0 +1,236 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"free-games-scrape/internal/models"
+)
+
+// Kinds of notifications_sent records
+const (
+	// NotificationAnnouncement marks a game as announced to a guild, either
+	// delivered or marked seen with /markseen
+	NotificationAnnouncement = "announcement"
+	// NotificationExpiryReminder is the "last chance" reminder posted before a
+	// Free Now game ends
+	NotificationExpiryReminder = "expiry_reminder"
+	// NotificationRelease is the post made when a Coming Soon game becomes
+	// free, for guilds whose /comingsoon mode announces releases
+	NotificationRelease = "release"
+)
+
+// notificationsSentSchema is the notifications_sent table, keyed like games
+// by store, title and end date within a guild and kind
+const notificationsSentSchema = `(
+		guild_id TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		source TEXT NOT NULL DEFAULT 'epic',
+		game_title TEXT NOT NULL,
+		free_to TEXT NOT NULL DEFAULT '',
+		sent_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (guild_id, kind, source, game_title, free_to)
+	)`
+
+// createNotificationsSentTable creates the notifications_sent table, which
+// remembers one-off notifications per guild and game so restarts don't repeat them
+func (d *Database) createNotificationsSentTable() error {
+	if _, err := d.db.Exec(`CREATE TABLE IF NOT EXISTS notifications_sent ` + notificationsSentSchema); err != nil {
+		return fmt.Errorf("failed to create notifications_sent table: %w", err)
+	}
+
+	log.Println("Notifications sent table created/verified")
+	return d.migrateNotificationsSentSource()
+}
+
+// migrateNotificationsSentSource rebuilds a notifications_sent table from
+// before games were keyed by source, taking each record's source from the
+// stored game it is about
+func (d *Database) migrateNotificationsSentSource() error {
+	exists, err := d.hasColumn("notifications_sent", "source")
+	if err != nil || exists {
+		return err
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		CREATE TABLE notifications_sent_new ` + notificationsSentSchema + `;
+
+		INSERT OR IGNORE INTO notifications_sent_new (guild_id, kind, source, game_title, free_to, sent_at)
+		SELECT n.guild_id, n.kind,
+			COALESCE((SELECT g.source FROM games g WHERE g.title = n.game_title AND g.free_to = n.free_to LIMIT 1), 'epic'),
+			n.game_title, n.free_to, n.sent_at
+		FROM notifications_sent n;
+
+		DROP TABLE notifications_sent;
+		ALTER TABLE notifications_sent_new RENAME TO notifications_sent;
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to migrate notifications_sent table: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Println("Migrated notifications_sent table to key games by source")
+	return nil
+}
+
+// NotificationSent reports whether a notification of kind was already sent to
+// a guild for a game's current promotion
+func (d *Database) NotificationSent(guildID, kind string, game models.Game) (bool, error) {
+	var count int
+	err := d.db.QueryRow(`
+		SELECT COUNT(*) FROM notifications_sent
+		WHERE guild_id = ? AND kind = ? AND source = ? AND game_title = ? AND free_to = ?
+	`, guildID, kind, game.StoreSource(), game.Title, game.FreeTo).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check sent notification: %w", err)
+	}
+	return count > 0, nil
+}
+
+// RecordNotificationSent remembers that a notification of kind was sent to a
+// guild for a game's current promotion
+func (d *Database) RecordNotificationSent(guildID, kind string, game models.Game) error {
+	now := time.Now().UTC().Format("2006-01-02 15:04:05")
+	_, err := d.db.Exec(`
+		INSERT OR IGNORE INTO notifications_sent (guild_id, kind, source, game_title, free_to, sent_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, guildID, kind, game.StoreSource(), game.Title, game.FreeTo, now)
+	if err != nil {
+		return fmt.Errorf("failed to record sent notification: %w", err)
+	}
+	return nil
+}
+
+// MarkNotificationsSent records a notification of kind for each game in a
+// guild, returning how many were not recorded before
+func (d *Database) MarkNotificationsSent(guildID, kind string, games []models.Game) (int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT OR IGNORE INTO notifications_sent (guild_id, kind, source, game_title, free_to, sent_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC().Format("2006-01-02 15:04:05")
+	marked := 0
+	for _, game := range games {
+		result, err := stmt.Exec(guildID, kind, game.StoreSource(), game.Title, game.FreeTo, now)
+		if err != nil {
+			return 0, fmt.Errorf("failed to record sent notification for %s: %w", game.Title, err)
+		}
+		if rows, _ := result.RowsAffected(); rows > 0 {
+			marked++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return marked, nil
+}
+
+// GetSentNotifications returns the games a guild already got a notification
+// of kind for, keyed by NotificationKey
+func (d *Database) GetSentNotifications(guildID, kind string) (map[string]bool, error) {
+	rows, err := d.db.Query(`
+		SELECT source, game_title, free_to FROM notifications_sent WHERE guild_id = ? AND kind = ?
+	`, guildID, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sent notifications: %w", err)
+	}
+	defer rows.Close()
+
+	sent := make(map[string]bool)
+	for rows.Next() {
+		var game models.Game
+		if err := rows.Scan(&game.Source, &game.Title, &game.FreeTo); err != nil {
+			return nil, fmt.Errorf("failed to scan sent notification: %w", err)
+		}
+		sent[NotificationKey(game)] = true
+	}
+	return sent, rows.Err()
+}
+
+// NotificationKey identifies a game's promotion in GetSentNotifications
+func NotificationKey(game models.Game) string {
+	return game.SourceKey() + "|" + game.FreeTo
+}
+
+// announcementsBackfilledKey is set in bot_state once the games announced
+// before servers were offered every current game have been recorded as
+// announced to each configured server
+const announcementsBackfilledKey = "announcements_backfilled"
+
+// backfillAnnouncementsQuery records every game already announced somewhere
+// (games.notified) as announced to the servers matching the WHERE clause
+const backfillAnnouncementsQuery = `
+	INSERT OR IGNORE INTO notifications_sent (guild_id, kind, source, game_title, free_to, sent_at)
+	SELECT s.guild_id, 'announcement', g.source, g.title, g.free_to, CURRENT_TIMESTAMP
+	FROM server_configs s CROSS JOIN games g
+	WHERE g.notified = 1`
+
+// execer is implemented by *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// backfillAnnouncements runs once per database. Servers used to be offered
+// only games never announced anywhere, so those already announced are
+// recorded for every configured server; otherwise offering each server all
+// current games would post them again.
+func (d *Database) backfillAnnouncements() error {
+	done, err := d.GetBotState(announcementsBackfilledKey)
+	if err != nil || done != "" {
+		return err
+	}
+
+	result, err := d.db.Exec(backfillAnnouncementsQuery)
+	if err != nil {
+		return fmt.Errorf("failed to backfill announcements: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		log.Printf("Recorded %d earlier announcements per server", rows)
+	}
+	return d.SetBotState(announcementsBackfilledKey, "1")
+}
+
+// markAnnouncedForGuild records every game already announced somewhere as
+// announced to one server, so it is not caught up on games it presumably got
+func markAnnouncedForGuild(db execer, guildID string) error {
+	if _, err := db.Exec(backfillAnnouncementsQuery+` AND s.guild_id = ?`, guildID); err != nil {
+		return fmt.Errorf("failed to record announcements for guild %s: %w", guildID, err)
+	}
+	return nil
+}
+
+// CleanupOldNotifications removes sent-notification records older than
+// NotificationSentRetentionDays, long after the promotions they cover ended
+func (d *Database) CleanupOldNotifications() error {
+	result, err := d.db.Exec(`DELETE FROM notifications_sent WHERE sent_at < datetime('now', ?)`, fmt.Sprintf("-%d days", NotificationSentRetentionDays))
+	if err != nil {
+		return fmt.Errorf("failed to cleanup sent notifications: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		log.Printf("Cleaned up %d old sent notification records", rows)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"free-games-scrape/internal/models"
+)
+
+// PostedMessage is an automatic announcement or reminder the bot posted,
+// remembered so it can be edited when the store corrects the promotion's
+// dates. Kind is one of the Notification* kinds, and Position and Total place
+// the game in the batch it was announced in.
+type PostedMessage struct {
+	ID        int64
+	GuildID   string
+	ChannelID string
+	MessageID string
+	Webhook   bool
+	Plain     bool
+	Kind      string
+	Position  int
+	Total     int
+	Mention   string
+	// Game is the promotion the message is about, as currently stored
+	Game models.Game
+}
+
+// createPostedMessagesTable creates the posted_messages table
+func (d *Database) createPostedMessagesTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS posted_messages (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		guild_id TEXT NOT NULL,
+		channel_id TEXT NOT NULL,
+		message_id TEXT NOT NULL,
+		webhook INTEGER DEFAULT 0,
+		plain INTEGER DEFAULT 0,
+		kind TEXT NOT NULL,
+		source TEXT NOT NULL DEFAULT 'epic',
+		game_title TEXT NOT NULL,
+		free_to TEXT NOT NULL DEFAULT '',
+		position INTEGER DEFAULT 0,
+		total INTEGER DEFAULT 0,
+		mention TEXT DEFAULT '',
+		correction_pending INTEGER DEFAULT 0,
+		posted_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_posted_messages_game ON posted_messages(game_title, free_to);
+	CREATE INDEX IF NOT EXISTS idx_posted_messages_pending ON posted_messages(correction_pending);
+	`
+
+	if _, err := d.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create posted_messages table: %w", err)
+	}
+
+	log.Println("Posted messages table created/verified")
+	return d.ensurePostedMessagesSource()
+}
+
+// ensurePostedMessagesSource adds posted_messages.source to tables from
+// before games were keyed by source, taking each message's source from the
+// stored game it is about
+func (d *Database) ensurePostedMessagesSource() error {
+	exists, err := d.hasColumn("posted_messages", "source")
+	if err != nil || exists {
+		return err
+	}
+
+	if err := d.ensureColumn("posted_messages", "source", "TEXT NOT NULL DEFAULT 'epic'"); err != nil {
+		return err
+	}
+	_, err = d.db.Exec(`
+		UPDATE posted_messages SET source = COALESCE((
+			SELECT g.source FROM games g WHERE g.title = posted_messages.game_title AND g.free_to = posted_messages.free_to LIMIT 1
+		), 'epic')
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to set the source of posted messages: %w", err)
+	}
+	return nil
+}
+
+// RecordPostedMessage remembers a posted announcement or reminder
+func (d *Database) RecordPostedMessage(message PostedMessage) error {
+	_, err := d.db.Exec(`
+		INSERT INTO posted_messages (guild_id, channel_id, message_id, webhook, plain, kind, source, game_title, free_to, position, total, mention, posted_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, message.GuildID, message.ChannelID, message.MessageID, message.Webhook, message.Plain, message.Kind,
+		message.Game.StoreSource(), message.Game.Title, message.Game.FreeTo, message.Position, message.Total, message.Mention,
+		time.Now().UTC().Format(storedTimeLayout))
+	if err != nil {
+		return fmt.Errorf("failed to record posted message: %w", err)
+	}
+	return nil
+}
+
+// CorrectPromotionDates handles a scrape reporting different dates for a
+// promotion that is still running. Posted messages about it are queued for
+// an edit, and the number queued is returned. Promotions are stored by title
+// and end date, so a corrected end date also moves the promotion's sent
+// notifications, announced flag, store link and edit history to the row just
+// saved for the new end date and removes the old row; otherwise every server
+// would get the game announced again. The end date change is recorded in
+// game_changes and returned.
+func (d *Database) CorrectPromotionDates(previous, current models.Game) (int, []models.FieldChange, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var edits []models.FieldChange
+	if previous.FreeTo != current.FreeTo {
+		edit, err := movePromotion(tx, previous, current)
+		if err != nil {
+			return 0, nil, err
+		}
+		if edit != nil {
+			edits = append(edits, *edit)
+		}
+	}
+
+	result, err := tx.Exec(`
+		UPDATE posted_messages SET free_to = ?, correction_pending = 1
+		WHERE source = ? AND game_title = ? AND free_to = ?
+	`, current.FreeTo, previous.StoreSource(), previous.Title, previous.FreeTo)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to queue corrections of %s: %w", current.Title, err)
+	}
+	queued, _ := result.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return int(queued), edits, nil
+}
+
+// movePromotion moves what is stored about previous's promotion to
+// current's row, returning the recorded end date change, or nil when
+// previous was never stored
+func movePromotion(tx *sql.Tx, previous, current models.Game) (*models.FieldChange, error) {
+	var oldID, newID int64
+	source := current.StoreSource()
+	err := tx.QueryRow(`SELECT id FROM games WHERE source = ? AND title = ? AND free_to = ?`, source, previous.Title, previous.FreeTo).Scan(&oldID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stored game %s: %w", previous.Title, err)
+	}
+	if err := tx.QueryRow(`SELECT id FROM games WHERE source = ? AND title = ? AND free_to = ?`, source, current.Title, current.FreeTo).Scan(&newID); err != nil {
+		return nil, fmt.Errorf("failed to load id of %s: %w", current.Title, err)
+	}
+
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		// Records already kept for the new end date stay, the others move over
+		{`UPDATE OR IGNORE notifications_sent SET free_to = ? WHERE source = ? AND game_title = ? AND free_to = ?`, []interface{}{current.FreeTo, source, previous.Title, previous.FreeTo}},
+		{`DELETE FROM notifications_sent WHERE source = ? AND game_title = ? AND free_to = ?`, []interface{}{source, previous.Title, previous.FreeTo}},
+		{`UPDATE games SET
+			notified = MAX(COALESCE(notified, 0), (SELECT COALESCE(notified, 0) FROM games WHERE id = ?)),
+			store_url = COALESCE(NULLIF(store_url, ''), (SELECT store_url FROM games WHERE id = ?))
+		WHERE id = ?`, []interface{}{oldID, oldID, newID}},
+		{`UPDATE game_changes SET game_id = ? WHERE game_id = ?`, []interface{}{newID, oldID}},
+		{`DELETE FROM games WHERE id = ?`, []interface{}{oldID}},
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement.query, statement.args...); err != nil {
+			return nil, fmt.Errorf("failed to move promotion of %s: %w", current.Title, err)
+		}
+	}
+
+	edits := []models.FieldChange{{GameTitle: current.Title, Field: models.FieldFreeTo, OldValue: previous.FreeTo, NewValue: current.FreeTo}}
+	if err := recordFieldChanges(tx, current, edits, time.Now()); err != nil {
+		return nil, err
+	}
+	return &edits[0], nil
+}
+
+// GetPendingCorrections returns the posted messages waiting for an edit with
+// corrected dates, those about promotions ending soonest first. Messages
+// whose game is no longer stored are left out.
+func (d *Database) GetPendingCorrections() ([]PostedMessage, error) {
+	rows, err := d.db.Query(`
+		SELECT p.id, p.guild_id, p.channel_id, p.message_id, p.webhook, p.plain, p.kind, p.position, p.total, COALESCE(p.mention, ''),
+			g.id, g.title, g.image_url, g.status, g.free_from, g.free_to, COALESCE(g.store_url, ''), COALESCE(g.source, ''), COALESCE(g.regions, ''),
+			COALESCE(g.images, ''), COALESCE(g.period, ''), COALESCE(g.free_from_at, ''), COALESCE(g.free_to_at, '')
+		FROM posted_messages p
+		JOIN games g ON g.source = p.source AND g.title = p.game_title AND g.free_to = p.free_to
+		WHERE p.correction_pending = 1
+		ORDER BY COALESCE(g.free_to_at, '9999'), p.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending corrections: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []PostedMessage
+	for rows.Next() {
+		var message PostedMessage
+		scanner := prefixScanner{row: rows, prefix: []interface{}{&message.ID, &message.GuildID, &message.ChannelID, &message.MessageID,
+			&message.Webhook, &message.Plain, &message.Kind, &message.Position, &message.Total, &message.Mention}}
+		if err := scanGame(scanner, &message.Game); err != nil {
+			return nil, fmt.Errorf("failed to scan pending correction: %w", err)
+		}
+		messages = append(messages, message)
+	}
+	return messages, rows.Err()
+}
+
+// prefixScanner scans its leading columns into prefix and passes the rest
+// on, so scanGame can read a game selected after other columns
+type prefixScanner struct {
+	row    rowScanner
+	prefix []interface{}
+}
+
+func (p prefixScanner) Scan(dest ...interface{}) error {
+	return p.row.Scan(append(p.prefix, dest...)...)
+}
+
+// ClearCorrectionPending marks a posted message as corrected, or as given up
+// on when it can no longer be edited
+func (d *Database) ClearCorrectionPending(id int64) error {
+	if _, err := d.db.Exec(`UPDATE posted_messages SET correction_pending = 0 WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to clear pending correction: %w", err)
+	}
+	return nil
+}
+
+// CleanupOldPostedMessages removes posted message records older than
+// PostedMessageRetentionDays, long after the promotions they cover ended
+func (d *Database) CleanupOldPostedMessages() error {
+	result, err := d.db.Exec(`DELETE FROM posted_messages WHERE posted_at < datetime('now', ?)`, fmt.Sprintf("-%d days", PostedMessageRetentionDays))
+	if err != nil {
+		return fmt.Errorf("failed to cleanup posted messages: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		log.Printf("Cleaned up %d old posted message records", rows)
+	}
+	return nil
+}
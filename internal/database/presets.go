@@ -0,0 +1,450 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"free-games-scrape/internal/models"
+)
+
+// MaxUserPresets caps how many presets one user can save
+const MaxUserPresets = 10
+
+// presetNamePattern is what a saved preset may be called
+var presetNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// Setting is one server setting presets can carry. Every such setting is
+// registered in settingsRegistry, so saved presets and the /settings apply
+// diff cover new settings without further changes.
+type Setting struct {
+	// Key is the server_configs column and the key used in presets
+	Key   string
+	Label string
+	// Empty describes the empty value, e.g. "off" or "bot default"
+	Empty string
+	// Get returns the setting of config in preset form
+	Get func(config *ServerConfig) string
+	// Parse validates a preset value and returns what is stored in the column
+	Parse func(value string) (interface{}, error)
+}
+
+// Display renders a preset value for people
+func (s Setting) Display(value string) string {
+	if value == "" {
+		return s.Empty
+	}
+	return value
+}
+
+// settingsRegistry lists the settings presets can change, in display order.
+// Channel, webhook and role are specific to one server and left out.
+var settingsRegistry = []Setting{
+	{
+		Key:   "post_delay_seconds",
+		Label: "Delay between posts (seconds)",
+		Empty: "0",
+		Get:   func(c *ServerConfig) string { return strconv.Itoa(c.PostDelaySeconds) },
+		Parse: func(value string) (interface{}, error) {
+			seconds, err := strconv.Atoi(value)
+			if err != nil || seconds < 0 || seconds > MaxPostDelaySeconds {
+				return nil, fmt.Errorf("post_delay_seconds must be between 0 and %d", MaxPostDelaySeconds)
+			}
+			return seconds, nil
+		},
+	},
+	{
+		Key:   "text_fallback",
+		Label: "Text fallback",
+		Get:   func(c *ServerConfig) string { return onOff(c.TextFallback) },
+		Parse: parseOnOff,
+	},
+	{
+		Key:   "expiry_reminders",
+		Label: "Last-chance reminders",
+		Get:   func(c *ServerConfig) string { return onOff(c.ExpiryReminders) },
+		Parse: parseOnOff,
+	},
+	{
+		Key:   "comingsoon_mode",
+		Label: "Coming Soon mode",
+		Get:   func(c *ServerConfig) string { return c.ComingSoonMode },
+		Parse: func(value string) (interface{}, error) {
+			switch value {
+			case ComingSoonAnnounce, ComingSoonReleaseOnly, ComingSoonBoth:
+				return value, nil
+			}
+			return nil, fmt.Errorf("unknown comingsoon_mode %q", value)
+		},
+	},
+	{
+		Key:   "message_format",
+		Label: "Message format",
+		Get:   func(c *ServerConfig) string { return c.MessageFormat },
+		Parse: func(value string) (interface{}, error) {
+			switch value {
+			case FormatEmbed, FormatPlain, FormatBoth:
+				return value, nil
+			}
+			return nil, fmt.Errorf("unknown message_format %q", value)
+		},
+	},
+	{
+		Key:   "sources",
+		Label: "Stores",
+		Empty: "all",
+		Get:   func(c *ServerConfig) string { return c.Sources },
+		Parse: func(value string) (interface{}, error) {
+			for _, source := range strings.Split(value, ",") {
+				switch source {
+				case "", models.SourceEpic, models.SourceGOG:
+				default:
+					return nil, fmt.Errorf("unknown source %q", source)
+				}
+			}
+			return value, nil
+		},
+	},
+	{
+		Key:   "region",
+		Label: "Region",
+		Empty: "bot default",
+		Get:   func(c *ServerConfig) string { return c.Region },
+		Parse: func(value string) (interface{}, error) {
+			if value == "" {
+				return nil, nil
+			}
+			if _, ok := models.LookupLocale(value); !ok {
+				return nil, fmt.Errorf("unsupported region %q", value)
+			}
+			return value, nil
+		},
+	},
+	{
+		Key:   "timezone",
+		Label: "Timezone",
+		Empty: DefaultTimezone,
+		Get:   func(c *ServerConfig) string { return c.Timezone },
+		Parse: func(value string) (interface{}, error) {
+			if value == "" {
+				return nil, nil
+			}
+			if _, err := LoadTimezone(value); err != nil {
+				return nil, err
+			}
+			return value, nil
+		},
+	},
+	{
+		Key:   "claim_reminder",
+		Label: "Claim reminder",
+		Empty: "off",
+		Get:   func(c *ServerConfig) string { return c.ClaimReminder },
+		Parse: func(value string) (interface{}, error) {
+			if value == "" {
+				return nil, nil
+			}
+			if len([]rune(value)) > MaxClaimReminderLength {
+				return nil, fmt.Errorf("claim_reminder is longer than %d characters", MaxClaimReminderLength)
+			}
+			return value, nil
+		},
+	},
+	{
+		Key:   "banner_images",
+		Label: "Banner images",
+		Get:   func(c *ServerConfig) string { return onOff(c.BannerImages) },
+		Parse: parseOnOff,
+	},
+	{
+		Key:   "content_policy",
+		Label: "Content policy",
+		Empty: "none",
+		Get:   func(c *ServerConfig) string { return c.ContentPolicy },
+		Parse: func(value string) (interface{}, error) {
+			if value == "" {
+				return nil, nil
+			}
+			return ParseContentPolicy(value)
+		},
+	},
+}
+
+// onOff renders a boolean setting in preset form
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
+
+// parseOnOff parses a boolean setting in preset form
+func parseOnOff(value string) (interface{}, error) {
+	switch value {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+	return nil, fmt.Errorf("expected on or off, got %q", value)
+}
+
+// SettingsRegistry returns every setting presets can change
+func SettingsRegistry() []Setting {
+	return append([]Setting(nil), settingsRegistry...)
+}
+
+// Preset is a named bundle of settings. Built-in presets have no owner;
+// saved ones belong to the user who saved them.
+type Preset struct {
+	Name     string            `json:"name"`
+	OwnerID  string            `json:"owner_id,omitempty"`
+	Settings map[string]string `json:"settings"`
+}
+
+// builtinPresets are the presets shipped with the bot. Settings a preset
+// leaves out keep their current value.
+var builtinPresets = []Preset{
+	{
+		Name: "minimal",
+		Settings: map[string]string{
+			"post_delay_seconds": "0",
+			"expiry_reminders":   "off",
+			"comingsoon_mode":    ComingSoonReleaseOnly,
+			"message_format":     FormatPlain,
+			"claim_reminder":     "",
+		},
+	},
+	{
+		Name: "full-featured",
+		Settings: map[string]string{
+			"text_fallback":    "on",
+			"expiry_reminders": "on",
+			"comingsoon_mode":  ComingSoonBoth,
+			"message_format":   FormatBoth,
+			"sources":          "",
+			"claim_reminder":   "Claim it before the giveaway ends to keep it forever!",
+		},
+	},
+	{
+		Name: "family-friendly",
+		Settings: map[string]string{
+			"post_delay_seconds": "10",
+			"text_fallback":      "on",
+			"expiry_reminders":   "on",
+			"comingsoon_mode":    ComingSoonReleaseOnly,
+			"message_format":     FormatEmbed,
+			"claim_reminder":     "Check the age rating on the store page before claiming.",
+		},
+	},
+}
+
+// BuiltinPresets returns the presets shipped with the bot
+func BuiltinPresets() []Preset {
+	return append([]Preset(nil), builtinPresets...)
+}
+
+// BuiltinPreset returns the built-in preset called name, or nil
+func BuiltinPreset(name string) *Preset {
+	for _, preset := range builtinPresets {
+		if preset.Name == name {
+			preset := preset
+			return &preset
+		}
+	}
+	return nil
+}
+
+// CurrentSettings returns every registered setting of config in preset form
+func CurrentSettings(config *ServerConfig) map[string]string {
+	settings := make(map[string]string, len(settingsRegistry))
+	for _, setting := range settingsRegistry {
+		settings[setting.Key] = setting.Get(config)
+	}
+	return settings
+}
+
+// SettingChange is one setting a preset would change
+type SettingChange struct {
+	Setting  Setting
+	OldValue string
+	NewValue string
+}
+
+// DiffPreset returns the settings applying preset to config would change, in
+// registry order
+func DiffPreset(config *ServerConfig, preset *Preset) []SettingChange {
+	var changes []SettingChange
+	for _, setting := range settingsRegistry {
+		value, ok := preset.Settings[setting.Key]
+		if !ok {
+			continue
+		}
+		if current := setting.Get(config); current != value {
+			changes = append(changes, SettingChange{Setting: setting, OldValue: current, NewValue: value})
+		}
+	}
+	return changes
+}
+
+// ValidatePresetName checks the name a user saves a preset under
+func ValidatePresetName(name string) error {
+	if !presetNamePattern.MatchString(name) {
+		return fmt.Errorf("preset names are 1-32 lowercase letters, digits and dashes")
+	}
+	if BuiltinPreset(name) != nil {
+		return fmt.Errorf("%q is a built-in preset", name)
+	}
+	return nil
+}
+
+// ApplyPreset writes every setting of preset to a guild's active config in a
+// single transaction. Nothing is changed when any setting is unknown,
+// invalid or fails to save.
+func (d *Database) ApplyPreset(guildID string, preset *Preset) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	registered := make(map[string]bool, len(settingsRegistry))
+	for _, setting := range settingsRegistry {
+		registered[setting.Key] = true
+		value, ok := preset.Settings[setting.Key]
+		if !ok {
+			continue
+		}
+		stored, err := setting.Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s in preset %s: %w", setting.Key, preset.Name, err)
+		}
+		query := fmt.Sprintf(`UPDATE server_configs SET %s = ?, updated_at = CURRENT_TIMESTAMP WHERE guild_id = ? AND active = 1`, setting.Key)
+		result, err := tx.Exec(query, stored, guildID)
+		if err != nil {
+			return fmt.Errorf("failed to update %s: %w", setting.Key, err)
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return fmt.Errorf("no active server config for guild %s", guildID)
+		}
+	}
+	for key := range preset.Settings {
+		if !registered[key] {
+			return fmt.Errorf("preset %s has unknown setting %q", preset.Name, key)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit preset: %w", err)
+	}
+	log.Printf("Applied preset %s to guild %s", preset.Name, guildID)
+	return nil
+}
+
+// createSettingsPresetsTable creates the settings_presets table
+func (d *Database) createSettingsPresetsTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS settings_presets (
+		user_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		settings TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (user_id, name)
+	);
+	`
+
+	if _, err := d.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create settings_presets table: %w", err)
+	}
+
+	log.Println("Settings presets table created/verified")
+	return nil
+}
+
+// SaveUserPreset stores settings as a preset of userID, replacing a preset of
+// the same name. A user can keep at most MaxUserPresets presets.
+func (d *Database) SaveUserPreset(userID, name string, settings map[string]string) error {
+	if err := ValidatePresetName(name); err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to encode preset: %w", err)
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var others int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM settings_presets WHERE user_id = ? AND name != ?`, userID, name).Scan(&others); err != nil {
+		return fmt.Errorf("failed to count presets: %w", err)
+	}
+	if others >= MaxUserPresets {
+		return fmt.Errorf("you already have %d saved presets; delete one first", MaxUserPresets)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO settings_presets (user_id, name, settings, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(user_id, name) DO UPDATE SET settings = excluded.settings, created_at = excluded.created_at
+	`, userID, name, string(encoded), time.Now().UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return fmt.Errorf("failed to save preset: %w", err)
+	}
+	return tx.Commit()
+}
+
+// GetUserPreset returns the preset userID saved as name, or nil
+func (d *Database) GetUserPreset(userID, name string) (*Preset, error) {
+	var encoded string
+	err := d.db.QueryRow(`SELECT settings FROM settings_presets WHERE user_id = ? AND name = ?`, userID, name).Scan(&encoded)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preset: %w", err)
+	}
+
+	preset := &Preset{Name: name, OwnerID: userID}
+	if err := json.Unmarshal([]byte(encoded), &preset.Settings); err != nil {
+		return nil, fmt.Errorf("failed to decode preset %s: %w", name, err)
+	}
+	return preset, nil
+}
+
+// GetUserPresetNames returns the names of the presets userID saved, sorted
+func (d *Database) GetUserPresetNames(userID string) ([]string, error) {
+	rows, err := d.db.Query(`SELECT name FROM settings_presets WHERE user_id = ? ORDER BY name`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query presets: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan preset name: %w", err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// DeleteUserPreset removes a saved preset, reporting whether it existed
+func (d *Database) DeleteUserPreset(userID, name string) (bool, error) {
+	result, err := d.db.Exec(`DELETE FROM settings_presets WHERE user_id = ? AND name = ?`, userID, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete preset: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
@@ -0,0 +1,193 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// customizedServer configures guild with settings that differ from every
+// built-in preset
+func customizedServer(t *testing.T, db *Database, guildID string) *ServerConfig {
+	t.Helper()
+	if _, err := db.SaveServerConfig(guildID, "channel"); err != nil {
+		t.Fatalf("SaveServerConfig: %v", err)
+	}
+	for _, err := range []error{
+		db.SetPostDelay(guildID, 20),
+		db.SetTextFallback(guildID, false),
+		db.SetExpiryReminders(guildID, true),
+		db.SetComingSoonMode(guildID, ComingSoonAnnounce),
+		db.SetMessageFormat(guildID, FormatBoth),
+		db.SetTimezone(guildID, "Europe/Berlin"),
+		db.SetMentionRole(guildID, "role"),
+		db.SetClaimReminder(guildID, "Our own reminder"),
+	} {
+		if err != nil {
+			t.Fatalf("customizing the server: %v", err)
+		}
+	}
+	config, err := db.GetServerConfig(guildID)
+	if err != nil || config == nil {
+		t.Fatalf("GetServerConfig = %v, %v", config, err)
+	}
+	return config
+}
+
+func TestApplyPresetOverCustomSettings(t *testing.T) {
+	for _, preset := range BuiltinPresets() {
+		t.Run(preset.Name, func(t *testing.T) {
+			db := newTestDB(t)
+			before := customizedServer(t, db, "guild")
+			if len(DiffPreset(before, &preset)) == 0 {
+				t.Fatal("the preset changes nothing over the custom settings")
+			}
+
+			if err := db.ApplyPreset("guild", &preset); err != nil {
+				t.Fatalf("ApplyPreset: %v", err)
+			}
+			after, err := db.GetServerConfig("guild")
+			if err != nil {
+				t.Fatalf("GetServerConfig: %v", err)
+			}
+
+			if changes := DiffPreset(after, &preset); len(changes) != 0 {
+				t.Errorf("after applying, DiffPreset = %+v, want no changes", changes)
+			}
+			// Settings the preset leaves out, and those outside the registry,
+			// keep their custom values
+			current := CurrentSettings(after)
+			for key, value := range CurrentSettings(before) {
+				if _, inPreset := preset.Settings[key]; !inPreset && current[key] != value {
+					t.Errorf("%s = %q, want it kept at %q", key, current[key], value)
+				}
+			}
+			if after.ChannelID != before.ChannelID || after.RoleID != before.RoleID {
+				t.Errorf("channel and role = %q, %q, want %q, %q kept", after.ChannelID, after.RoleID, before.ChannelID, before.RoleID)
+			}
+		})
+	}
+}
+
+func TestApplyPresetRollsBackOnFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]string
+	}{
+		// post_delay_seconds is written before the later setting fails
+		{name: "invalid later setting", settings: map[string]string{"post_delay_seconds": "5", "timezone": "Mars/Olympus_Mons"}},
+		{name: "unknown setting", settings: map[string]string{"post_delay_seconds": "5", "message_format": FormatPlain, "theme": "dark"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			before := customizedServer(t, db, "guild")
+
+			if err := db.ApplyPreset("guild", &Preset{Name: "broken", Settings: tt.settings}); err == nil {
+				t.Fatal("ApplyPreset accepted a broken preset")
+			}
+			after, err := db.GetServerConfig("guild")
+			if err != nil {
+				t.Fatalf("GetServerConfig: %v", err)
+			}
+			if got, want := CurrentSettings(after), CurrentSettings(before); !reflect.DeepEqual(got, want) {
+				t.Errorf("settings after a failed apply = %v, want them unchanged at %v", got, want)
+			}
+		})
+	}
+}
+
+func TestApplyPresetNeedsActiveConfig(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.ApplyPreset("unknown", BuiltinPreset("minimal")); err == nil {
+		t.Error("ApplyPreset succeeded for a guild without a config")
+	}
+}
+
+func TestDiffPresetCoversRegistry(t *testing.T) {
+	db := newTestDB(t)
+	config := customizedServer(t, db, "guild")
+
+	// A preset setting every registered value to something else lists
+	// every setting, in registry order
+	settings := make(map[string]string)
+	for key := range CurrentSettings(config) {
+		settings[key] = "changed"
+	}
+	changes := DiffPreset(config, &Preset{Name: "everything", Settings: settings})
+	registry := SettingsRegistry()
+	if len(changes) != len(registry) {
+		t.Fatalf("DiffPreset listed %d changes, want one per registered setting (%d)", len(changes), len(registry))
+	}
+	for i, change := range changes {
+		if change.Setting.Key != registry[i].Key || change.NewValue != "changed" {
+			t.Errorf("change %d = %s → %q, want %s", i, change.Setting.Key, change.NewValue, registry[i].Key)
+		}
+	}
+}
+
+func TestSavedPresetAppliesToAnotherServer(t *testing.T) {
+	db := newTestDB(t)
+	source := customizedServer(t, db, "source")
+	if _, err := db.SaveServerConfig("target", "channel"); err != nil {
+		t.Fatalf("SaveServerConfig: %v", err)
+	}
+
+	if err := db.SaveUserPreset("user", "my-setup", CurrentSettings(source)); err != nil {
+		t.Fatalf("SaveUserPreset: %v", err)
+	}
+	preset, err := db.GetUserPreset("user", "my-setup")
+	if err != nil || preset == nil {
+		t.Fatalf("GetUserPreset = %v, %v", preset, err)
+	}
+	if other, err := db.GetUserPreset("someone-else", "my-setup"); err != nil || other != nil {
+		t.Errorf("another user's GetUserPreset = %v, %v, want nothing", other, err)
+	}
+
+	if err := db.ApplyPreset("target", preset); err != nil {
+		t.Fatalf("ApplyPreset: %v", err)
+	}
+	target, err := db.GetServerConfig("target")
+	if err != nil {
+		t.Fatalf("GetServerConfig: %v", err)
+	}
+	if got, want := CurrentSettings(target), CurrentSettings(source); !reflect.DeepEqual(got, want) {
+		t.Errorf("target settings = %v, want the source's %v", got, want)
+	}
+}
+
+func TestSaveUserPresetLimits(t *testing.T) {
+	db := newTestDB(t)
+	settings := map[string]string{"message_format": FormatPlain}
+
+	for _, name := range []string{"", "Has Caps", "minimal", "-dash-first"} {
+		if err := db.SaveUserPreset("user", name, settings); err == nil {
+			t.Errorf("SaveUserPreset(%q) accepted an invalid name", name)
+		}
+	}
+
+	for i := 0; i < MaxUserPresets; i++ {
+		if err := db.SaveUserPreset("user", fmt.Sprintf("preset-%d", i), settings); err != nil {
+			t.Fatalf("SaveUserPreset %d: %v", i, err)
+		}
+	}
+	if err := db.SaveUserPreset("user", "one-too-many", settings); err == nil {
+		t.Errorf("saved more than %d presets", MaxUserPresets)
+	}
+	// Replacing an existing preset does not count against the limit
+	if err := db.SaveUserPreset("user", "preset-0", map[string]string{"message_format": FormatEmbed}); err != nil {
+		t.Errorf("replacing a preset at the limit: %v", err)
+	}
+
+	deleted, err := db.DeleteUserPreset("user", "preset-0")
+	if err != nil || !deleted {
+		t.Fatalf("DeleteUserPreset = %v, %v", deleted, err)
+	}
+	names, err := db.GetUserPresetNames("user")
+	if err != nil {
+		t.Fatalf("GetUserPresetNames: %v", err)
+	}
+	if len(names) != MaxUserPresets-1 || names[0] != "preset-1" {
+		t.Errorf("GetUserPresetNames = %v, want the remaining presets sorted", names)
+	}
+}
@@ -0,0 +1,147 @@
+package database
+
+import "fmt"
+
+// Retention windows for data the bot prunes on its own
+const (
+	GameRetentionDays             = 30
+	DeliveryDecisionRetentionDays = 30
+	NotificationSentRetentionDays = 30
+	PostedMessageRetentionDays    = 30
+)
+
+// DataCategory describes one table the bot stores data in, for the /privacy
+// disclosure. Every table must be registered in dataCategories: New refuses to
+// open a database containing a table without an entry.
+type DataCategory struct {
+	Table       string
+	Name        string
+	Description string
+	Retention   string
+	// GuildColumn holds the guild ID for per-server data; empty for data not
+	// tied to any server
+	GuildColumn string
+}
+
+// PerGuild reports whether the category holds data about individual servers
+func (c DataCategory) PerGuild() bool {
+	return c.GuildColumn != ""
+}
+
+// dataCategories is the registry of everything the bot stores
+var dataCategories = []DataCategory{
+	{
+		Table:       "server_configs",
+		Name:        "Server settings",
+		Description: "Notification channel ID, optional webhook URL, ping role ID, announcement delay, region, claim reminder, text fallback, expiry reminder and Coming Soon settings, channel check results and whether notifications are active.",
+		Retention:   "Kept while the bot is in the server. /unsubscribe or removing the bot deactivates the settings but keeps them so /setup can resume them.",
+		GuildColumn: "guild_id",
+	},
+	{
+		Table:       "known_guilds",
+		Name:        "Server name",
+		Description: "Server ID, name and member count, and when the bot first and last connected to the server.",
+		Retention:   "Deleted when the bot is removed from the server.",
+		GuildColumn: "guild_id",
+	},
+	{
+		Table:       "muted_games",
+		Name:        "Muted games",
+		Description: "Game titles muted with /mute and the user ID of the admin who muted each one.",
+		Retention:   "Kept until the game is unmuted with /unmute.",
+		GuildColumn: "guild_id",
+	},
+	{
+		Table:       "delivery_decisions",
+		Name:        "Notification records",
+		Description: "Channel ID, game title and outcome of each announcement attempt, shown by /status view:recent.",
+		Retention:   fmt.Sprintf("Deleted after %d days.", DeliveryDecisionRetentionDays),
+		GuildColumn: "guild_id",
+	},
+	{
+		Table:       "notifications_sent",
+		Name:        "Sent notification records",
+		Description: "Game titles already announced to the server (or marked seen with /markseen) and titles it got a \"last chance\" reminder for, so neither is repeated.",
+		Retention:   fmt.Sprintf("Deleted after %d days.", NotificationSentRetentionDays),
+		GuildColumn: "guild_id",
+	},
+	{
+		Table:       "posted_messages",
+		Name:        "Posted announcement records",
+		Description: "Channel and message IDs of the announcements and reminders posted in the server, so they can be edited when a store corrects a promotion's dates.",
+		Retention:   fmt.Sprintf("Deleted after %d days.", PostedMessageRetentionDays),
+		GuildColumn: "guild_id",
+	},
+	{
+		Table:       "user_subscriptions",
+		Name:        "DM subscriptions",
+		Description: "Discord user IDs of people who asked for free game DMs with /subscribe, and how many DMs in a row failed.",
+		Retention:   "Deleted on /unsubscribe. DMs stop after repeated failures, for example when DMs are closed.",
+	},
+	{
+		Table:       "settings_presets",
+		Name:        "Saved settings presets",
+		Description: "Discord user IDs of admins who saved a server's settings as a named preset with /settings save, and the saved settings.",
+		Retention:   "Kept until deleted with /settings delete.",
+	},
+	{
+		Table:       "games",
+		Name:        "Free games catalog",
+		Description: "Public store listings of free games. Contains no server or user data.",
+		Retention:   fmt.Sprintf("Deleted %d days after a game was last seen in a store.", GameRetentionDays),
+	},
+	{
+		Table:       "game_changes",
+		Name:        "Game edit history",
+		Description: "Store listing fields that changed during a promotion, such as titles and images. Contains no server or user data.",
+		Retention:   fmt.Sprintf("Deleted after %d days.", GameChangeRetentionDays),
+	},
+	{
+		Table:       "scrape_history",
+		Name:        "Scrape history",
+		Description: "Time, outcome, duration and number of games found of each store scrape. Contains no server or user data.",
+		Retention:   fmt.Sprintf("Deleted after %d days.", ScrapeHistoryRetentionDays),
+	},
+	{
+		Table:       "bot_state",
+		Name:        "Bot bookkeeping",
+		Description: "Instance-wide settings such as the command registration strategy. Contains no server or user data.",
+		Retention:   "Overwritten as the bot runs.",
+	},
+	{
+		Table:       "restarts",
+		Name:        "Restart history",
+		Description: "Start and stop times of the bot process. Contains no server or user data.",
+		Retention:   "Kept indefinitely.",
+	},
+}
+
+// DataCategories returns every registered data category
+func DataCategories() []DataCategory {
+	return append([]DataCategory(nil), dataCategories...)
+}
+
+// GuildData is a per-server data category with the number of rows currently
+// stored for one guild
+type GuildData struct {
+	DataCategory
+	Rows int
+}
+
+// GetGuildData counts the rows stored for guildID in every per-server category
+func (d *Database) GetGuildData(guildID string) ([]GuildData, error) {
+	var data []GuildData
+	for _, category := range dataCategories {
+		if !category.PerGuild() {
+			continue
+		}
+
+		var rows int
+		query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = ?", category.Table, category.GuildColumn)
+		if err := d.db.QueryRow(query, guildID).Scan(&rows); err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", category.Table, err)
+		}
+		data = append(data, GuildData{DataCategory: category, Rows: rows})
+	}
+	return data, nil
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"sort"
+	"testing"
+)
+
+// TestEveryTableHasADataCategory keeps /privacy complete: a feature adding a
+// table must register it in dataCategories, and every registered category
+// must name a real table
+func TestEveryTableHasADataCategory(t *testing.T) {
+	db := newTestDB(t)
+
+	rows, err := db.db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`)
+	if err != nil {
+		t.Fatalf("listing tables: %v", err)
+	}
+	defer rows.Close()
+	tables := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("scanning table name: %v", err)
+		}
+		tables[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("listing tables: %v", err)
+	}
+
+	registered := make(map[string]bool, len(dataCategories))
+	for _, category := range dataCategories {
+		registered[category.Table] = true
+		if !tables[category.Table] {
+			t.Errorf("data category %q names table %s, which does not exist", category.Name, category.Table)
+		}
+	}
+
+	var missing []string
+	for table := range tables {
+		if !registered[table] {
+			missing = append(missing, table)
+		}
+	}
+	sort.Strings(missing)
+	for _, table := range missing {
+		t.Errorf("table %s has no data category; register it in dataCategories", table)
+	}
+}
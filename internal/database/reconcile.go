@@ -0,0 +1,56 @@
+package database
+
+import (
+	"fmt"
+)
+
+// Reasons stored in server_configs.needs_attention when channel
+// reconciliation finds a configured channel unusable
+const (
+	AttentionChannelDeleted      = "channel_deleted"
+	AttentionChannelMoved        = "channel_moved"
+	AttentionChannelInaccessible = "channel_inaccessible"
+)
+
+// GetActiveServerConfigsAfter returns up to limit active server configs whose
+// guild ID sorts after afterGuildID, in guild ID order, so a scan over all
+// configs can stop and resume where it left off
+func (d *Database) GetActiveServerConfigsAfter(afterGuildID string, limit int) ([]*ServerConfig, error) {
+	query := `
+		SELECT ` + serverConfigColumns + `
+		FROM server_configs
+		WHERE active = 1 AND guild_id > ?
+		ORDER BY guild_id
+		LIMIT ?
+	`
+
+	rows, err := d.db.Query(query, afterGuildID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query server configs: %w", err)
+	}
+	defer rows.Close()
+
+	var configs []*ServerConfig
+	for rows.Next() {
+		var config ServerConfig
+		if err := scanServerConfig(rows, &config); err != nil {
+			return nil, fmt.Errorf("failed to scan server config: %w", err)
+		}
+		configs = append(configs, &config)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate server configs: %w", err)
+	}
+
+	return configs, nil
+}
+
+// SetNeedsAttention flags a guild's config with one of the Attention*
+// reasons; an empty reason clears the flag. The config stays active.
+func (d *Database) SetNeedsAttention(guildID, reason string) error {
+	var value interface{}
+	if reason != "" {
+		value = reason
+	}
+	return d.updateServerSetting(guildID, "needs_attention", value)
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Restart records one run of the bot. CleanShutdown is only set when the run
+// stopped gracefully, so a run that crashed or was killed stays unclean.
+type Restart struct {
+	ID            int64
+	StartedAt     time.Time
+	StoppedAt     *time.Time
+	Version       string
+	CleanShutdown bool
+}
+
+// createRestartsTable creates the restarts table
+func (d *Database) createRestartsTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS restarts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		started_at DATETIME NOT NULL,
+		stopped_at DATETIME,
+		version TEXT,
+		clean_shutdown BOOLEAN DEFAULT 0
+	);
+	CREATE INDEX IF NOT EXISTS idx_restarts_started_at ON restarts(started_at);
+	`
+
+	if _, err := d.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create restarts table: %w", err)
+	}
+
+	log.Println("Restarts table created/verified")
+	return nil
+}
+
+// RecordStartup records the start of a run and returns its ID
+func (d *Database) RecordStartup(version string, startedAt time.Time) (int64, error) {
+	result, err := d.db.Exec(
+		`INSERT INTO restarts (started_at, version) VALUES (?, ?)`,
+		startedAt.UTC().Format("2006-01-02 15:04:05"), version,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record startup: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+// MarkCleanShutdown flags a run as having stopped gracefully
+func (d *Database) MarkCleanShutdown(id int64, stoppedAt time.Time) error {
+	_, err := d.db.Exec(
+		`UPDATE restarts SET clean_shutdown = 1, stopped_at = ? WHERE id = ?`,
+		stoppedAt.UTC().Format("2006-01-02 15:04:05"), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark clean shutdown: %w", err)
+	}
+	return nil
+}
+
+// GetRestarts returns up to limit runs, most recent first
+func (d *Database) GetRestarts(limit int) ([]Restart, error) {
+	rows, err := d.db.Query(`
+		SELECT id, started_at, stopped_at, COALESCE(version, ''), COALESCE(clean_shutdown, 0)
+		FROM restarts
+		ORDER BY id DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query restarts: %w", err)
+	}
+	defer rows.Close()
+
+	var restarts []Restart
+	for rows.Next() {
+		var r Restart
+		var stoppedAt sql.NullTime
+		if err := rows.Scan(&r.ID, &r.StartedAt, &stoppedAt, &r.Version, &r.CleanShutdown); err != nil {
+			return nil, fmt.Errorf("failed to scan restart: %w", err)
+		}
+		if stoppedAt.Valid {
+			r.StoppedAt = &stoppedAt.Time
+		}
+		restarts = append(restarts, r)
+	}
+	return restarts, rows.Err()
+}
+
+// CountUncleanRestarts counts runs started since the given time that never
+// shut down cleanly, excluding the run with excludeID (normally the current one)
+func (d *Database) CountUncleanRestarts(since time.Time, excludeID int64) (int, error) {
+	var count int
+	err := d.db.QueryRow(`
+		SELECT COUNT(*) FROM restarts
+		WHERE started_at >= ? AND COALESCE(clean_shutdown, 0) = 0 AND id != ?
+	`, since.UTC().Format("2006-01-02 15:04:05"), excludeID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unclean restarts: %w", err)
+	}
+	return count, nil
+}
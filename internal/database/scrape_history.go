@@ -0,0 +1,86 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// ScrapeHistoryRetentionDays is how long scrape runs are kept
+const ScrapeHistoryRetentionDays = 30
+
+// ScrapeRun records one scrape of the stores
+type ScrapeRun struct {
+	ID         int64
+	StartedAt  time.Time
+	Success    bool
+	GamesFound int
+	Duration   time.Duration
+	// FailureClass is the models.Failure* class of a failed run, "" on success
+	FailureClass string
+}
+
+// createScrapeHistoryTable creates the scrape_history table
+func (d *Database) createScrapeHistoryTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS scrape_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		started_at DATETIME NOT NULL,
+		success BOOLEAN NOT NULL,
+		games_found INTEGER DEFAULT 0,
+		duration_ms INTEGER DEFAULT 0,
+		failure_class TEXT
+	);
+	CREATE INDEX IF NOT EXISTS idx_scrape_history_started_at ON scrape_history(started_at);
+	`
+
+	if _, err := d.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create scrape_history table: %w", err)
+	}
+
+	log.Println("Scrape history table created/verified")
+	return nil
+}
+
+// RecordScrape stores a scrape run and drops runs older than
+// ScrapeHistoryRetentionDays
+func (d *Database) RecordScrape(run ScrapeRun) error {
+	_, err := d.db.Exec(
+		`INSERT INTO scrape_history (started_at, success, games_found, duration_ms, failure_class) VALUES (?, ?, ?, ?, NULLIF(?, ''))`,
+		formatStoredTime(run.StartedAt), run.Success, run.GamesFound, run.Duration.Milliseconds(), run.FailureClass,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record scrape: %w", err)
+	}
+
+	if _, err := d.db.Exec(`DELETE FROM scrape_history WHERE started_at < datetime('now', ?)`, fmt.Sprintf("-%d days", ScrapeHistoryRetentionDays)); err != nil {
+		return fmt.Errorf("failed to cleanup scrape history: %w", err)
+	}
+	return nil
+}
+
+// GetScrapeHistory returns up to limit scrape runs, most recent first
+func (d *Database) GetScrapeHistory(limit int) ([]ScrapeRun, error) {
+	rows, err := d.db.Query(`
+		SELECT id, started_at, success, COALESCE(games_found, 0), COALESCE(duration_ms, 0), COALESCE(failure_class, '')
+		FROM scrape_history
+		ORDER BY started_at DESC, id DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scrape history: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []ScrapeRun
+	for rows.Next() {
+		var run ScrapeRun
+		var durationMS int64
+		if err := rows.Scan(&run.ID, &run.StartedAt, &run.Success, &run.GamesFound, &durationMS, &run.FailureClass); err != nil {
+			return nil, fmt.Errorf("failed to scan scrape run: %w", err)
+		}
+		run.Duration = time.Duration(durationMS) * time.Millisecond
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
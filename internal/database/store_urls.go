@@ -0,0 +1,113 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"free-games-scrape/internal/models"
+)
+
+// Outcomes of a store URL lookup, stored in games.store_url_lookup. Rows
+// without an outcome are still pending, including ones whose lookup failed.
+const (
+	StoreURLResolved  = "resolved"
+	StoreURLAmbiguous = "ambiguous"
+	StoreURLManual    = "manual"
+	StoreURLFailed    = "failed"
+)
+
+// missingStoreURL selects Epic games without a store link
+const missingStoreURL = `COALESCE(store_url, '') = '' AND COALESCE(source, '') != '` + models.SourceGOG + `'`
+
+// NextStoreURLLookup returns the title of a game still waiting for a store
+// URL lookup, or "" when none is left. Titles never tried come first, then
+// the ones whose last lookup failed longest ago.
+func (d *Database) NextStoreURLLookup() (string, error) {
+	var title string
+	err := d.db.QueryRow(`
+		SELECT title FROM games
+		WHERE ` + missingStoreURL + ` AND store_url_lookup IS NULL
+		ORDER BY COALESCE(store_url_lookup_at, ''), id
+		LIMIT 1
+	`).Scan(&title)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get next store URL lookup: %w", err)
+	}
+	return title, nil
+}
+
+// RecordStoreURLLookup stores the outcome of looking up title for every
+// stored game of that title without a store link. A failed lookup only
+// records the attempt, so the title is retried after the others.
+func (d *Database) RecordStoreURLLookup(title, outcome, storeURL string) error {
+	now := time.Now().UTC().Format("2006-01-02 15:04:05")
+	var err error
+	switch outcome {
+	case StoreURLResolved:
+		_, err = d.db.Exec(`UPDATE games SET store_url = ?, store_url_lookup = ?, store_url_lookup_at = ? WHERE title = ? AND `+missingStoreURL,
+			storeURL, outcome, now, title)
+	case StoreURLAmbiguous:
+		_, err = d.db.Exec(`UPDATE games SET store_url_lookup = ?, store_url_lookup_at = ? WHERE title = ? AND `+missingStoreURL, outcome, now, title)
+	case StoreURLFailed:
+		_, err = d.db.Exec(`UPDATE games SET store_url_lookup_at = ? WHERE title = ? AND `+missingStoreURL, now, title)
+	default:
+		return fmt.Errorf("unknown store URL lookup outcome %q", outcome)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record store URL lookup: %w", err)
+	}
+	return nil
+}
+
+// GetAmbiguousStoreURLGames returns up to limit games whose store URL lookup
+// needs a person to pick the link, oldest first
+func (d *Database) GetAmbiguousStoreURLGames(limit int) ([]models.Game, error) {
+	rows, err := d.db.Query(`
+		SELECT `+gameColumns+` FROM games
+		WHERE store_url_lookup = ? AND COALESCE(store_url, '') = ''
+		ORDER BY id
+		LIMIT ?
+	`, StoreURLAmbiguous, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ambiguous store URL games: %w", err)
+	}
+	defer rows.Close()
+
+	var games []models.Game
+	for rows.Next() {
+		var game models.Game
+		if err := scanGame(rows, &game); err != nil {
+			return nil, fmt.Errorf("failed to scan game: %w", err)
+		}
+		games = append(games, game)
+	}
+	return games, rows.Err()
+}
+
+// SetGameStoreURL sets the store link of the game with id, and of other
+// stored games of the same store and title without one, reporting the title
+// or "" when no game has that id
+func (d *Database) SetGameStoreURL(id int64, storeURL string) (string, error) {
+	var title, source string
+	err := d.db.QueryRow(`SELECT title, source FROM games WHERE id = ?`, id).Scan(&title, &source)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get game %d: %w", id, err)
+	}
+
+	now := time.Now().UTC().Format("2006-01-02 15:04:05")
+	_, err = d.db.Exec(`
+		UPDATE games SET store_url = ?, store_url_lookup = ?, store_url_lookup_at = ?
+		WHERE id = ? OR (source = ? AND title = ? AND COALESCE(store_url, '') = '')
+	`, storeURL, StoreURLManual, now, id, source, title)
+	if err != nil {
+		return "", fmt.Errorf("failed to set store URL: %w", err)
+	}
+	return title, nil
+}
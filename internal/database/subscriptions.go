@@ -0,0 +1,130 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// UserSubscription is a user who asked for free game DMs with /subscribe
+type UserSubscription struct {
+	UserID    string    `json:"user_id"`
+	Active    bool      `json:"active"`
+	Failures  int       `json:"failures"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// createUserSubscriptionsTable creates the user_subscriptions table
+func (d *Database) createUserSubscriptionsTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS user_subscriptions (
+		user_id TEXT PRIMARY KEY,
+		active INTEGER DEFAULT 1,
+		failures INTEGER DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	`
+
+	if _, err := d.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create user_subscriptions table: %w", err)
+	}
+
+	log.Println("User subscriptions table created/verified")
+	return nil
+}
+
+// SubscribeUser activates DM notifications for a user, resetting any earlier
+// delivery failures. It reports whether the user was already subscribed.
+func (d *Database) SubscribeUser(userID string) (bool, error) {
+	existing, err := d.GetUserSubscription(userID)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now().UTC().Format("2006-01-02 15:04:05")
+	query := `
+		INSERT INTO user_subscriptions (user_id, active, failures, created_at, updated_at)
+		VALUES (?, 1, 0, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET active = 1, failures = 0, updated_at = excluded.updated_at
+	`
+	if _, err := d.db.Exec(query, userID, now, now); err != nil {
+		return false, fmt.Errorf("failed to subscribe user: %w", err)
+	}
+	return existing != nil && existing.Active, nil
+}
+
+// UnsubscribeUser deletes a user's DM subscription, reporting whether it was
+// active
+func (d *Database) UnsubscribeUser(userID string) (bool, error) {
+	existing, err := d.GetUserSubscription(userID)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := d.db.Exec(`DELETE FROM user_subscriptions WHERE user_id = ?`, userID); err != nil {
+		return false, fmt.Errorf("failed to unsubscribe user: %w", err)
+	}
+	return existing != nil && existing.Active, nil
+}
+
+// GetUserSubscription returns a user's subscription, or nil if they never
+// subscribed
+func (d *Database) GetUserSubscription(userID string) (*UserSubscription, error) {
+	var sub UserSubscription
+	err := d.db.QueryRow(`
+		SELECT user_id, active, failures, created_at FROM user_subscriptions WHERE user_id = ?
+	`, userID).Scan(&sub.UserID, &sub.Active, &sub.Failures, &sub.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user subscription: %w", err)
+	}
+	return &sub, nil
+}
+
+// GetActiveSubscribers returns the IDs of every user with active DM notifications
+func (d *Database) GetActiveSubscribers() ([]string, error) {
+	rows, err := d.db.Query(`SELECT user_id FROM user_subscriptions WHERE active = 1 ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan subscriber: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
+
+// RecordSubscriptionFailure counts a failed DM to a user and deactivates the
+// subscription once maxFailures consecutive DMs have failed. It reports
+// whether the subscription was deactivated.
+func (d *Database) RecordSubscriptionFailure(userID string, maxFailures int) (bool, error) {
+	now := time.Now().UTC().Format("2006-01-02 15:04:05")
+	if _, err := d.db.Exec(`UPDATE user_subscriptions SET failures = failures + 1, updated_at = ? WHERE user_id = ?`, now, userID); err != nil {
+		return false, fmt.Errorf("failed to record subscription failure: %w", err)
+	}
+
+	result, err := d.db.Exec(`UPDATE user_subscriptions SET active = 0 WHERE user_id = ? AND active = 1 AND failures >= ?`, userID, maxFailures)
+	if err != nil {
+		return false, fmt.Errorf("failed to deactivate subscription: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// ResetSubscriptionFailures clears a user's failure count after a successful DM
+func (d *Database) ResetSubscriptionFailures(userID string) error {
+	if _, err := d.db.Exec(`UPDATE user_subscriptions SET failures = 0 WHERE user_id = ? AND failures > 0`, userID); err != nil {
+		return fmt.Errorf("failed to reset subscription failures: %w", err)
+	}
+	return nil
+}
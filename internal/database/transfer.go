@@ -0,0 +1,210 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"free-games-scrape/internal/models"
+)
+
+// ExportVersion is the format written by ExportData; ImportData rejects
+// exports of any other version
+const ExportVersion = 1
+
+// Export is a portable copy of the games catalog and server settings, used
+// to seed another instance
+type Export struct {
+	Version       int                    `json:"version"`
+	ExportedAt    time.Time              `json:"exported_at"`
+	Games         []ExportedGame         `json:"games"`
+	ServerConfigs []ExportedServerConfig `json:"server_configs"`
+}
+
+// ExportedGame is a stored game including the promotion window, which
+// models.Game keeps out of JSON
+type ExportedGame struct {
+	models.Game
+	FreeFromAt *time.Time `json:"free_from_at,omitempty"`
+	FreeToAt   *time.Time `json:"free_to_at,omitempty"`
+}
+
+// ExportedServerConfig is a server's settings including whether they are
+// active and the webhook URL, which ServerConfig keeps out of JSON
+type ExportedServerConfig struct {
+	ServerConfig
+	Active     bool   `json:"active"`
+	WebhookURL string `json:"webhook_url,omitempty"`
+}
+
+// ImportResult counts the records an import inserted and the ones skipped
+// because a record with the same key already existed
+type ImportResult struct {
+	GamesImported   int
+	GamesSkipped    int
+	ConfigsImported int
+	ConfigsSkipped  int
+}
+
+// ExportData returns every stored game and server config
+func (d *Database) ExportData() (*Export, error) {
+	export := &Export{
+		Version:       ExportVersion,
+		ExportedAt:    time.Now().UTC(),
+		Games:         []ExportedGame{},
+		ServerConfigs: []ExportedServerConfig{},
+	}
+
+	rows, err := d.db.Query(`SELECT ` + gameColumns + ` FROM games ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query games: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var game models.Game
+		if err := scanGame(rows, &game); err != nil {
+			return nil, fmt.Errorf("failed to scan game: %w", err)
+		}
+		exported := ExportedGame{Game: game, FreeFromAt: exportTime(game.FreeFromTime), FreeToAt: exportTime(game.FreeToTime)}
+		exported.ID = 0
+		export.Games = append(export.Games, exported)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate games: %w", err)
+	}
+
+	active := make(map[string]bool)
+	activeRows, err := d.db.Query(`SELECT guild_id FROM server_configs WHERE active = 1`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query active server configs: %w", err)
+	}
+	defer activeRows.Close()
+	for activeRows.Next() {
+		var guildID string
+		if err := activeRows.Scan(&guildID); err != nil {
+			return nil, fmt.Errorf("failed to scan active server config: %w", err)
+		}
+		active[guildID] = true
+	}
+	if err := activeRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate active server configs: %w", err)
+	}
+
+	configRows, err := d.db.Query(`SELECT ` + serverConfigColumns + ` FROM server_configs ORDER BY guild_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query server configs: %w", err)
+	}
+	defer configRows.Close()
+	for configRows.Next() {
+		var config ServerConfig
+		if err := scanServerConfig(configRows, &config); err != nil {
+			return nil, fmt.Errorf("failed to scan server config: %w", err)
+		}
+		export.ServerConfigs = append(export.ServerConfigs, ExportedServerConfig{
+			ServerConfig: config,
+			Active:       active[config.GuildID],
+			WebhookURL:   config.WebhookURL,
+		})
+	}
+	if err := configRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate server configs: %w", err)
+	}
+
+	return export, nil
+}
+
+// exportTime returns t in UTC, or nil for the zero time
+func exportTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// ImportData inserts the games and server configs of an export in a single
+// transaction. Records whose key (store, title and end date for games, guild
+// for configs) is already stored are skipped rather than overwritten. Imported
+// games are marked as announced, to imported servers too, so the new
+// instance does not post them again, and are treated as seen now until the
+// next scrape updates them. Records are expected to be validated by the
+// caller.
+func (d *Database) ImportData(export *Export) (*ImportResult, error) {
+	if export.Version != ExportVersion {
+		return nil, fmt.Errorf("unsupported export version %d (expected %d)", export.Version, ExportVersion)
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := &ImportResult{}
+	gameStmt, err := tx.Prepare(`
+		INSERT OR IGNORE INTO games (title, image_url, status, free_from, free_to, store_url, source, regions, images, period, free_from_at, free_to_at, notified, updated_at, last_seen)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare game statement: %w", err)
+	}
+	defer gameStmt.Close()
+
+	for _, game := range export.Games {
+		var freeFromAt, freeToAt time.Time
+		if game.FreeFromAt != nil {
+			freeFromAt = *game.FreeFromAt
+		}
+		if game.FreeToAt != nil {
+			freeToAt = *game.FreeToAt
+		}
+		res, err := gameStmt.Exec(game.Title, game.ImageURL, game.Status, game.FreeFrom, game.FreeTo, game.StoreURL, game.StoreSource(), strings.Join(game.Regions, ","), strings.Join(game.Images, "\n"),
+			game.Period, formatStoredTime(freeFromAt), formatStoredTime(freeToAt))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import game %s: %w", game.Title, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			result.GamesImported++
+		} else {
+			result.GamesSkipped++
+		}
+	}
+
+	configStmt, err := tx.Prepare(`
+		INSERT OR IGNORE INTO server_configs (guild_id, channel_id, active, post_delay_seconds, text_fallback, role_id, region, claim_reminder, expiry_reminders, webhook_url, comingsoon_mode, message_format, timezone, sources, content_policy, banner_images, updated_at)
+		VALUES (?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?, NULLIF(?, ''), ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?, CURRENT_TIMESTAMP)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare server config statement: %w", err)
+	}
+	defer configStmt.Close()
+
+	for _, config := range export.ServerConfigs {
+		mode := config.ComingSoonMode
+		if mode == "" {
+			mode = ComingSoonBoth
+		}
+		format := config.MessageFormat
+		if format == "" {
+			format = FormatEmbed
+		}
+		res, err := configStmt.Exec(config.GuildID, config.ChannelID, config.Active, config.PostDelaySeconds, config.TextFallback, config.RoleID, config.Region, config.ClaimReminder,
+			config.ExpiryReminders, config.WebhookURL, mode, format, config.Timezone, config.Sources, config.ContentPolicy, config.BannerImages)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import server config for guild %s: %w", config.GuildID, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			result.ConfigsImported++
+			if err := markAnnouncedForGuild(tx, config.GuildID); err != nil {
+				return nil, err
+			}
+		} else {
+			result.ConfigsSkipped++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit import: %w", err)
+	}
+	return result, nil
+}
@@ -3,11 +3,14 @@ package logger
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"runtime"
 	"strings"
 	"time"
+
+	"free-games-scrape/internal/security"
 )
 
 // Logger wraps slog.Logger with additional functionality
@@ -26,8 +29,14 @@ const (
 	LevelError LogLevel = "error"
 )
 
-// New creates a new logger instance
+// New creates a new logger instance writing to stdout
 func New(level LogLevel, environment string) *Logger {
+	return NewWithOutput(level, environment, os.Stdout)
+}
+
+// NewWithOutput creates a logger writing to out, for commands whose stdout
+// carries their own output
+func NewWithOutput(level LogLevel, environment string, out io.Writer) *Logger {
 	var slogLevel slog.Level
 	switch level {
 	case LevelDebug:
@@ -67,10 +76,10 @@ func New(level LogLevel, environment string) *Logger {
 
 	if environment == "production" {
 		// JSON format for production
-		handler = slog.NewJSONHandler(os.Stdout, opts)
+		handler = slog.NewJSONHandler(out, opts)
 	} else {
 		// Text format for development
-		handler = slog.NewTextHandler(os.Stdout, opts)
+		handler = slog.NewTextHandler(out, opts)
 	}
 
 	logger := slog.New(handler)
@@ -81,10 +90,87 @@ func New(level LogLevel, environment string) *Logger {
 	}
 }
 
-// WithContext adds context to the logger
+// contextKey is the type for values this package stores in a context
+type contextKey string
+
+const (
+	requestIDKey contextKey = "request_id"
+	guildIDKey   contextKey = "guild_id"
+	userIDKey    contextKey = "user_id"
+	componentKey contextKey = "component"
+)
+
+// contextFields lists the context values WithContext attaches, in output order
+var contextFields = []contextKey{requestIDKey, guildIDKey, userIDKey, componentKey}
+
+// NewRequestID generates a short random ID used to correlate log lines
+// belonging to one interaction, scrape cycle or HTTP request
+func NewRequestID() string {
+	id, err := security.GenerateSecureToken(4)
+	if err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano()&0xffffffff)
+	}
+	return id
+}
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// ContextWithGuildID returns a copy of ctx carrying the Discord guild ID
+func ContextWithGuildID(ctx context.Context, guildID string) context.Context {
+	return context.WithValue(ctx, guildIDKey, guildID)
+}
+
+// ContextWithUserID returns a copy of ctx carrying the Discord user ID
+func ContextWithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// ContextWithComponent returns a copy of ctx carrying the component name
+func ContextWithComponent(ctx context.Context, component string) context.Context {
+	return context.WithValue(ctx, componentKey, component)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, if any
+func RequestIDFromContext(ctx context.Context) string {
+	return stringFromContext(ctx, requestIDKey)
+}
+
+// GuildIDFromContext returns the guild ID stored in ctx, if any
+func GuildIDFromContext(ctx context.Context) string {
+	return stringFromContext(ctx, guildIDKey)
+}
+
+// UserIDFromContext returns the user ID stored in ctx, if any
+func UserIDFromContext(ctx context.Context) string {
+	return stringFromContext(ctx, userIDKey)
+}
+
+// stringFromContext returns the string value stored under key, if any
+func stringFromContext(ctx context.Context, key contextKey) string {
+	if ctx == nil {
+		return ""
+	}
+	value, _ := ctx.Value(key).(string)
+	return value
+}
+
+// WithContext adds the request ID, guild ID, user ID and component carried by
+// ctx to the logger. Values that are absent or empty are skipped.
 func (l *Logger) WithContext(ctx context.Context) *Logger {
+	var args []interface{}
+	for _, key := range contextFields {
+		if value := stringFromContext(ctx, key); value != "" {
+			args = append(args, string(key), value)
+		}
+	}
+	if len(args) == 0 {
+		return l
+	}
 	return &Logger{
-		Logger: l.Logger.With(),
+		Logger: l.Logger.With(args...),
 		level:  l.level,
 	}
 }
@@ -0,0 +1,151 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// records decodes the JSON lines a production logger wrote
+func records(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", line, err)
+		}
+		lines = append(lines, record)
+	}
+	return lines
+}
+
+func TestStructuredFields(t *testing.T) {
+	var out bytes.Buffer
+	l := NewWithOutput(LevelInfo, "production", &out)
+
+	l.WithComponent("scraper").WithFields(map[string]interface{}{"games_found": 3, "locale": "en-US"}).Info("Scraping completed")
+	l.WithError(nil).Warn("No error attached")
+
+	lines := records(t, &out)
+	if len(lines) != 2 {
+		t.Fatalf("wrote %d lines, want 2", len(lines))
+	}
+	want := map[string]interface{}{
+		"level":       "INFO",
+		"msg":         "Scraping completed",
+		"component":   "scraper",
+		"games_found": float64(3),
+		"locale":      "en-US",
+	}
+	for key, value := range want {
+		if lines[0][key] != value {
+			t.Errorf("%s = %v, want %v", key, lines[0][key], value)
+		}
+	}
+	if _, err := time.Parse(time.RFC3339, lines[0]["time"].(string)); err != nil {
+		t.Errorf("time %v is not RFC 3339: %v", lines[0]["time"], err)
+	}
+	if _, ok := lines[1]["error"]; ok {
+		t.Errorf("WithError(nil) added an error field: %v", lines[1])
+	}
+}
+
+func TestLevelFiltersOutput(t *testing.T) {
+	var out bytes.Buffer
+	l := NewWithOutput(LevelWarn, "production", &out)
+
+	l.Debug("debug")
+	l.Info("info")
+	l.Warn("warn")
+	l.Error("error")
+
+	var messages []string
+	for _, record := range records(t, &out) {
+		messages = append(messages, record["msg"].(string))
+	}
+	if got := strings.Join(messages, ","); got != "warn,error" {
+		t.Errorf("logged %q, want only warn and error", got)
+	}
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	var out bytes.Buffer
+	l := NewWithOutput(LevelInfo, "production", &out)
+
+	requestID := NewRequestID()
+	ctx := ContextWithRequestID(context.Background(), requestID)
+	ctx = ContextWithGuildID(ctx, "123456789012345678")
+	ctx = ContextWithUserID(ctx, "")
+
+	// The ID survives contexts derived for cancellation and is the same for
+	// every logger taken from them
+	derived, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	if got := RequestIDFromContext(derived); got != requestID {
+		t.Fatalf("RequestIDFromContext = %q, want %q", got, requestID)
+	}
+	l.WithContext(ctx).Info("Handling command")
+	l.WithContext(derived).WithFields(map[string]interface{}{"command": "games"}).Info("Command finished")
+
+	lines := records(t, &out)
+	if len(lines) != 2 {
+		t.Fatalf("wrote %d lines, want 2", len(lines))
+	}
+	for _, record := range lines {
+		if record["request_id"] != requestID {
+			t.Errorf("%q logged request_id %v, want %q", record["msg"], record["request_id"], requestID)
+		}
+		if record["guild_id"] != "123456789012345678" {
+			t.Errorf("%q logged guild_id %v", record["msg"], record["guild_id"])
+		}
+		if _, ok := record["user_id"]; ok {
+			t.Errorf("%q logged an empty user_id", record["msg"])
+		}
+	}
+	if lines[1]["command"] != "games" {
+		t.Errorf("fields added after WithContext were lost: %v", lines[1])
+	}
+}
+
+func TestWithContextWithoutValues(t *testing.T) {
+	l := NewWithOutput(LevelInfo, "production", &bytes.Buffer{})
+	if got := l.WithContext(context.Background()); got != l {
+		t.Error("WithContext without values returned a new logger")
+	}
+	if got := RequestIDFromContext(nil); got != "" {
+		t.Errorf("RequestIDFromContext(nil) = %q, want empty", got)
+	}
+}
+
+func TestTextOutputCarriesRequestID(t *testing.T) {
+	var out bytes.Buffer
+	l := NewWithOutput(LevelInfo, "staging", &out)
+
+	l.WithContext(ContextWithRequestID(context.Background(), "abcd1234")).Info("Scrape started")
+	if !strings.Contains(out.String(), "request_id=abcd1234") {
+		t.Errorf("text output %q does not carry the request ID", out.String())
+	}
+}
+
+func TestNewRequestID(t *testing.T) {
+	format := regexp.MustCompile(`^[0-9a-f]{8}$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id := NewRequestID()
+		if !format.MatchString(id) {
+			t.Fatalf("request ID %q is not 8 hex digits", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) < 95 {
+		t.Errorf("got %d distinct IDs out of 100", len(seen))
+	}
+}
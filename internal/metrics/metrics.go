@@ -15,11 +15,18 @@ type Metrics struct {
 	errors               int64
 	serversJoined        int64
 	serversLeft          int64
+	serversRejected      int64
 	lastScrapeTime       time.Time
 	lastScrapeSuccess    bool
 	lastScrapeDuration   time.Duration
 	activeConnections    int64
 	totalMemoryUsage     int64
+	lastDeliveryDuration time.Duration
+	workerUtilization    []float64
+	rateLimiters         int64
+	lastImageWarmup      string
+	lastImageWarmupTime  time.Duration
+	storeURLLookups      map[string]int64
 }
 
 // New creates a new metrics instance
@@ -120,6 +127,22 @@ func (m *Metrics) GetServersLeft() int64 {
 	return m.serversLeft
 }
 
+// IncrementServersRejected increments the counter of servers left because
+// they are not in GUILD_ALLOWLIST
+func (m *Metrics) IncrementServersRejected() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.serversRejected++
+}
+
+// GetServersRejected returns the number of servers left because they are not
+// in GUILD_ALLOWLIST
+func (m *Metrics) GetServersRejected() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.serversRejected
+}
+
 // SetLastScrapeTime sets the last scrape time and success status
 func (m *Metrics) SetLastScrapeTime(success bool, duration time.Duration) {
 	m.mu.Lock()
@@ -136,6 +159,69 @@ func (m *Metrics) GetLastScrapeInfo() (time.Time, bool, time.Duration) {
 	return m.lastScrapeTime, m.lastScrapeSuccess, m.lastScrapeDuration
 }
 
+// SetDeliveryStats records the wall time of the last delivery cycle and the
+// fraction of that time each delivery worker spent sending
+func (m *Metrics) SetDeliveryStats(duration time.Duration, utilization []float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastDeliveryDuration = duration
+	m.workerUtilization = append([]float64(nil), utilization...)
+}
+
+// GetDeliveryStats returns the last delivery cycle duration and per-worker utilization
+func (m *Metrics) GetDeliveryStats() (time.Duration, []float64) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastDeliveryDuration, append([]float64(nil), m.workerUtilization...)
+}
+
+// SetImageWarmup records the outcome of the image warm-up before the last
+// delivery cycle and how long it held the cycle back
+func (m *Metrics) SetImageWarmup(result string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastImageWarmup = result
+	m.lastImageWarmupTime = duration
+}
+
+// GetImageWarmup returns the outcome and duration of the last image warm-up
+func (m *Metrics) GetImageWarmup() (string, time.Duration) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastImageWarmup, m.lastImageWarmupTime
+}
+
+// IncrementStoreURLLookups counts a store URL backfill lookup by outcome
+func (m *Metrics) IncrementStoreURLLookups(outcome string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.storeURLLookups == nil {
+		m.storeURLLookups = make(map[string]int64)
+	}
+	m.storeURLLookups[outcome]++
+}
+
+// GetStoreURLLookups returns the number of store URL backfill lookups with outcome
+func (m *Metrics) GetStoreURLLookups(outcome string) int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.storeURLLookups[outcome]
+}
+
+// SetRateLimiters records how many per-channel rate limiters are kept
+func (m *Metrics) SetRateLimiters(count int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rateLimiters = count
+}
+
+// GetRateLimiters returns the number of per-channel rate limiters kept
+func (m *Metrics) GetRateLimiters() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.rateLimiters
+}
+
 // SetActiveConnections sets the number of active connections
 func (m *Metrics) SetActiveConnections(count int64) {
 	m.mu.Lock()
@@ -170,18 +256,24 @@ func (m *Metrics) Summary() map[string]interface{} {
 	defer m.mu.RUnlock()
 	
 	return map[string]interface{}{
-		"uptime":              m.GetUptime().String(),
+		"uptime":              time.Since(m.startTime).String(),
 		"commands_executed":   m.commandsExecuted,
 		"messages_processed":  m.messagesProcessed,
 		"games_scraped":       m.gamesScraped,
 		"errors":              m.errors,
 		"servers_joined":      m.serversJoined,
 		"servers_left":        m.serversLeft,
+		"servers_rejected":    m.serversRejected,
 		"last_scrape_time":    m.lastScrapeTime,
 		"last_scrape_success": m.lastScrapeSuccess,
 		"last_scrape_duration": m.lastScrapeDuration.String(),
 		"active_connections":  m.activeConnections,
 		"memory_usage_bytes":  m.totalMemoryUsage,
+		"last_delivery_duration": m.lastDeliveryDuration.String(),
+		"worker_utilization":  m.workerUtilization,
+		"rate_limiters":       m.rateLimiters,
+		"last_image_warmup":   m.lastImageWarmup,
+		"last_image_warmup_duration": m.lastImageWarmupTime.String(),
 	}
 }
 
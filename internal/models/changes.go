@@ -0,0 +1,143 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Game fields whose edits are recorded in the change history
+const (
+	FieldTitle    = "title"
+	FieldImageURL = "image_url"
+	FieldStatus   = "status"
+	FieldFreeFrom = "free_from"
+	FieldFreeTo   = "free_to"
+	FieldStoreURL = "store_url"
+	FieldPeriod   = "period"
+)
+
+// FieldChange is one edit to a stored game's metadata, detected when a scrape
+// is saved
+type FieldChange struct {
+	GameID     int64     `json:"game_id"`
+	GameTitle  string    `json:"game_title"`
+	Field      string    `json:"field"`
+	OldValue   string    `json:"old_value"`
+	NewValue   string    `json:"new_value"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// DiffFields compares a stored game with the scraped version of the same
+// promotion. Store URL and period are only compared when scraped, since an
+// empty value keeps the stored one. Titles identify games and are compared
+// by the caller.
+func DiffFields(stored, scraped Game) []FieldChange {
+	var changes []FieldChange
+	add := func(field, oldValue, newValue string) {
+		changes = append(changes, FieldChange{GameTitle: scraped.Title, Field: field, OldValue: oldValue, NewValue: newValue})
+	}
+
+	if !SameImage(stored.ImageURL, scraped.ImageURL) {
+		add(FieldImageURL, stored.ImageURL, scraped.ImageURL)
+	}
+	if stored.Status != scraped.Status {
+		add(FieldStatus, stored.Status, scraped.Status)
+	}
+	if stored.FreeFrom != scraped.FreeFrom {
+		add(FieldFreeFrom, stored.FreeFrom, scraped.FreeFrom)
+	}
+	if scraped.StoreURL != "" && stored.StoreURL != scraped.StoreURL {
+		add(FieldStoreURL, stored.StoreURL, scraped.StoreURL)
+	}
+	if scraped.Period != "" && stored.Period != scraped.Period {
+		add(FieldPeriod, stored.Period, scraped.Period)
+	}
+	return changes
+}
+
+// ReleasedGames returns the games whose stored status went from Coming Soon
+// to Free Now when they were saved, according to the edits recorded then
+func ReleasedGames(games []Game, edits []FieldChange) []Game {
+	flipped := make(map[string]bool)
+	for _, edit := range edits {
+		if edit.Field == FieldStatus && edit.OldValue == StatusComingSoon && edit.NewValue == StatusFreeNow {
+			flipped[edit.GameTitle] = true
+		}
+	}
+
+	var released []Game
+	for _, game := range games {
+		if flipped[game.Title] && game.Status == StatusFreeNow {
+			released = append(released, game)
+		}
+	}
+	return released
+}
+
+// SameImage reports whether two image URLs point at the same image. The query
+// string and fragment are ignored because CDNs rotate signed tokens there.
+func SameImage(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return strings.EqualFold(ua.Host, ub.Host) && ua.Path == ub.Path
+}
+
+// WordDiff renders the change from oldText to newText word by word. Removed
+// runs are shown as [-words-] and added runs as {+words+}, e.g.
+// "Control {+Ultimate Edition+}".
+func WordDiff(oldText, newText string) string {
+	a, b := strings.Fields(oldText), strings.Fields(newText)
+
+	// lcs[i][j] is the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out, removed, added []string
+	flush := func() {
+		if len(removed) > 0 {
+			out = append(out, "[-"+strings.Join(removed, " ")+"-]")
+			removed = nil
+		}
+		if len(added) > 0 {
+			out = append(out, "{+"+strings.Join(added, " ")+"+}")
+			added = nil
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			flush()
+			out = append(out, a[i])
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			added = append(added, b[j])
+			j++
+		default:
+			removed = append(removed, a[i])
+			i++
+		}
+	}
+	flush()
+	return strings.Join(out, " ")
+}
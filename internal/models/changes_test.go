@@ -0,0 +1,115 @@
+package models
+
+import "testing"
+
+func TestDiffFields(t *testing.T) {
+	stored := Game{
+		Title:    "Control",
+		ImageURL: "https://cdn1.epicgames.com/offer/control.jpg?h=480&token=abc",
+		Status:   StatusComingSoon,
+		FreeFrom: "Jul 24",
+		FreeTo:   "Jul 31",
+		StoreURL: "https://store.epicgames.com/p/control",
+		Period:   "Free Jul 24 - Jul 31",
+	}
+	tests := []struct {
+		name      string
+		change    func(g *Game)
+		wantField string
+		oldValue  string
+		newValue  string
+	}{
+		{name: "unchanged", change: func(g *Game) {}},
+		{name: "image replaced", change: func(g *Game) { g.ImageURL = "https://cdn1.epicgames.com/offer/control-new.jpg" }, wantField: FieldImageURL, oldValue: stored.ImageURL, newValue: "https://cdn1.epicgames.com/offer/control-new.jpg"},
+		{name: "image token rotated", change: func(g *Game) { g.ImageURL = "https://cdn1.epicgames.com/offer/control.jpg?h=480&token=xyz" }},
+		{name: "image host case", change: func(g *Game) { g.ImageURL = "https://CDN1.epicgames.com/offer/control.jpg#top" }},
+		{name: "status", change: func(g *Game) { g.Status = StatusFreeNow }, wantField: FieldStatus, oldValue: StatusComingSoon, newValue: StatusFreeNow},
+		{name: "start date", change: func(g *Game) { g.FreeFrom = "Jul 25" }, wantField: FieldFreeFrom, oldValue: "Jul 24", newValue: "Jul 25"},
+		{name: "store link", change: func(g *Game) { g.StoreURL = "https://store.epicgames.com/p/control-ultimate" }, wantField: FieldStoreURL, oldValue: stored.StoreURL, newValue: "https://store.epicgames.com/p/control-ultimate"},
+		{name: "store link not scraped", change: func(g *Game) { g.StoreURL = "" }},
+		{name: "period", change: func(g *Game) { g.Period = "Free Jul 24 - Jul 31 at 11:00 AM" }, wantField: FieldPeriod, oldValue: stored.Period, newValue: "Free Jul 24 - Jul 31 at 11:00 AM"},
+		{name: "period not scraped", change: func(g *Game) { g.Period = "" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraped := stored
+			tt.change(&scraped)
+			changes := DiffFields(stored, scraped)
+			if tt.wantField == "" {
+				if len(changes) != 0 {
+					t.Errorf("DiffFields = %+v, want no changes", changes)
+				}
+				return
+			}
+			if len(changes) != 1 {
+				t.Fatalf("DiffFields = %+v, want one %s change", changes, tt.wantField)
+			}
+			want := FieldChange{GameTitle: "Control", Field: tt.wantField, OldValue: tt.oldValue, NewValue: tt.newValue}
+			if changes[0] != want {
+				t.Errorf("DiffFields = %+v, want %+v", changes[0], want)
+			}
+		})
+	}
+}
+
+func TestSameImage(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"https://cdn.example.com/a.jpg", "https://cdn.example.com/a.jpg", true},
+		{"https://cdn.example.com/a.jpg?token=1", "https://cdn.example.com/a.jpg?token=2", true},
+		{"https://cdn.example.com/a.jpg", "https://CDN.example.com/a.jpg#x", true},
+		{"https://cdn.example.com/a.jpg", "https://cdn.example.com/b.jpg", false},
+		{"https://cdn.example.com/a.jpg", "https://other.example.com/a.jpg", false},
+		{"https://cdn.example.com/A.jpg", "https://cdn.example.com/a.jpg", false},
+		{"", "https://cdn.example.com/a.jpg", false},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		if got := SameImage(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameImage(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestReleasedGames(t *testing.T) {
+	games := []Game{
+		{Title: "Released", Status: StatusFreeNow},
+		{Title: "Still Upcoming", Status: StatusComingSoon},
+		{Title: "Always Free", Status: StatusFreeNow},
+	}
+	edits := []FieldChange{
+		{GameTitle: "Released", Field: FieldStatus, OldValue: StatusComingSoon, NewValue: StatusFreeNow},
+		{GameTitle: "Always Free", Field: FieldFreeFrom, OldValue: "Jul 17", NewValue: "Jul 18"},
+		{GameTitle: "Still Upcoming", Field: FieldStatus, OldValue: StatusFreeNow, NewValue: StatusComingSoon},
+	}
+	released := ReleasedGames(games, edits)
+	if len(released) != 1 || released[0].Title != "Released" {
+		t.Errorf("ReleasedGames = %v, want only Released", released)
+	}
+}
+
+func TestWordDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{name: "unchanged", old: "Control", new: "Control", want: "Control"},
+		{name: "words added", old: "Control", new: "Control Ultimate Edition", want: "Control {+Ultimate Edition+}"},
+		{name: "words removed", old: "Control Ultimate Edition", new: "Control", want: "Control [-Ultimate Edition-]"},
+		{name: "word replaced", old: "Free Jul 24 - Jul 31", new: "Free Jul 24 - Aug 1", want: "Free Jul 24 - [-Jul 31-] {+Aug 1+}"},
+		{name: "added in the middle", old: "Alan Wake Remastered", new: "Alan Wake 2 Remastered", want: "Alan Wake {+2+} Remastered"},
+		{name: "from empty", old: "", new: "New Game", want: "{+New Game+}"},
+		{name: "to empty", old: "Old Game", new: "", want: "[-Old Game-]"},
+		{name: "extra spaces", old: "Control  Deluxe", new: "Control Deluxe", want: "Control Deluxe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WordDiff(tt.old, tt.new); got != tt.want {
+				t.Errorf("WordDiff(%q, %q) = %q, want %q", tt.old, tt.new, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// SkipReason explains why a game was not announced to a guild. Every filter
+// in the delivery pipeline reports one of these so admins can see why a game
+// never arrived.
+type SkipReason string
+
+// Skip reasons produced by the delivery pipeline
+const (
+	SkipReasonNone               SkipReason = ""
+	SkipReasonFilteredSource     SkipReason = "filtered_source"
+	SkipReasonRepeatPolicy       SkipReason = "repeat_policy"
+	SkipReasonMuted              SkipReason = "muted"
+	SkipReasonRegion             SkipReason = "region_unavailable"
+	SkipReasonComingSoonMode     SkipReason = "coming_soon_mode"
+	SkipReasonRateLimited        SkipReason = "send_rate_limited"
+	SkipReasonMissingPermissions SkipReason = "send_missing_permissions"
+	SkipReasonChannelNotFound    SkipReason = "send_channel_not_found"
+	SkipReasonSendFailed         SkipReason = "send_failed"
+)
+
+// Description returns a human-readable explanation of the skip reason
+func (r SkipReason) Description() string {
+	switch r {
+	case SkipReasonNone:
+		return "Delivered"
+	case SkipReasonFilteredSource:
+		return "Filtered by store source"
+	case SkipReasonRepeatPolicy:
+		return "Already announced (repeat policy)"
+	case SkipReasonMuted:
+		return "Game was muted by a server admin"
+	case SkipReasonRegion:
+		return "Game is not free in this server's region"
+	case SkipReasonComingSoonMode:
+		return "Server only announces games once they are free"
+	case SkipReasonRateLimited:
+		return "Discord rate limited the message"
+	case SkipReasonMissingPermissions:
+		return "Bot lacks permission to post in the channel"
+	case SkipReasonChannelNotFound:
+		return "Notification channel no longer exists"
+	case SkipReasonSendFailed:
+		return "Message could not be sent"
+	}
+	return string(r)
+}
+
+// DeliveryDecision records whether a game was announced to a guild and, if not,
+// why. Policy is the guild's content policy the announcement was rendered under.
+type DeliveryDecision struct {
+	CycleID   string     `json:"cycle_id"`
+	GuildID   string     `json:"guild_id"`
+	ChannelID string     `json:"channel_id"`
+	GameTitle string     `json:"game_title"`
+	FreeTo    string     `json:"free_to"`
+	Delivered bool       `json:"delivered"`
+	Reason    SkipReason `json:"reason,omitempty"`
+	Policy    string     `json:"policy,omitempty"`
+	DecidedAt time.Time  `json:"decided_at"`
+}
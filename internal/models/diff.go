@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// ChangeType classifies a difference between two scrapes
+type ChangeType string
+
+const (
+	ChangeAdded     ChangeType = "added"
+	ChangeChanged   ChangeType = "changed"
+	ChangeWithdrawn ChangeType = "withdrawn"
+)
+
+// GameChange is one difference detected between the previous and current
+// scrape. Previous is set for changed and withdrawn games.
+type GameChange struct {
+	Type     ChangeType
+	Game     Game
+	Previous *Game
+}
+
+// DiffGames compares two scrapes by store and title. A game whose previous promotion has
+// already ended counts as added again rather than changed, and a game missing
+// from the current scrape is only withdrawn if its promotion had not ended yet.
+func DiffGames(previous, current []Game, now time.Time) []GameChange {
+	previousByTitle := make(map[string]Game, len(previous))
+	for _, game := range previous {
+		game.ParseDates(now)
+		if existing, ok := previousByTitle[game.SourceKey()]; !ok || game.FreeToTime.After(existing.FreeToTime) {
+			previousByTitle[game.SourceKey()] = game
+		}
+	}
+
+	var changes []GameChange
+	seen := make(map[string]bool, len(current))
+	for _, game := range current {
+		seen[game.SourceKey()] = true
+
+		prev, ok := previousByTitle[game.SourceKey()]
+		switch {
+		case !ok || !prev.liveAt(now):
+			changes = append(changes, GameChange{Type: ChangeAdded, Game: game})
+		case prev.FreeTo != game.FreeTo || prev.FreeFrom != game.FreeFrom || prev.Status != game.Status:
+			prevCopy := prev
+			changes = append(changes, GameChange{Type: ChangeChanged, Game: game, Previous: &prevCopy})
+		}
+	}
+
+	for _, game := range previous {
+		if seen[game.SourceKey()] {
+			continue
+		}
+		seen[game.SourceKey()] = true
+
+		prev := previousByTitle[game.SourceKey()]
+		if prev.liveAt(now) {
+			changes = append(changes, GameChange{Type: ChangeWithdrawn, Game: prev, Previous: &prev})
+		}
+	}
+
+	return changes
+}
+
+// liveAt reports whether the promotion had not yet ended at now. Games with
+// unknown end dates are assumed live.
+func (g *Game) liveAt(now time.Time) bool {
+	return g.FreeToTime.IsZero() || now.Before(g.FreeToTime)
+}
+
+// IsDateCorrection reports whether a change only moved the dates of a running
+// promotion: its status is the same, and its end date or, while it is Coming
+// Soon, its start date differs. Announcements show both, so they need an edit.
+func (c GameChange) IsDateCorrection() bool {
+	if c.Type != ChangeChanged || c.Previous == nil || c.Previous.Status != c.Game.Status {
+		return false
+	}
+	return c.Previous.FreeTo != c.Game.FreeTo ||
+		(c.Game.Status == StatusComingSoon && c.Previous.FreeFrom != c.Game.FreeFrom)
+}
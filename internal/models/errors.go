@@ -1,6 +1,14 @@
 package models
 
-import "errors"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
 
 // Common errors used throughout the application
 var (
@@ -9,4 +17,116 @@ var (
 	ErrDiscordSendFail  = errors.New("failed to send message to Discord")
 	ErrConfigMissing    = errors.New("required configuration is missing")
 	ErrScrapingFailed   = errors.New("scraping operation failed")
-)
\ No newline at end of file
+)
+
+// ErrScraperUnavailable means this host can't scrape at all: scraping is
+// turned off with SCRAPING_ENABLED or Chrome is missing
+var ErrScraperUnavailable = errors.New("scraping is unavailable on this host")
+
+// RetriesExhaustedError is returned by a scraper that failed on every attempt
+type RetriesExhaustedError struct {
+	// Source names what was being fetched, e.g. "Epic promotions (en-US)"
+	Source   string
+	Attempts int
+	// Err is the error of the last attempt
+	Err error
+}
+
+func (e *RetriesExhaustedError) Error() string {
+	return fmt.Sprintf("failed to fetch %s after %d attempts: %v", e.Source, e.Attempts, e.Err)
+}
+
+// Unwrap returns the last attempt's error
+func (e *RetriesExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports a RetriesExhaustedError as ErrScrapingFailed
+func (e *RetriesExhaustedError) Is(target error) bool {
+	return target == ErrScrapingFailed
+}
+
+// Scrape failure classes, recorded with each failed scrape so operators can
+// tell a store outage from a scraper bug
+const (
+	// FailureUpstreamOutage is a store that answered with a server error,
+	// rate limiting or a bot challenge, or could not be reached at all
+	FailureUpstreamOutage = "upstream_outage"
+	// FailureExtraction is a page or feed that loaded but could not be read,
+	// usually because the store changed its layout
+	FailureExtraction = "extraction_failure"
+	// FailureTimeout is a request that did not finish in time
+	FailureTimeout = "timeout"
+	// FailureUnknown is any other failure
+	FailureUnknown = "unknown"
+)
+
+// UpstreamError is returned when a store responds in a way that means the
+// store itself is having issues rather than the scraper
+type UpstreamError struct {
+	StatusCode int
+	// Challenge is set when the response was a bot challenge or block page
+	Challenge bool
+}
+
+func (e *UpstreamError) Error() string {
+	if e.Challenge {
+		return fmt.Sprintf("store returned a challenge page (status %d)", e.StatusCode)
+	}
+	return fmt.Sprintf("store returned status %d", e.StatusCode)
+}
+
+// challengeSignatures appear in Cloudflare challenge and block pages
+var challengeSignatures = []string{
+	"Just a moment...",
+	"Attention Required! | Cloudflare",
+	"cf-browser-verification",
+	"challenge-platform",
+	"cf-chl-",
+}
+
+// IsChallengePage reports whether a response body or page title is a bot
+// challenge or block page rather than store content
+func IsChallengePage(content string) bool {
+	for _, signature := range challengeSignatures {
+		if strings.Contains(content, signature) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsUpstreamStatus reports whether an HTTP status from a store means the
+// store is failing: a server error, rate limiting or a blocked request
+func IsUpstreamStatus(code int) bool {
+	return code >= 500 || code == http.StatusTooManyRequests || code == http.StatusForbidden
+}
+
+// ClassifyScrapeFailure returns the Failure* class of a scrape error, or ""
+// for nil
+func ClassifyScrapeFailure(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var upstream *UpstreamError
+	if errors.As(err, &upstream) {
+		return FailureUpstreamOutage
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return FailureTimeout
+	}
+	// Connection and DNS failures; Chrome reports them as net::ERR_* codes
+	if errors.As(err, &netErr) || strings.Contains(err.Error(), "net::ERR_") {
+		return FailureUpstreamOutage
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.Is(err, ErrNoGamesFound) || errors.Is(err, ErrInvalidGameData) || errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return FailureExtraction
+	}
+	return FailureUnknown
+}
@@ -2,16 +2,105 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
 // Game represents a free game from Epic Games Store
 type Game struct {
+	// ID is the database row, zero for games not loaded from the database
+	ID int64 `json:"id,omitempty"`
+
 	Title    string `json:"title"`
 	ImageURL string `json:"image_url"`
 	Status   string `json:"status"`
 	FreeFrom string `json:"free_from"`
 	FreeTo   string `json:"free_to"`
+	StoreURL string `json:"store_url,omitempty"`
+	Source   string `json:"source,omitempty"`
+
+	// Period is the raw promotion text from the store page, such as
+	// "Free Jul 24 - Jul 31". Only the Chrome scraper sets it.
+	Period string `json:"period,omitempty"`
+
+	// Images lists every image of the offer, starting with ImageURL. Bundles
+	// can have several; most games have none beyond ImageURL.
+	Images []string `json:"images,omitempty"`
+
+	// Regions lists the Epic locales the game was found in. Empty means the
+	// scraper did not distinguish regions.
+	Regions []string `json:"regions,omitempty"`
+
+	// FreeFromTime and FreeToTime are the promotion window and the source of
+	// truth for date logic; FreeFrom and FreeTo are kept for display and as
+	// identity keys. FreeToTime is the moment the game stops being free.
+	// Populated by ParseDates or directly by scrapers that know exact times.
+	FreeFromTime time.Time `json:"-"`
+	FreeToTime   time.Time `json:"-"`
+}
+
+// MaxGameImages caps how many images are kept per game. Discord shows at most
+// four images in one embed gallery.
+const MaxGameImages = 4
+
+// GalleryImages returns the images to show after the main image
+func (g *Game) GalleryImages() []string {
+	if len(g.Images) < 2 {
+		return nil
+	}
+	return g.Images[1:]
+}
+
+// Game sources. An empty source means the Epic Games Store, which was the
+// only source before multi-store support.
+const (
+	SourceEpic = "epic"
+	SourceGOG  = "gog"
+)
+
+// SourceName returns the human-readable store name for the game's source
+func (g *Game) SourceName() string {
+	switch g.Source {
+	case SourceGOG:
+		return "GOG"
+	default:
+		return "Epic Games Store"
+	}
+}
+
+// StoreSource returns the game's source, SourceEpic for games without one
+func (g *Game) StoreSource() string {
+	if g.Source == "" {
+		return SourceEpic
+	}
+	return g.Source
+}
+
+// SourceKey identifies a game by its store and title. Two stores giving away
+// a game of the same title are different games.
+func (g *Game) SourceKey() string {
+	return g.StoreSource() + "|" + g.Title
+}
+
+// EpicStoreBaseURL is prefixed to relative store links
+const EpicStoreBaseURL = "https://store.epicgames.com"
+
+// NormalizeStoreURL turns a scraped link (absolute or relative like
+// "/en-US/p/slug") into an absolute Epic Games Store URL
+func NormalizeStoreURL(href string) string {
+	href = strings.TrimSpace(href)
+	switch {
+	case href == "":
+		return ""
+	case strings.HasPrefix(href, "https://") || strings.HasPrefix(href, "http://"):
+		return href
+	case strings.HasPrefix(href, "//"):
+		return "https:" + href
+	case strings.HasPrefix(href, "/"):
+		return EpicStoreBaseURL + href
+	default:
+		return EpicStoreBaseURL + "/" + href
+	}
 }
 
 // GameStatus constants for game availability
@@ -22,20 +111,101 @@ const (
 
 // IsActive checks if a "Free Now" game is still active
 func (g *Game) IsActive() bool {
-	if g.Status != StatusFreeNow || g.FreeTo == "" {
+	return g.IsActiveAt(time.Now())
+}
+
+// IsActiveAt checks if a "Free Now" game is still free at now. Games without
+// a known end (FreeToTime unset) are never considered active.
+func (g *Game) IsActiveAt(now time.Time) bool {
+	if g.Status != StatusFreeNow || g.FreeToTime.IsZero() {
 		return false
 	}
+	return now.Before(g.FreeToTime)
+}
+
+// ParseDates fills FreeFromTime and FreeToTime from the display strings
+// (e.g. "Jul 17"), inferring the year relative to now. Fields that are already
+// set are left alone; missing or unparseable dates stay zero.
+func (g *Game) ParseDates(now time.Time) {
+	from, to := parseDateRange(g.FreeFrom, g.FreeTo, now)
+	if g.FreeFromTime.IsZero() {
+		g.FreeFromTime = from
+	}
+	if g.FreeToTime.IsZero() {
+		g.FreeToTime = to
+	}
+}
 
-	currentYear := time.Now().Year()
-	// Parse FreeTo date (e.g., "Jul 17" -> "Jul 17 2025")
-	freeToDate, err := time.Parse("Jan 02 2006", g.FreeTo+" "+fmt.Sprintf("%d", currentYear))
-	if err != nil {
-		return false
+// parseDateRange parses a "Jan 2" style range. Each date gets the year that
+// puts it closest to now, and an end date before the start date is rolled
+// into the next year so a Dec 28 - Jan 4 promotion stays contiguous. The end
+// is returned as the start of the following day (end-of-day expiration).
+func parseDateRange(fromText, toText string, now time.Time) (time.Time, time.Time) {
+	from := parseDisplayDate(fromText, now)
+	to := parseDisplayDate(toText, now)
+
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		to = to.AddDate(1, 0, 0)
+	}
+	if !to.IsZero() {
+		to = to.AddDate(0, 0, 1)
+	}
+	return from, to
+}
+
+// parseDisplayDate parses "Jul 17" into the occurrence closest to now
+func parseDisplayDate(text string, now time.Time) time.Time {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return time.Time{}
+	}
+
+	var best time.Time
+	for _, year := range []int{now.Year() - 1, now.Year(), now.Year() + 1} {
+		candidate, err := time.ParseInLocation("Jan 2 2006", fmt.Sprintf("%s %d", text, year), now.Location())
+		if err != nil {
+			return time.Time{}
+		}
+		if best.IsZero() || absDuration(candidate.Sub(now)) < absDuration(best.Sub(now)) {
+			best = candidate
+		}
 	}
-	
-	// Add one day to account for end-of-day expiration
-	freeToDate = freeToDate.Add(24 * time.Hour)
-	return time.Now().Before(freeToDate)
+	return best
+}
+
+// absDuration returns the absolute value of d
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// Validate checks that a game has the minimum data required to be stored
+func (g *Game) Validate() error {
+	if strings.TrimSpace(g.Title) == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidGameData)
+	}
+	if g.Status != StatusFreeNow && g.Status != StatusComingSoon {
+		return fmt.Errorf("%w: unknown status %q for %s", ErrInvalidGameData, g.Status, g.Title)
+	}
+	return nil
+}
+
+// GameSnapshot is a point-in-time dump of the games catalog
+type GameSnapshot struct {
+	CreatedAt time.Time      `json:"created_at"`
+	Games     []SnapshotGame `json:"games"`
+}
+
+// SnapshotGame is a game in a snapshot along with whether it was announced
+// and the promotion window, which Game keeps out of JSON. Snapshots written
+// before they were recorded have neither.
+type SnapshotGame struct {
+	Game
+	Notified   *bool      `json:"notified,omitempty"`
+	FreeFromAt *time.Time `json:"free_from_at,omitempty"`
+	FreeToAt   *time.Time `json:"free_to_at,omitempty"`
 }
 
 // GameCollection represents a collection of games categorized by status
@@ -63,6 +233,11 @@ func NewGameCollection(games []Game) *GameCollection {
 	return collection
 }
 
+// All returns the Free Now games followed by the Coming Soon games
+func (gc *GameCollection) All() []Game {
+	return append(append([]Game{}, gc.FreeNow...), gc.ComingSoon...)
+}
+
 // HasActiveFreeGames checks if there are any active "Free Now" games
 func (gc *GameCollection) HasActiveFreeGames() bool {
 	for _, game := range gc.FreeNow {
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDates(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		now      time.Time
+		wantFrom time.Time
+		wantTo   time.Time
+	}{
+		{
+			name: "same month",
+			from: "Jul 17", to: "Jul 24",
+			now:      at(2025, time.July, 20, 12, 0),
+			wantFrom: at(2025, time.July, 17, 0, 0),
+			wantTo:   at(2025, time.July, 25, 0, 0),
+		},
+		{
+			name: "december to january seen in december",
+			from: "Dec 28", to: "Jan 4",
+			now:      at(2025, time.December, 29, 12, 0),
+			wantFrom: at(2025, time.December, 28, 0, 0),
+			wantTo:   at(2026, time.January, 5, 0, 0),
+		},
+		{
+			name: "december to january seen in january",
+			from: "Dec 28", to: "Jan 4",
+			now:      at(2026, time.January, 3, 12, 0),
+			wantFrom: at(2025, time.December, 28, 0, 0),
+			wantTo:   at(2026, time.January, 5, 0, 0),
+		},
+		{
+			name: "ending on dec 31",
+			from: "Dec 24", to: "Dec 31",
+			now:      at(2025, time.December, 30, 12, 0),
+			wantFrom: at(2025, time.December, 24, 0, 0),
+			wantTo:   at(2026, time.January, 1, 0, 0),
+		},
+		{
+			name:   "end only",
+			to:     "Jan 2",
+			now:    at(2025, time.December, 27, 12, 0),
+			wantTo: at(2026, time.January, 3, 0, 0),
+		},
+		{name: "empty dates", now: at(2025, time.July, 20, 12, 0)},
+		{name: "unparseable dates", from: "soon", to: "TBA", now: at(2025, time.July, 20, 12, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := Game{FreeFrom: tt.from, FreeTo: tt.to}
+			game.ParseDates(tt.now)
+			if !game.FreeFromTime.Equal(tt.wantFrom) {
+				t.Errorf("FreeFromTime = %v, want %v", game.FreeFromTime, tt.wantFrom)
+			}
+			if !game.FreeToTime.Equal(tt.wantTo) {
+				t.Errorf("FreeToTime = %v, want %v", game.FreeToTime, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestParseDatesKeepsExactTimes(t *testing.T) {
+	exact := at(2025, time.July, 24, 15, 0)
+	game := Game{FreeFrom: "Jul 17", FreeTo: "Jul 24", FreeToTime: exact}
+	game.ParseDates(at(2025, time.July, 20, 12, 0))
+	if !game.FreeToTime.Equal(exact) {
+		t.Errorf("FreeToTime = %v, want the scraper's %v", game.FreeToTime, exact)
+	}
+}
+
+func TestIsActiveAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		from   string
+		to     string
+		parsed time.Time
+		now    time.Time
+		want   bool
+	}{
+		{name: "running", status: StatusFreeNow, from: "Jul 17", to: "Jul 24", parsed: at(2025, time.July, 20, 12, 0), now: at(2025, time.July, 20, 12, 0), want: true},
+		{name: "last day", status: StatusFreeNow, from: "Jul 17", to: "Jul 24", parsed: at(2025, time.July, 20, 12, 0), now: at(2025, time.July, 24, 23, 59), want: true},
+		{name: "ended", status: StatusFreeNow, from: "Jul 17", to: "Jul 24", parsed: at(2025, time.July, 20, 12, 0), now: at(2025, time.July, 25, 0, 0)},
+		// A Dec 28 - Jan 4 giveaway stays active across New Year
+		{name: "across new year in december", status: StatusFreeNow, from: "Dec 28", to: "Jan 4", parsed: at(2025, time.December, 28, 12, 0), now: at(2025, time.December, 31, 12, 0), want: true},
+		{name: "across new year in january", status: StatusFreeNow, from: "Dec 28", to: "Jan 4", parsed: at(2025, time.December, 28, 12, 0), now: at(2026, time.January, 3, 12, 0), want: true},
+		{name: "across new year ended", status: StatusFreeNow, from: "Dec 28", to: "Jan 4", parsed: at(2025, time.December, 28, 12, 0), now: at(2026, time.January, 5, 0, 0)},
+		{name: "empty end", status: StatusFreeNow, from: "Jul 17", parsed: at(2025, time.July, 20, 12, 0), now: at(2025, time.July, 20, 12, 0)},
+		{name: "no dates", status: StatusFreeNow, parsed: at(2025, time.July, 20, 12, 0), now: at(2025, time.July, 20, 12, 0)},
+		{name: "coming soon", status: StatusComingSoon, from: "Jul 24", to: "Jul 31", parsed: at(2025, time.July, 20, 12, 0), now: at(2025, time.July, 20, 12, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := Game{Status: tt.status, FreeFrom: tt.from, FreeTo: tt.to}
+			game.ParseDates(tt.parsed)
+			if got := game.IsActiveAt(tt.now); got != tt.want {
+				t.Errorf("IsActiveAt(%v) = %v, want %v (FreeToTime %v)", tt.now, got, tt.want, game.FreeToTime)
+			}
+		})
+	}
+}
+
+func TestIsActiveAtExactTimes(t *testing.T) {
+	// The end time is the source of truth, whatever the display dates say
+	game := Game{Status: StatusFreeNow, FreeFrom: "Dec 26", FreeTo: "Jan 2", FreeToTime: at(2026, time.January, 2, 16, 0)}
+
+	tests := []struct {
+		now  time.Time
+		want bool
+	}{
+		{at(2025, time.December, 31, 23, 59), true},
+		{at(2026, time.January, 2, 15, 59), true},
+		{at(2026, time.January, 2, 16, 0), false},
+		{at(2027, time.January, 1, 12, 0), false},
+	}
+	for _, tt := range tests {
+		if got := game.IsActiveAt(tt.now); got != tt.want {
+			t.Errorf("IsActiveAt(%v) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+
+	game.FreeToTime = time.Time{}
+	if game.IsActiveAt(at(2025, time.December, 31, 12, 0)) {
+		t.Error("IsActiveAt = true for a game without an end time")
+	}
+}
@@ -0,0 +1,176 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// PromotionPeriod is a promotion window parsed from the store's display text,
+// e.g. "Free Jul 24 - Jul 31" or "Free Now - Jul 31 at 11:00 AM"
+type PromotionPeriod struct {
+	// FreeNow is set when the text says the game is free right now
+	FreeNow bool
+
+	// From and To are the dates in "Jan 2" form, matching Game.FreeFrom and
+	// Game.FreeTo. From is empty for Free Now periods.
+	From string
+	To   string
+
+	// Start and End are the parsed window. Without an explicit time Start is
+	// midnight and End the start of the following day. Either may be zero
+	// when the text does not give that date.
+	Start time.Time
+	End   time.Time
+}
+
+var (
+	// periodSeparator splits the two ends of a period on a hyphen, en dash,
+	// em dash or "until"
+	periodSeparator = regexp.MustCompile(`\s*[-–—]\s*|\s+until\s+`)
+
+	// periodTime matches a trailing "at 11:00 AM" style time
+	periodTime = regexp.MustCompile(`(?i)\s*(?:at|@)\s+(\d{1,2}(?::\d{2})?\s*(?:[AP]\.?M\.?)?)\s*$`)
+)
+
+// Date and time layouts accepted in period text
+var (
+	periodDateLayouts = []string{"Jan 2", "January 2"}
+	periodYearLayouts = []string{"Jan 2 2006", "January 2 2006"}
+	periodTimeLayouts = []string{"3:04 PM", "3:04PM", "3 PM", "3PM", "15:04"}
+)
+
+// ParsePeriod parses the period text shown on Epic's free games page. It
+// accepts hyphens or dashes between the dates, optional "at <time>" suffixes,
+// optional years and single-date forms ("Free Now", "Free Jul 24"). Dates
+// without a year get the year closest to now, and an end before the start is
+// moved to the following year.
+func ParsePeriod(text string, now time.Time) (PromotionPeriod, error) {
+	var period PromotionPeriod
+
+	trimmed := strings.Join(strings.Fields(text), " ")
+	if len(trimmed) >= 4 && strings.EqualFold(trimmed[:4], "free") {
+		trimmed = strings.TrimSpace(trimmed[4:])
+	}
+	if trimmed == "" {
+		return period, fmt.Errorf("empty period %q", text)
+	}
+
+	parts := periodSeparator.Split(trimmed, 2)
+	startText := strings.TrimSpace(parts[0])
+	endText := ""
+	if len(parts) == 2 {
+		endText = strings.TrimSpace(parts[1])
+	}
+
+	if strings.EqualFold(startText, "now") {
+		period.FreeNow = true
+	} else if startText != "" {
+		start, _, err := parsePeriodPoint(startText, now, false)
+		if err != nil {
+			return period, fmt.Errorf("invalid period start in %q: %w", text, err)
+		}
+		period.Start = start
+		period.From = start.Format("Jan 2")
+	}
+
+	if endText != "" {
+		end, yearGiven, err := parsePeriodPoint(endText, now, true)
+		if err != nil {
+			return period, fmt.Errorf("invalid period end in %q: %w", text, err)
+		}
+		if !yearGiven && !period.Start.IsZero() && end.Before(period.Start) {
+			end = end.AddDate(1, 0, 0)
+		}
+		period.End = end
+		period.To = dayOf(end, endText).Format("Jan 2")
+	}
+
+	if !period.FreeNow && period.Start.IsZero() && period.End.IsZero() {
+		return period, fmt.Errorf("no dates in period %q", text)
+	}
+	return period, nil
+}
+
+// parsePeriodPoint parses one end of a period such as "Jul 31", "Jul 31, 2025"
+// or "Jul 31 at 11:00 AM". An end without a time is returned as the start of
+// the following day. yearGiven reports whether the text included a year.
+func parsePeriodPoint(text string, now time.Time, isEnd bool) (t time.Time, yearGiven bool, err error) {
+	var clock time.Time
+	hasTime := false
+	if match := periodTime.FindStringSubmatch(text); match != nil {
+		clock, err = parsePeriodClock(match[1])
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		hasTime = true
+		text = strings.TrimSpace(text[:len(text)-len(match[0])])
+	}
+
+	text = strings.TrimSpace(strings.ReplaceAll(text, ",", ""))
+	date, yearGiven, err := parsePeriodDate(text, now)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	if hasTime {
+		return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location()), yearGiven, nil
+	}
+	if isEnd {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date, yearGiven, nil
+}
+
+// parsePeriodDate parses "Jul 31" or "Jul 31 2025" at midnight in now's
+// location, choosing the year closest to now when none is given
+func parsePeriodDate(text string, now time.Time) (time.Time, bool, error) {
+	for _, layout := range periodYearLayouts {
+		if date, err := time.ParseInLocation(layout, text, now.Location()); err == nil {
+			return date, true, nil
+		}
+	}
+
+	for _, layout := range periodDateLayouts {
+		if _, err := time.Parse(layout, text); err != nil {
+			continue
+		}
+
+		var best time.Time
+		for _, year := range []int{now.Year() - 1, now.Year(), now.Year() + 1} {
+			candidate, err := time.ParseInLocation(layout+" 2006", fmt.Sprintf("%s %d", text, year), now.Location())
+			if err != nil {
+				continue
+			}
+			if best.IsZero() || absDuration(candidate.Sub(now)) < absDuration(best.Sub(now)) {
+				best = candidate
+			}
+		}
+		if !best.IsZero() {
+			return best, false, nil
+		}
+	}
+
+	return time.Time{}, false, fmt.Errorf("unrecognized date %q", text)
+}
+
+// parsePeriodClock parses "11:00 AM", "11 AM" or "23:00"
+func parsePeriodClock(text string) (time.Time, error) {
+	text = strings.ToUpper(strings.ReplaceAll(text, ".", ""))
+	for _, layout := range periodTimeLayouts {
+		if clock, err := time.Parse(layout, text); err == nil {
+			return clock, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q", text)
+}
+
+// dayOf returns the calendar day an end point refers to. Ends without a time
+// were moved to the following midnight, so step back to the displayed day.
+func dayOf(end time.Time, text string) time.Time {
+	if periodTime.MatchString(text) {
+		return end
+	}
+	return end.AddDate(0, 0, -1)
+}
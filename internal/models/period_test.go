@@ -0,0 +1,253 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// at returns a time in UTC, the location periods are parsed in by tests
+func at(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParsePeriodClock(t *testing.T) {
+	tests := []struct {
+		text      string
+		hour, min int
+		wantErr   bool
+	}{
+		{text: "11:00 AM", hour: 11},
+		{text: "11:30 PM", hour: 23, min: 30},
+		{text: "12:00 AM", hour: 0},
+		{text: "12:00 PM", hour: 12},
+		{text: "4PM", hour: 16},
+		{text: "4 pm", hour: 16},
+		{text: "4:15pm", hour: 16, min: 15},
+		{text: "11 a.m.", hour: 11},
+		{text: "23:00", hour: 23},
+		{text: "0:05", hour: 0, min: 5},
+		{text: "", wantErr: true},
+		{text: "noon", wantErr: true},
+		{text: "25:00", wantErr: true},
+		{text: "13 PM", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			clock, err := parsePeriodClock(tt.text)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsePeriodClock(%q) = %v, want an error", tt.text, clock)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePeriodClock(%q): %v", tt.text, err)
+			}
+			if clock.Hour() != tt.hour || clock.Minute() != tt.min {
+				t.Errorf("parsePeriodClock(%q) = %02d:%02d, want %02d:%02d", tt.text, clock.Hour(), clock.Minute(), tt.hour, tt.min)
+			}
+		})
+	}
+}
+
+func TestParsePeriodDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		now       time.Time
+		want      time.Time
+		yearGiven bool
+		wantErr   bool
+	}{
+		{name: "same year", text: "Jul 31", now: at(2025, time.July, 20, 12, 0), want: at(2025, time.July, 31, 0, 0)},
+		{name: "full month name", text: "July 31", now: at(2025, time.July, 20, 12, 0), want: at(2025, time.July, 31, 0, 0)},
+		{name: "explicit year", text: "Jul 31 2024", now: at(2025, time.July, 20, 12, 0), want: at(2024, time.July, 31, 0, 0), yearGiven: true},
+		{name: "full month with year", text: "January 2 2026", now: at(2025, time.December, 28, 12, 0), want: at(2026, time.January, 2, 0, 0), yearGiven: true},
+		// December → January: a January date seen in late December is next year
+		{name: "january seen in december", text: "Jan 4", now: at(2025, time.December, 28, 12, 0), want: at(2026, time.January, 4, 0, 0)},
+		// January → December: a December date seen in early January is last year
+		{name: "december seen in january", text: "Dec 28", now: at(2026, time.January, 3, 12, 0), want: at(2025, time.December, 28, 0, 0)},
+		{name: "new year's eve", text: "Dec 31", now: at(2025, time.December, 31, 23, 0), want: at(2025, time.December, 31, 0, 0)},
+		{name: "new year's day", text: "Jan 1", now: at(2025, time.December, 31, 23, 0), want: at(2026, time.January, 1, 0, 0)},
+		{name: "leap day", text: "Feb 29", now: at(2024, time.February, 20, 12, 0), want: at(2024, time.February, 29, 0, 0)},
+		{name: "empty", text: "", now: at(2025, time.July, 20, 12, 0), wantErr: true},
+		{name: "not a date", text: "soon", now: at(2025, time.July, 20, 12, 0), wantErr: true},
+		{name: "day out of range", text: "Jul 32", now: at(2025, time.July, 20, 12, 0), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, yearGiven, err := parsePeriodDate(tt.text, tt.now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsePeriodDate(%q) = %v, want an error", tt.text, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePeriodDate(%q): %v", tt.text, err)
+			}
+			if !got.Equal(tt.want) || yearGiven != tt.yearGiven {
+				t.Errorf("parsePeriodDate(%q) = %v, %v, want %v, %v", tt.text, got, yearGiven, tt.want, tt.yearGiven)
+			}
+		})
+	}
+}
+
+func TestParsePeriodPoint(t *testing.T) {
+	now := at(2025, time.July, 20, 12, 0)
+	tests := []struct {
+		name      string
+		text      string
+		now       time.Time
+		isEnd     bool
+		want      time.Time
+		yearGiven bool
+		wantErr   bool
+	}{
+		{name: "start date", text: "Jul 24", now: now, want: at(2025, time.July, 24, 0, 0)},
+		{name: "end date is exclusive", text: "Jul 31", now: now, isEnd: true, want: at(2025, time.August, 1, 0, 0)},
+		{name: "end with time", text: "Jul 31 at 11:00 AM", now: now, isEnd: true, want: at(2025, time.July, 31, 11, 0)},
+		{name: "start with time", text: "Jul 24 at 4:00 PM", now: now, want: at(2025, time.July, 24, 16, 0)},
+		{name: "at sign", text: "Jul 31 @ 11 AM", now: now, isEnd: true, want: at(2025, time.July, 31, 11, 0)},
+		{name: "comma and year", text: "Jul 31, 2025", now: now, isEnd: true, want: at(2025, time.August, 1, 0, 0), yearGiven: true},
+		{name: "year and time", text: "Jul 31, 2025 at 11:00 AM", now: now, isEnd: true, want: at(2025, time.July, 31, 11, 0), yearGiven: true},
+		// December → January rollovers
+		{name: "dec 31 end rolls into january", text: "Dec 31", now: at(2025, time.December, 20, 12, 0), isEnd: true, want: at(2026, time.January, 1, 0, 0)},
+		{name: "january end seen in december", text: "Jan 2 at 11:00 AM", now: at(2025, time.December, 28, 12, 0), isEnd: true, want: at(2026, time.January, 2, 11, 0)},
+		{name: "bad time", text: "Jul 31 at 25:00", now: now, isEnd: true, wantErr: true},
+		{name: "bad date", text: "someday at 11:00 AM", now: now, isEnd: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, yearGiven, err := parsePeriodPoint(tt.text, tt.now, tt.isEnd)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsePeriodPoint(%q) = %v, want an error", tt.text, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePeriodPoint(%q): %v", tt.text, err)
+			}
+			if !got.Equal(tt.want) || yearGiven != tt.yearGiven {
+				t.Errorf("parsePeriodPoint(%q) = %v, %v, want %v, %v", tt.text, got, yearGiven, tt.want, tt.yearGiven)
+			}
+		})
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	now := at(2025, time.July, 20, 12, 0)
+	tests := []struct {
+		name    string
+		text    string
+		now     time.Time
+		want    PromotionPeriod
+		wantErr bool
+	}{
+		// Formats observed on the free games page
+		{
+			name: "coming soon range",
+			text: "Free Jul 24 - Jul 31",
+			now:  now,
+			want: PromotionPeriod{From: "Jul 24", To: "Jul 31", Start: at(2025, time.July, 24, 0, 0), End: at(2025, time.August, 1, 0, 0)},
+		},
+		{
+			name: "free now with end time",
+			text: "Free Now - Jul 31 at 11:00 AM",
+			now:  now,
+			want: PromotionPeriod{FreeNow: true, To: "Jul 31", End: at(2025, time.July, 31, 11, 0)},
+		},
+		{
+			name: "single start date",
+			text: "Free Jul 24",
+			now:  now,
+			want: PromotionPeriod{From: "Jul 24", Start: at(2025, time.July, 24, 0, 0)},
+		},
+		// Separators and spacing
+		{
+			name: "en dash",
+			text: "Free Jul 24 – Jul 31",
+			now:  now,
+			want: PromotionPeriod{From: "Jul 24", To: "Jul 31", Start: at(2025, time.July, 24, 0, 0), End: at(2025, time.August, 1, 0, 0)},
+		},
+		{
+			name: "em dash without spaces",
+			text: "Free Jul 24—Jul 31",
+			now:  now,
+			want: PromotionPeriod{From: "Jul 24", To: "Jul 31", Start: at(2025, time.July, 24, 0, 0), End: at(2025, time.August, 1, 0, 0)},
+		},
+		{
+			name: "until",
+			text: "Free now until Jul 31 at 11:00 AM",
+			now:  now,
+			want: PromotionPeriod{FreeNow: true, To: "Jul 31", End: at(2025, time.July, 31, 11, 0)},
+		},
+		{
+			name: "extra whitespace",
+			text: "  Free   Jul 24  -  Jul 31 ",
+			now:  now,
+			want: PromotionPeriod{From: "Jul 24", To: "Jul 31", Start: at(2025, time.July, 24, 0, 0), End: at(2025, time.August, 1, 0, 0)},
+		},
+		{
+			name: "times on both ends",
+			text: "Free Jul 24 at 4:00 PM - Jul 31 at 4:00 PM",
+			now:  now,
+			want: PromotionPeriod{From: "Jul 24", To: "Jul 31", Start: at(2025, time.July, 24, 16, 0), End: at(2025, time.July, 31, 16, 0)},
+		},
+		// December → January rollovers
+		{
+			name: "range across new year seen in december",
+			text: "Free Dec 28 - Jan 4",
+			now:  at(2025, time.December, 20, 12, 0),
+			want: PromotionPeriod{From: "Dec 28", To: "Jan 4", Start: at(2025, time.December, 28, 0, 0), End: at(2026, time.January, 5, 0, 0)},
+		},
+		{
+			name: "range across new year seen in january",
+			text: "Free Dec 28 - Jan 4",
+			now:  at(2026, time.January, 2, 12, 0),
+			want: PromotionPeriod{From: "Dec 28", To: "Jan 4", Start: at(2025, time.December, 28, 0, 0), End: at(2026, time.January, 5, 0, 0)},
+		},
+		{
+			name: "free now ending in january",
+			text: "Free Now - Jan 2 at 11:00 AM",
+			now:  at(2025, time.December, 26, 12, 0),
+			want: PromotionPeriod{FreeNow: true, To: "Jan 2", End: at(2026, time.January, 2, 11, 0)},
+		},
+		{
+			name: "range ending on dec 31",
+			text: "Free Dec 24 - Dec 31",
+			now:  at(2025, time.December, 20, 12, 0),
+			want: PromotionPeriod{From: "Dec 24", To: "Dec 31", Start: at(2025, time.December, 24, 0, 0), End: at(2026, time.January, 1, 0, 0)},
+		},
+		{
+			name: "explicit years across new year",
+			text: "Free Dec 28, 2025 - Jan 4, 2026",
+			now:  now,
+			want: PromotionPeriod{From: "Dec 28", To: "Jan 4", Start: at(2025, time.December, 28, 0, 0), End: at(2026, time.January, 5, 0, 0)},
+		},
+		// Missing or unreadable dates
+		{name: "empty", text: "", now: now, wantErr: true},
+		{name: "only free", text: "Free", now: now, wantErr: true},
+		{name: "unreadable start", text: "Free soon - Jul 31", now: now, wantErr: true},
+		{name: "unreadable end", text: "Free Jul 24 - later", now: now, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePeriod(tt.text, tt.now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParsePeriod(%q) = %+v, want an error", tt.text, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePeriod(%q): %v", tt.text, err)
+			}
+			if got.FreeNow != tt.want.FreeNow || got.From != tt.want.From || got.To != tt.want.To ||
+				!got.Start.Equal(tt.want.Start) || !got.End.Equal(tt.want.End) {
+				t.Errorf("ParsePeriod(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
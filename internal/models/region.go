@@ -0,0 +1,60 @@
+package models
+
+import "strings"
+
+// DefaultLocale is the Epic store locale scraped when EPIC_LOCALE is unset
+const DefaultLocale = "en-US"
+
+// Locale is an Epic store locale together with the country whose promotions
+// it shows
+type Locale struct {
+	Code    string
+	Country string
+	Name    string
+}
+
+// SupportedLocales lists the locales that can be scraped and chosen with
+// /region. Discord allows at most 25 choices, so keep the list under that.
+var SupportedLocales = []Locale{
+	{Code: "en-US", Country: "US", Name: "United States"},
+	{Code: "en-GB", Country: "GB", Name: "United Kingdom"},
+	{Code: "en-CA", Country: "CA", Name: "Canada"},
+	{Code: "en-AU", Country: "AU", Name: "Australia"},
+	{Code: "en-IN", Country: "IN", Name: "India"},
+	{Code: "de-DE", Country: "DE", Name: "Germany"},
+	{Code: "fr-FR", Country: "FR", Name: "France"},
+	{Code: "es-ES", Country: "ES", Name: "Spain"},
+	{Code: "es-MX", Country: "MX", Name: "Mexico"},
+	{Code: "it-IT", Country: "IT", Name: "Italy"},
+	{Code: "pl-PL", Country: "PL", Name: "Poland"},
+	{Code: "pt-BR", Country: "BR", Name: "Brazil"},
+	{Code: "tr-TR", Country: "TR", Name: "Turkey"},
+	{Code: "ja-JP", Country: "JP", Name: "Japan"},
+	{Code: "ko-KR", Country: "KR", Name: "South Korea"},
+	{Code: "zh-CN", Country: "CN", Name: "China"},
+}
+
+// LookupLocale finds a supported locale by code, ignoring case
+func LookupLocale(code string) (Locale, bool) {
+	code = strings.TrimSpace(code)
+	for _, locale := range SupportedLocales {
+		if strings.EqualFold(locale.Code, code) {
+			return locale, true
+		}
+	}
+	return Locale{}, false
+}
+
+// AvailableIn reports whether the game was scraped for locale. Games without
+// region tags (e.g. from the Chrome or GOG scrapers) are available everywhere.
+func (g *Game) AvailableIn(locale string) bool {
+	if len(g.Regions) == 0 || locale == "" {
+		return true
+	}
+	for _, region := range g.Regions {
+		if strings.EqualFold(region, locale) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// StoreURLMatchThreshold is the lowest TitleSimilarity at which a catalog
+// search result is accepted as the store page of a game
+const StoreURLMatchThreshold = 0.9
+
+// romanNumerals maps roman numeral words to digits so "Civilization VI" and
+// "Civilization 6" normalize alike
+var romanNumerals = map[string]string{
+	"ii": "2", "iii": "3", "iv": "4", "v": "5", "vi": "6", "vii": "7", "viii": "8", "ix": "9", "x": "10",
+}
+
+// editionWords end a title without changing which game it is
+var editionWords = map[string]bool{
+	"edition": true, "goty": true, "deluxe": true, "definitive": true, "complete": true,
+	"standard": true, "ultimate": true, "remastered": true, "enhanced": true,
+}
+
+// NormalizeTitle lowercases a title and reduces it to words: trademark signs
+// and punctuation are dropped, "&" becomes "and" and roman numerals become
+// digits
+func NormalizeTitle(title string) string {
+	title = strings.NewReplacer("™", "", "®", "", "©", "", "&", " and ", "'", "", "’", "").Replace(strings.ToLower(title))
+	words := strings.FieldsFunc(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		if digits, ok := romanNumerals[word]; ok {
+			words[i] = digits
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// baseTitle strips edition words such as "Game of the Year Edition" from the
+// end of a normalized title
+func baseTitle(normalized string) string {
+	words := strings.Fields(normalized)
+	for len(words) > 1 && editionWords[words[len(words)-1]] {
+		words = words[:len(words)-1]
+		// "game of the year" only counts as an edition before "edition"
+		if n := len(words); n > 4 && strings.Join(words[n-4:], " ") == "game of the year" {
+			words = words[:n-4]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// TitleSimilarity scores how likely two titles name the same game, from 0 to
+// 1. Equal normalized titles score 1 and titles differing only in their
+// edition 0.95; others score by shared words, scaled below the match
+// threshold so only near-identical titles are accepted.
+func TitleSimilarity(a, b string) float64 {
+	na, nb := NormalizeTitle(a), NormalizeTitle(b)
+	if na == "" || nb == "" {
+		return 0
+	}
+	if na == nb {
+		return 1
+	}
+	if baseTitle(na) == baseTitle(nb) {
+		return 0.95
+	}
+
+	wordsA, wordsB := strings.Fields(na), strings.Fields(nb)
+	inA := make(map[string]bool, len(wordsA))
+	for _, word := range wordsA {
+		inA[word] = true
+	}
+	shared := 0
+	for _, word := range wordsB {
+		if inA[word] {
+			shared++
+			delete(inA, word)
+		}
+	}
+	return 0.85 * 2 * float64(shared) / float64(len(wordsA)+len(wordsB))
+}
+
+// StoreCandidate is a catalog search result for a title lookup
+type StoreCandidate struct {
+	Title    string
+	StoreURL string
+}
+
+// MatchStoreURL picks the store URL of the candidate best matching title. It
+// reports false when no candidate reaches StoreURLMatchThreshold or when
+// candidates with different URLs score equally well, leaving the choice to a
+// person.
+func MatchStoreURL(title string, candidates []StoreCandidate) (string, bool) {
+	var best string
+	bestScore, runnerUp := 0.0, 0.0
+	for _, candidate := range candidates {
+		if candidate.StoreURL == "" {
+			continue
+		}
+		score := TitleSimilarity(title, candidate.Title)
+		switch {
+		case candidate.StoreURL == best:
+			bestScore = max(bestScore, score)
+		case score > bestScore:
+			runnerUp = bestScore
+			best, bestScore = candidate.StoreURL, score
+		case score > runnerUp:
+			runnerUp = score
+		}
+	}
+	if bestScore < StoreURLMatchThreshold || runnerUp >= bestScore {
+		return "", false
+	}
+	return best, true
+}
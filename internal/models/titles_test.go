@@ -0,0 +1,149 @@
+package models
+
+import "testing"
+
+func TestNormalizeTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Control", "control"},
+		{"DOOM®", "doom"},
+		{"Assassin's Creed® Syndicate", "assassins creed syndicate"},
+		{"Tom Clancy’s The Division™", "tom clancys the division"},
+		{"Ori and the Will of the Wisps", "ori and the will of the wisps"},
+		{"Rock & Roll Racing", "rock and roll racing"},
+		{"Sid Meier's Civilization® VI", "sid meiers civilization 6"},
+		{"Final Fantasy XII", "final fantasy xii"},
+		{"Half-Life: Alyx", "half life alyx"},
+		{"  Celeste  ", "celeste"},
+		{"Pokémon-like: Temtem", "pokémon like temtem"},
+		{"™ ®", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			if got := NormalizeTitle(tt.title); got != tt.want {
+				t.Errorf("NormalizeTitle(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      string
+		want      float64
+		wantMatch bool
+		// partial titles share some words and score between 0 and the
+		// match threshold
+		partial bool
+	}{
+		{name: "identical", a: "Control", b: "Control", want: 1, wantMatch: true},
+		{name: "trademark signs", a: "DOOM® Eternal™", b: "DOOM Eternal", want: 1, wantMatch: true},
+		{name: "roman numeral", a: "Sid Meier's Civilization VI", b: "Sid Meier’s Civilization 6", want: 1, wantMatch: true},
+		{name: "ampersand", a: "Rock & Roll Racing", b: "Rock and Roll Racing", want: 1, wantMatch: true},
+		{name: "punctuation", a: "Half-Life: Alyx", b: "Half Life Alyx", want: 1, wantMatch: true},
+		{name: "edition", a: "Control", b: "Control Ultimate Edition", want: 0.95, wantMatch: true},
+		{name: "game of the year edition", a: "The Witcher 3: Wild Hunt", b: "The Witcher 3: Wild Hunt – Game of the Year Edition", want: 0.95, wantMatch: true},
+		{name: "stacked edition words", a: "Borderlands 2", b: "Borderlands 2 Remastered Deluxe Edition", want: 0.95, wantMatch: true},
+		{name: "different sequel", a: "Civilization V", b: "Civilization VI", partial: true},
+		{name: "numbered sequel", a: "Borderlands", b: "Borderlands 2", partial: true},
+		{name: "subtitle", a: "Assassin's Creed", b: "Assassin's Creed Syndicate", partial: true},
+		{name: "shared words only", a: "The Long Dark", b: "The Long Return", partial: true},
+		{name: "game of the year as the title", a: "Game of the Year", b: "Game of the Year Edition", want: 0.95, wantMatch: true},
+		{name: "unrelated", a: "Celeste", b: "Hades"},
+		{name: "empty", a: "", b: "Hades"},
+		{name: "only symbols", a: "™", b: "®"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TitleSimilarity(tt.a, tt.b)
+			if tt.partial && (got <= 0 || got >= StoreURLMatchThreshold) {
+				t.Errorf("TitleSimilarity(%q, %q) = %v, want a partial score", tt.a, tt.b, got)
+			}
+			if !tt.partial && got != tt.want {
+				t.Errorf("TitleSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if match := got >= StoreURLMatchThreshold; match != tt.wantMatch {
+				t.Errorf("TitleSimilarity(%q, %q) = %v, want match %v", tt.a, tt.b, got, tt.wantMatch)
+			}
+			if reverse := TitleSimilarity(tt.b, tt.a); reverse != got {
+				t.Errorf("TitleSimilarity is not symmetric: %v one way, %v the other", got, reverse)
+			}
+		})
+	}
+}
+
+func TestMatchStoreURL(t *testing.T) {
+	const (
+		civ5    = "https://store.epicgames.com/p/civilization-v"
+		civ6    = "https://store.epicgames.com/p/sid-meiers-civilization-vi"
+		civ6Alt = "https://store.epicgames.com/p/civilization-vi-platinum"
+	)
+	tests := []struct {
+		name       string
+		title      string
+		candidates []StoreCandidate
+		want       string
+		wantOK     bool
+	}{
+		{
+			name:  "best match among sequels",
+			title: "Sid Meier's Civilization® VI",
+			candidates: []StoreCandidate{
+				{Title: "Sid Meier's Civilization V", StoreURL: civ5},
+				{Title: "Sid Meier’s Civilization 6", StoreURL: civ6},
+			},
+			want: civ6, wantOK: true,
+		},
+		{
+			name:  "exact title beats an edition",
+			title: "Sid Meier's Civilization VI",
+			candidates: []StoreCandidate{
+				{Title: "Sid Meier's Civilization VI Platinum Edition", StoreURL: civ6Alt},
+				{Title: "Sid Meier's Civilization VI", StoreURL: civ6},
+			},
+			want: civ6, wantOK: true,
+		},
+		{
+			name:  "same page listed twice",
+			title: "Control",
+			candidates: []StoreCandidate{
+				{Title: "Control", StoreURL: "https://store.epicgames.com/p/control"},
+				{Title: "Control Ultimate Edition", StoreURL: "https://store.epicgames.com/p/control"},
+			},
+			want: "https://store.epicgames.com/p/control", wantOK: true,
+		},
+		{
+			name:  "editions on different pages are ambiguous",
+			title: "Control",
+			candidates: []StoreCandidate{
+				{Title: "Control Ultimate Edition", StoreURL: "https://store.epicgames.com/p/control-ultimate"},
+				{Title: "Control Deluxe Edition", StoreURL: "https://store.epicgames.com/p/control-deluxe"},
+			},
+		},
+		{
+			name:       "only a sequel",
+			title:      "Borderlands",
+			candidates: []StoreCandidate{{Title: "Borderlands 2", StoreURL: "https://store.epicgames.com/p/borderlands-2"}},
+		},
+		{
+			name:       "candidate without a URL",
+			title:      "Hades",
+			candidates: []StoreCandidate{{Title: "Hades"}},
+		},
+		{
+			name:  "no candidates",
+			title: "Hades",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := MatchStoreURL(tt.title, tt.candidates)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("MatchStoreURL(%q) = %q, %v, want %q, %v", tt.title, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
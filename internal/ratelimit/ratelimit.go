@@ -2,112 +2,161 @@ package ratelimit
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 )
 
-// RateLimiter implements a token bucket rate limiter
+// ErrClosed is returned by Wait once the rate limiter has been closed
+var ErrClosed = errors.New("rate limiter closed")
+
+// RateLimiter implements a token bucket rate limiter. Tokens are computed
+// from elapsed time on demand, so an idle limiter costs no goroutine. Waiters
+// reserve tokens in arrival order, which keeps scheduling fair under load.
 type RateLimiter struct {
-	tokens    chan struct{}
-	ticker    *time.Ticker
-	mu        sync.Mutex
-	closed    bool
-	ctx       context.Context
-	cancel    context.CancelFunc
+	mu       sync.Mutex
+	interval time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+	lastUsed time.Time
+	closed   chan struct{}
+	once     sync.Once
 }
 
 // NewRateLimiter creates a new rate limiter
 // rate: number of operations per second
 // burst: maximum number of operations that can be performed at once
 func NewRateLimiter(rate int, burst int) *RateLimiter {
-	ctx, cancel := context.WithCancel(context.Background())
-	
-	rl := &RateLimiter{
-		tokens: make(chan struct{}, burst),
-		ticker: time.NewTicker(time.Second / time.Duration(rate)),
-		ctx:    ctx,
-		cancel: cancel,
+	now := time.Now()
+	return &RateLimiter{
+		interval: time.Second / time.Duration(rate),
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     now,
+		lastUsed: now,
+		closed:   make(chan struct{}),
 	}
-	
-	// Fill the bucket initially
-	for i := 0; i < burst; i++ {
-		select {
-		case rl.tokens <- struct{}{}:
-		default:
-			break
+}
+
+// advance adds the tokens earned since the last update. Must hold rl.mu.
+func (rl *RateLimiter) advance(now time.Time) {
+	if elapsed := now.Sub(rl.last); elapsed > 0 {
+		rl.tokens += float64(elapsed) / float64(rl.interval)
+		if rl.tokens > rl.burst {
+			rl.tokens = rl.burst
 		}
+		rl.last = now
 	}
-	
-	// Start the token refill goroutine
-	go rl.refill()
-	
-	return rl
 }
 
 // Wait waits for a token to become available
 func (rl *RateLimiter) Wait(ctx context.Context) error {
 	select {
-	case <-rl.tokens:
+	case <-rl.closed:
+		return ErrClosed
+	default:
+	}
+
+	rl.mu.Lock()
+	now := time.Now()
+	rl.advance(now)
+	rl.tokens--
+	rl.lastUsed = now
+	delay := time.Duration(-rl.tokens * float64(rl.interval))
+	rl.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
 		return nil
 	case <-ctx.Done():
+		rl.release()
 		return ctx.Err()
-	case <-rl.ctx.Done():
-		return rl.ctx.Err()
+	case <-rl.closed:
+		return ErrClosed
+	}
+}
+
+// release gives back a reserved token that was never used
+func (rl *RateLimiter) release() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.advance(time.Now())
+	rl.tokens++
+	if rl.tokens > rl.burst {
+		rl.tokens = rl.burst
 	}
 }
 
 // TryWait attempts to get a token without blocking
 func (rl *RateLimiter) TryWait() bool {
-	select {
-	case <-rl.tokens:
-		return true
-	default:
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	rl.advance(now)
+	if rl.tokens < 1 {
 		return false
 	}
+	rl.tokens--
+	rl.lastUsed = now
+	return true
 }
 
-// refill adds tokens to the bucket at the specified rate
-func (rl *RateLimiter) refill() {
-	defer rl.ticker.Stop()
-	
-	for {
-		select {
-		case <-rl.ticker.C:
-			select {
-			case rl.tokens <- struct{}{}:
-			default:
-				// Bucket is full, skip this token
-			}
-		case <-rl.ctx.Done():
-			return
-		}
-	}
+// touch marks the limiter as used at now, before a wait that may start later
+func (rl *RateLimiter) touch(now time.Time) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.lastUsed = now
 }
 
-// Close stops the rate limiter
-func (rl *RateLimiter) Close() {
+// idleSince reports whether the limiter has been unused since before cutoff
+// and has no outstanding reservations, so dropping it changes nothing
+func (rl *RateLimiter) idleSince(cutoff time.Time) bool {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
-	
-	if !rl.closed {
-		rl.closed = true
-		rl.cancel()
-		close(rl.tokens)
-	}
+	rl.advance(time.Now())
+	return rl.lastUsed.Before(cutoff) && rl.tokens >= rl.burst
 }
 
+// Close stops the rate limiter, failing current and future waits
+func (rl *RateLimiter) Close() {
+	rl.once.Do(func() { close(rl.closed) })
+}
+
+// DefaultChannelIdleTimeout is how long a channel limiter is kept after its
+// last message when no other timeout is configured
+const DefaultChannelIdleTimeout = 10 * time.Minute
+
 // DiscordRateLimiter provides Discord-specific rate limiting
 type DiscordRateLimiter struct {
-	global   *RateLimiter
-	channels map[string]*RateLimiter
-	mu       sync.RWMutex
+	global      *RateLimiter
+	channels    map[string]*RateLimiter
+	mu          sync.RWMutex
+	idleTimeout time.Duration
+	lastSweep   time.Time
 }
 
-// NewDiscordRateLimiter creates a Discord-specific rate limiter
-func NewDiscordRateLimiter() *DiscordRateLimiter {
+// NewDiscordRateLimiter creates a Discord-specific rate limiter. Channel
+// limiters unused for idleTimeout are evicted; zero uses
+// DefaultChannelIdleTimeout.
+func NewDiscordRateLimiter(idleTimeout time.Duration) *DiscordRateLimiter {
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultChannelIdleTimeout
+	}
+
 	return &DiscordRateLimiter{
-		global:   NewRateLimiter(50, 1),  // Discord global rate limit
-		channels: make(map[string]*RateLimiter),
+		global:      NewRateLimiter(50, 1), // Discord global rate limit
+		channels:    make(map[string]*RateLimiter),
+		idleTimeout: idleTimeout,
+		lastSweep:   time.Now(),
 	}
 }
 
@@ -117,12 +166,17 @@ func (drl *DiscordRateLimiter) WaitForChannel(ctx context.Context, channelID str
 	if err := drl.global.Wait(ctx); err != nil {
 		return err
 	}
-	
-	// Wait for channel-specific rate limit
+
+	// Wait for channel-specific rate limit. The limiter is marked used while
+	// the lock is held, so eviction can't drop it before the wait starts and
+	// leave a second limiter for the same channel.
 	drl.mu.RLock()
 	channelLimiter, exists := drl.channels[channelID]
+	if exists {
+		channelLimiter.touch(time.Now())
+	}
 	drl.mu.RUnlock()
-	
+
 	if !exists {
 		drl.mu.Lock()
 		// Double-check after acquiring write lock
@@ -130,19 +184,57 @@ func (drl *DiscordRateLimiter) WaitForChannel(ctx context.Context, channelID str
 			channelLimiter = NewRateLimiter(5, 1) // 5 messages per second per channel
 			drl.channels[channelID] = channelLimiter
 		}
+		channelLimiter.touch(time.Now())
+		drl.evictIdleLocked(false)
 		drl.mu.Unlock()
 	}
-	
+
 	return channelLimiter.Wait(ctx)
 }
 
+// Evict drops channel limiters idle for longer than the idle timeout and
+// returns how many were removed
+func (drl *DiscordRateLimiter) Evict() int {
+	drl.mu.Lock()
+	defer drl.mu.Unlock()
+	return drl.evictIdleLocked(true)
+}
+
+// evictIdleLocked removes idle channel limiters. Unless forced it only sweeps
+// once per idle timeout, so the cost is amortized over new channels. Must
+// hold drl.mu for writing.
+func (drl *DiscordRateLimiter) evictIdleLocked(force bool) int {
+	now := time.Now()
+	if !force && now.Sub(drl.lastSweep) < drl.idleTimeout {
+		return 0
+	}
+	drl.lastSweep = now
+
+	cutoff := now.Add(-drl.idleTimeout)
+	evicted := 0
+	for channelID, limiter := range drl.channels {
+		if limiter.idleSince(cutoff) {
+			delete(drl.channels, channelID)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// ActiveChannels returns the number of channel limiters currently kept
+func (drl *DiscordRateLimiter) ActiveChannels() int {
+	drl.mu.RLock()
+	defer drl.mu.RUnlock()
+	return len(drl.channels)
+}
+
 // Close closes all rate limiters
 func (drl *DiscordRateLimiter) Close() {
 	drl.mu.Lock()
 	defer drl.mu.Unlock()
-	
+
 	drl.global.Close()
 	for _, limiter := range drl.channels {
 		limiter.Close()
 	}
-}
\ No newline at end of file
+}
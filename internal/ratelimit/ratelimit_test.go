@@ -0,0 +1,258 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWaitSpacesCallsByRate(t *testing.T) {
+	rl := NewRateLimiter(50, 1)
+	defer rl.Close()
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := rl.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+	// The first call uses the burst token, the other five wait 20ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("6 waits at 50/s took %v, want at least 100ms", elapsed)
+	}
+}
+
+func TestTryWaitUsesBurst(t *testing.T) {
+	rl := NewRateLimiter(1, 3)
+	defer rl.Close()
+
+	for i := 0; i < 3; i++ {
+		if !rl.TryWait() {
+			t.Fatalf("TryWait %d failed within the burst", i+1)
+		}
+	}
+	if rl.TryWait() {
+		t.Error("TryWait succeeded after the burst was used up")
+	}
+}
+
+func TestWaitCancelledReleasesToken(t *testing.T) {
+	rl := NewRateLimiter(1, 1)
+	defer rl.Close()
+
+	if !rl.TryWait() {
+		t.Fatal("TryWait failed on a full bucket")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := rl.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait = %v, want context.DeadlineExceeded", err)
+	}
+
+	// The cancelled reservation was given back, so the bucket holds what it
+	// earned meanwhile rather than owing a token
+	rl.mu.Lock()
+	tokens := rl.tokens
+	rl.mu.Unlock()
+	if tokens < -0.1 {
+		t.Errorf("tokens = %.2f after a cancelled wait, want the reservation released", tokens)
+	}
+}
+
+func TestWaitAfterClose(t *testing.T) {
+	rl := NewRateLimiter(1, 1)
+	rl.Close()
+	rl.Close() // closing twice is harmless
+
+	if err := rl.Wait(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("Wait = %v, want ErrClosed", err)
+	}
+}
+
+func TestCloseRacingWait(t *testing.T) {
+	rl := NewRateLimiter(1, 1)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- rl.Wait(context.Background())
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	rl.Close()
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil && !errors.Is(err, ErrClosed) {
+			t.Errorf("Wait = %v, want nil or ErrClosed", err)
+		}
+	}
+}
+
+func TestWaitForChannelConcurrent(t *testing.T) {
+	drl := NewDiscordRateLimiter(time.Minute)
+	defer drl.Close()
+	drl.global = NewRateLimiter(1_000_000, 1_000_000)
+
+	const channels = 50
+	var wg sync.WaitGroup
+	for i := 0; i < channels; i++ {
+		for j := 0; j < 2; j++ {
+			wg.Add(1)
+			go func(channelID string) {
+				defer wg.Done()
+				if err := drl.WaitForChannel(context.Background(), channelID); err != nil {
+					t.Errorf("WaitForChannel(%s): %v", channelID, err)
+				}
+			}(fmt.Sprintf("channel-%d", i))
+		}
+	}
+	wg.Wait()
+
+	if got := drl.ActiveChannels(); got != channels {
+		t.Errorf("ActiveChannels = %d, want %d", got, channels)
+	}
+}
+
+func TestWaitForChannelOrdersPerChannel(t *testing.T) {
+	drl := NewDiscordRateLimiter(time.Minute)
+	defer drl.Close()
+	drl.global = NewRateLimiter(1_000_000, 1_000_000)
+
+	// Five messages to one channel at 5/s take about 800ms; the burst token
+	// covers the first
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := drl.WaitForChannel(context.Background(), "channel"); err != nil {
+			t.Fatalf("WaitForChannel: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 750*time.Millisecond {
+		t.Errorf("5 messages to one channel took %v, want about 800ms", elapsed)
+	}
+}
+
+func TestEvictDropsIdleChannels(t *testing.T) {
+	drl := NewDiscordRateLimiter(20 * time.Millisecond)
+	defer drl.Close()
+	drl.global = NewRateLimiter(1_000_000, 1_000_000)
+
+	for _, channelID := range []string{"a", "b", "c"} {
+		if err := drl.WaitForChannel(context.Background(), channelID); err != nil {
+			t.Fatalf("WaitForChannel: %v", err)
+		}
+	}
+	// Channel limiters refill at 5/s, so they are idle once full again
+	time.Sleep(250 * time.Millisecond)
+	if err := drl.WaitForChannel(context.Background(), "c"); err != nil {
+		t.Fatalf("WaitForChannel: %v", err)
+	}
+
+	if evicted := drl.Evict(); evicted != 2 {
+		t.Errorf("Evict = %d, want 2", evicted)
+	}
+	if got := drl.ActiveChannels(); got != 1 {
+		t.Errorf("ActiveChannels = %d, want 1", got)
+	}
+}
+
+func TestWaitForChannelSurvivesEviction(t *testing.T) {
+	drl := NewDiscordRateLimiter(50 * time.Millisecond)
+	defer drl.Close()
+	drl.global = NewRateLimiter(1_000_000, 1_000_000)
+
+	if err := drl.WaitForChannel(context.Background(), "channel"); err != nil {
+		t.Fatalf("WaitForChannel: %v", err)
+	}
+	drl.mu.RLock()
+	limiter := drl.channels["channel"]
+	drl.mu.RUnlock()
+
+	// Let the limiter go idle, then look it up again: the lookup marks it
+	// used, so an eviction before its wait starts keeps it
+	time.Sleep(250 * time.Millisecond)
+	drl.mu.RLock()
+	limiter.touch(time.Now())
+	drl.mu.RUnlock()
+	if evicted := drl.Evict(); evicted != 0 {
+		t.Errorf("Evict dropped %d limiters that were just looked up", evicted)
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				drl.Evict()
+			}
+		}
+	}()
+	for i := 0; i < 3; i++ {
+		if err := drl.WaitForChannel(context.Background(), "channel"); err != nil {
+			t.Fatalf("WaitForChannel: %v", err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func BenchmarkTryWait(b *testing.B) {
+	rl := NewRateLimiter(1_000_000_000, 1_000_000_000)
+	defer rl.Close()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			rl.TryWait()
+		}
+	})
+}
+
+func BenchmarkWaitForChannel(b *testing.B) {
+	for _, channels := range []int{1, 100, 10_000} {
+		b.Run(fmt.Sprintf("channels=%d", channels), func(b *testing.B) {
+			drl := NewDiscordRateLimiter(time.Minute)
+			defer drl.Close()
+			drl.global = NewRateLimiter(1_000_000_000, 1_000_000_000)
+
+			ids := make([]string, channels)
+			for i := range ids {
+				ids[i] = fmt.Sprintf("channel-%d", i)
+			}
+			// Channel limiters only allow 5/s, so only the lookups are timed:
+			// each wait is cancelled before it would sleep
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				drl.WaitForChannel(ctx, ids[i%channels])
+			}
+		})
+	}
+}
+
+func BenchmarkEvict(b *testing.B) {
+	drl := NewDiscordRateLimiter(time.Minute)
+	defer drl.Close()
+	drl.global = NewRateLimiter(1_000_000_000, 1_000_000_000)
+	for i := 0; i < 10_000; i++ {
+		drl.WaitForChannel(context.Background(), fmt.Sprintf("channel-%d", i))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		drl.Evict()
+	}
+}
@@ -0,0 +1,304 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/models"
+)
+
+// DefaultPromotionsURL is Epic's public free games promotions endpoint
+const DefaultPromotionsURL = "https://store-site-backend-static.ak.epicgames.com/freeGamesPromotions?locale=en-US&country=US&allowCountries=US"
+
+// promotionsURLFormat builds the promotions endpoint for a locale and country
+const promotionsURLFormat = "https://store-site-backend-static.ak.epicgames.com/freeGamesPromotions?locale=%s&country=%s&allowCountries=%s"
+
+// APIScraper reads free games from Epic's freeGamesPromotions JSON endpoint.
+// Unlike EpicScraper it needs no browser.
+type APIScraper struct {
+	config *config.ScraperConfig
+	client *http.Client
+	url    string
+	locale string
+	now    func() time.Time
+}
+
+// NewAPIScraper creates a new Epic JSON API scraper for the default locale
+// (EPIC_LOCALE)
+func NewAPIScraper(cfg *config.ScraperConfig) *APIScraper {
+	return NewAPIScraperForLocale(cfg, cfg.Locale)
+}
+
+// NewAPIScraperForLocale creates an Epic JSON API scraper for one locale. Games
+// it finds are tagged with that locale. EPIC_PROMOTIONS_URL only overrides
+// the endpoint of the default locale.
+func NewAPIScraperForLocale(cfg *config.ScraperConfig, locale string) *APIScraper {
+	if locale == "" {
+		locale = models.DefaultLocale
+	}
+
+	url := DefaultPromotionsURL
+	if info, ok := models.LookupLocale(locale); ok {
+		locale = info.Code
+		url = fmt.Sprintf(promotionsURLFormat, info.Code, info.Country, info.Country)
+	}
+	if cfg.PromotionsURL != "" && strings.EqualFold(locale, cfg.Locale) {
+		url = cfg.PromotionsURL
+	}
+
+	return &APIScraper{
+		config: cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		url:    url,
+		locale: locale,
+		now:    time.Now,
+	}
+}
+
+// promotionsResponse mirrors the parts of the freeGamesPromotions payload we use
+type promotionsResponse struct {
+	Data struct {
+		Catalog struct {
+			SearchStore struct {
+				Elements []promotionElement `json:"elements"`
+			} `json:"searchStore"`
+		} `json:"Catalog"`
+	} `json:"data"`
+}
+
+type promotionElement struct {
+	Title       string `json:"title"`
+	ProductSlug string `json:"productSlug"`
+	URLSlug     string `json:"urlSlug"`
+	KeyImages   []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"keyImages"`
+	CatalogNs struct {
+		Mappings []pageMapping `json:"mappings"`
+	} `json:"catalogNs"`
+	OfferMappings []pageMapping `json:"offerMappings"`
+	Promotions    *struct {
+		PromotionalOffers         []promotionGroup `json:"promotionalOffers"`
+		UpcomingPromotionalOffers []promotionGroup `json:"upcomingPromotionalOffers"`
+	} `json:"promotions"`
+}
+
+type pageMapping struct {
+	PageSlug string `json:"pageSlug"`
+	PageType string `json:"pageType"`
+}
+
+type promotionGroup struct {
+	PromotionalOffers []struct {
+		StartDate       time.Time `json:"startDate"`
+		EndDate         time.Time `json:"endDate"`
+		DiscountSetting struct {
+			DiscountType       string `json:"discountType"`
+			DiscountPercentage int    `json:"discountPercentage"`
+		} `json:"discountSetting"`
+	} `json:"promotionalOffers"`
+}
+
+// ScrapeGames fetches and parses the promotions feed with retries
+func (s *APIScraper) ScrapeGames(ctx context.Context) ([]models.Game, error) {
+	attempts := s.config.MaxRetries
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		games, err := s.fetch(ctx)
+		if err == nil {
+			log.Printf("Successfully fetched %d games from the Epic API (%s)", len(games), s.locale)
+			return games, nil
+		}
+
+		lastErr = err
+		log.Printf("Epic API (%s) attempt %d/%d failed: %v", s.locale, attempt, attempts, err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if attempt < attempts {
+			if err := sleepContext(ctx, s.config.RetryDelay); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return nil, &models.RetriesExhaustedError{Source: fmt.Sprintf("Epic promotions (%s)", s.locale), Attempts: attempts, Err: lastErr}
+}
+
+// fetch performs a single request against the promotions endpoint
+func (s *APIScraper) fetch(ctx context.Context) ([]models.Game, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if s.config.UserAgent != "" {
+		req.Header.Set("User-Agent", s.config.UserAgent)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := readResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload promotionsResponse
+	if err := decodeJSON(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode promotions: %w", err)
+	}
+
+	return s.parse(payload), nil
+}
+
+// parse converts promotion elements into games. Only 100%-off promotions are
+// kept: running ones become Free Now and upcoming ones Coming Soon.
+func (s *APIScraper) parse(payload promotionsResponse) []models.Game {
+	now := s.now()
+	var games []models.Game
+
+	for _, element := range payload.Data.Catalog.SearchStore.Elements {
+		if element.Promotions == nil || strings.TrimSpace(element.Title) == "" {
+			continue
+		}
+
+		game := models.Game{
+			Title:    strings.TrimSpace(element.Title),
+			ImageURL: element.imageURL(),
+			Images:   element.imageURLs(),
+			StoreURL: element.storeURL(),
+			Source:   models.SourceEpic,
+			Regions:  []string{s.locale},
+		}
+
+		if start, end, ok := freePromotion(element.Promotions.PromotionalOffers); ok && !now.Before(start) && now.Before(end) {
+			game.Status = models.StatusFreeNow
+			game.FreeFrom = formatPromoDate(start)
+			game.FreeTo = formatPromoDate(end)
+			game.FreeFromTime = start
+			game.FreeToTime = end
+		} else if start, end, ok := freePromotion(element.Promotions.UpcomingPromotionalOffers); ok && now.Before(start) {
+			game.Status = models.StatusComingSoon
+			game.FreeFrom = formatPromoDate(start)
+			game.FreeTo = formatPromoDate(end)
+			game.FreeFromTime = start
+			game.FreeToTime = end
+		} else {
+			continue
+		}
+
+		games = append(games, game)
+	}
+
+	return games
+}
+
+// freePromotion returns the window of the first 100%-off offer in groups
+func freePromotion(groups []promotionGroup) (time.Time, time.Time, bool) {
+	for _, group := range groups {
+		for _, offer := range group.PromotionalOffers {
+			if offer.DiscountSetting.DiscountPercentage == 0 {
+				return offer.StartDate, offer.EndDate, true
+			}
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// formatPromoDate renders a promotion date the way the store page shows it
+// (e.g. "Jul 17"), so both scrapers produce the same title|free_to keys
+func formatPromoDate(t time.Time) string {
+	return t.Local().Format("Jan 02")
+}
+
+// imageURL picks the best available key image
+func (e promotionElement) imageURL() string {
+	for _, preferred := range []string{"OfferImageWide", "DieselStoreFrontWide", "Thumbnail", "OfferImageTall"} {
+		for _, image := range e.KeyImages {
+			if image.Type == preferred && image.URL != "" {
+				return image.URL
+			}
+		}
+	}
+	if len(e.KeyImages) > 0 {
+		return e.KeyImages[0].URL
+	}
+	return ""
+}
+
+// galleryImageTypes are the key image types that can show different artwork,
+// as opposed to the same cover cropped to another aspect ratio
+var galleryImageTypes = map[string]bool{
+	"OfferImageWide":       true,
+	"DieselStoreFrontWide": true,
+	"featuredMedia":        true,
+	"Screenshot":           true,
+}
+
+// imageURLs returns the main image followed by any distinct gallery images,
+// up to models.MaxGameImages
+func (e promotionElement) imageURLs() []string {
+	main := e.imageURL()
+	if main == "" {
+		return nil
+	}
+
+	images := []string{main}
+	seen := map[string]bool{main: true}
+	for _, image := range e.KeyImages {
+		if len(images) == models.MaxGameImages {
+			break
+		}
+		if !galleryImageTypes[image.Type] || image.URL == "" || seen[image.URL] {
+			continue
+		}
+		seen[image.URL] = true
+		images = append(images, image.URL)
+	}
+	return images
+}
+
+// storeURL builds the product page URL from the first usable slug
+func (e promotionElement) storeURL() string {
+	slug := productHomeSlug(e.CatalogNs.Mappings)
+	if slug == "" {
+		slug = productHomeSlug(e.OfferMappings)
+	}
+	if slug == "" {
+		slug = strings.TrimSuffix(e.ProductSlug, "/home")
+	}
+	if slug == "" || slug == "[]" {
+		slug = e.URLSlug
+	}
+	if slug == "" || slug == "[]" {
+		return ""
+	}
+	return models.NormalizeStoreURL("/en-US/p/" + slug)
+}
+
+// productHomeSlug returns the slug of the product home page mapping, if any
+func productHomeSlug(mappings []pageMapping) string {
+	for _, mapping := range mappings {
+		if mapping.PageSlug != "" && mapping.PageType == "productHome" {
+			return mapping.PageSlug
+		}
+	}
+	return ""
+}
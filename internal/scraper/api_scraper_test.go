@@ -0,0 +1,94 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/models"
+)
+
+// galleryFeed is a promotions payload with a bundle carrying several
+// artworks and a single-image game, as the feed reports them
+const galleryFeed = `{"data": {"Catalog": {"searchStore": {"elements": [
+	{
+		"title": "Bundle Of Games",
+		"productSlug": "bundle-of-games",
+		"keyImages": [
+			{"type": "OfferImageTall", "url": "https://cdn.example/bundle/tall.jpg"},
+			{"type": "Thumbnail", "url": "https://cdn.example/bundle/thumb.jpg"},
+			{"type": "OfferImageWide", "url": "https://cdn.example/bundle/wide.jpg"},
+			{"type": "DieselStoreFrontWide", "url": "https://cdn.example/bundle/wide.jpg"},
+			{"type": "featuredMedia", "url": "https://cdn.example/bundle/featured.jpg"},
+			{"type": "Screenshot", "url": "https://cdn.example/bundle/shot1.jpg"},
+			{"type": "Screenshot", "url": "https://cdn.example/bundle/shot2.jpg"},
+			{"type": "Screenshot", "url": "https://cdn.example/bundle/shot3.jpg"}
+		],
+		"promotions": {"promotionalOffers": [{"promotionalOffers": [
+			{"startDate": "2026-07-10T15:00:00.000Z", "endDate": "2026-07-17T15:00:00.000Z", "discountSetting": {"discountType": "PERCENTAGE", "discountPercentage": 0}}
+		]}]}
+	},
+	{
+		"title": "Single Game",
+		"productSlug": "single-game",
+		"keyImages": [
+			{"type": "OfferImageWide", "url": "https://cdn.example/single/wide.jpg"},
+			{"type": "OfferImageTall", "url": "https://cdn.example/single/tall.jpg"}
+		],
+		"promotions": {"promotionalOffers": [{"promotionalOffers": [
+			{"startDate": "2026-07-10T15:00:00.000Z", "endDate": "2026-07-17T15:00:00.000Z", "discountSetting": {"discountType": "PERCENTAGE", "discountPercentage": 0}}
+		]}]}
+	}
+]}}}}`
+
+func TestScrapeGamesKeepsGalleryImages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, galleryFeed)
+	}))
+	defer server.Close()
+
+	cfg := &config.ScraperConfig{PromotionsURL: server.URL, Locale: "en-US", Timeout: 5 * time.Second, MaxRetries: 1}
+	s := NewAPIScraper(cfg)
+	s.now = func() time.Time { return time.Date(2026, time.July, 12, 0, 0, 0, 0, time.UTC) }
+
+	games, err := s.ScrapeGames(context.Background())
+	if err != nil {
+		t.Fatalf("ScrapeGames: %v", err)
+	}
+	if len(games) != 2 {
+		t.Fatalf("scraped %d games, want 2", len(games))
+	}
+
+	// The main image comes first, followed by distinct artwork up to the cap;
+	// tall and thumbnail crops of the cover are left out
+	bundle := games[0]
+	wantBundle := []string{
+		"https://cdn.example/bundle/wide.jpg",
+		"https://cdn.example/bundle/featured.jpg",
+		"https://cdn.example/bundle/shot1.jpg",
+		"https://cdn.example/bundle/shot2.jpg",
+	}
+	if bundle.ImageURL != wantBundle[0] {
+		t.Errorf("bundle main image = %s, want %s", bundle.ImageURL, wantBundle[0])
+	}
+	if fmt.Sprint(bundle.Images) != fmt.Sprint(wantBundle) {
+		t.Errorf("bundle images = %v, want %v", bundle.Images, wantBundle)
+	}
+	if len(bundle.Images) != models.MaxGameImages {
+		t.Errorf("kept %d images, want the cap of %d", len(bundle.Images), models.MaxGameImages)
+	}
+
+	// A single-image game has no gallery
+	single := games[1]
+	if single.ImageURL != "https://cdn.example/single/wide.jpg" || len(single.Images) != 1 {
+		t.Errorf("single game image %s with images %v, want just its wide image", single.ImageURL, single.Images)
+	}
+	if gallery := single.GalleryImages(); gallery != nil {
+		t.Errorf("single game gallery = %v, want none", gallery)
+	}
+}
@@ -0,0 +1,114 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/models"
+)
+
+// DefaultCatalogSearchURL is Epic's public store GraphQL endpoint
+const DefaultCatalogSearchURL = "https://graphql.epicgames.com/graphql"
+
+// catalogSearchCount is how many results a title search asks for
+const catalogSearchCount = 10
+
+// catalogSearchQuery searches the store by keywords, returning the fields
+// promotionElement.storeURL builds product URLs from
+const catalogSearchQuery = `query searchStoreQuery($keywords: String, $country: String!, $locale: String, $count: Int) {
+  Catalog {
+    searchStore(keywords: $keywords, country: $country, locale: $locale, count: $count) {
+      elements {
+        title
+        productSlug
+        urlSlug
+        catalogNs { mappings(pageType: "productHome") { pageSlug pageType } }
+        offerMappings { pageSlug pageType }
+      }
+    }
+  }
+}`
+
+// CatalogSearcher looks up store pages by game title in Epic's catalog
+type CatalogSearcher struct {
+	config *config.ScraperConfig
+	client *http.Client
+	url    string
+}
+
+// NewCatalogSearcher creates a catalog searcher for the default locale
+func NewCatalogSearcher(cfg *config.ScraperConfig) *CatalogSearcher {
+	return &CatalogSearcher{
+		config: cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		url:    DefaultCatalogSearchURL,
+	}
+}
+
+// Search returns the catalog entries matching title, with their store URLs
+func (s *CatalogSearcher) Search(ctx context.Context, title string) ([]models.StoreCandidate, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	locale, country := models.DefaultLocale, "US"
+	if info, ok := models.LookupLocale(s.config.Locale); ok {
+		locale, country = info.Code, info.Country
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"query": catalogSearchQuery,
+		"variables": map[string]interface{}{
+			"keywords": title,
+			"country":  country,
+			"locale":   locale,
+			"count":    catalogSearchCount,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode search: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if s.config.UserAgent != "" {
+		req.Header.Set("User-Agent", s.config.UserAgent)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := readResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// The search response has the same shape as the promotions feed
+	var result struct {
+		promotionsResponse
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := decodeJSON(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode catalog search: %w", err)
+	}
+	if len(result.Errors) > 0 && len(result.Data.Catalog.SearchStore.Elements) == 0 {
+		return nil, fmt.Errorf("catalog search failed: %s", result.Errors[0].Message)
+	}
+
+	var candidates []models.StoreCandidate
+	for _, element := range result.Data.Catalog.SearchStore.Elements {
+		candidates = append(candidates, models.StoreCandidate{Title: element.Title, StoreURL: element.storeURL()})
+	}
+	return candidates, nil
+}
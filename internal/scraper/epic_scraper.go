@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"os/exec"
 	"time"
 
 	"github.com/chromedp/chromedp"
@@ -24,9 +26,14 @@ func NewEpicScraper(cfg *config.ScraperConfig) *EpicScraper {
 }
 
 // ScrapeGames scrapes free games from Epic Games Store
-func (s *EpicScraper) ScrapeGames() ([]models.Game, error) {
+func (s *EpicScraper) ScrapeGames(ctx context.Context) ([]models.Game, error) {
+	// Without Chrome the browser can't start, so don't try
+	if _, err := exec.LookPath(s.config.ChromePath); err != nil {
+		return nil, fmt.Errorf("%w: Chrome/Chromium not found, set CHROME_PATH", models.ErrScraperUnavailable)
+	}
+
 	// Create context with Chrome executable path
-	allocCtx, cancel := chromedp.NewExecAllocator(context.Background(),
+	allocCtx, cancel := chromedp.NewExecAllocator(ctx,
 		chromedp.ExecPath(s.config.ChromePath),
 		chromedp.UserAgent(s.config.UserAgent),
 		chromedp.Flag("headless", true),
@@ -36,7 +43,7 @@ func (s *EpicScraper) ScrapeGames() ([]models.Game, error) {
 	)
 	defer cancel()
 
-	ctx, cancel := chromedp.NewContext(allocCtx)
+	ctx, cancel = chromedp.NewContext(allocCtx)
 	defer cancel()
 
 	// Set timeout
@@ -44,30 +51,71 @@ func (s *EpicScraper) ScrapeGames() ([]models.Game, error) {
 	defer cancel()
 
 	var games []models.Game
+	var lastErr error
 
 	// Attempt to scrape with retries
 	for attempt := 1; attempt <= 3; attempt++ {
 		log.Printf("Scraping attempt %d/3", attempt)
 		
+		var title string
 		err := chromedp.Run(ctx,
 			chromedp.Navigate("https://store.epicgames.com/en-US/free-games"),
 			chromedp.WaitVisible("body", chromedp.ByQuery),
-			chromedp.Sleep(5*time.Second), // Wait longer for dynamic content to load
+			chromedp.Sleep(s.config.RequestDelay), // Give dynamic content time to render (SCRAPER_REQUEST_DELAY)
+			chromedp.Title(&title),
 			chromedp.Evaluate(s.getScrapingScript(), &games),
 		)
 		
 		if err == nil && len(games) > 0 {
+			now := time.Now()
+			for i := range games {
+				games[i].StoreURL = models.NormalizeStoreURL(games[i].StoreURL)
+				applyPeriod(&games[i], now)
+				games[i].ParseDates(now)
+				games[i].Source = models.SourceEpic
+			}
 			log.Printf("Successfully scraped %d games", len(games))
 			return games, nil
 		}
 		
-		log.Printf("Attempt %d failed: %v. Retrying...", attempt, err)
+		lastErr = err
+		if lastErr == nil && models.IsChallengePage(title) {
+			lastErr = &models.UpstreamError{StatusCode: http.StatusOK, Challenge: true}
+		}
+		if lastErr == nil {
+			lastErr = models.ErrNoGamesFound
+		}
+		log.Printf("Attempt %d failed: %v. Retrying...", attempt, lastErr)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if attempt < 3 {
-			time.Sleep(5 * time.Second)
+			if err := sleepContext(ctx, s.config.RetryDelay); err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	return nil, fmt.Errorf("failed to scrape data after 3 attempts")
+	return nil, &models.RetriesExhaustedError{Source: "Epic store page", Attempts: 3, Err: lastErr}
+}
+
+// applyPeriod fills a game's free window from the raw period text scraped
+// from its card. Unparseable text is logged and leaves the game unchanged.
+func applyPeriod(game *models.Game, now time.Time) {
+	if game.Period == "" {
+		return
+	}
+
+	period, err := models.ParsePeriod(game.Period, now)
+	if err != nil {
+		log.Printf("Could not parse period for %s: %v", game.Title, err)
+		return
+	}
+
+	game.FreeFrom = period.From
+	game.FreeTo = period.To
+	game.FreeFromTime = period.Start
+	game.FreeToTime = period.End
 }
 
 // getScrapingScript returns the JavaScript code for scraping game data
@@ -94,24 +142,17 @@ func (s *EpicScraper) getScrapingScript() string {
 					const imageElement = container.querySelector('img[data-image], img[src]');
 					game.image_url = imageElement?.getAttribute('data-image') || imageElement?.getAttribute('src') || '';
 					
+					// Extract store link (the card is usually wrapped in an anchor to /p/<slug>)
+					const linkElement = container.closest('a[href]') || container.querySelector('a[href*="/p/"], a[href]');
+					game.store_url = linkElement?.getAttribute('href') || '';
+					
 					// Extract status
 					const statusElement = container.querySelector('.css-82y1uz span, .css-gyjcm9 span, [data-testid="offer-status"]');
 					game.status = statusElement?.textContent?.trim() || '';
 					
-					// Extract period information
+					// Extract the raw period text; it is parsed in Go
 					const periodElement = container.querySelector('.css-1p5cyzj-ROOT p span, [data-testid="offer-period"]');
-					const period = periodElement?.textContent?.trim() || '';
-					
-					if (period.includes('Free Now')) {
-						const parts = period.split(' - ');
-						game.free_to = parts.length > 1 ? parts[1].split(' at ')[0].trim() : '';
-					} else if (period.includes('Free')) {
-						const parts = period.split(' - ');
-						if (parts.length > 1) {
-							game.free_from = parts[0].replace('Free', '').trim();
-							game.free_to = parts[1].trim();
-						}
-					}
+					game.period = periodElement?.textContent?.trim() || '';
 					
 					// Only add games with valid titles
 					if (game.title) {
@@ -0,0 +1,143 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/models"
+)
+
+// DefaultGOGCatalogURL lists GOG games temporarily discounted to zero, which is
+// how GOG giveaways appear in the public catalog
+const DefaultGOGCatalogURL = "https://catalog.gog.com/v1/catalog?limit=48&order=desc:trending&discounted=eq:true&productType=in:game,pack&price=between:0,0&countryCode=US&locale=en-US&currencyCode=USD"
+
+// GOGScraper reads temporarily free games from the GOG catalog API
+type GOGScraper struct {
+	config *config.ScraperConfig
+	client *http.Client
+	url    string
+}
+
+// NewGOGScraper creates a new GOG giveaway scraper
+func NewGOGScraper(cfg *config.ScraperConfig) *GOGScraper {
+	url := cfg.GOGCatalogURL
+	if url == "" {
+		url = DefaultGOGCatalogURL
+	}
+
+	return &GOGScraper{
+		config: cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		url:    url,
+	}
+}
+
+// gogCatalogResponse mirrors the parts of the GOG catalog payload we use
+type gogCatalogResponse struct {
+	Products []struct {
+		Title           string `json:"title"`
+		Slug            string `json:"slug"`
+		StoreLink       string `json:"storeLink"`
+		CoverHorizontal string `json:"coverHorizontal"`
+		Price           *struct {
+			Discount   string `json:"discount"`
+			FinalMoney struct {
+				Amount string `json:"amount"`
+			} `json:"finalMoney"`
+		} `json:"price"`
+	} `json:"products"`
+}
+
+// ScrapeGames fetches the GOG catalog with retries
+func (s *GOGScraper) ScrapeGames(ctx context.Context) ([]models.Game, error) {
+	attempts := s.config.MaxRetries
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		games, err := s.fetch(ctx)
+		if err == nil {
+			log.Printf("Successfully fetched %d games from GOG", len(games))
+			return games, nil
+		}
+
+		lastErr = err
+		log.Printf("GOG attempt %d/%d failed: %v", attempt, attempts, err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if attempt < attempts {
+			if err := sleepContext(ctx, s.config.RetryDelay); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return nil, &models.RetriesExhaustedError{Source: "GOG catalog", Attempts: attempts, Err: lastErr}
+}
+
+// fetch performs a single catalog request
+func (s *GOGScraper) fetch(ctx context.Context) ([]models.Game, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if s.config.UserAgent != "" {
+		req.Header.Set("User-Agent", s.config.UserAgent)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := readResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload gogCatalogResponse
+	if err := decodeJSON(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode GOG catalog: %w", err)
+	}
+
+	var games []models.Game
+	for _, product := range payload.Products {
+		title := strings.TrimSpace(product.Title)
+		if title == "" || product.Price == nil || !isZeroAmount(product.Price.FinalMoney.Amount) {
+			continue
+		}
+
+		storeURL := product.StoreLink
+		if storeURL == "" && product.Slug != "" {
+			storeURL = "https://www.gog.com/en/game/" + product.Slug
+		}
+
+		games = append(games, models.Game{
+			Title:    title,
+			ImageURL: product.CoverHorizontal,
+			Status:   models.StatusFreeNow,
+			StoreURL: storeURL,
+			Source:   models.SourceGOG,
+		})
+	}
+
+	return games, nil
+}
+
+// isZeroAmount reports whether a GOG money amount such as "0.00" is zero
+func isZeroAmount(amount string) bool {
+	amount = strings.TrimSpace(amount)
+	return amount != "" && strings.Trim(amount, "0.") == ""
+}
@@ -0,0 +1,76 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/models"
+)
+
+// MultiLocaleScraper queries the Epic API once per configured locale and
+// merges the results, tagging each game with every locale it is free in
+type MultiLocaleScraper struct {
+	scrapers []*APIScraper
+	delay    time.Duration
+}
+
+// NewMultiLocaleScraper creates a scraper covering every locale in EPIC_LOCALES
+func NewMultiLocaleScraper(cfg *config.ScraperConfig) *MultiLocaleScraper {
+	scrapers := make([]*APIScraper, 0, len(cfg.Locales))
+	for _, locale := range cfg.Locales {
+		scrapers = append(scrapers, NewAPIScraperForLocale(cfg, locale))
+	}
+
+	return &MultiLocaleScraper{
+		scrapers: scrapers,
+		delay:    cfg.RequestDelay,
+	}
+}
+
+// ScrapeGames scrapes every locale in order. A failing locale is logged and
+// skipped; an error is returned only when every locale fails.
+func (s *MultiLocaleScraper) ScrapeGames(ctx context.Context) ([]models.Game, error) {
+	var merged []models.Game
+	index := make(map[string]int)
+	var lastErr error
+	succeeded := 0
+
+	for i, scraper := range s.scrapers {
+		if i > 0 && s.delay > 0 {
+			if err := sleepContext(ctx, s.delay); err != nil {
+				return nil, err
+			}
+		}
+
+		games, err := scraper.ScrapeGames(ctx)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			log.Printf("Skipping locale %s: %v", scraper.locale, err)
+			lastErr = err
+			continue
+		}
+		succeeded++
+
+		for _, game := range games {
+			key := strings.ToLower(game.Title)
+			if j, ok := index[key]; ok {
+				merged[j].Regions = append(merged[j].Regions, game.Regions...)
+				continue
+			}
+			index[key] = len(merged)
+			merged = append(merged, game)
+		}
+	}
+
+	if succeeded == 0 && lastErr != nil {
+		return nil, fmt.Errorf("failed to scrape any locale: %w", lastErr)
+	}
+
+	return merged, nil
+}
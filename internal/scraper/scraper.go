@@ -0,0 +1,161 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/models"
+)
+
+// Scraper fetches the current set of free games from a store. GameService
+// depends only on this interface, so any source (or a fake) can be injected.
+// Implementations stop early and return ctx.Err() once ctx is cancelled.
+type Scraper interface {
+	ScrapeGames(ctx context.Context) ([]models.Game, error)
+}
+
+// Compile-time checks that every implementation can be injected into GameService
+var (
+	_ Scraper = (*EpicScraper)(nil)
+	_ Scraper = (*APIScraper)(nil)
+	_ Scraper = (*FallbackScraper)(nil)
+	_ Scraper = (*MultiLocaleScraper)(nil)
+	_ Scraper = (*GOGScraper)(nil)
+)
+
+// Scraper modes accepted by SCRAPER_MODE
+const (
+	ModeAuto   = "auto"
+	ModeAPI    = "api"
+	ModeChrome = "chrome"
+)
+
+// New builds the scraper selected by cfg.Mode. In auto mode the JSON API is
+// used first and headless Chrome is kept as a fallback when it is installed.
+func New(cfg *config.ScraperConfig) (Scraper, error) {
+	switch strings.ToLower(cfg.Mode) {
+	case ModeAPI:
+		return newEpicAPIScraper(cfg), nil
+	case ModeChrome:
+		return NewEpicScraper(cfg), nil
+	case ModeAuto, "":
+		if cfg.ChromePath == "" {
+			log.Println("Chrome not found, using the Epic JSON API without a fallback")
+			return newEpicAPIScraper(cfg), nil
+		}
+		return NewFallbackScraper(newEpicAPIScraper(cfg), NewEpicScraper(cfg)), nil
+	default:
+		return nil, fmt.Errorf("unknown scraper mode %q", cfg.Mode)
+	}
+}
+
+// newEpicAPIScraper returns the JSON API scraper, covering every locale in
+// EPIC_LOCALES when more than one is configured
+func newEpicAPIScraper(cfg *config.ScraperConfig) Scraper {
+	if len(cfg.Locales) > 1 {
+		return NewMultiLocaleScraper(cfg)
+	}
+	return NewAPIScraper(cfg)
+}
+
+// FallbackScraper tries a primary scraper and falls back to a secondary one
+// when the primary fails or finds nothing
+type FallbackScraper struct {
+	primary  Scraper
+	fallback Scraper
+}
+
+// NewFallbackScraper creates a scraper that uses fallback when primary fails
+func NewFallbackScraper(primary, fallback Scraper) *FallbackScraper {
+	return &FallbackScraper{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+// ScrapeGames scrapes with the primary scraper, then the fallback
+func (s *FallbackScraper) ScrapeGames(ctx context.Context) ([]models.Game, error) {
+	games, err := s.primary.ScrapeGames(ctx)
+	if err == nil && len(games) > 0 {
+		return games, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if err != nil {
+		log.Printf("Primary scraper failed, falling back: %v", err)
+	} else {
+		log.Println("Primary scraper found no games, falling back")
+	}
+
+	games, fallbackErr := s.fallback.ScrapeGames(ctx)
+	if errors.Is(fallbackErr, models.ErrScraperUnavailable) {
+		// Only the primary's outcome is left to report
+		log.Printf("Fallback scraper unavailable: %v", fallbackErr)
+		return nil, err
+	}
+	if fallbackErr != nil {
+		if err != nil {
+			return nil, fmt.Errorf("primary scraper: %v; fallback scraper: %w", err, fallbackErr)
+		}
+		return nil, fmt.Errorf("fallback scraper: %w", fallbackErr)
+	}
+
+	return games, nil
+}
+
+// maxResponseBytes caps how much of a store response is read
+const maxResponseBytes = 32 << 20
+
+// readResponse reads a store response body. A status meaning the store is
+// failing, or a challenge page instead of content, is returned as a
+// models.UpstreamError so it is not mistaken for a scraper bug.
+func readResponse(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return body, nil
+	}
+
+	challenge := models.IsChallengePage(string(body))
+	if challenge || models.IsUpstreamStatus(resp.StatusCode) {
+		return nil, &models.UpstreamError{StatusCode: resp.StatusCode, Challenge: challenge}
+	}
+	return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+}
+
+// decodeJSON unmarshals a store response into v. A challenge page served
+// with status 200 is returned as a models.UpstreamError.
+func decodeJSON(body []byte, v interface{}) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		if models.IsChallengePage(string(body)) {
+			return &models.UpstreamError{StatusCode: http.StatusOK, Challenge: true}
+		}
+		return err
+	}
+	return nil
+}
+
+// sleepContext pauses for d, returning early with ctx.Err() if ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
@@ -18,14 +19,25 @@ type Validator struct {
 	channelPattern *regexp.Regexp
 	// URL pattern for validation
 	urlPattern *regexp.Regexp
+	// Discord webhook path pattern
+	webhookPathPattern *regexp.Regexp
+}
+
+// discordWebhookHosts lists the hosts Discord serves webhooks from
+var discordWebhookHosts = map[string]bool{
+	"discord.com":        true,
+	"discordapp.com":     true,
+	"ptb.discord.com":    true,
+	"canary.discord.com": true,
 }
 
 // NewValidator creates a new validator instance
 func NewValidator() *Validator {
 	return &Validator{
-		discordIDPattern: regexp.MustCompile(`^\d{17,19}$`),
-		channelPattern:   regexp.MustCompile(`^<#\d{17,19}>$`),
-		urlPattern:       regexp.MustCompile(`^https?://[^\s/$.?#].[^\s]*$`),
+		discordIDPattern:   regexp.MustCompile(`^\d{17,19}$`),
+		channelPattern:     regexp.MustCompile(`^<#\d{17,19}>$`),
+		urlPattern:         regexp.MustCompile(`^https?://[^\s/$.?#].[^\s]*$`),
+		webhookPathPattern: regexp.MustCompile(`^/api/(?:v\d+/)?webhooks/(\d{17,20})/([A-Za-z0-9_-]{20,100})/?$`),
 	}
 }
 
@@ -68,6 +80,45 @@ func (v *Validator) ValidateURL(url string) error {
 	return nil
 }
 
+// ValidateDiscordWebhookURL checks that a URL points at a Discord webhook and
+// returns it normalized to https://discord.com/api/webhooks/<id>/<token>.
+// Anything else (other hosts, IP addresses, custom ports, credentials) is
+// rejected so a webhook setting cannot be used to reach internal services.
+func (v *Validator) ValidateDiscordWebhookURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if err := v.ValidateURL(rawURL); err != nil {
+		return "", err
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid webhook URL: %w", err)
+	}
+
+	if parsed.Scheme != "https" {
+		return "", fmt.Errorf("webhook URL must use https")
+	}
+	if parsed.User != nil {
+		return "", fmt.Errorf("webhook URL must not contain credentials")
+	}
+	if parsed.Port() != "" {
+		return "", fmt.Errorf("webhook URL must not specify a port")
+	}
+	if !discordWebhookHosts[strings.ToLower(parsed.Hostname())] {
+		return "", fmt.Errorf("webhook URL must be a discord.com webhook, got host %q", parsed.Hostname())
+	}
+	if parsed.RawQuery != "" || parsed.Fragment != "" {
+		return "", fmt.Errorf("webhook URL must not contain a query or fragment")
+	}
+
+	matches := v.webhookPathPattern.FindStringSubmatch(parsed.EscapedPath())
+	if matches == nil {
+		return "", fmt.Errorf("webhook URL path must look like /api/webhooks/<id>/<token>")
+	}
+
+	return fmt.Sprintf("https://discord.com/api/webhooks/%s/%s", matches[1], matches[2]), nil
+}
+
 // SanitizeInput sanitizes user input to prevent injection attacks
 func (v *Validator) SanitizeInput(input string) string {
 	// Remove null bytes
@@ -238,6 +289,10 @@ func ValidateURL(url string) error {
 	return globalValidator.ValidateURL(url)
 }
 
+func ValidateDiscordWebhookURL(rawURL string) (string, error) {
+	return globalValidator.ValidateDiscordWebhookURL(rawURL)
+}
+
 func SanitizeInput(input string) string {
 	return globalValidator.SanitizeInput(input)
 }
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"log"
+
+	"free-games-scrape/internal/models"
+)
+
+// CorrectAnnouncedDates records the date corrections among a scrape's
+// changes and queues edits of the messages already posted about them,
+// returning the end date edits recorded.
+// It must run after the scrape is saved and before new games are looked up,
+// so a promotion whose end date moved is not announced again.
+func (gs *GameService) CorrectAnnouncedDates(changes []models.GameChange) []models.FieldChange {
+	var edits []models.FieldChange
+	for _, change := range changes {
+		if !change.IsDateCorrection() {
+			continue
+		}
+		messages, gameEdits, err := gs.db.CorrectPromotionDates(*change.Previous, change.Game)
+		if err != nil {
+			log.Printf("Error correcting the dates of %s: %v", change.Game.Title, err)
+			continue
+		}
+		if messages > 0 {
+			log.Printf("Dates of %s were corrected; %d posted messages queued for an edit", change.Game.Title, messages)
+		}
+		edits = append(edits, gameEdits...)
+	}
+	return edits
+}
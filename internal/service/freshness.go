@@ -0,0 +1,106 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"free-games-scrape/internal/models"
+)
+
+// StaleFactor is how many refresh intervals may pass since the last
+// successful scrape before the stored games are reported as stale
+const StaleFactor = 1.5
+
+// Freshness describes how current the stored games are
+type Freshness struct {
+	// LastScrape is the last successful scrape, zero if none was recorded
+	LastScrape time.Time
+	// Age is the time since LastScrape, zero if none was recorded
+	Age time.Duration
+	// Stale is set once Age exceeds StaleFactor refresh intervals
+	Stale bool
+	// Outage is set when the latest scrape failed because the store itself
+	// was having issues rather than the scraper
+	Outage bool
+}
+
+// OutageMessage is shown on status surfaces while the latest scrape failed
+// with an upstream outage
+const OutageMessage = "Epic Games Store appears to be having issues"
+
+// Warning returns the notice shown with stale data or during a store outage,
+// or "" when the data is fresh
+func (f Freshness) Warning() string {
+	if f.Outage {
+		if f.LastScrape.IsZero() {
+			return "⚠️ " + OutageMessage
+		}
+		return fmt.Sprintf("⚠️ %s — these games are from %s ago", OutageMessage, formatAge(f.Age))
+	}
+	if !f.Stale {
+		return ""
+	}
+	return fmt.Sprintf("⚠️ This data is %s old — Epic may have rotated since", formatAge(f.Age))
+}
+
+// formatAge renders an age in whole hours, or days beyond two days
+func formatAge(age time.Duration) string {
+	hours := int(age / time.Hour)
+	if hours >= 48 {
+		return fmt.Sprintf("%d days", hours/24)
+	}
+	if hours == 1 {
+		return "1 hour"
+	}
+	return fmt.Sprintf("%d hours", hours)
+}
+
+// SetRefreshInterval sets the scheduled check interval freshness is judged by
+func (gs *GameService) SetRefreshInterval(interval time.Duration) {
+	gs.refreshInterval = interval
+}
+
+// Freshness reports how old the stored games are at now. Without a refresh
+// interval or a recorded scrape the data is never reported stale.
+func (gs *GameService) Freshness(now time.Time) (Freshness, error) {
+	runs, err := gs.db.GetScrapeHistory(1)
+	if err != nil {
+		return Freshness{}, err
+	}
+	outage := len(runs) > 0 && runs[0].FailureClass == models.FailureUpstreamOutage
+
+	lastScrape, err := gs.db.GetLastScrapeTime()
+	if err != nil {
+		return Freshness{}, err
+	}
+	if lastScrape.IsZero() {
+		return Freshness{Outage: outage}, nil
+	}
+
+	freshness := freshnessAt(lastScrape, now, gs.refreshInterval)
+	freshness.Outage = outage
+	return freshness, nil
+}
+
+// freshnessAt computes freshness for data scraped at lastScrape. Data exactly
+// StaleFactor intervals old still counts as fresh.
+func freshnessAt(lastScrape, now time.Time, interval time.Duration) Freshness {
+	age := now.Sub(lastScrape)
+	if age < 0 {
+		age = 0
+	}
+	threshold := time.Duration(float64(interval) * StaleFactor)
+	return Freshness{
+		LastScrape: lastScrape,
+		Age:        age,
+		Stale:      interval > 0 && age > threshold,
+	}
+}
+
+// recordSuccessfulScrape stores the time of a scrape whose games were saved
+func (gs *GameService) recordSuccessfulScrape(at time.Time) {
+	if err := gs.db.SetLastScrapeTime(at); err != nil {
+		log.Printf("Warning: failed to record scrape time: %v", err)
+	}
+}
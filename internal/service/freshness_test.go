@@ -0,0 +1,93 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"free-games-scrape/internal/models"
+)
+
+func TestFreshnessAtThreshold(t *testing.T) {
+	lastScrape := time.Date(2025, time.July, 20, 12, 0, 0, 0, time.UTC)
+	const interval = 24 * time.Hour
+	threshold := 36 * time.Hour
+
+	tests := []struct {
+		name      string
+		age       time.Duration
+		interval  time.Duration
+		wantAge   time.Duration
+		wantStale bool
+	}{
+		{name: "just scraped", interval: interval},
+		{name: "one interval", age: interval, interval: interval, wantAge: interval},
+		{name: "just under the threshold", age: threshold - time.Second, interval: interval, wantAge: threshold - time.Second},
+		{name: "exactly the threshold", age: threshold, interval: interval, wantAge: threshold},
+		{name: "just over the threshold", age: threshold + time.Second, interval: interval, wantAge: threshold + time.Second, wantStale: true},
+		{name: "days old", age: 5 * interval, interval: interval, wantAge: 5 * interval, wantStale: true},
+		{name: "no refresh interval", age: 30 * interval, wantAge: 30 * interval},
+		{name: "scrape time ahead of the clock", age: -time.Minute, interval: interval},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := freshnessAt(lastScrape, lastScrape.Add(tt.age), tt.interval)
+			if got.Age != tt.wantAge || got.Stale != tt.wantStale || !got.LastScrape.Equal(lastScrape) {
+				t.Errorf("freshnessAt(age %v) = %+v, want age %v, stale %v", tt.age, got, tt.wantAge, tt.wantStale)
+			}
+		})
+	}
+}
+
+func TestFreshnessWarning(t *testing.T) {
+	tests := []struct {
+		name      string
+		freshness Freshness
+		want      string
+	}{
+		{name: "fresh", freshness: Freshness{LastScrape: time.Now(), Age: time.Hour}},
+		{name: "stale for an hour", freshness: Freshness{LastScrape: time.Now(), Age: time.Hour + time.Minute, Stale: true}, want: "⚠️ This data is 1 hour old — Epic may have rotated since"},
+		{name: "stale in hours", freshness: Freshness{LastScrape: time.Now(), Age: 38 * time.Hour, Stale: true}, want: "⚠️ This data is 38 hours old — Epic may have rotated since"},
+		{name: "stale in days", freshness: Freshness{LastScrape: time.Now(), Age: 50 * time.Hour, Stale: true}, want: "⚠️ This data is 2 days old — Epic may have rotated since"},
+		{name: "outage without data", freshness: Freshness{Outage: true}, want: "⚠️ " + OutageMessage},
+		{name: "outage with data", freshness: Freshness{LastScrape: time.Now(), Age: 3 * time.Hour, Outage: true}, want: "⚠️ " + OutageMessage + " — these games are from 3 hours ago"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.freshness.Warning(); got != tt.want {
+				t.Errorf("Warning() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFreshnessFromSavedGames(t *testing.T) {
+	gs, _ := newTestService(t)
+	gs.SetRefreshInterval(time.Hour)
+
+	freshness, err := gs.Freshness(time.Now())
+	if err != nil {
+		t.Fatalf("Freshness: %v", err)
+	}
+	if freshness.Stale || !freshness.LastScrape.IsZero() {
+		t.Errorf("Freshness before any scrape = %+v, want nothing recorded and not stale", freshness)
+	}
+
+	before := time.Now()
+	if _, err := gs.SaveGames([]models.Game{freeNow("Game")}); err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+
+	if freshness, err = gs.Freshness(time.Now()); err != nil {
+		t.Fatalf("Freshness: %v", err)
+	}
+	if freshness.Stale || freshness.LastScrape.Before(before.Truncate(time.Second)) {
+		t.Errorf("Freshness after saving = %+v, want the save time and not stale", freshness)
+	}
+
+	if freshness, err = gs.Freshness(time.Now().Add(2 * time.Hour)); err != nil {
+		t.Fatalf("Freshness: %v", err)
+	}
+	if !freshness.Stale {
+		t.Errorf("Freshness two intervals later = %+v, want stale", freshness)
+	}
+}
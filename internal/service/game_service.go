@@ -1,35 +1,69 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"strings"
+	"sync"
 	"time"
 
 	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/metrics"
 	"free-games-scrape/internal/models"
 	"free-games-scrape/internal/scraper"
 )
 
 // GameService handles game-related business logic
 type GameService struct {
-	db      *database.Database
-	scraper *scraper.EpicScraper
+	db       *database.Database
+	metrics  *metrics.Metrics
+	scrapers []scraper.Scraper
+
+	// refreshInterval is the scheduled check interval, used to judge freshness
+	refreshInterval time.Duration
+
+	// scrapeMu guards scraping, the scrape in progress if any
+	scrapeMu sync.Mutex
+	scraping *scrapeCall
+	// saveMu serializes saves so two of them never write the games at once
+	saveMu sync.Mutex
+
+	// refreshEdits are the edits saved by manual refreshes since the last
+	// game check took them, guarded by editsMu
+	editsMu      sync.Mutex
+	refreshEdits []models.FieldChange
 }
 
-// NewGameService creates a new game service
-func NewGameService(db *database.Database, scraper *scraper.EpicScraper) *GameService {
+// scrapeCall is a scrape in progress, whose result is shared by every caller
+// that asked for a scrape while it ran. done is closed once it finished.
+type scrapeCall struct {
+	done  chan struct{}
+	games []models.Game
+	err   error
+}
+
+// NewGameService creates a new game service. Games from all scrapers are
+// merged; when scrapers list the same game of one store, the earlier wins.
+func NewGameService(db *database.Database, appMetrics *metrics.Metrics, scrapers ...scraper.Scraper) *GameService {
 	return &GameService{
-		db:      db,
-		scraper: scraper,
+		db:       db,
+		metrics:  appMetrics,
+		scrapers: scrapers,
 	}
 }
 
-// RefreshGames scrapes new games and updates the database
-func (gs *GameService) RefreshGames() error {
+// RefreshGames scrapes new games and updates the database. The edits saved
+// are kept for the next game check (see TakeRefreshEdits), which announces
+// the releases among them and reports them in the ops changelog.
+func (gs *GameService) RefreshGames(ctx context.Context) error {
 	log.Println("Starting game refresh...")
 	
 	// Scrape games from Epic Games Store
-	scrapedGames, err := gs.ScrapeGames()
+	scrapedGames, err := gs.ScrapeGames(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to scrape games: %w", err)
 	}
@@ -40,14 +74,30 @@ func (gs *GameService) RefreshGames() error {
 	}
 
 	// Save games to database
-	if err := gs.SaveGames(scrapedGames); err != nil {
+	edits, err := gs.SaveGames(scrapedGames)
+	if err != nil {
 		return fmt.Errorf("failed to save games to database: %w", err)
 	}
+	gs.editsMu.Lock()
+	gs.refreshEdits = append(gs.refreshEdits, edits...)
+	gs.editsMu.Unlock()
 
 	log.Printf("Successfully refreshed %d games", len(scrapedGames))
 	return nil
 }
 
+// TakeRefreshEdits returns the edits saved by manual refreshes since the
+// last call. A game check saving the same games finds no edit for them, so
+// without these a Coming Soon game that a refresh saw become free would
+// never be announced as released.
+func (gs *GameService) TakeRefreshEdits() []models.FieldChange {
+	gs.editsMu.Lock()
+	defer gs.editsMu.Unlock()
+	edits := gs.refreshEdits
+	gs.refreshEdits = nil
+	return edits
+}
+
 // GetActiveGames returns all currently active games from the database
 func (gs *GameService) GetActiveGames() (*models.GameCollection, error) {
 	games, err := gs.db.GetActiveGames()
@@ -58,6 +108,12 @@ func (gs *GameService) GetActiveGames() (*models.GameCollection, error) {
 	return models.NewGameCollection(games), nil
 }
 
+// GetActiveGamesVersion returns a value that changes whenever the active
+// games may have changed, cheaper to query than the games themselves
+func (gs *GameService) GetActiveGamesVersion() (string, error) {
+	return gs.db.GetActiveGamesVersion()
+}
+
 // GetNewGamesSince returns games that are new since the specified time
 func (gs *GameService) GetNewGamesSince(since time.Time) (*models.GameCollection, error) {
 	games, err := gs.db.GetNewGames(since)
@@ -68,42 +124,261 @@ func (gs *GameService) GetNewGamesSince(since time.Time) (*models.GameCollection
 	return models.NewGameCollection(games), nil
 }
 
+// GetGamesEndingWithin returns active Free Now games whose promotion ends
+// within d from now
+func (gs *GameService) GetGamesEndingWithin(d time.Duration) ([]models.Game, error) {
+	now := time.Now()
+	games, err := gs.db.GetGamesEndingBetween(now, now.Add(d))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ending games: %w", err)
+	}
+	return games, nil
+}
+
+// GetUnnotifiedGames returns the scraped games that have not been announced
+// yet. The games must already be saved.
+func (gs *GameService) GetUnnotifiedGames(games []models.Game) (*models.GameCollection, error) {
+	unnotified, err := gs.db.GetUnnotifiedGames(games)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unnotified games: %w", err)
+	}
+
+	return models.NewGameCollection(unnotified), nil
+}
+
+// MarkGamesNotified records that every game in the collection was announced
+func (gs *GameService) MarkGamesNotified(gameCollection *models.GameCollection) error {
+	if err := gs.db.MarkGamesNotified(gameCollection.All()); err != nil {
+		return fmt.Errorf("failed to mark games as notified: %w", err)
+	}
+	return nil
+}
+
+// GetGameHistory returns games first seen between start and end
+func (gs *GameService) GetGameHistory(start, end time.Time) ([]models.Game, error) {
+	if !end.After(start) {
+		return nil, fmt.Errorf("invalid history range: end must be after start")
+	}
+
+	games, err := gs.db.GetGameHistory(start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game history: %w", err)
+	}
+
+	return games, nil
+}
+
 // GetGameByTitle retrieves a specific game by title
 func (gs *GameService) GetGameByTitle(title string) (*models.Game, error) {
 	return gs.db.GetGameByTitle(title)
 }
 
-// ShouldRefresh determines if games should be refreshed based on cache age
+// ShouldRefresh reports whether the last successful scrape, which survives
+// restarts, is at least maxAge old or was never recorded
 func (gs *GameService) ShouldRefresh(maxAge time.Duration) (bool, error) {
-	// For now, we'll refresh based on time intervals
-	// In a more sophisticated implementation, you could track last refresh time in the database
-	return true, nil
+	lastScrape, err := gs.db.GetLastScrapeTime()
+	if err != nil {
+		return true, fmt.Errorf("failed to get last scrape time: %w", err)
+	}
+	return lastScrape.IsZero() || time.Since(lastScrape) >= maxAge, nil
 }
 
-// ScrapeGames scrapes games from Epic Games Store without saving to database
-func (gs *GameService) ScrapeGames() ([]models.Game, error) {
-	log.Println("Scraping games from Epic Games Store...")
-	
-	scrapedGames, err := gs.scraper.ScrapeGames()
-	if err != nil {
-		return nil, fmt.Errorf("failed to scrape games: %w", err)
+// ScrapeGames scrapes games from every configured source without saving to
+// the database. A failing source is logged and skipped; an error is returned
+// only when every source fails or ctx is cancelled, wrapping
+// models.ErrScraperUnavailable when this host can't scrape. Only one scrape
+// runs at a time, so the scheduled check and /refresh never launch two
+// browsers: a caller arriving while a scrape runs waits for it and gets its
+// result.
+func (gs *GameService) ScrapeGames(ctx context.Context) ([]models.Game, error) {
+	if len(gs.scrapers) == 0 {
+		return nil, fmt.Errorf("%w: SCRAPING_ENABLED is false", models.ErrScraperUnavailable)
+	}
+
+	gs.scrapeMu.Lock()
+	if call := gs.scraping; call != nil {
+		gs.scrapeMu.Unlock()
+		log.Println("A scrape is already in progress, waiting for its result")
+		select {
+		case <-call.done:
+			return append([]models.Game(nil), call.games...), call.err
+		case <-ctx.Done():
+			return nil, fmt.Errorf("scraping cancelled: %w", ctx.Err())
+		}
+	}
+	call := &scrapeCall{done: make(chan struct{})}
+	gs.scraping = call
+	gs.scrapeMu.Unlock()
+
+	call.games, call.err = gs.scrape(ctx)
+
+	gs.scrapeMu.Lock()
+	gs.scraping = nil
+	gs.scrapeMu.Unlock()
+	close(call.done)
+	return append([]models.Game(nil), call.games...), call.err
+}
+
+// scrape runs the scrapers for ScrapeGames and records the run
+func (gs *GameService) scrape(ctx context.Context) (scrapedGames []models.Game, err error) {
+	log.Printf("Scraping games from %d source(s)...", len(gs.scrapers))
+
+	start := time.Now()
+	defer func() {
+		// Nothing was scraped, so there is no run to record
+		if errors.Is(err, models.ErrScraperUnavailable) {
+			return
+		}
+		duration := time.Since(start)
+		gs.metrics.SetLastScrapeTime(err == nil, duration)
+		if err != nil {
+			gs.metrics.IncrementErrors()
+		} else {
+			gs.metrics.IncrementGamesScraped(int64(len(scrapedGames)))
+		}
+		run := database.ScrapeRun{StartedAt: start, Success: err == nil, GamesFound: len(scrapedGames), Duration: duration, FailureClass: models.ClassifyScrapeFailure(err)}
+		if recordErr := gs.db.RecordScrape(run); recordErr != nil {
+			log.Printf("Warning: failed to record scrape history: %v", recordErr)
+		}
+	}()
+
+	var results [][]models.Game
+	var lastErr error
+	for _, s := range gs.scrapers {
+		games, err := s.ScrapeGames(ctx)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("scraping cancelled: %w", ctx.Err())
+		}
+		if err != nil {
+			log.Printf("Scraper %T failed: %v", s, err)
+			lastErr = err
+			continue
+		}
+		results = append(results, games)
+	}
+
+	if len(results) == 0 && lastErr != nil {
+		return nil, fmt.Errorf("failed to scrape games: %w", lastErr)
 	}
 
+	scrapedGames = mergeGames(results...)
 	log.Printf("Successfully scraped %d games", len(scrapedGames))
 	return scrapedGames, nil
 }
 
-// SaveGames saves games to the database
-func (gs *GameService) SaveGames(games []models.Game) error {
-	if err := gs.db.SaveGames(games); err != nil {
-		return fmt.Errorf("failed to save games to database: %w", err)
+// mergeGames concatenates scrape results, dropping games of a store already
+// listed by an earlier result. A game given away by two stores is kept once
+// per store.
+func mergeGames(results ...[]models.Game) []models.Game {
+	seen := make(map[string]bool)
+	var merged []models.Game
+	for _, games := range results {
+		for _, game := range games {
+			key := game.StoreSource() + "|" + strings.ToLower(strings.TrimSpace(game.Title))
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, game)
+		}
 	}
+	return merged
+}
+
+// SaveGames saves games to the database, returning the recorded edits of
+// games that were already stored. Saves run one at a time.
+func (gs *GameService) SaveGames(games []models.Game) ([]models.FieldChange, error) {
+	gs.saveMu.Lock()
+	defer gs.saveMu.Unlock()
+
+	changes, err := gs.db.SaveGames(games)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save games to database: %w", err)
+	}
+	gs.recordSuccessfulScrape(time.Now())
 
 	// Cleanup old games
 	if err := gs.db.CleanupOldGames(); err != nil {
 		log.Printf("Warning: failed to cleanup old games: %v", err)
 	}
+	if err := gs.db.CleanupOldNotifications(); err != nil {
+		log.Printf("Warning: failed to cleanup sent notifications: %v", err)
+	}
+	if err := gs.db.CleanupOldGameChanges(); err != nil {
+		log.Printf("Warning: failed to cleanup game changes: %v", err)
+	}
+	if err := gs.db.CleanupOldPostedMessages(); err != nil {
+		log.Printf("Warning: failed to cleanup posted messages: %v", err)
+	}
 
 	log.Printf("Successfully saved %d games to database", len(games))
-	return nil
-}
\ No newline at end of file
+	return changes, nil
+}
+
+// GetScrapeHistory returns up to limit scrape runs, most recent first
+func (gs *GameService) GetScrapeHistory(limit int) ([]database.ScrapeRun, error) {
+	runs, err := gs.db.GetScrapeHistory(limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scrape history: %w", err)
+	}
+	return runs, nil
+}
+
+// GetGameChanges returns a game's recorded edits and its title, which is
+// empty when no game has the ID
+func (gs *GameService) GetGameChanges(gameID int64) (string, []models.FieldChange, error) {
+	title, changes, err := gs.db.GetGameChanges(gameID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get game changes: %w", err)
+	}
+	return title, changes, nil
+}
+
+// WriteSnapshot writes the whole games catalog as JSON and returns the number of games written
+func (gs *GameService) WriteSnapshot(w io.Writer) (int, error) {
+	games, err := gs.db.GetSnapshotGames()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load games for snapshot: %w", err)
+	}
+
+	snapshot := models.GameSnapshot{
+		CreatedAt: time.Now().UTC(),
+		Games:     games,
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snapshot); err != nil {
+		return 0, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	return len(games), nil
+}
+
+// RestoreSnapshot validates a JSON snapshot and replaces the games catalog with it
+func (gs *GameService) RestoreSnapshot(r io.Reader) (int, error) {
+	var snapshot models.GameSnapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return 0, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	seen := make(map[string]bool, len(snapshot.Games))
+	for i := range snapshot.Games {
+		game := &snapshot.Games[i]
+		if err := game.Validate(); err != nil {
+			return 0, fmt.Errorf("invalid game at index %d: %w", i, err)
+		}
+
+		key := game.SourceKey() + "|" + game.FreeTo
+		if seen[key] {
+			return 0, fmt.Errorf("duplicate game at index %d: %s", i, game.Title)
+		}
+		seen[key] = true
+	}
+
+	if err := gs.db.ReplaceAllGames(snapshot.Games); err != nil {
+		return 0, fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+
+	return len(snapshot.Games), nil
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/metrics"
+	"free-games-scrape/internal/models"
+	"free-games-scrape/internal/scraper"
+)
+
+// fakeScraper returns games, counting its calls. When release is set, each
+// call signals started and blocks until release is closed.
+type fakeScraper struct {
+	games   []models.Game
+	err     error
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (f *fakeScraper) ScrapeGames(ctx context.Context) ([]models.Game, error) {
+	f.calls.Add(1)
+	if f.release != nil {
+		f.started <- struct{}{}
+		select {
+		case <-f.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return append([]models.Game(nil), f.games...), f.err
+}
+
+// newTestService returns a service over a private in-memory database
+func newTestService(t *testing.T, scrapers ...*fakeScraper) (*GameService, *database.Database) {
+	t.Helper()
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := database.New(fmt.Sprintf("file:%s?mode=memory&cache=shared", name))
+	if err != nil {
+		t.Fatalf("database.New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	var injected []scraper.Scraper
+	for _, s := range scrapers {
+		injected = append(injected, s)
+	}
+	return NewGameService(db, metrics.New(), injected...), db
+}
+
+func freeNow(title string) models.Game {
+	return models.Game{Title: title, Status: models.StatusFreeNow, FreeFrom: "Jan 01", FreeTo: "Jan 08"}
+}
+
+func TestScrapeGamesSharesInFlightScrape(t *testing.T) {
+	const callers = 20
+	fake := &fakeScraper{
+		games:   []models.Game{freeNow("Shared Game")},
+		started: make(chan struct{}, callers),
+		release: make(chan struct{}),
+	}
+	gs, _ := newTestService(t, fake)
+
+	var ready, done sync.WaitGroup
+	results := make([][]models.Game, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		ready.Add(1)
+		done.Add(1)
+		go func(i int) {
+			defer done.Done()
+			ready.Done()
+			results[i], errs[i] = gs.ScrapeGames(context.Background())
+		}(i)
+	}
+	ready.Wait()
+	<-fake.started
+	// Give every caller time to find the scrape in flight before it ends
+	time.Sleep(50 * time.Millisecond)
+	close(fake.release)
+	done.Wait()
+
+	if got := fake.calls.Load(); got != 1 {
+		t.Fatalf("scraper called %d times by %d concurrent callers, want 1", got, callers)
+	}
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil {
+			t.Errorf("caller %d: %v", i, errs[i])
+			continue
+		}
+		if len(results[i]) != 1 || results[i][0].Title != "Shared Game" {
+			t.Errorf("caller %d got %v, want the shared result", i, results[i])
+		}
+	}
+
+	// Callers get their own copy of the shared slice
+	results[0][0].Title = "Changed"
+	if results[1][0].Title != "Shared Game" {
+		t.Error("changing one caller's games changed another's")
+	}
+}
+
+func TestRefreshGamesRunsOneScrape(t *testing.T) {
+	const callers = 10
+	fake := &fakeScraper{
+		games:   []models.Game{freeNow("Shared Game")},
+		started: make(chan struct{}, callers),
+		release: make(chan struct{}),
+	}
+	gs, db := newTestService(t, fake)
+
+	var done sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			errs <- gs.RefreshGames(context.Background())
+		}()
+	}
+	<-fake.started
+	time.Sleep(50 * time.Millisecond)
+	close(fake.release)
+	done.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("RefreshGames: %v", err)
+		}
+	}
+	if got := fake.calls.Load(); got != 1 {
+		t.Errorf("scraper called %d times by %d concurrent refreshes, want 1", got, callers)
+	}
+	games, err := db.GetActiveGames()
+	if err != nil {
+		t.Fatalf("GetActiveGames: %v", err)
+	}
+	if len(games) != 1 {
+		t.Errorf("stored %d games, want 1", len(games))
+	}
+}
+
+func TestScrapeGamesAfterInFlightScrape(t *testing.T) {
+	fake := &fakeScraper{games: []models.Game{freeNow("Game")}}
+	gs, _ := newTestService(t, fake)
+
+	for i := 0; i < 3; i++ {
+		if _, err := gs.ScrapeGames(context.Background()); err != nil {
+			t.Fatalf("ScrapeGames: %v", err)
+		}
+	}
+	// Sequential calls each run their own scrape
+	if got := fake.calls.Load(); got != 3 {
+		t.Errorf("scraper called %d times by 3 sequential calls, want 3", got)
+	}
+}
+
+func TestScrapeGamesWaiterCancelled(t *testing.T) {
+	fake := &fakeScraper{
+		games:   []models.Game{freeNow("Game")},
+		started: make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+	gs, _ := newTestService(t, fake)
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := gs.ScrapeGames(context.Background())
+		first <- err
+	}()
+	<-fake.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := gs.ScrapeGames(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waiting caller got %v, want context.DeadlineExceeded", err)
+	}
+
+	// The scrape in flight is not affected by the waiter giving up
+	close(fake.release)
+	if err := <-first; err != nil {
+		t.Errorf("first caller: %v", err)
+	}
+}
+
+func TestScrapeGamesWithoutScrapers(t *testing.T) {
+	gs, _ := newTestService(t)
+	if _, err := gs.ScrapeGames(context.Background()); !errors.Is(err, models.ErrScraperUnavailable) {
+		t.Errorf("ScrapeGames = %v, want ErrScraperUnavailable", err)
+	}
+}
+
+func comingSoon(title string) models.Game {
+	return models.Game{Title: title, Status: models.StatusComingSoon, FreeFrom: "Jan 08", FreeTo: "Jan 15"}
+}
+
+func TestRefreshGamesStoresScrapedGames(t *testing.T) {
+	fake := &fakeScraper{games: []models.Game{freeNow("Free Game"), comingSoon("Next Game")}}
+	gs, _ := newTestService(t, fake)
+
+	if err := gs.RefreshGames(context.Background()); err != nil {
+		t.Fatalf("RefreshGames: %v", err)
+	}
+
+	active, err := gs.GetActiveGames()
+	if err != nil {
+		t.Fatalf("GetActiveGames: %v", err)
+	}
+	if len(active.FreeNow) != 1 || active.FreeNow[0].Title != "Free Game" {
+		t.Errorf("FreeNow = %v, want Free Game", active.FreeNow)
+	}
+	if len(active.ComingSoon) != 1 || active.ComingSoon[0].Title != "Next Game" {
+		t.Errorf("ComingSoon = %v, want Next Game", active.ComingSoon)
+	}
+}
+
+func TestRefreshGamesScrapeFailure(t *testing.T) {
+	scrapeErr := errors.New("page did not load")
+	fake := &fakeScraper{err: scrapeErr}
+	gs, _ := newTestService(t, fake)
+
+	if err := gs.RefreshGames(context.Background()); !errors.Is(err, scrapeErr) {
+		t.Errorf("RefreshGames = %v, want the scraper's error", err)
+	}
+	active, err := gs.GetActiveGames()
+	if err != nil {
+		t.Fatalf("GetActiveGames: %v", err)
+	}
+	if len(active.All()) != 0 {
+		t.Errorf("stored %v after a failed scrape", active.All())
+	}
+}
+
+func TestRefreshGamesMergesScrapers(t *testing.T) {
+	failing := &fakeScraper{err: errors.New("blocked")}
+	epic := &fakeScraper{games: []models.Game{freeNow("Epic Game")}}
+	other := &fakeScraper{games: []models.Game{freeNow("Epic Game"), freeNow("Other Game")}}
+	gs, _ := newTestService(t, failing, epic, other)
+
+	// One scraper failing doesn't fail the refresh while others succeed
+	if err := gs.RefreshGames(context.Background()); err != nil {
+		t.Fatalf("RefreshGames: %v", err)
+	}
+	active, err := gs.GetActiveGames()
+	if err != nil {
+		t.Fatalf("GetActiveGames: %v", err)
+	}
+	if got := len(active.FreeNow); got != 2 {
+		t.Errorf("stored %d Free Now games, want 2 with the duplicate merged", got)
+	}
+}
+
+// TestNewGamesAcrossChecks follows the steps of a game check: scrape, save,
+// look up the games not announced yet and mark them announced
+func TestNewGamesAcrossChecks(t *testing.T) {
+	fake := &fakeScraper{games: []models.Game{freeNow("First Game")}}
+	gs, _ := newTestService(t, fake)
+
+	check := func() []models.Game {
+		t.Helper()
+		scraped, err := gs.ScrapeGames(context.Background())
+		if err != nil {
+			t.Fatalf("ScrapeGames: %v", err)
+		}
+		if _, err := gs.SaveGames(scraped); err != nil {
+			t.Fatalf("SaveGames: %v", err)
+		}
+		newGames, err := gs.GetUnnotifiedGames(scraped)
+		if err != nil {
+			t.Fatalf("GetUnnotifiedGames: %v", err)
+		}
+		if err := gs.MarkGamesNotified(newGames); err != nil {
+			t.Fatalf("MarkGamesNotified: %v", err)
+		}
+		return newGames.All()
+	}
+
+	if got := check(); len(got) != 1 || got[0].Title != "First Game" {
+		t.Errorf("first check found %v, want First Game", got)
+	}
+	if got := check(); len(got) != 0 {
+		t.Errorf("repeated check found %v, want nothing new", got)
+	}
+
+	fake.games = append(fake.games, comingSoon("Second Game"))
+	if got := check(); len(got) != 1 || got[0].Title != "Second Game" {
+		t.Errorf("check after a new game found %v, want Second Game", got)
+	}
+
+	// The same title given away again with new dates is a new promotion
+	fake.games = []models.Game{{Title: "First Game", Status: models.StatusFreeNow, FreeFrom: "Feb 01", FreeTo: "Feb 08"}}
+	if got := check(); len(got) != 1 || got[0].FreeTo != "Feb 08" {
+		t.Errorf("check after a repeat giveaway found %v, want First Game until Feb 08", got)
+	}
+}
+
+// TestStatusTransitionsAcrossChecks follows two game checks over fixture
+// data: a game that is new, one that flips from Coming Soon to Free Now, one
+// that is unchanged and one whose promotion ended
+func TestStatusTransitionsAcrossChecks(t *testing.T) {
+	flipping := models.Game{Title: "Flipping Game", Status: models.StatusComingSoon, FreeFrom: "Jan 08", FreeTo: "Jan 15"}
+	unchanged := freeNow("Unchanged Game")
+	expired := models.Game{Title: "Expired Game", Status: models.StatusFreeNow, FreeFrom: "Jan 01", FreeTo: "Jan 08"}
+	fake := &fakeScraper{games: []models.Game{flipping, unchanged, expired}}
+	gs, _ := newTestService(t, fake)
+
+	// check returns the games a game check announces as new and as released
+	check := func() (newGames, released []models.Game) {
+		t.Helper()
+		scraped, err := gs.ScrapeGames(context.Background())
+		if err != nil {
+			t.Fatalf("ScrapeGames: %v", err)
+		}
+		edits, err := gs.SaveGames(scraped)
+		if err != nil {
+			t.Fatalf("SaveGames: %v", err)
+		}
+		edits = append(gs.TakeRefreshEdits(), edits...)
+		unnotified, err := gs.GetUnnotifiedGames(scraped)
+		if err != nil {
+			t.Fatalf("GetUnnotifiedGames: %v", err)
+		}
+		if err := gs.MarkGamesNotified(unnotified); err != nil {
+			t.Fatalf("MarkGamesNotified: %v", err)
+		}
+		return unnotified.All(), models.ReleasedGames(scraped, edits)
+	}
+	titlesOf := func(games []models.Game) []string {
+		var titles []string
+		for _, game := range games {
+			titles = append(titles, game.Title)
+		}
+		return titles
+	}
+
+	if newGames, released := check(); len(newGames) != 3 || len(released) != 0 {
+		t.Fatalf("first check announced %v new and %v released, want all three new", titlesOf(newGames), titlesOf(released))
+	}
+
+	nowFree := flipping
+	nowFree.Status = models.StatusFreeNow
+	fake.games = []models.Game{nowFree, unchanged, freeNow("New Game")}
+	newGames, released := check()
+	if got := titlesOf(newGames); len(got) != 1 || got[0] != "New Game" {
+		t.Errorf("second check announced %v as new, want only New Game", got)
+	}
+	if got := titlesOf(released); len(got) != 1 || got[0] != "Flipping Game" {
+		t.Errorf("second check announced %v as released, want only Flipping Game", got)
+	}
+
+	// Nothing is announced again by the next check
+	if newGames, released := check(); len(newGames) != 0 || len(released) != 0 {
+		t.Errorf("third check announced %v new and %v released, want nothing", titlesOf(newGames), titlesOf(released))
+	}
+}
+
+func TestStatusTransitionSeenByRefresh(t *testing.T) {
+	upcoming := models.Game{Title: "Flipping Game", Status: models.StatusComingSoon, FreeFrom: "Jan 08", FreeTo: "Jan 15"}
+	fake := &fakeScraper{games: []models.Game{upcoming}}
+	gs, _ := newTestService(t, fake)
+
+	scraped, err := gs.ScrapeGames(context.Background())
+	if err != nil {
+		t.Fatalf("ScrapeGames: %v", err)
+	}
+	if _, err := gs.SaveGames(scraped); err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+
+	// A manual refresh saves the flip before the next game check runs
+	nowFree := upcoming
+	nowFree.Status = models.StatusFreeNow
+	fake.games = []models.Game{nowFree}
+	if err := gs.RefreshGames(context.Background()); err != nil {
+		t.Fatalf("RefreshGames: %v", err)
+	}
+
+	scraped, err = gs.ScrapeGames(context.Background())
+	if err != nil {
+		t.Fatalf("ScrapeGames: %v", err)
+	}
+	edits, err := gs.SaveGames(scraped)
+	if err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+	if len(edits) != 0 {
+		t.Errorf("the game check recorded %v again", edits)
+	}
+	edits = append(gs.TakeRefreshEdits(), edits...)
+	if released := models.ReleasedGames(scraped, edits); len(released) != 1 {
+		t.Errorf("ReleasedGames = %v, want the game the refresh saw flip", released)
+	}
+	if edits := gs.TakeRefreshEdits(); len(edits) != 0 {
+		t.Errorf("TakeRefreshEdits returned %v twice", edits)
+	}
+}
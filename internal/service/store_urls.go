@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+// StoreURLSearcher finds store pages by game title
+type StoreURLSearcher interface {
+	Search(ctx context.Context, title string) ([]models.StoreCandidate, error)
+}
+
+// BackfillNextStoreURL looks up the store link of one stored game without
+// one and returns the outcome, one of the database.StoreURL* outcomes, or ""
+// when no game is waiting. Progress is kept in the database, so the backfill
+// resumes where it stopped after a restart.
+func (gs *GameService) BackfillNextStoreURL(ctx context.Context, searcher StoreURLSearcher) (string, error) {
+	title, err := gs.db.NextStoreURLLookup()
+	if err != nil || title == "" {
+		return "", err
+	}
+
+	outcome, storeURL := database.StoreURLAmbiguous, ""
+	candidates, searchErr := searcher.Search(ctx, title)
+	if searchErr != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		outcome = database.StoreURLFailed
+	} else if url, ok := models.MatchStoreURL(title, candidates); ok {
+		outcome, storeURL = database.StoreURLResolved, url
+	}
+
+	if err := gs.db.RecordStoreURLLookup(title, outcome, storeURL); err != nil {
+		return "", err
+	}
+	gs.metrics.IncrementStoreURLLookups(outcome)
+
+	switch outcome {
+	case database.StoreURLResolved:
+		log.Printf("Backfilled store URL of %s: %s", title, storeURL)
+	case database.StoreURLAmbiguous:
+		log.Printf("No confident store URL match for %s among %d results; left for /admin linkgame", title, len(candidates))
+	case database.StoreURLFailed:
+		return outcome, fmt.Errorf("store URL lookup for %s failed: %w", title, searchErr)
+	}
+	return outcome, nil
+}
+
+// GetAmbiguousStoreURLGames returns games whose store link must be set by hand
+func (gs *GameService) GetAmbiguousStoreURLGames(limit int) ([]models.Game, error) {
+	return gs.db.GetAmbiguousStoreURLGames(limit)
+}
+
+// SetGameStoreURL sets a store link by hand, returning the game's title or ""
+// when no game has id
+func (gs *GameService) SetGameStoreURL(id int64, storeURL string) (string, error) {
+	return gs.db.SetGameStoreURL(id, storeURL)
+}
@@ -0,0 +1,51 @@
+package setup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// tokenCheckTimeout bounds the live token check
+const tokenCheckTimeout = 15 * time.Second
+
+// ErrTokenRejected means Discord refused the token, as opposed to the check
+// failing for network reasons
+var ErrTokenRejected = errors.New("discord rejected the token")
+
+// BotIdentity is the bot account a token belongs to
+type BotIdentity struct {
+	ID       string
+	Username string
+}
+
+// VerifyToken checks a bot token against the Discord API by fetching the
+// token's own user. It returns ErrTokenRejected when Discord answers 401.
+func VerifyToken(ctx context.Context, token string) (*BotIdentity, error) {
+	session, err := discordgo.New("Bot " + token)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Discord session: %w", err)
+	}
+	session.MaxRestRetries = 0
+
+	ctx, cancel := context.WithTimeout(ctx, tokenCheckTimeout)
+	defer cancel()
+
+	user, err := session.User("@me", discordgo.WithContext(ctx))
+	if err != nil {
+		var restErr *discordgo.RESTError
+		if errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusUnauthorized {
+			return nil, ErrTokenRejected
+		}
+		return nil, fmt.Errorf("error contacting Discord: %w", err)
+	}
+
+	if !user.Bot {
+		return nil, fmt.Errorf("token belongs to user %s, not a bot account", user.Username)
+	}
+	return &BotIdentity{ID: user.ID, Username: user.Username}, nil
+}
@@ -0,0 +1,79 @@
+package setup
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Values are the settings setup writes to the .env file
+type Values struct {
+	Token     string
+	ClientID  string
+	ChannelID string
+	// ChromePath is empty when no Chrome is used; the file then selects
+	// SCRAPER_MODE=api
+	ChromePath string
+	WebPort    string
+}
+
+// RenderEnv formats values as a .env file. Settings not collected by setup
+// are left to their defaults; see .env.example for the full list.
+func RenderEnv(values Values, now time.Time) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Generated by `free-games-bot setup` on %s\n", now.Format("2006-01-02 15:04"))
+	b.WriteString("# See .env.example for every available setting.\n\n")
+
+	b.WriteString("# Discord Bot Configuration\n")
+	writeEnvLine(&b, "DISCORD_BOT_TOKEN", values.Token)
+	writeEnvLine(&b, "DISCORD_CLIENT_ID", values.ClientID)
+	if values.ChannelID != "" {
+		writeEnvLine(&b, "DISCORD_CHANNEL_ID", values.ChannelID)
+	} else {
+		b.WriteString("# DISCORD_CHANNEL_ID=your_discord_channel_id_here\n")
+	}
+
+	b.WriteString("\n# Scraper Configuration\n")
+	if values.ChromePath != "" {
+		writeEnvLine(&b, "SCRAPER_MODE", "auto")
+		writeEnvLine(&b, "CHROME_PATH", values.ChromePath)
+	} else {
+		writeEnvLine(&b, "SCRAPER_MODE", "api")
+	}
+
+	b.WriteString("\n# Web Server Configuration\n")
+	writeEnvLine(&b, "WEB_PORT", values.WebPort)
+
+	return b.String()
+}
+
+// WriteEnv writes the rendered .env file readable only by its owner, since it
+// contains the bot token
+func WriteEnv(path string, values Values) error {
+	if err := os.WriteFile(path, []byte(RenderEnv(values, time.Now())), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeEnvLine writes KEY=value, quoting values godotenv would otherwise
+// misread
+func writeEnvLine(b *strings.Builder, key, value string) {
+	fmt.Fprintf(b, "%s=%s\n", key, quoteEnvValue(value))
+}
+
+// quoteEnvValue single-quotes values containing spaces, comment markers,
+// quotes or backslashes. Single-quoted values are taken literally, which keeps
+// Windows paths intact.
+func quoteEnvValue(value string) string {
+	if !strings.ContainsAny(value, " \t#'\"\\$=") {
+		return value
+	}
+	if !strings.Contains(value, "'") {
+		return "'" + value + "'"
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`).Replace(value)
+	return `"` + escaped + `"`
+}
@@ -0,0 +1,153 @@
+package setup
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+)
+
+// Prompter asks the operator for setup values
+type Prompter interface {
+	// Prompt asks for a value, returning def when the answer is empty
+	Prompt(label, def string) (string, error)
+	// PromptSecret asks for a value without echoing it to the terminal
+	PromptSecret(label string) (string, error)
+	// Confirm asks a yes/no question
+	Confirm(label string, def bool) (bool, error)
+}
+
+// TerminalPrompter prompts on a terminal. Secrets are read with echo turned
+// off through stty; where that is unavailable the input stays visible and a
+// warning is printed.
+type TerminalPrompter struct {
+	in    *bufio.Reader
+	out   io.Writer
+	stdin *os.File
+}
+
+// NewTerminalPrompter creates a prompter reading from stdin and writing to out
+func NewTerminalPrompter(stdin *os.File, out io.Writer) *TerminalPrompter {
+	return &TerminalPrompter{
+		in:    bufio.NewReader(stdin),
+		out:   out,
+		stdin: stdin,
+	}
+}
+
+// Prompt asks for a value, returning def when the answer is empty
+func (p *TerminalPrompter) Prompt(label, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", label, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", label)
+	}
+
+	answer, err := p.readLine()
+	if err != nil {
+		return "", err
+	}
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// PromptSecret asks for a value with terminal echo disabled
+func (p *TerminalPrompter) PromptSecret(label string) (string, error) {
+	fmt.Fprintf(p.out, "%s: ", label)
+
+	restore, err := p.disableEcho()
+	if err != nil {
+		fmt.Fprintf(p.out, "\n  (cannot hide input on this terminal, it will be visible)\n%s: ", label)
+	} else {
+		defer func() {
+			restore()
+			fmt.Fprintln(p.out)
+		}()
+	}
+
+	return p.readLine()
+}
+
+// Confirm asks a yes/no question
+func (p *TerminalPrompter) Confirm(label string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+
+	for {
+		fmt.Fprintf(p.out, "%s [%s]: ", label, hint)
+		answer, err := p.readLine()
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.out, "  Please answer y or n.")
+	}
+}
+
+// readLine reads one trimmed line of input
+func (p *TerminalPrompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// disableEcho turns terminal echo off and returns a function restoring it.
+// Echo is also restored if the user interrupts the prompt.
+func (p *TerminalPrompter) disableEcho() (func(), error) {
+	if runtime.GOOS == "windows" {
+		return nil, fmt.Errorf("stty is not available on windows")
+	}
+	if info, err := p.stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil, fmt.Errorf("stdin is not a terminal")
+	}
+	if err := p.stty("-echo"); err != nil {
+		return nil, err
+	}
+
+	interrupts := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(interrupts, os.Interrupt)
+	go func() {
+		select {
+		case <-interrupts:
+			p.stty("echo")
+			fmt.Fprintln(p.out)
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(interrupts)
+		close(done)
+		p.stty("echo")
+	}, nil
+}
+
+// stty runs stty against the prompter's terminal
+func (p *TerminalPrompter) stty(args ...string) error {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = p.stdin
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("stty %s failed: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
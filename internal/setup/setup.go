@@ -0,0 +1,446 @@
+// Package setup implements the `setup` subcommand, which walks self-hosters
+// through creating a .env file
+package setup
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/security"
+	"github.com/joho/godotenv"
+)
+
+// defaultWebPort matches the WEB_PORT default in config
+const defaultWebPort = "3000"
+
+// noChrome is the answer to the Chrome prompt selecting API-only scraping
+const noChrome = "none"
+
+// Options control a setup run. In non-interactive mode every value comes from
+// here; interactively they are used as prompt defaults.
+type Options struct {
+	Token      string
+	ClientID   string
+	ChannelID  string
+	ChromePath string
+	WebPort    string
+	Output     string
+
+	NonInteractive bool
+	Force          bool
+	SkipVerify     bool
+	Check          bool
+}
+
+// Main runs the setup subcommand with its command line arguments and returns
+// the process exit code. The token is never accepted as a flag so it does not
+// end up in shell history: it is prompted for, read from DISCORD_BOT_TOKEN or
+// read from -token-file.
+func Main(args []string) int {
+	fs := flag.NewFlagSet("setup", flag.ContinueOnError)
+	opts := Options{}
+	tokenFile := ""
+	fs.StringVar(&opts.Output, "output", ".env", "path of the .env file to write")
+	fs.BoolVar(&opts.NonInteractive, "non-interactive", false, "take every value from flags and the environment instead of prompting")
+	fs.StringVar(&tokenFile, "token-file", "", "read the bot token from this file (- for stdin) instead of DISCORD_BOT_TOKEN")
+	fs.StringVar(&opts.ClientID, "client-id", os.Getenv("DISCORD_CLIENT_ID"), "Discord application (client) ID")
+	fs.StringVar(&opts.ChannelID, "channel-id", os.Getenv("DISCORD_CHANNEL_ID"), "optional legacy notification channel ID")
+	fs.StringVar(&opts.ChromePath, "chrome-path", os.Getenv("CHROME_PATH"), "Chrome/Chromium executable, or \"none\" for API-only scraping")
+	fs.StringVar(&opts.WebPort, "web-port", os.Getenv("WEB_PORT"), "port for the web server (default 3000)")
+	fs.BoolVar(&opts.Force, "force", false, "overwrite an existing .env file")
+	fs.BoolVar(&opts.SkipVerify, "skip-verify", false, "do not check the token against the Discord API")
+	fs.BoolVar(&opts.Check, "check", false, "check the written configuration (non-interactive mode)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	opts.Token = os.Getenv("DISCORD_BOT_TOKEN")
+	if tokenFile != "" {
+		token, err := readTokenFile(tokenFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Setup failed: %v\n", err)
+			return 1
+		}
+		opts.Token = token
+	}
+
+	if err := Run(context.Background(), opts, NewTerminalPrompter(os.Stdin, os.Stdout), os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Setup failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// Run collects the settings, verifies the token and writes the .env file.
+// The prompter is only used when opts.NonInteractive is false.
+func Run(ctx context.Context, opts Options, prompter Prompter, out io.Writer) error {
+	if opts.Output == "" {
+		opts.Output = ".env"
+	}
+
+	var (
+		values Values
+		err    error
+	)
+	if opts.NonInteractive {
+		values, err = collectFromOptions(ctx, opts, out)
+	} else {
+		values, err = collectInteractive(ctx, opts, prompter, out)
+	}
+	if err != nil {
+		return err
+	}
+	if values == (Values{}) {
+		return nil
+	}
+
+	if err := WriteEnv(opts.Output, values); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\nWrote %s\n", opts.Output)
+
+	check := opts.Check
+	if !opts.NonInteractive {
+		if check, err = prompter.Confirm("Check the configuration now?", true); err != nil {
+			return err
+		}
+	}
+	if check {
+		if !RunChecks(opts.Output, out) {
+			return fmt.Errorf("configuration checks failed")
+		}
+	}
+
+	fmt.Fprintln(out, "\nStart the bot, then run /setup #channel in your Discord server.")
+	return nil
+}
+
+// collectFromOptions validates values given on the command line
+func collectFromOptions(ctx context.Context, opts Options, out io.Writer) (Values, error) {
+	if _, err := os.Stat(opts.Output); err == nil && !opts.Force {
+		return Values{}, fmt.Errorf("%s already exists (use -force to overwrite)", opts.Output)
+	}
+
+	if opts.Token == "" {
+		return Values{}, fmt.Errorf("no bot token: set DISCORD_BOT_TOKEN or use -token-file")
+	}
+	if err := security.ValidateDiscordToken(opts.Token); err != nil {
+		return Values{}, err
+	}
+
+	clientID := opts.ClientID
+	if !opts.SkipVerify {
+		bot, err := VerifyToken(ctx, opts.Token)
+		if err != nil {
+			return Values{}, err
+		}
+		fmt.Fprintf(out, "Token belongs to bot %s (%s)\n", bot.Username, bot.ID)
+		if clientID == "" {
+			clientID = bot.ID
+		}
+	}
+	if clientID == "" {
+		return Values{}, fmt.Errorf("no client ID: use -client-id")
+	}
+	if err := security.ValidateDiscordID(clientID); err != nil {
+		return Values{}, fmt.Errorf("invalid client ID: %w", err)
+	}
+
+	if opts.ChannelID != "" {
+		if err := security.ValidateDiscordID(opts.ChannelID); err != nil {
+			return Values{}, fmt.Errorf("invalid channel ID: %w", err)
+		}
+	}
+
+	chromePath := opts.ChromePath
+	switch {
+	case strings.EqualFold(chromePath, noChrome):
+		chromePath = ""
+	case chromePath == "":
+		if candidates := config.ChromeCandidates(); len(candidates) > 0 {
+			chromePath = candidates[0]
+		}
+	default:
+		if _, err := os.Stat(chromePath); err != nil {
+			return Values{}, fmt.Errorf("chrome path %s: %w", chromePath, err)
+		}
+	}
+
+	webPort, err := parseWebPort(opts.WebPort)
+	if err != nil {
+		return Values{}, err
+	}
+
+	return Values{
+		Token:      opts.Token,
+		ClientID:   clientID,
+		ChannelID:  opts.ChannelID,
+		ChromePath: chromePath,
+		WebPort:    webPort,
+	}, nil
+}
+
+// collectInteractive prompts for each setting. It returns empty Values when
+// the user declines to overwrite an existing file.
+func collectInteractive(ctx context.Context, opts Options, prompter Prompter, out io.Writer) (Values, error) {
+	fmt.Fprintln(out, "Free Games Bot setup")
+	fmt.Fprintln(out, "Create a bot at https://discord.com/developers/applications and copy its token from the Bot page.")
+	fmt.Fprintln(out)
+
+	if _, err := os.Stat(opts.Output); err == nil && !opts.Force {
+		overwrite, err := prompter.Confirm(fmt.Sprintf("%s already exists. Overwrite it?", opts.Output), false)
+		if err != nil {
+			return Values{}, err
+		}
+		if !overwrite {
+			fmt.Fprintln(out, "Nothing written.")
+			return Values{}, nil
+		}
+	}
+
+	token, bot, err := promptToken(ctx, opts, prompter, out)
+	if err != nil {
+		return Values{}, err
+	}
+
+	clientDefault := opts.ClientID
+	if clientDefault == "" && bot != nil {
+		clientDefault = bot.ID
+	}
+	clientID, err := promptValid(prompter, out, "Application (client) ID", clientDefault, func(id string) error {
+		if id == "" {
+			return fmt.Errorf("the client ID is required")
+		}
+		return security.ValidateDiscordID(id)
+	})
+	if err != nil {
+		return Values{}, err
+	}
+
+	fmt.Fprintln(out, "\nA notification channel is optional: servers normally pick one with /setup.")
+	channelID, err := promptValid(prompter, out, "Legacy notification channel ID (blank to skip)", opts.ChannelID, func(id string) error {
+		if id == "" {
+			return nil
+		}
+		return security.ValidateDiscordID(id)
+	})
+	if err != nil {
+		return Values{}, err
+	}
+
+	chromePath, err := promptChrome(opts, prompter, out)
+	if err != nil {
+		return Values{}, err
+	}
+
+	portDefault := strings.TrimPrefix(opts.WebPort, ":")
+	if portDefault == "" {
+		portDefault = defaultWebPort
+	}
+	webPort, err := promptValid(prompter, out, "Web server port", portDefault, func(port string) error {
+		_, err := parseWebPort(port)
+		return err
+	})
+	if err != nil {
+		return Values{}, err
+	}
+	webPort, _ = parseWebPort(webPort)
+
+	return Values{
+		Token:      token,
+		ClientID:   clientID,
+		ChannelID:  channelID,
+		ChromePath: chromePath,
+		WebPort:    webPort,
+	}, nil
+}
+
+// promptToken asks for the bot token until it passes the format check and,
+// unless skipped, the live check against Discord
+func promptToken(ctx context.Context, opts Options, prompter Prompter, out io.Writer) (string, *BotIdentity, error) {
+	for {
+		token := opts.Token
+		opts.Token = ""
+		if token == "" {
+			var err error
+			if token, err = prompter.PromptSecret("Bot token (input hidden)"); err != nil {
+				return "", nil, err
+			}
+		} else {
+			fmt.Fprintln(out, "Using the bot token from DISCORD_BOT_TOKEN.")
+		}
+
+		if err := security.ValidateDiscordToken(token); err != nil {
+			fmt.Fprintf(out, "  %v\n", err)
+			continue
+		}
+		if opts.SkipVerify {
+			return token, nil, nil
+		}
+
+		fmt.Fprintln(out, "  Checking the token with Discord...")
+		bot, err := VerifyToken(ctx, token)
+		if err == nil {
+			fmt.Fprintf(out, "  Token belongs to bot %s (%s)\n", bot.Username, bot.ID)
+			return token, bot, nil
+		}
+
+		fmt.Fprintf(out, "  %v\n", err)
+		if errors.Is(err, ErrTokenRejected) {
+			continue
+		}
+		keep, err := prompter.Confirm("  Could not verify the token. Use it anyway?", false)
+		if err != nil {
+			return "", nil, err
+		}
+		if keep {
+			return token, nil, nil
+		}
+	}
+}
+
+// promptChrome offers the detected Chrome installs, a custom path or
+// API-only scraping
+func promptChrome(opts Options, prompter Prompter, out io.Writer) (string, error) {
+	candidates := config.ChromeCandidates()
+
+	fmt.Fprintln(out, "\nChrome/Chromium is used as a fallback when the Epic API fails.")
+	if len(candidates) == 0 {
+		fmt.Fprintln(out, "No Chrome install was found in the usual locations.")
+	} else {
+		fmt.Fprintln(out, "Found:")
+		for n, path := range candidates {
+			fmt.Fprintf(out, "  %d) %s\n", n+1, path)
+		}
+	}
+	fmt.Fprintf(out, "Enter a number, a path, or %q to scrape without Chrome.\n", noChrome)
+
+	def := opts.ChromePath
+	if def == "" {
+		def = noChrome
+		if len(candidates) > 0 {
+			def = "1"
+		}
+	}
+
+	for {
+		answer, err := prompter.Prompt("Chrome", def)
+		if err != nil {
+			return "", err
+		}
+
+		if strings.EqualFold(answer, noChrome) {
+			return "", nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil {
+			if n >= 1 && n <= len(candidates) {
+				return candidates[n-1], nil
+			}
+			fmt.Fprintf(out, "  Choose a number between 1 and %d.\n", len(candidates))
+			continue
+		}
+		if _, err := os.Stat(answer); err != nil {
+			fmt.Fprintf(out, "  %s does not exist.\n", answer)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// promptValid prompts until validate accepts the answer
+func promptValid(prompter Prompter, out io.Writer, label, def string, validate func(string) error) (string, error) {
+	for {
+		answer, err := prompter.Prompt(label, def)
+		if err != nil {
+			return "", err
+		}
+		if err := validate(answer); err != nil {
+			fmt.Fprintf(out, "  %v\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// parseWebPort accepts "3000" or ":3000", defaulting to 3000
+func parseWebPort(port string) (string, error) {
+	port = strings.TrimPrefix(strings.TrimSpace(port), ":")
+	if port == "" {
+		return defaultWebPort, nil
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("invalid port %q (expected 1-65535)", port)
+	}
+	return port, nil
+}
+
+// readTokenFile reads a token from a file, or stdin for "-"
+func readTokenFile(path string) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read token: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// RunChecks loads the written file the way the bot does and reports problems
+// it would hit on start. It reports whether every check passed.
+func RunChecks(path string, out io.Writer) bool {
+	fmt.Fprintln(out, "\nChecking configuration:")
+	ok := true
+	report := func(err error, pass string) {
+		if err != nil {
+			ok = false
+			fmt.Fprintf(out, "  ✗ %v\n", err)
+			return
+		}
+		fmt.Fprintf(out, "  ✓ %s\n", pass)
+	}
+
+	if err := godotenv.Overload(path); err != nil {
+		report(fmt.Errorf("failed to load %s: %w", path, err), "")
+		return false
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			report(errors.New(line), "")
+		}
+		return false
+	}
+	report(nil, "configuration is valid")
+
+	// Degraded subsystems are warnings; the bot starts without them
+	for _, issue := range cfg.CheckEnvironment() {
+		if issue.Fatal {
+			report(issue, "")
+			continue
+		}
+		fmt.Fprintf(out, "  ! %v\n", issue)
+	}
+	if ok {
+		report(nil, "Chrome, web port and database directory are usable")
+	}
+
+	return ok
+}
@@ -0,0 +1,255 @@
+package setup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// validToken passes the token format check
+const validToken = "MTIzNDU2Nzg5MDEyMzQ1Njc4.GaBcDe.abcdefghijklmnopqrstuvwxyzABCDEFGH"
+
+// fakePrompter answers prompts from a script, in order, and records the
+// labels it was asked
+type fakePrompter struct {
+	answers []string
+	asked   []string
+}
+
+func (p *fakePrompter) next(label string) (string, error) {
+	p.asked = append(p.asked, label)
+	if len(p.answers) == 0 {
+		return "", errors.New("no answer left for " + label)
+	}
+	answer := p.answers[0]
+	p.answers = p.answers[1:]
+	return answer, nil
+}
+
+func (p *fakePrompter) Prompt(label, def string) (string, error) {
+	answer, err := p.next(label)
+	if answer == "" {
+		return def, err
+	}
+	return answer, err
+}
+
+func (p *fakePrompter) PromptSecret(label string) (string, error) {
+	return p.next(label)
+}
+
+func (p *fakePrompter) Confirm(label string, def bool) (bool, error) {
+	answer, err := p.next(label)
+	if answer == "" {
+		return def, err
+	}
+	return answer == "y", err
+}
+
+// timesAsked counts the prompts whose label starts with prefix
+func (p *fakePrompter) timesAsked(prefix string) int {
+	n := 0
+	for _, label := range p.asked {
+		if strings.HasPrefix(label, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+// readEnv parses a written .env file the way the bot loads it
+func readEnv(t *testing.T, path string) map[string]string {
+	t.Helper()
+	env, err := godotenv.Read(path)
+	if err != nil {
+		t.Fatalf("godotenv.Read: %v", err)
+	}
+	return env
+}
+
+func TestRunInteractiveWritesEnv(t *testing.T) {
+	output := filepath.Join(t.TempDir(), ".env")
+	prompter := &fakePrompter{answers: []string{
+		validToken,           // bot token
+		"123456789012345678", // client ID
+		"",                   // no legacy channel
+		"none",               // no Chrome
+		":8080",              // web port
+		"n",                  // skip the configuration check
+	}}
+	var out strings.Builder
+
+	opts := Options{Output: output, SkipVerify: true}
+	if err := Run(context.Background(), opts, prompter, &out); err != nil {
+		t.Fatalf("Run: %v\noutput:\n%s", err, out.String())
+	}
+
+	env := readEnv(t, output)
+	want := map[string]string{
+		"DISCORD_BOT_TOKEN": validToken,
+		"DISCORD_CLIENT_ID": "123456789012345678",
+		"SCRAPER_MODE":      "api",
+		"WEB_PORT":          "8080",
+	}
+	for key, value := range want {
+		if env[key] != value {
+			t.Errorf("%s = %q, want %q", key, env[key], value)
+		}
+	}
+	for _, key := range []string{"DISCORD_CHANNEL_ID", "CHROME_PATH"} {
+		if _, ok := env[key]; ok {
+			t.Errorf("%s was written, want it left out", key)
+		}
+	}
+
+	info, err := os.Stat(output)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("file mode = %o, want 600 since it holds the token", perm)
+	}
+}
+
+func TestRunInteractiveRejectsInvalidInput(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, ".env")
+	chrome := filepath.Join(dir, "Google Chrome", "chrome")
+	if err := os.MkdirAll(filepath.Dir(chrome), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(chrome, nil, 0755); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	prompter := &fakePrompter{answers: []string{
+		"too-short", validToken, // bot token
+		"not-an-id", "123456789012345678", // client ID
+		"42", "234567890123456789", // legacy channel
+		"99", filepath.Join(dir, "missing"), chrome, // Chrome
+		"70000", "port", "3001", // web port
+		"n", // skip the configuration check
+	}}
+	var out strings.Builder
+
+	opts := Options{Output: output, SkipVerify: true}
+	if err := Run(context.Background(), opts, prompter, &out); err != nil {
+		t.Fatalf("Run: %v\noutput:\n%s", err, out.String())
+	}
+
+	asked := map[string]int{
+		"Bot token":                      2,
+		"Application (client) ID":        2,
+		"Legacy notification channel ID": 2,
+		"Chrome":                         3,
+		"Web server port":                3,
+	}
+	for label, want := range asked {
+		if got := prompter.timesAsked(label); got != want {
+			t.Errorf("asked %q %d times, want %d", label, got, want)
+		}
+	}
+	for _, message := range []string{"Discord token too short", "does not exist", `invalid port "70000"`, `invalid port "port"`} {
+		if !strings.Contains(out.String(), message) {
+			t.Errorf("output does not report %q:\n%s", message, out.String())
+		}
+	}
+
+	env := readEnv(t, output)
+	want := map[string]string{
+		"DISCORD_CLIENT_ID":  "123456789012345678",
+		"DISCORD_CHANNEL_ID": "234567890123456789",
+		"SCRAPER_MODE":       "auto",
+		"CHROME_PATH":        chrome,
+		"WEB_PORT":           "3001",
+	}
+	for key, value := range want {
+		if env[key] != value {
+			t.Errorf("%s = %q, want %q", key, env[key], value)
+		}
+	}
+}
+
+func TestRunInteractiveKeepsExistingFile(t *testing.T) {
+	output := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(output, []byte("DISCORD_BOT_TOKEN=keep\n"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	prompter := &fakePrompter{answers: []string{"n"}}
+	var out strings.Builder
+	if err := Run(context.Background(), Options{Output: output, SkipVerify: true}, prompter, &out); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if env := readEnv(t, output); env["DISCORD_BOT_TOKEN"] != "keep" {
+		t.Errorf("existing file was overwritten: %v", env)
+	}
+}
+
+func TestRunNonInteractiveRejectsInvalidOptions(t *testing.T) {
+	valid := Options{Token: validToken, ClientID: "123456789012345678", ChromePath: noChrome, NonInteractive: true, SkipVerify: true}
+
+	tests := []struct {
+		name    string
+		change  func(*Options)
+		exists  bool
+		wantErr string
+	}{
+		{name: "no token", change: func(o *Options) { o.Token = "" }, wantErr: "no bot token"},
+		{name: "malformed token", change: func(o *Options) { o.Token = "abc" }, wantErr: "too short"},
+		{name: "no client ID", change: func(o *Options) { o.ClientID = "" }, wantErr: "no client ID"},
+		{name: "invalid client ID", change: func(o *Options) { o.ClientID = "abc" }, wantErr: "invalid client ID"},
+		{name: "invalid channel ID", change: func(o *Options) { o.ChannelID = "12" }, wantErr: "invalid channel ID"},
+		{name: "missing Chrome", change: func(o *Options) { o.ChromePath = "/nonexistent/chrome" }, wantErr: "chrome path"},
+		{name: "invalid port", change: func(o *Options) { o.WebPort = "0" }, wantErr: "invalid port"},
+		{name: "existing file", exists: true, wantErr: "already exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := valid
+			opts.Output = filepath.Join(t.TempDir(), ".env")
+			if tt.change != nil {
+				tt.change(&opts)
+			}
+			if tt.exists {
+				if err := os.WriteFile(opts.Output, nil, 0600); err != nil {
+					t.Fatalf("WriteFile: %v", err)
+				}
+			}
+
+			var out strings.Builder
+			err := Run(context.Background(), opts, nil, &out)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Run error = %v, want it to mention %q", err, tt.wantErr)
+			}
+			if info, statErr := os.Stat(opts.Output); !tt.exists && statErr == nil {
+				t.Errorf("wrote %s (%d bytes) despite the error", opts.Output, info.Size())
+			}
+		})
+	}
+}
+
+func TestRenderEnvQuotesValues(t *testing.T) {
+	values := Values{
+		Token:      validToken,
+		ClientID:   "123456789012345678",
+		ChromePath: `C:\Program Files\Google\Chrome\Application\chrome.exe`,
+		WebPort:    "3000",
+	}
+	for _, path := range []string{values.ChromePath, "/opt/chrome #1/chrome", "/home/o'neil/$HOME/chrome"} {
+		values.ChromePath = path
+		env, err := godotenv.Unmarshal(RenderEnv(values, time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)))
+		if err != nil {
+			t.Fatalf("godotenv.Unmarshal: %v", err)
+		}
+		if env["CHROME_PATH"] != path {
+			t.Errorf("CHROME_PATH = %q, want %q", env["CHROME_PATH"], path)
+		}
+	}
+}
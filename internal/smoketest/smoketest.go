@@ -0,0 +1,315 @@
+// Package smoketest implements the `smoketest` subcommand, an end-to-end
+// check of a deployment: it connects to Discord, scrapes, saves to a
+// temporary database and posts a labeled test announcement, then prints the
+// result of each stage as JSON
+package smoketest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"free-games-scrape/internal/app"
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/logger"
+	"free-games-scrape/internal/models"
+	"free-games-scrape/internal/scraper"
+	"free-games-scrape/internal/security"
+)
+
+// Scrape sources selected with -source
+const (
+	SourceFixture = "fixture"
+	SourceStore   = "store"
+)
+
+// Stage statuses
+const (
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// defaultTimeout bounds a whole smoke test run
+const defaultTimeout = 2 * time.Minute
+
+// Options control a smoke test run
+type Options struct {
+	ChannelID string
+	Source    string
+	Timeout   time.Duration
+}
+
+// StageResult is the outcome of one stage of the smoke test
+type StageResult struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	DurationMS int64  `json:"duration_ms"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+// Report is the JSON summary the smoke test prints. Passed is false when any
+// stage failed, except cleanup, which is done when possible.
+type Report struct {
+	Passed  bool          `json:"passed"`
+	Version string        `json:"version"`
+	Source  string        `json:"source"`
+	Stages  []StageResult `json:"stages"`
+}
+
+// Main runs the smoketest subcommand with its command line arguments and
+// returns the process exit code: 0 when every stage passed, 1 otherwise and
+// 2 for invalid flags. The report goes to stdout and logs to stderr.
+func Main(args []string) int {
+	fs := flag.NewFlagSet("smoketest", flag.ContinueOnError)
+	opts := Options{}
+	fs.StringVar(&opts.ChannelID, "channel", os.Getenv("SMOKE_TEST_CHANNEL_ID"), "channel to post the test announcement to")
+	fs.StringVar(&opts.Source, "source", SourceFixture, "scrape a built-in fixture game (fixture) or the real stores (store)")
+	fs.DurationVar(&opts.Timeout, "timeout", defaultTimeout, "fail if the smoke test takes longer than this")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if opts.Source != SourceFixture && opts.Source != SourceStore {
+		fmt.Fprintf(os.Stderr, "-source must be %s or %s\n", SourceFixture, SourceStore)
+		return 2
+	}
+	if opts.Timeout <= 0 {
+		fmt.Fprintln(os.Stderr, "-timeout must be positive")
+		return 2
+	}
+
+	report := Run(context.Background(), opts)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write report: %v\n", err)
+		return 1
+	}
+	if !report.Passed {
+		return 1
+	}
+	return 0
+}
+
+// Run performs the smoke test and returns its report. A run exceeding
+// opts.Timeout is reported as failed even if a stage is still blocked.
+func Run(ctx context.Context, opts Options) *Report {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	r := &runner{ctx: ctx, opts: opts, report: Report{Version: app.Version, Source: opts.Source, Stages: []StageResult{}}}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.run()
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		// Give the stage that noticed the deadline a moment to record it
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report := r.report
+	report.Stages = append([]StageResult{}, r.report.Stages...)
+	report.Passed = !r.failed && ctx.Err() == nil
+	if ctx.Err() != nil {
+		report.Stages = append(report.Stages, StageResult{
+			Name:   "timeout",
+			Status: StatusFailed,
+			Detail: fmt.Sprintf("smoke test did not finish within %s", opts.Timeout),
+		})
+	}
+	return &report
+}
+
+// runner records stage results as they complete so a timed out run can still
+// report how far it got
+type runner struct {
+	ctx  context.Context
+	opts Options
+
+	mu     sync.Mutex
+	report Report
+	failed bool
+}
+
+// stage runs fn as the named stage unless an earlier stage failed or the run
+// timed out, in which case it is recorded as skipped
+func (r *runner) stage(name string, fn func() (string, error)) {
+	r.mu.Lock()
+	failed := r.failed
+	r.mu.Unlock()
+	if failed || r.ctx.Err() != nil {
+		r.record(StageResult{Name: name, Status: StatusSkipped}, false)
+		return
+	}
+
+	start := time.Now()
+	detail, err := fn()
+	result := StageResult{Name: name, Status: StatusPassed, DurationMS: time.Since(start).Milliseconds(), Detail: detail}
+	if err != nil {
+		result.Status = StatusFailed
+		result.Detail = err.Error()
+	}
+	r.record(result, err != nil)
+}
+
+// record appends a stage result, marking the run failed if it failed
+func (r *runner) record(result StageResult, failed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.report.Stages = append(r.report.Stages, result)
+	r.failed = r.failed || failed
+}
+
+// run performs the stages in order, then cleans up whatever was created
+func (r *runner) run() {
+	var (
+		cfg        *config.Config
+		tempDir    string
+		components *app.Components
+		games      []models.Game
+		messageID  string
+	)
+
+	r.stage("config", func() (string, error) {
+		if err := security.ValidateDiscordID(r.opts.ChannelID); err != nil {
+			return "", fmt.Errorf("a valid -channel or SMOKE_TEST_CHANNEL_ID is required: %w", err)
+		}
+		var err error
+		if cfg, err = config.Load(); err != nil {
+			return "", err
+		}
+		if err := security.ValidateDiscordToken(cfg.Discord.Token); err != nil {
+			return "", err
+		}
+		// Nothing but the test announcement may be posted
+		cfg.Discord.OpsChannelID = ""
+		cfg.Discord.ChannelID = ""
+		cfg.Discord.ImageWarmupChannelID = ""
+		return "channel " + r.opts.ChannelID, nil
+	})
+
+	r.stage("startup", func() (string, error) {
+		var err error
+		if tempDir, err = os.MkdirTemp("", "free-games-smoketest-"); err != nil {
+			return "", fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		cfg.Database.Path = filepath.Join(tempDir, "smoketest.db")
+
+		var scrapers []scraper.Scraper
+		if r.opts.Source == SourceFixture {
+			scrapers = append(scrapers, fixtureScraper{})
+		}
+		appLogger := logger.NewWithOutput(logger.LogLevel(cfg.App.LogLevel), cfg.App.Environment, os.Stderr)
+		if components, err = app.NewComponents(cfg, appLogger, scrapers...); err != nil {
+			return "", err
+		}
+		return "components wired with a temporary database", nil
+	})
+
+	r.stage("discord_connect", func() (string, error) {
+		if err := components.Bot.Connect(); err != nil {
+			return "", err
+		}
+		return "gateway connection opened", nil
+	})
+
+	r.stage("scrape", func() (string, error) {
+		var err error
+		if games, err = components.GameService.ScrapeGames(r.ctx); err != nil {
+			return "", err
+		}
+		if len(games) == 0 {
+			return "", errors.New("no games scraped")
+		}
+		return fmt.Sprintf("%d games scraped", len(games)), nil
+	})
+
+	r.stage("save", func() (string, error) {
+		if _, err := components.GameService.SaveGames(games); err != nil {
+			return "", err
+		}
+		active, err := components.GameService.GetActiveGames()
+		if err != nil {
+			return "", err
+		}
+		count := len(active.All())
+		if count == 0 {
+			return "", errors.New("no active games read back after saving")
+		}
+		return fmt.Sprintf("%d active games read back", count), nil
+	})
+
+	r.stage("announce", func() (string, error) {
+		var err error
+		if messageID, err = components.Bot.SendSmokeTestAnnouncement(r.ctx, r.opts.ChannelID, games[0]); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("posted %s as message %s", games[0].Title, messageID), nil
+	})
+
+	r.cleanup(messageID, components, tempDir)
+}
+
+// cleanup deletes the test announcement, disconnects and removes the
+// temporary database. Failures are reported without failing the run.
+func (r *runner) cleanup(messageID string, components *app.Components, tempDir string) {
+	result := StageResult{Name: "cleanup", Status: StatusSkipped}
+	if messageID != "" {
+		start := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := components.Bot.DeleteSmokeTestAnnouncement(ctx, r.opts.ChannelID, messageID)
+		cancel()
+		result = StageResult{Name: "cleanup", Status: StatusPassed, DurationMS: time.Since(start).Milliseconds(), Detail: "test announcement deleted"}
+		if err != nil {
+			result.Status = StatusFailed
+			result.Detail = err.Error()
+		}
+	}
+	r.record(result, false)
+
+	if components != nil {
+		components.Bot.Stop()
+		components.DB.Close()
+	}
+	if tempDir != "" {
+		os.RemoveAll(tempDir)
+	}
+}
+
+// fixtureScraper returns one fixed game so the smoke test does not depend on
+// the stores being reachable
+type fixtureScraper struct{}
+
+func (fixtureScraper) ScrapeGames(ctx context.Context) ([]models.Game, error) {
+	now := time.Now()
+	end := now.AddDate(0, 0, 7)
+	return []models.Game{{
+		Title:        "Smoke Test Game",
+		Status:       models.StatusFreeNow,
+		FreeFrom:     now.Format("Jan 2"),
+		FreeTo:       end.Format("Jan 2"),
+		StoreURL:     "https://store.epicgames.com/",
+		Source:       models.SourceEpic,
+		FreeFromTime: now,
+		FreeToTime:   end,
+	}}, nil
+}
@@ -0,0 +1,243 @@
+// Package transfer implements the `export` and `import` subcommands, which
+// copy the games catalog and server settings between instances
+package transfer
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+	"free-games-scrape/internal/security"
+)
+
+// ExportMain runs the export subcommand with its command line arguments and
+// returns the process exit code. The export is written to the path given as
+// the only argument, or to stdout for "-" or no argument. It contains webhook
+// URLs, so it should be handled like the .env file.
+func ExportMain(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	dbPath := fs.String("db", defaultDatabasePath(), "database to export")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	if err := Export(*dbPath, fs.Arg(0)); err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// ImportMain runs the import subcommand with its command line arguments and
+// returns the process exit code. The export is read from the path given as
+// the only argument, or from stdin for "-".
+func ImportMain(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	dbPath := fs.String("db", defaultDatabasePath(), "database to import into")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: import [-db path] <export.json | ->")
+		return 2
+	}
+
+	if err := Import(*dbPath, fs.Arg(0), os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Import failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// defaultDatabasePath matches the DATABASE_PATH default in config
+func defaultDatabasePath() string {
+	if path := os.Getenv("DATABASE_PATH"); path != "" {
+		return path
+	}
+	return "games.db"
+}
+
+// Export writes the games and server configs of the database at dbPath as
+// JSON to outPath, or to stdout when outPath is empty or "-"
+func Export(dbPath, outPath string) error {
+	db, err := database.New(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	export, err := db.ExportData()
+	if err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if outPath != "" && outPath != "-" {
+		file, err := os.OpenFile(outPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", outPath, err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(export); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d games and %d server configs\n", len(export.Games), len(export.ServerConfigs))
+	return nil
+}
+
+// Import validates the export at inPath ("-" for stdin) and inserts its
+// records into the database at dbPath in a single transaction. Invalid
+// records and records already stored are skipped and counted.
+func Import(dbPath, inPath string, out io.Writer) error {
+	in := io.Reader(os.Stdin)
+	if inPath != "-" {
+		file, err := os.Open(inPath)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", inPath, err)
+		}
+		defer file.Close()
+		in = file
+	}
+
+	var export database.Export
+	if err := json.NewDecoder(in).Decode(&export); err != nil {
+		return fmt.Errorf("failed to parse export: %w", err)
+	}
+
+	valid := database.Export{Version: export.Version, ExportedAt: export.ExportedAt}
+	invalidGames, invalidConfigs := 0, 0
+	for i, game := range export.Games {
+		if err := validateGame(&game); err != nil {
+			fmt.Fprintf(out, "Skipping game %d (%q): %v\n", i+1, game.Title, err)
+			invalidGames++
+			continue
+		}
+		valid.Games = append(valid.Games, game)
+	}
+	for i, config := range export.ServerConfigs {
+		if err := validateServerConfig(&config); err != nil {
+			fmt.Fprintf(out, "Skipping server config %d (guild %q): %v\n", i+1, config.GuildID, err)
+			invalidConfigs++
+			continue
+		}
+		valid.ServerConfigs = append(valid.ServerConfigs, config)
+	}
+
+	db, err := database.New(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	result, err := db.ImportData(&valid)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Games: %d imported, %d already present, %d invalid\n", result.GamesImported, result.GamesSkipped, invalidGames)
+	fmt.Fprintf(out, "Server configs: %d imported, %d already present, %d invalid\n", result.ConfigsImported, result.ConfigsSkipped, invalidConfigs)
+	return nil
+}
+
+// validateGame checks an exported game before it is imported
+func validateGame(game *database.ExportedGame) error {
+	game.Title = strings.TrimSpace(game.Title)
+	switch {
+	case game.Title == "":
+		return errors.New("missing title")
+	case game.FreeTo == "":
+		return errors.New("missing free_to")
+	case game.Status != models.StatusFreeNow && game.Status != models.StatusComingSoon:
+		return fmt.Errorf("unknown status %q", game.Status)
+	case len(game.Images) > models.MaxGameImages:
+		return fmt.Errorf("more than %d images", models.MaxGameImages)
+	}
+	if game.StoreURL != "" {
+		if err := security.ValidateURL(game.StoreURL); err != nil {
+			return fmt.Errorf("invalid store_url: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateServerConfig checks an exported server config before it is
+// imported, normalizing its webhook URL
+func validateServerConfig(config *database.ExportedServerConfig) error {
+	if err := security.ValidateDiscordID(config.GuildID); err != nil {
+		return fmt.Errorf("invalid guild_id: %w", err)
+	}
+	if err := security.ValidateDiscordID(config.ChannelID); err != nil {
+		return fmt.Errorf("invalid channel_id: %w", err)
+	}
+	if config.RoleID != "" {
+		if err := security.ValidateDiscordID(config.RoleID); err != nil {
+			return fmt.Errorf("invalid role_id: %w", err)
+		}
+	}
+	if config.WebhookURL != "" {
+		webhookURL, err := security.ValidateDiscordWebhookURL(config.WebhookURL)
+		if err != nil {
+			return fmt.Errorf("invalid webhook_url: %w", err)
+		}
+		config.WebhookURL = webhookURL
+	}
+	if config.Region != "" {
+		if _, ok := models.LookupLocale(config.Region); !ok {
+			return fmt.Errorf("unsupported region %q", config.Region)
+		}
+	}
+	if config.PostDelaySeconds < 0 || config.PostDelaySeconds > database.MaxPostDelaySeconds {
+		return fmt.Errorf("post_delay_seconds must be between 0 and %d", database.MaxPostDelaySeconds)
+	}
+	if utf8.RuneCountInString(config.ClaimReminder) > database.MaxClaimReminderLength {
+		return fmt.Errorf("claim_reminder is longer than %d characters", database.MaxClaimReminderLength)
+	}
+	switch config.ComingSoonMode {
+	case "", database.ComingSoonAnnounce, database.ComingSoonReleaseOnly, database.ComingSoonBoth:
+	default:
+		return fmt.Errorf("unknown comingsoon_mode %q", config.ComingSoonMode)
+	}
+	switch config.MessageFormat {
+	case "", database.FormatEmbed, database.FormatPlain, database.FormatBoth:
+	default:
+		return fmt.Errorf("unknown message_format %q", config.MessageFormat)
+	}
+	for _, source := range strings.Split(config.Sources, ",") {
+		switch source {
+		case "", models.SourceEpic, models.SourceGOG:
+		default:
+			return fmt.Errorf("unknown source %q", source)
+		}
+	}
+	if config.Timezone != "" {
+		if _, err := database.LoadTimezone(config.Timezone); err != nil {
+			return err
+		}
+	}
+	if config.ContentPolicy != "" {
+		policy, err := database.ParseContentPolicy(config.ContentPolicy)
+		if err != nil {
+			return fmt.Errorf("invalid content_policy: %w", err)
+		}
+		config.ContentPolicy = policy
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package transfer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/models"
+)
+
+const (
+	newGuild      = "111111111111111111"
+	existingGuild = "333333333333333333"
+)
+
+// openDB opens a database file that is closed with the test
+func openDB(t *testing.T, path string) *database.Database {
+	t.Helper()
+	db, err := database.New(path)
+	if err != nil {
+		t.Fatalf("database.New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// writeExport exports the database at dbPath and adds records that fail
+// validation, returning the export's path
+func writeExport(t *testing.T, dbPath string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "export.json")
+	if err := Export(dbPath, path); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	var export database.Export
+	if err := json.Unmarshal(data, &export); err != nil {
+		t.Fatalf("export is not valid JSON: %v", err)
+	}
+	export.Games = append(export.Games, database.ExportedGame{Game: models.Game{Title: " ", Status: models.StatusFreeNow, FreeTo: "Jul 17"}})
+	export.ServerConfigs = append(export.ServerConfigs, database.ExportedServerConfig{ServerConfig: database.ServerConfig{GuildID: "guild", ChannelID: "222222222222222222"}})
+	data, err = json.Marshal(export)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestImportSeedsAnotherInstance(t *testing.T) {
+	dir := t.TempDir()
+	announced := models.Game{Title: "Announced Game", Status: models.StatusFreeNow, FreeTo: "Jul 17", StoreURL: "https://store.epicgames.com/p/announced-game"}
+	local := models.Game{Title: "Local Game", Status: models.StatusFreeNow, FreeTo: "Jul 17"}
+
+	// The old instance has both games and two servers, one of them with a
+	// webhook on a legacy host and a loosely typed content policy
+	sourcePath := filepath.Join(dir, "old.db")
+	source := openDB(t, sourcePath)
+	if _, err := source.SaveGames([]models.Game{announced, local}); err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+	for _, guildID := range []string{newGuild, existingGuild} {
+		if _, err := source.SaveServerConfig(guildID, "222222222222222222"); err != nil {
+			t.Fatalf("SaveServerConfig: %v", err)
+		}
+	}
+	if err := source.SetWebhookURL(newGuild, "https://discordapp.com/api/webhooks/123456789012345678/abcDEF-123_tokenXYZ789"); err != nil {
+		t.Fatalf("SetWebhookURL: %v", err)
+	}
+	if err := source.SetContentPolicy(newGuild, "text-only no-links"); err != nil {
+		t.Fatalf("SetContentPolicy: %v", err)
+	}
+	exportPath := writeExport(t, sourcePath)
+
+	// The new instance already knows one game and one server
+	targetPath := filepath.Join(dir, "new.db")
+	target := openDB(t, targetPath)
+	if _, err := target.SaveGames([]models.Game{local}); err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+	if _, err := target.SaveServerConfig(existingGuild, "444444444444444444"); err != nil {
+		t.Fatalf("SaveServerConfig: %v", err)
+	}
+
+	var out strings.Builder
+	if err := Import(targetPath, exportPath, &out); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	for _, want := range []string{
+		"Games: 1 imported, 1 already present, 1 invalid",
+		"Server configs: 1 imported, 1 already present, 1 invalid",
+		`Skipping game 3 (""): missing title`,
+		`Skipping server config 3 (guild "guild"): invalid guild_id`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("import report does not contain %q:\n%s", want, out.String())
+		}
+	}
+
+	imported, err := target.GetServerConfig(newGuild)
+	if err != nil || imported == nil {
+		t.Fatalf("GetServerConfig: %v, %v", imported, err)
+	}
+	if imported.WebhookURL != "https://discord.com/api/webhooks/123456789012345678/abcDEF-123_tokenXYZ789" {
+		t.Errorf("webhook URL = %q, want it normalized to discord.com", imported.WebhookURL)
+	}
+	if imported.ContentPolicy != "no-links,text-only" {
+		t.Errorf("content policy = %q, want the stored form", imported.ContentPolicy)
+	}
+	if kept, _ := target.GetServerConfig(existingGuild); kept == nil || kept.ChannelID != "444444444444444444" {
+		t.Errorf("existing server config was overwritten: %+v", kept)
+	}
+
+	// The imported server is not caught up on imported games, which the old
+	// instance already posted, but is on games new to it here. Servers that
+	// were already configured keep their own history.
+	announcements := []struct {
+		guildID string
+		game    models.Game
+		want    bool
+	}{
+		{newGuild, announced, true},
+		{newGuild, local, false},
+		{existingGuild, announced, false},
+	}
+	for _, tt := range announcements {
+		sent, err := target.NotificationSent(tt.guildID, database.NotificationAnnouncement, tt.game)
+		if err != nil {
+			t.Fatalf("NotificationSent: %v", err)
+		}
+		if sent != tt.want {
+			t.Errorf("%s recorded as announced to %s: %v, want %v", tt.game.Title, tt.guildID, sent, tt.want)
+		}
+	}
+
+	// Importing again changes nothing
+	out.Reset()
+	if err := Import(targetPath, exportPath, &out); err != nil {
+		t.Fatalf("second Import: %v", err)
+	}
+	for _, want := range []string{
+		"Games: 0 imported, 2 already present, 1 invalid",
+		"Server configs: 0 imported, 2 already present, 1 invalid",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("second import report does not contain %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestImportRejectsUnreadableExports(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "new.db")
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "not JSON", content: "games.db", wantErr: "failed to parse export"},
+		{name: "other version", content: `{"version": 99, "games": [], "server_configs": []}`, wantErr: "unsupported export version 99"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "export.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+			err := Import(dbPath, path, &strings.Builder{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Import error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := Import(dbPath, filepath.Join(dir, "missing.json"), &strings.Builder{}); err == nil {
+		t.Error("Import of a missing file succeeded")
+	}
+}
@@ -0,0 +1,125 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"free-games-scrape/internal/config"
+	"free-games-scrape/pkg/client"
+)
+
+// TestClientAgainstServer runs the public API client against the real
+// handlers, checking each failure unwraps to its sentinel error
+func TestClientAgainstServer(t *testing.T) {
+	_, handler := newTestServer(t, &config.WebConfig{AdminToken: "secret"})
+
+	// A proxy in front of the bot answers 429 while limited is set
+	var limited atomic.Bool
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if limited.Load() {
+			w.Header().Set("Retry-After", "0")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error": "rate limited"}`))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	newClient := func(opts ...client.Option) *client.Client {
+		c, err := client.New(server.URL, append([]client.Option{client.WithRetries(2, time.Millisecond)}, opts...)...)
+		if err != nil {
+			t.Fatalf("client.New: %v", err)
+		}
+		return c
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		limited  bool
+		call     func() error
+		wantErr  error
+		wantHits int32
+	}{
+		{
+			name: "games",
+			call: func() error {
+				games, err := newClient().Games(ctx)
+				if err == nil && games.Total != 0 {
+					t.Errorf("listed %d games, want none", games.Total)
+				}
+				return err
+			},
+			wantHits: 1,
+		},
+		{
+			name: "admin endpoint with the token",
+			call: func() error {
+				_, err := newClient(client.WithToken("secret")).Restarts(ctx)
+				return err
+			},
+			wantHits: 1,
+		},
+		{
+			name: "admin endpoint with a wrong token",
+			call: func() error {
+				_, err := newClient(client.WithToken("guess")).Restarts(ctx)
+				return err
+			},
+			wantErr:  client.ErrUnauthorized,
+			wantHits: 1,
+		},
+		{
+			name: "changes of a missing game",
+			call: func() error {
+				_, err := newClient().GameChanges(ctx, 999)
+				return err
+			},
+			wantErr:  client.ErrNotFound,
+			wantHits: 1,
+		},
+		{
+			name:    "rate limited",
+			limited: true,
+			call: func() error {
+				_, err := newClient().Status(ctx)
+				return err
+			},
+			wantErr:  client.ErrRateLimited,
+			wantHits: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limited.Store(tt.limited)
+			hits.Store(0)
+
+			err := tt.call()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				var apiErr *client.APIError
+				if !errors.As(err, &apiErr) || apiErr.Message == "" {
+					t.Errorf("error %v carries no API message", err)
+				}
+			}
+			if got := hits.Load(); got != tt.wantHits {
+				t.Errorf("made %d requests, want %d", got, tt.wantHits)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"free-games-scrape/internal/service"
+	"free-games-scrape/pkg/api"
+)
+
+// GatewayStatus reports whether the Discord gateway session is connected.
+// *bot.DiscordBot implements it.
+type GatewayStatus interface {
+	Connected() bool
+}
+
+// healthCheckTimeout bounds the database ping of /readyz
+const healthCheckTimeout = 2 * time.Second
+
+// handleHealthz is the liveness probe: it succeeds as long as the process
+// serves HTTP
+func (ws *WebServer) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	ws.writeJSON(w, http.StatusOK, api.HealthResponse{
+		Status:        api.HealthOK,
+		UptimeSeconds: int64(ws.metrics.GetUptime() / time.Second),
+	})
+}
+
+// handleReadyz is the readiness probe. It checks the Discord gateway, the
+// database and the scrapes, and answers 503 naming the failing components.
+func (ws *WebServer) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	health := ws.checkReadiness(r.Context())
+	statusCode := http.StatusOK
+	if health.Status != api.HealthOK {
+		statusCode = http.StatusServiceUnavailable
+	}
+	ws.writeJSON(w, statusCode, health)
+}
+
+// checkReadiness runs every readiness check
+func (ws *WebServer) checkReadiness(ctx context.Context) api.HealthResponse {
+	health := api.HealthResponse{
+		Status:        api.HealthOK,
+		UptimeSeconds: int64(ws.metrics.GetUptime() / time.Second),
+		Checks: map[string]api.HealthCheck{
+			"discord":  ws.checkDiscord(),
+			"database": ws.checkDatabase(ctx),
+			"scrape":   ws.checkScrape(time.Now()),
+		},
+	}
+	for _, check := range health.Checks {
+		if check.Status != api.HealthOK {
+			health.Status = api.HealthUnavailable
+		}
+	}
+	return health
+}
+
+// checkDiscord reports whether the gateway session is connected
+func (ws *WebServer) checkDiscord() api.HealthCheck {
+	if ws.gateway == nil || !ws.gateway.Connected() {
+		return api.HealthCheck{Status: api.HealthUnavailable, Detail: "gateway not connected"}
+	}
+	return api.HealthCheck{Status: api.HealthOK, Detail: "gateway connected"}
+}
+
+// checkDatabase pings the database
+func (ws *WebServer) checkDatabase(ctx context.Context) api.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	if err := ws.db.Ping(ctx); err != nil {
+		return api.HealthCheck{Status: api.HealthUnavailable, Detail: err.Error()}
+	}
+	return api.HealthCheck{Status: api.HealthOK}
+}
+
+// checkScrape fails once the stored games are stale, i.e. the scheduled
+// scrapes kept failing, or when no scrape ever succeeded and the last attempt
+// since startup failed
+func (ws *WebServer) checkScrape(now time.Time) api.HealthCheck {
+	freshness, err := ws.gameService.Freshness(now)
+	if err != nil {
+		return api.HealthCheck{Status: api.HealthUnavailable, Detail: err.Error()}
+	}
+	lastAttempt, lastSuccess, _ := ws.metrics.GetLastScrapeInfo()
+
+	if freshness.LastScrape.IsZero() {
+		if !lastAttempt.IsZero() && !lastSuccess {
+			return api.HealthCheck{Status: api.HealthUnavailable, Detail: fmt.Sprintf("no successful scrape yet; the attempt at %s failed", lastAttempt.UTC().Format(time.RFC3339))}
+		}
+		return api.HealthCheck{Status: api.HealthOK, Detail: "no scrape finished yet"}
+	}
+
+	detail := fmt.Sprintf("last successful scrape at %s", freshness.LastScrape.UTC().Format(time.RFC3339))
+	if !lastAttempt.IsZero() && !lastSuccess {
+		detail += fmt.Sprintf("; the attempt at %s failed", lastAttempt.UTC().Format(time.RFC3339))
+	}
+	if freshness.Outage {
+		detail += "; " + service.OutageMessage
+	}
+	if freshness.Stale {
+		return api.HealthCheck{Status: api.HealthUnavailable, Detail: "stale data: " + detail}
+	}
+	return api.HealthCheck{Status: api.HealthOK, Detail: detail}
+}
@@ -0,0 +1,191 @@
+package web
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/pkg/api"
+)
+
+// Scrape history limits: the default and largest ?limit= of
+// /api/scrape-history, and the runs shown on /history
+const (
+	defaultScrapeHistoryLimit = 50
+	maxScrapeHistoryLimit     = 200
+)
+
+// Sparkline geometry of the /history page, in pixels
+const (
+	sparkBarWidth  = 6
+	sparkBarGap    = 2
+	sparkHeight    = 40
+	sparkMinHeight = 2
+)
+
+// sparkBar is one run in the /history sparkline
+type sparkBar struct {
+	X, Y, Height int
+	Failed       bool
+	Label        string
+}
+
+// historyPageData feeds the /history page
+type historyPageData struct {
+	Branding    config.BrandingConfig
+	Runs        []database.ScrapeRun
+	Bars        []sparkBar
+	Width       int
+	Height      int
+	SuccessRate string
+}
+
+var historyTemplate = template.Must(template.New("history").Funcs(template.FuncMap{
+	"formatTime": formatTime,
+	"duration":   func(d time.Duration) string { return d.Round(100 * time.Millisecond).String() },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Branding.Name}} - Scrape History</title>
+    <style>
+        body { font-family: 'Segoe UI', sans-serif; background: #f8f9fa; margin: 0; padding: 20px; color: #2c2f33; }
+        .container { background: white; border-radius: 12px; box-shadow: 0 8px 32px rgba(0,0,0,0.1); padding: 40px; max-width: 800px; margin: 0 auto; }
+        h1 { color: #7289da; }
+        table { width: 100%; border-collapse: collapse; margin: 20px 0 30px; }
+        th, td { text-align: left; padding: 10px; border-bottom: 1px solid #e3e5e8; }
+        th { background: #f2f3f5; }
+        .ok { fill: #43b581; color: #43b581; }
+        .failed { fill: #f04747; color: #f04747; }
+        {{if .Branding.HasCustomAccent}}h1 { color: {{.Branding.HexColor}}; }{{end}}
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>{{if .Branding.LogoURL}}<img src="{{.Branding.LogoURL}}" alt="" style="height: 1.2em; vertical-align: middle;">{{else}}📈{{end}} Scrape History</h1>
+        {{if .Runs}}
+        <p>{{.SuccessRate}} of the last {{len .Runs}} scrapes succeeded. Bars show the games found, oldest first; failed scrapes are red.</p>
+        <svg width="{{.Width}}" height="{{.Height}}" role="img" aria-label="Games found per scrape">
+            {{range .Bars}}<rect x="{{.X}}" y="{{.Y}}" width="` + strconv.Itoa(sparkBarWidth) + `" height="{{.Height}}" class="{{if .Failed}}failed{{else}}ok{{end}}"><title>{{.Label}}</title></rect>
+            {{end}}
+        </svg>
+        <table>
+            <tr><th>Time</th><th>Result</th><th>Games found</th><th>Duration</th></tr>
+            {{range .Runs}}<tr><td>{{formatTime .StartedAt}}</td><td>{{if .Success}}<span class="ok">✔ Success</span>{{else}}<span class="failed">✘ Failed{{with .FailureClass}} ({{.}}){{end}}</span>{{end}}</td><td>{{.GamesFound}}</td><td>{{duration .Duration}}</td></tr>
+            {{end}}
+        </table>
+        {{else}}
+        <p>No scrapes have been recorded yet.</p>
+        {{end}}
+        <p><a href="/api/scrape-history" style="color: #7289da; text-decoration: none;">🔌 JSON</a> · <a href="/help" style="color: #7289da; text-decoration: none;">📖 View Documentation</a></p>
+    </div>
+</body>
+</html>`))
+
+// handleHistory renders the recent scrape runs as a sparkline and a table
+func (ws *WebServer) handleHistory(w http.ResponseWriter, r *http.Request) {
+	runs, err := ws.gameService.GetScrapeHistory(defaultScrapeHistoryLimit)
+	if err != nil {
+		log.Printf("Error loading scrape history: %v", err)
+		http.Error(w, "Failed to load scrape history", http.StatusInternalServerError)
+		return
+	}
+
+	data := historyPageData{
+		Branding:    ws.config.Branding,
+		Runs:        runs,
+		Bars:        sparkline(runs),
+		Width:       len(runs) * (sparkBarWidth + sparkBarGap),
+		Height:      sparkHeight,
+		SuccessRate: strconv.Itoa(int(successRate(runs)*100+0.5)) + "%",
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := historyTemplate.Execute(w, data); err != nil {
+		log.Printf("Error rendering scrape history page: %v", err)
+	}
+}
+
+// sparkline lays out one bar per run, oldest on the left, scaled to the most
+// games found. Failed runs are drawn at full height.
+func sparkline(runs []database.ScrapeRun) []sparkBar {
+	most := 1
+	for _, run := range runs {
+		most = max(most, run.GamesFound)
+	}
+
+	bars := make([]sparkBar, 0, len(runs))
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		height := sparkHeight
+		label := formatTime(run.StartedAt) + ": failed"
+		if run.FailureClass != "" {
+			label += " (" + run.FailureClass + ")"
+		}
+		if run.Success {
+			height = max(sparkMinHeight, run.GamesFound*sparkHeight/most)
+			label = formatTime(run.StartedAt) + ": " + strconv.Itoa(run.GamesFound) + " games"
+		}
+		bars = append(bars, sparkBar{
+			X:      len(bars) * (sparkBarWidth + sparkBarGap),
+			Y:      sparkHeight - height,
+			Height: height,
+			Failed: !run.Success,
+			Label:  label,
+		})
+	}
+	return bars
+}
+
+// successRate returns the share of runs that succeeded, 0 for no runs
+func successRate(runs []database.ScrapeRun) float64 {
+	if len(runs) == 0 {
+		return 0
+	}
+	succeeded := 0
+	for _, run := range runs {
+		if run.Success {
+			succeeded++
+		}
+	}
+	return float64(succeeded) / float64(len(runs))
+}
+
+func (ws *WebServer) handleAPIScrapeHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	limit := defaultScrapeHistoryLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			ws.writeJSON(w, http.StatusBadRequest, api.ErrorResponse{Error: "limit must be a positive integer"})
+			return
+		}
+		limit = min(n, maxScrapeHistoryLimit)
+	}
+
+	runs, err := ws.gameService.GetScrapeHistory(limit)
+	if err != nil {
+		log.Printf("Error loading scrape history: %v", err)
+		ws.writeJSON(w, http.StatusInternalServerError, api.ErrorResponse{Error: "Failed to get scrape history"})
+		return
+	}
+
+	response := api.ScrapeHistoryResponse{Runs: make([]api.ScrapeRun, 0, len(runs)), SuccessRate: successRate(runs)}
+	for _, run := range runs {
+		response.Runs = append(response.Runs, api.ScrapeRun{
+			StartedAt:    run.StartedAt.UTC(),
+			Success:      run.Success,
+			GamesFound:   run.GamesFound,
+			DurationMS:   run.Duration.Milliseconds(),
+			FailureClass: run.FailureClass,
+		})
+	}
+
+	ws.writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,60 @@
+package web
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"free-games-scrape/internal/database"
+)
+
+// writeMetric writes one metric in the Prometheus text exposition format
+func writeMetric(w io.Writer, name, metricType, help string, value interface{}) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, metricType, name, value)
+}
+
+// storeURLOutcomeHelp completes the help text of the store URL lookup counters
+var storeURLOutcomeHelp = map[string]string{
+	database.StoreURLResolved:  "found a store link",
+	database.StoreURLAmbiguous: "were left for /admin linkgame",
+	database.StoreURLFailed:    "failed and will be retried",
+}
+
+// handleMetrics serves the application metrics for Prometheus to scrape
+func (ws *WebServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	serverCount, err := ws.db.GetServerCount()
+	if err != nil {
+		log.Printf("Error counting servers for metrics: %v", err)
+	}
+	lastScrape, lastSuccess, lastDuration := ws.metrics.GetLastScrapeInfo()
+
+	var sb strings.Builder
+	writeMetric(&sb, "commands_executed_total", "counter", "Bot commands handled.", ws.metrics.GetCommandsExecuted())
+	writeMetric(&sb, "messages_processed_total", "counter", "Prefix command messages handled.", ws.metrics.GetMessagesProcessed())
+	writeMetric(&sb, "games_scraped_total", "counter", "Games returned by successful scrapes.", ws.metrics.GetGamesScraped())
+	writeMetric(&sb, "errors_total", "counter", "Failed scrapes and recovered handler panics.", ws.metrics.GetErrors())
+	writeMetric(&sb, "servers_joined_total", "counter", "Servers joined since startup.", ws.metrics.GetServersJoined())
+	writeMetric(&sb, "servers_left_total", "counter", "Servers left since startup.", ws.metrics.GetServersLeft())
+	writeMetric(&sb, "servers_rejected_total", "counter", "Servers left since startup because they are not in GUILD_ALLOWLIST.", ws.metrics.GetServersRejected())
+	writeMetric(&sb, "active_servers", "gauge", "Servers with an active notification channel.", serverCount)
+
+	success := 0
+	if lastSuccess {
+		success = 1
+	}
+	writeMetric(&sb, "last_scrape_success", "gauge", "Whether the last scrape succeeded (1) or failed (0).", success)
+	writeMetric(&sb, "last_scrape_duration_seconds", "gauge", "Duration of the last scrape.", lastDuration.Seconds())
+	if !lastScrape.IsZero() {
+		writeMetric(&sb, "last_scrape_timestamp_seconds", "gauge", "Unix time of the last scrape.", lastScrape.Unix())
+	}
+	for _, outcome := range []string{database.StoreURLResolved, database.StoreURLAmbiguous, database.StoreURLFailed} {
+		writeMetric(&sb, "store_url_lookups_"+outcome+"_total", "counter", "Store URL backfill lookups that "+storeURLOutcomeHelp[outcome]+".", ws.metrics.GetStoreURLLookups(outcome))
+	}
+	writeMetric(&sb, "rate_limiter_channels", "gauge", "Per-channel rate limiters currently kept.", ws.metrics.GetRateLimiters())
+	writeMetric(&sb, "uptime_seconds", "gauge", "Seconds since the bot started.", ws.metrics.GetUptime().Seconds())
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	io.WriteString(w, sb.String())
+}
@@ -0,0 +1,76 @@
+package web
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/database"
+)
+
+// privacyPageData feeds the /privacy page. Categories come from the same
+// registry the Discord /privacy command uses.
+type privacyPageData struct {
+	Branding config.BrandingConfig
+	PerGuild []database.DataCategory
+	Global   []database.DataCategory
+}
+
+var privacyTemplate = template.Must(template.New("privacy").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Branding.Name}} - Privacy</title>
+    <style>
+        body { font-family: 'Segoe UI', sans-serif; background: #f8f9fa; margin: 0; padding: 20px; color: #2c2f33; }
+        .container { background: white; border-radius: 12px; box-shadow: 0 8px 32px rgba(0,0,0,0.1); padding: 40px; max-width: 800px; margin: 0 auto; }
+        h1, h2 { color: #7289da; }
+        table { width: 100%; border-collapse: collapse; margin-bottom: 30px; }
+        th, td { text-align: left; padding: 10px; border-bottom: 1px solid #e3e5e8; vertical-align: top; }
+        th { background: #f2f3f5; }
+        {{if .Branding.HasCustomAccent}}h1, h2 { color: {{.Branding.HexColor}}; }{{end}}
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>{{if .Branding.LogoURL}}<img src="{{.Branding.LogoURL}}" alt="" style="height: 1.2em; vertical-align: middle;">{{else}}🔒{{end}} Privacy</h1>
+        <p>{{.Branding.Name}} stores only what it needs to send free game notifications. It does not store message content.
+        Server admins can run <code>/privacy</code> in Discord to see how many records are currently kept for their server.</p>
+
+        <h2>Data stored per server</h2>
+        <table>
+            <tr><th>Data</th><th>What is stored</th><th>Retention</th></tr>
+            {{range .PerGuild}}<tr><td>{{.Name}}</td><td>{{.Description}}</td><td>{{.Retention}}</td></tr>
+            {{end}}
+        </table>
+
+        <h2>Data not tied to any server</h2>
+        <table>
+            <tr><th>Data</th><th>What is stored</th><th>Retention</th></tr>
+            {{range .Global}}<tr><td>{{.Name}}</td><td>{{.Description}}</td><td>{{.Retention}}</td></tr>
+            {{end}}
+        </table>
+
+        <p><a href="/help" style="color: #7289da; text-decoration: none;">📖 View Documentation</a></p>
+    </div>
+</body>
+</html>`))
+
+// handlePrivacy renders the privacy notice from the data category registry
+func (ws *WebServer) handlePrivacy(w http.ResponseWriter, r *http.Request) {
+	data := privacyPageData{Branding: ws.config.Branding}
+	for _, category := range database.DataCategories() {
+		if category.PerGuild() {
+			data.PerGuild = append(data.PerGuild, category)
+		} else {
+			data.Global = append(data.Global, category)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := privacyTemplate.Execute(w, data); err != nil {
+		log.Printf("Error rendering privacy page: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package web
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"free-games-scrape/internal/models"
+	"free-games-scrape/pkg/api"
+)
+
+// rssFeed is an RSS 2.0 document
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// writeRSS renders the listed games of a /api/games response as an RSS 2.0
+// feed, one item per game
+func (ws *WebServer) writeRSS(w http.ResponseWriter, response api.GamesResponse) {
+	link := ws.config.PublicURL
+	if link == "" {
+		link = "/"
+	}
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         ws.config.Branding.Name + " - Free Games",
+			Link:          link,
+			Description:   "Games that are free to claim on the Epic Games Store and GOG",
+			LastBuildDate: response.LastUpdated.UTC().Format(time.RFC1123Z),
+			Items:         make([]rssItem, 0, len(response.Games)),
+		},
+	}
+
+	for _, game := range response.Games {
+		item := rssItem{
+			Title:       game.Title,
+			Link:        game.StoreURL,
+			Description: rssDescription(game),
+			GUID:        rssGUID{Value: strings.Join([]string{game.Source, strconv.FormatInt(game.ID, 10), game.FreeTo}, ":")},
+		}
+		if game.FreeFromAt != nil {
+			item.PubDate = game.FreeFromAt.Format(time.RFC1123Z)
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		log.Printf("Error encoding RSS feed: %v", err)
+		http.Error(w, "Failed to encode feed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	w.Write(append(body, '\n'))
+}
+
+// rssDescription summarizes a game's promotion for its feed item
+func rssDescription(game api.Game) string {
+	source := (&models.Game{Source: game.Source}).SourceName()
+	if game.Status == models.StatusComingSoon {
+		if game.FreeFrom != "" {
+			return fmt.Sprintf("Free on %s from %s until %s", source, game.FreeFrom, game.FreeTo)
+		}
+		return fmt.Sprintf("Free on %s soon", source)
+	}
+	if game.FreeTo != "" {
+		return fmt.Sprintf("Free on %s until %s", source, game.FreeTo)
+	}
+	return "Free on " + source
+}
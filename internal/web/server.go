@@ -1,77 +1,189 @@
 package web
 
 import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"free-games-scrape/internal/config"
 	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/logger"
+	"free-games-scrape/internal/metrics"
+	"free-games-scrape/internal/models"
+	"free-games-scrape/internal/security"
 	"free-games-scrape/internal/service"
+	"free-games-scrape/pkg/api"
+	assets "free-games-scrape/web"
+	"hash/fnv"
+	"html"
 	"html/template"
+	"io/fs"
 	"log"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // WebServer handles HTTP requests for documentation
 type WebServer struct {
 	port        string
+	config      *config.WebConfig
+	logger      *logger.Logger
 	gameService *service.GameService
 	db          *database.Database
+	metrics     *metrics.Metrics
+	gateway     GatewayStatus
 	templates   *template.Template
+	server      *http.Server
 }
 
 // NewWebServer creates a new web server instance
-func NewWebServer(port string, gameService *service.GameService, db *database.Database) *WebServer {
-	return &WebServer{
-		port:        port,
+func NewWebServer(cfg *config.WebConfig, gameService *service.GameService, db *database.Database, appMetrics *metrics.Metrics, gateway GatewayStatus, appLogger *logger.Logger) *WebServer {
+	ws := &WebServer{
+		port:        cfg.Port,
+		config:      cfg,
+		logger:      appLogger.WithComponent("web"),
 		gameService: gameService,
 		db:          db,
+		metrics:     appMetrics,
+		gateway:     gateway,
 	}
+
+	ws.server = &http.Server{
+		Addr:           cfg.Port,
+		ReadTimeout:    cfg.ReadTimeout,
+		WriteTimeout:   cfg.WriteTimeout,
+		IdleTimeout:    cfg.IdleTimeout,
+		MaxHeaderBytes: cfg.MaxHeaderBytes,
+	}
+
+	return ws
 }
 
-// Start starts the web server
+// Start starts the web server and blocks until it stops. It returns nil
+// after Stop.
 func (ws *WebServer) Start() error {
 	// Load templates
 	if err := ws.loadTemplates(); err != nil {
 		return fmt.Errorf("failed to load templates: %w", err)
 	}
 
-	// Setup routes
-	ws.setupRoutes()
+	ws.server.Handler = ws.setupRoutes()
 
 	log.Printf("Starting web server on port %s", ws.port)
 	log.Printf("Documentation available at: http://localhost%s/help", ws.port)
 	log.Printf("Bot invite page available at: http://localhost%s/invite", ws.port)
 
-	return http.ListenAndServe(ws.port, nil)
+	if err := ws.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Stop gracefully shuts the web server down, waiting for in-flight requests
+// until ctx expires
+func (ws *WebServer) Stop(ctx context.Context) error {
+	log.Println("Shutting down web server")
+	if err := ws.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to shut down web server: %w", err)
+	}
+	return nil
 }
 
-// loadTemplates loads HTML templates
+// withRequestID tags every request with a request ID, exposed to handlers via
+// the request context and to clients via the X-Request-ID header
+func (ws *WebServer) withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := logger.NewRequestID()
+		w.Header().Set("X-Request-ID", requestID)
+		ctx := logger.ContextWithRequestID(r.Context(), requestID)
+
+		ws.logger.WithContext(ctx).WithFields(map[string]interface{}{
+			"method": r.Method,
+			"path":   r.URL.Path,
+		}).Debug("HTTP request received")
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// formatTime renders a time for the public pages in UTC with an explicit
+// suffix, so visitors in any timezone read it the same way
+func formatTime(t time.Time) string {
+	return t.UTC().Format("January 2, 2006 at 3:04 PM") + " UTC"
+}
+
+// loadTemplates parses the HTML templates embedded in the binary
 func (ws *WebServer) loadTemplates() error {
 	tmpl, err := template.New("").Funcs(template.FuncMap{
-		"formatTime": func(t time.Time) string {
-			return t.Format("January 2, 2006 at 3:04 PM")
-		},
-	}).ParseGlob("web/templates/*.html")
+		"formatTime": formatTime,
+	}).ParseFS(assets.Templates, "templates/*.html")
 	if err != nil {
-		// If templates don't exist, create them inline
-		ws.createInlineTemplates()
-		return nil
+		return err
 	}
 
 	ws.templates = tmpl
 	return nil
 }
 
-// setupRoutes configures HTTP routes
-func (ws *WebServer) setupRoutes() {
+// setupRoutes builds the server's routes on a mux of its own, wrapped in the
+// request ID, request logging and security header middleware
+func (ws *WebServer) setupRoutes() http.Handler {
+	mux := http.NewServeMux()
+
 	// Static files
-	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("web/static/"))))
+	static, err := fs.Sub(assets.Static, "static")
+	if err != nil {
+		panic(err) // the embedded directory is fixed at build time
+	}
+	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.FS(static))))
 
 	// Documentation endpoints
-	http.HandleFunc("/", ws.handleHome)
-	http.HandleFunc("/help", ws.handleHelp)
-	http.HandleFunc("/invite", ws.handleInvite)
-	http.HandleFunc("/api/status", ws.handleAPIStatus)
-	http.HandleFunc("/api/games", ws.handleAPIGames)
+	mux.HandleFunc("/", ws.handleHome)
+	mux.HandleFunc("/help", ws.handleHelp)
+	mux.HandleFunc("/invite", ws.handleInvite)
+	mux.HandleFunc("/privacy", ws.handlePrivacy)
+	mux.HandleFunc("/api/status", ws.handleAPIStatus)
+	mux.HandleFunc("/api/games", ws.handleAPIGames)
+	mux.HandleFunc("/api/games/{id}/changes", ws.handleAPIGameChanges)
+	mux.HandleFunc("/api/scrape-history", ws.handleAPIScrapeHistory)
+	mux.HandleFunc("/history", ws.handleHistory)
+	mux.HandleFunc("/metrics", ws.handleMetrics)
+	mux.HandleFunc("/healthz", ws.handleHealthz)
+	mux.HandleFunc("/readyz", ws.handleReadyz)
+
+	// Admin endpoints are only exposed when an admin token is configured
+	if ws.config.AdminToken != "" {
+		mux.HandleFunc("/api/admin/decisions", ws.requireAdmin(ws.handleAPIAdminDecisions))
+		mux.HandleFunc("/api/admin/restarts", ws.requireAdmin(ws.handleAPIAdminRestarts))
+	}
+
+	return ws.withRequestID(ws.logRequests(security.SecurityHeaders(mux)))
+}
+
+// statusRecorder remembers the status code a handler wrote
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests logs every request with its status and duration
+func (ws *WebServer) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		ws.logger.WithContext(r.Context()).LogHTTPRequest(r.Method, r.URL.Path, recorder.status, time.Since(start), r.UserAgent())
+	})
 }
 
 // Page data structures
@@ -82,14 +194,9 @@ type PageData struct {
 	GameCount   int
 	LastUpdate  time.Time
 	Games       interface{}
-}
-
-type StatusData struct {
-	Status      string    `json:"status"`
-	ServerCount int       `json:"server_count"`
-	GameCount   int       `json:"game_count"`
-	LastUpdate  time.Time `json:"last_update"`
-	Uptime      string    `json:"uptime"`
+	// StaleWarning is shown as a banner when the games are out of date
+	StaleWarning string
+	Branding     config.BrandingConfig
 }
 
 // Route handlers
@@ -102,7 +209,7 @@ func (ws *WebServer) handleHome(w http.ResponseWriter, r *http.Request) {
 }
 
 func (ws *WebServer) handleHelp(w http.ResponseWriter, r *http.Request) {
-	data := ws.getPageData("Free Games Bot - Complete Documentation")
+	data := ws.getPageData(ws.config.Branding.Name + " - Complete Documentation")
 	ws.renderTemplate(w, "documentation", data)
 }
 
@@ -113,13 +220,14 @@ func (ws *WebServer) handleInvite(w http.ResponseWriter, r *http.Request) {
 	clientID := "1393810058441392230"
 	permissions := "2147485696"
 	inviteURL := fmt.Sprintf("https://discord.com/api/oauth2/authorize?client_id=%s&permissions=%s&scope=bot%%20applications.commands", clientID, permissions)
+	branding := ws.config.Branding
 
 	fmt.Fprintf(w, `<!DOCTYPE html>
 <html lang="en">
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>Invite Free Games Bot</title>
+    <title>Invite %[2]s</title>
     <style>
         body { font-family: 'Segoe UI', sans-serif; background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%); margin: 0; padding: 20px; min-height: 100vh; display: flex; align-items: center; justify-content: center; }
         .container { background: white; border-radius: 12px; box-shadow: 0 8px 32px rgba(0,0,0,0.1); padding: 40px; max-width: 600px; text-align: center; }
@@ -138,19 +246,20 @@ func (ws *WebServer) handleInvite(w http.ResponseWriter, r *http.Request) {
         .steps li { margin-bottom: 10px; line-height: 1.5; }
         .note { background: #fff3cd; border: 1px solid #ffeaa7; color: #856404; padding: 15px; border-radius: 8px; margin-top: 20px; }
         .warning { background: #f8d7da; border: 1px solid #f5c6cb; color: #721c24; padding: 15px; border-radius: 8px; margin-bottom: 20px; }
+        %[4]s
     </style>
 </head>
 <body>
     <div class="container">
-        <div class="logo">🎮</div>
-        <h1>Invite Free Games Bot</h1>
-        <p class="subtitle">Add the Free Games Bot to your Discord server and never miss a free game again!</p>
+        <div class="logo">%[3]s</div>
+        <h1>Invite %[2]s</h1>
+        <p class="subtitle">Add %[2]s to your Discord server and never miss a free game again!</p>
         
         <div class="warning">
             <strong>⚠️ Setup Required:</strong> You need to configure your bot's Client ID first. See the setup guide below.
         </div>
         
-        <a href="%s" class="invite-button" target="_blank">📨 Invite Bot to Discord</a>
+        <a href="%[1]s" class="invite-button" target="_blank">📨 Invite Bot to Discord</a>
         
         <div class="permissions">
             <h3>🔐 Required Permissions</h3>
@@ -186,52 +295,299 @@ func (ws *WebServer) handleInvite(w http.ResponseWriter, r *http.Request) {
         </p>
     </div>
 </body>
-</html>`, inviteURL)
+</html>`, inviteURL, html.EscapeString(branding.Name), brandLogoHTML(branding, "4rem"), brandAccentCSS(branding, "h1, .permissions h3, .steps h3 { color: %s; } .invite-button { background: %s; }"))
 }
 
 func (ws *WebServer) handleAPIStatus(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-
 	serverCount, _ := ws.db.GetServerCount()
 	games, _ := ws.gameService.GetActiveGames()
 	gameCount := len(games.FreeNow) + len(games.ComingSoon)
 
-	status := StatusData{
+	status := api.StatusResponse{
 		Status:      "online",
 		ServerCount: serverCount,
 		GameCount:   gameCount,
-		LastUpdate:  time.Now(),
-		Uptime:      "24/7",
+		LastUpdate:  time.Now().UTC(),
+		Uptime:      ws.metrics.GetUptime().Round(time.Second).String(),
+	}
+	if ws.checkReadiness(r.Context()).Status != api.HealthOK {
+		status.Status = "degraded"
+	}
+	if freshness, err := ws.gameService.Freshness(time.Now()); err == nil {
+		if !freshness.LastScrape.IsZero() {
+			status.LastUpdate = freshness.LastScrape.UTC()
+		}
+		if freshness.Outage {
+			status.Message = service.OutageMessage
+		}
 	}
 
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	fmt.Fprintf(w, `{
-		"status": "%s",
-		"server_count": %d,
-		"game_count": %d,
-		"last_update": "%s",
-		"uptime": "%s"
-	}`, status.Status, status.ServerCount, status.GameCount,
-		status.LastUpdate.Format(time.RFC3339), status.Uptime)
+	ws.writeJSON(w, http.StatusOK, status)
+}
+
+// maxGamesLimit caps the ?limit= parameter of /api/games
+const maxGamesLimit = 100
+
+// Output formats of /api/games selected with ?format=
+const (
+	gamesFormatJSON = "json"
+	gamesFormatRSS  = "rss"
+)
+
+// gameStatusFilters maps the ?status= values of /api/games to game statuses
+var gameStatusFilters = map[string]string{
+	"free_now":    models.StatusFreeNow,
+	"coming_soon": models.StatusComingSoon,
 }
 
 func (ws *WebServer) handleAPIGames(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
+	query := r.URL.Query()
+	status := ""
+	if value := query.Get("status"); value != "" {
+		var ok bool
+		if status, ok = gameStatusFilters[value]; !ok {
+			ws.writeJSON(w, http.StatusBadRequest, api.ErrorResponse{Error: "status must be free_now or coming_soon"})
+			return
+		}
+	}
+
+	limit := maxGamesLimit
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			ws.writeJSON(w, http.StatusBadRequest, api.ErrorResponse{Error: "limit must be a positive integer"})
+			return
+		}
+		if n < limit {
+			limit = n
+		}
+	}
+
+	format := query.Get("format")
+	switch format {
+	case "", gamesFormatJSON:
+	case gamesFormatRSS:
+		// The feed lists the currently free games unless asked otherwise
+		if status == "" {
+			status = models.StatusFreeNow
+		}
+	default:
+		ws.writeJSON(w, http.StatusBadRequest, api.ErrorResponse{Error: "format must be json or rss"})
+		return
+	}
+
+	// Pollers revalidate with the ETag; an unchanged response is answered
+	// without loading the games
+	freshness, err := ws.gameService.Freshness(time.Now())
+	if err != nil {
+		log.Printf("Error checking data freshness: %v", err)
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(gamesCacheMaxAge/time.Second)))
+	if etag, err := ws.gamesETag(r.URL.RawQuery, freshness.Stale); err != nil {
+		log.Printf("Error computing games ETag: %v", err)
+	} else {
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
 	games, err := ws.gameService.GetActiveGames()
 	if err != nil {
-		http.Error(w, "Failed to get games", http.StatusInternalServerError)
+		ws.writeJSON(w, http.StatusInternalServerError, api.ErrorResponse{Error: "Failed to get games"})
 		return
 	}
 
-	fmt.Fprintf(w, `{
-		"free_now": %d,
-		"coming_soon": %d,
-		"total": %d,
-		"last_updated": "%s"
-	}`, len(games.FreeNow), len(games.ComingSoon),
-		len(games.FreeNow)+len(games.ComingSoon), time.Now().Format(time.RFC3339))
+	// Most recent promotions first
+	listed := append(append([]models.Game{}, games.FreeNow...), games.ComingSoon...)
+	sort.SliceStable(listed, func(i, j int) bool {
+		return listed[i].FreeFromTime.After(listed[j].FreeFromTime)
+	})
+
+	response := api.GamesResponse{
+		FreeNow:     len(games.FreeNow),
+		ComingSoon:  len(games.ComingSoon),
+		Total:       len(games.FreeNow) + len(games.ComingSoon),
+		LastUpdated: time.Now(),
+		Games:       make([]api.Game, 0, len(listed)),
+	}
+	if !freshness.LastScrape.IsZero() {
+		response.LastUpdated = freshness.LastScrape
+		response.Stale = freshness.Stale
+		response.DataAgeSeconds = int64(freshness.Age / time.Second)
+	}
+	for _, game := range listed {
+		if len(response.Games) == limit {
+			break
+		}
+		if status != "" && game.Status != status {
+			continue
+		}
+		response.Games = append(response.Games, api.Game{
+			ID:         game.ID,
+			Title:      game.Title,
+			Status:     game.Status,
+			ImageURL:   game.ImageURL,
+			Images:     game.Images,
+			FreeFrom:   game.FreeFrom,
+			FreeTo:     game.FreeTo,
+			FreeFromAt: apiTime(game.FreeFromTime),
+			FreeToAt:   apiTime(game.FreeToTime),
+			StoreURL:   game.StoreURL,
+			Source:     game.Source,
+		})
+	}
+
+	if format == gamesFormatRSS {
+		ws.writeRSS(w, response)
+		return
+	}
+	ws.writeJSON(w, http.StatusOK, response)
+}
+
+// gamesCacheMaxAge is how long clients may cache /api/games without
+// revalidating
+const gamesCacheMaxAge = 5 * time.Minute
+
+// gamesETag derives a weak validator for /api/games from the active games
+// version, the staleness flag and the query, since each query selects a
+// different representation. It is weak because data_age_seconds keeps
+// counting without the games changing.
+func (ws *WebServer) gamesETag(rawQuery string, stale bool) (string, error) {
+	version, err := ws.gameService.GetActiveGamesVersion()
+	if err != nil {
+		return "", err
+	}
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%s|%t|%s", version, stale, rawQuery)
+	return fmt.Sprintf(`W/"%x"`, hash.Sum64()), nil
+}
+
+func (ws *WebServer) handleAPIGameChanges(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	gameID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || gameID < 1 {
+		ws.writeJSON(w, http.StatusBadRequest, api.ErrorResponse{Error: "id must be a positive integer"})
+		return
+	}
+
+	title, changes, err := ws.gameService.GetGameChanges(gameID)
+	if err != nil {
+		log.Printf("Error loading changes of game %d: %v", gameID, err)
+		ws.writeJSON(w, http.StatusInternalServerError, api.ErrorResponse{Error: "Failed to get game changes"})
+		return
+	}
+	if title == "" {
+		ws.writeJSON(w, http.StatusNotFound, api.ErrorResponse{Error: "game not found"})
+		return
+	}
+
+	response := api.GameChangesResponse{
+		GameID:  gameID,
+		Title:   title,
+		Changes: make([]api.GameChange, 0, len(changes)),
+	}
+	for _, change := range changes {
+		entry := api.GameChange{
+			Field:      change.Field,
+			OldValue:   change.OldValue,
+			NewValue:   change.NewValue,
+			DetectedAt: change.DetectedAt.UTC(),
+		}
+		if change.Field == models.FieldTitle || change.Field == models.FieldPeriod {
+			entry.Diff = models.WordDiff(change.OldValue, change.NewValue)
+		}
+		response.Changes = append(response.Changes, entry)
+	}
+
+	ws.writeJSON(w, http.StatusOK, response)
+}
+
+// requireAdmin rejects requests without the configured admin bearer token
+func (ws *WebServer) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(ws.config.AdminToken)) != 1 {
+			ws.writeJSON(w, http.StatusUnauthorized, api.ErrorResponse{Error: "unauthorized"})
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (ws *WebServer) handleAPIAdminDecisions(w http.ResponseWriter, r *http.Request) {
+	guildID := r.URL.Query().Get("guild_id")
+	if err := security.ValidateDiscordID(guildID); err != nil {
+		ws.writeJSON(w, http.StatusBadRequest, api.ErrorResponse{Error: "a valid guild_id is required"})
+		return
+	}
+
+	decisions, err := ws.db.GetLatestDeliveryDecisions(guildID)
+	if err != nil {
+		log.Printf("Error loading delivery decisions: %v", err)
+		ws.writeJSON(w, http.StatusInternalServerError, api.ErrorResponse{Error: "Failed to get delivery decisions"})
+		return
+	}
+
+	response := api.DeliveryDecisionsResponse{
+		GuildID:   guildID,
+		Decisions: make([]api.DeliveryDecision, 0, len(decisions)),
+	}
+	for _, decision := range decisions {
+		response.Decisions = append(response.Decisions, api.DeliveryDecision{
+			CycleID:   decision.CycleID,
+			GuildID:   decision.GuildID,
+			ChannelID: decision.ChannelID,
+			GameTitle: decision.GameTitle,
+			FreeTo:    decision.FreeTo,
+			Delivered: decision.Delivered,
+			Reason:    string(decision.Reason),
+			Detail:    decision.Reason.Description(),
+			Policy:    decision.Policy,
+			DecidedAt: decision.DecidedAt,
+		})
+	}
+
+	ws.writeJSON(w, http.StatusOK, response)
+}
+
+// restartHistoryLimit caps how many runs /api/admin/restarts returns
+const restartHistoryLimit = 50
+
+func (ws *WebServer) handleAPIAdminRestarts(w http.ResponseWriter, r *http.Request) {
+	restarts, err := ws.db.GetRestarts(restartHistoryLimit)
+	if err != nil {
+		log.Printf("Error loading restart history: %v", err)
+		ws.writeJSON(w, http.StatusInternalServerError, api.ErrorResponse{Error: "Failed to get restart history"})
+		return
+	}
+
+	response := api.RestartsResponse{Restarts: make([]api.Restart, 0, len(restarts))}
+	for _, restart := range restarts {
+		response.Restarts = append(response.Restarts, api.Restart{
+			ID:            restart.ID,
+			StartedAt:     restart.StartedAt,
+			StoppedAt:     restart.StoppedAt,
+			Version:       restart.Version,
+			CleanShutdown: restart.CleanShutdown,
+		})
+	}
+
+	ws.writeJSON(w, http.StatusOK, response)
+}
+
+// apiTime returns t in UTC for an optional API timestamp, or nil for the zero time
+func apiTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
 }
 
 // Helper functions
@@ -240,191 +596,67 @@ func (ws *WebServer) getPageData(title string) PageData {
 	games, _ := ws.gameService.GetActiveGames()
 	gameCount := len(games.FreeNow) + len(games.ComingSoon)
 
-	return PageData{
+	data := PageData{
 		Title:       title,
 		Description: "Epic Games Store Free Games Discord Bot",
 		ServerCount: serverCount,
 		GameCount:   gameCount,
 		LastUpdate:  time.Now(),
 		Games:       games,
+		Branding:    ws.config.Branding,
+	}
+	if freshness, err := ws.gameService.Freshness(time.Now()); err != nil {
+		log.Printf("Error checking data freshness: %v", err)
+	} else if !freshness.LastScrape.IsZero() {
+		data.LastUpdate = freshness.LastScrape
+		data.StaleWarning = freshness.Warning()
 	}
+	return data
 }
 
-func (ws *WebServer) renderTemplate(w http.ResponseWriter, tmplName string, data PageData) {
-	if ws.templates != nil {
-		err := ws.templates.ExecuteTemplate(w, tmplName+".html", data)
-		if err != nil {
-			log.Printf("Template error: %v", err)
-			ws.renderInlineTemplate(w, tmplName, data)
-		}
-	} else {
-		ws.renderInlineTemplate(w, tmplName, data)
+// writeJSON encodes v as the JSON response body with the given status code.
+// The body is marshaled before anything is written so an encoding failure
+// produces a clean 500 rather than a truncated document.
+func (ws *WebServer) writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+		statusCode = http.StatusInternalServerError
+		body = []byte(`{"error":"failed to encode response"}`)
 	}
-}
 
-// createInlineTemplates creates templates when files don't exist
-func (ws *WebServer) createInlineTemplates() {
-	log.Println("Using inline templates")
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	w.Write(append(body, '\n'))
 }
 
-// renderInlineTemplate renders templates inline when files are not available
-func (ws *WebServer) renderInlineTemplate(w http.ResponseWriter, tmplName string, data PageData) {
+// renderTemplate renders a page, answering 500 rather than sending half a
+// page when the template fails
+func (ws *WebServer) renderTemplate(w http.ResponseWriter, tmplName string, data PageData) {
+	var buf bytes.Buffer
+	if err := ws.templates.ExecuteTemplate(&buf, tmplName+".html", data); err != nil {
+		log.Printf("Template error: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	buf.WriteTo(w)
+}
 
-	// Render the documentation template inline
-	fmt.Fprintf(w, `<!DOCTYPE html>
-<html lang="en">
-<head>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>%s</title>
-    <style>
-        body { font-family: 'Segoe UI', sans-serif; background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%); margin: 0; padding: 20px; }
-        .container { max-width: 1200px; margin: 0 auto; }
-        .header { background: white; border-radius: 8px; box-shadow: 0 2px 10px rgba(0,0,0,0.1); margin-bottom: 30px; padding: 30px; }
-        .header-content { display: flex; justify-content: space-between; align-items: center; flex-wrap: wrap; }
-        .logo h1 { color: #7289da; margin: 0; }
-        .stats { display: flex; gap: 20px; }
-        .stat-item { text-align: center; }
-        .stat-number { display: block; font-size: 2rem; font-weight: bold; color: #7289da; }
-        .stat-label { color: #72767d; font-size: 0.9rem; }
-        .content { background: white; border-radius: 8px; box-shadow: 0 2px 10px rgba(0,0,0,0.1); padding: 40px; }
-        .feature-grid { display: grid; grid-template-columns: repeat(auto-fit, minmax(300px, 1fr)); gap: 20px; margin: 30px 0; }
-        .feature-card { background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%); color: white; padding: 30px; border-radius: 8px; text-align: center; }
-        .feature-icon { font-size: 3rem; margin-bottom: 15px; }
-        .command-grid { display: grid; grid-template-columns: repeat(auto-fit, minmax(300px, 1fr)); gap: 15px; margin: 30px 0; }
-        .command-item { background: #f8f9fa; padding: 20px; border-radius: 8px; border-left: 4px solid #7289da; }
-        .badge { background: #f04747; color: white; padding: 2px 6px; border-radius: 4px; font-size: 0.8rem; }
-        code { background: #2c2f33; color: white; padding: 4px 8px; border-radius: 4px; }
-        .cta { text-align: center; margin-top: 40px; padding: 30px; background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%); color: white; border-radius: 8px; }
-        .invite-section { background: #e8f5e8; border: 2px solid #28a745; border-radius: 8px; padding: 20px; margin: 30px 0; text-align: center; }
-        .invite-button { background: #28a745; color: white; padding: 12px 24px; border: none; border-radius: 6px; font-size: 1rem; font-weight: bold; text-decoration: none; display: inline-block; margin: 10px; }
-        .invite-button:hover { background: #218838; }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <header class="header">
-            <div class="header-content">
-                <div class="logo">
-                    <h1>🎮 Free Games Bot</h1>
-                    <p>Epic Games Store Discord Bot - Complete Documentation</p>
-                </div>
-                <div class="stats">
-                    <div class="stat-item">
-                        <span class="stat-number">%d</span>
-                        <span class="stat-label">Servers</span>
-                    </div>
-                    <div class="stat-item">
-                        <span class="stat-number">%d</span>
-                        <span class="stat-label">Active Games</span>
-                    </div>
-                    <div class="stat-item">
-                        <span class="stat-number">Online</span>
-                        <span class="stat-label">Status</span>
-                    </div>
-                </div>
-            </div>
-        </header>
-        
-        <div class="content">
-            <div class="invite-section">
-                <h2 style="color: #28a745; margin-top: 0;">📨 Invite Bot to Your Server</h2>
-                <p>Ready to add the Free Games Bot to your Discord server?</p>
-                <a href="/invite" class="invite-button">Get Invite Link</a>
-                <a href="#setup" class="invite-button" style="background: #007bff;">Setup Guide</a>
-            </div>
-            
-            <h2 style="color: #7289da; text-align: center; margin-bottom: 30px;">🎮 Free Games Bot Documentation</h2>
-            
-            <div class="feature-grid">
-                <div class="feature-card">
-                    <div class="feature-icon">🔄</div>
-                    <h3>Automatic Monitoring</h3>
-                    <p>Checks Epic Games Store every 6 hours for new free games</p>
-                </div>
-                <div class="feature-card">
-                    <div class="feature-icon">💾</div>
-                    <h3>Smart Caching</h3>
-                    <p>SQLite database prevents duplicate notifications</p>
-                </div>
-                <div class="feature-card">
-                    <div class="feature-icon">🎯</div>
-                    <h3>Multi-Server Support</h3>
-                    <p>Configure different channels for each Discord server</p>
-                </div>
-            </div>
-            
-            <h3 id="setup" style="color: #7289da; margin-bottom: 20px;">🚀 Quick Setup</h3>
-            <ol style="line-height: 1.8; margin-bottom: 30px;">
-                <li><strong>Invite the bot</strong> using the invite link above</li>
-                <li><strong>Run setup:</strong> <code>/setup #your-channel</code></li>
-                <li><strong>Test it:</strong> <code>/games</code></li>
-                <li><strong>Enjoy automatic notifications!</strong></li>
-            </ol>
-            
-            <h3 style="color: #7289da; margin-bottom: 20px;">🎯 Available Commands</h3>
-            <div class="command-grid">
-                <div class="command-item">
-                    <strong>/setup &lt;channel&gt;</strong> <span class="badge">ADMIN</span>
-                    <p style="margin: 5px 0 0 0; color: #72767d;">Configure the bot for this server</p>
-                </div>
-                <div class="command-item">
-                    <strong>/games</strong>
-                    <p style="margin: 5px 0 0 0; color: #72767d;">Show current free games</p>
-                </div>
-                <div class="command-item">
-                    <strong>/refresh</strong> <span class="badge">ADMIN</span>
-                    <p style="margin: 5px 0 0 0; color: #72767d;">Manually refresh games</p>
-                </div>
-                <div class="command-item">
-                    <strong>/status</strong>
-                    <p style="margin: 5px 0 0 0; color: #72767d;">Show bot status and configuration</p>
-                </div>
-            </div>
-            
-            <h3 style="color: #7289da; margin-bottom: 20px;">✨ Key Features</h3>
-            <ul style="line-height: 1.8; margin-bottom: 30px;">
-                <li><strong>Automatic Monitoring:</strong> Checks Epic Games Store every 6 hours</li>
-                <li><strong>Rich Embeds:</strong> Beautiful Discord messages with game images</li>
-                <li><strong>Smart Notifications:</strong> Only notifies about new games, no duplicates</li>
-                <li><strong>Multi-Server:</strong> Each server has its own configuration</li>
-                <li><strong>Instant Commands:</strong> Fast responses using cached database data</li>
-                <li><strong>Cross-Platform:</strong> Works on Windows, macOS, and Linux</li>
-            </ul>
-            
-            <h3 style="color: #7289da; margin-bottom: 20px;">🔌 API Endpoints</h3>
-            <ul style="line-height: 1.8; margin-bottom: 30px;">
-                <li><strong>GET /help</strong> - This documentation page</li>
-                <li><strong>GET /invite</strong> - Bot invitation page</li>
-                <li><strong>GET /api/status</strong> - Bot status and statistics</li>
-                <li><strong>GET /api/games</strong> - Current game information</li>
-            </ul>
-            
-            <h3 style="color: #7289da; margin-bottom: 20px;">🔧 Troubleshooting</h3>
-            <div style="background: #f8f9fa; padding: 20px; border-radius: 8px; border-left: 4px solid #faa61a;">
-                <p><strong>Bot not responding?</strong></p>
-                <ul style="margin: 10px 0;">
-                    <li>Ensure you've run <code>/setup</code> first</li>
-                    <li>Check bot permissions (Send Messages, Use Slash Commands, Embed Links)</li>
-                    <li>Try commands in the configured channel</li>
-                    <li>Wait up to 1 hour for slash commands to sync globally</li>
-                </ul>
-            </div>
-            
-            <div class="cta">
-                <h3>🎮 Ready to Get Started?</h3>
-                <p>Add the Free Games Bot to your Discord server and never miss a free game again!</p>
-                <a href="/invite" class="invite-button">Get Invite Link</a>
-                <p style="margin-top: 20px; font-size: 0.9rem; opacity: 0.8;">
-                    Bot Status: <strong>Online</strong> | 
-                    Servers: <strong>%d</strong> | 
-                    Active Games: <strong>%d</strong>
-                </p>
-            </div>
-        </div>
-    </div>
-</body>
-</html>`, data.Title, data.ServerCount, data.GameCount, data.ServerCount, data.GameCount)
+// brandLogoHTML renders the configured logo at the given height for the
+// inline pages, or the default emoji when no logo is set
+func brandLogoHTML(branding config.BrandingConfig, height string) string {
+	if branding.LogoURL == "" {
+		return "🎮"
+	}
+	return fmt.Sprintf(`<img src="%s" alt="" style="height: %s; vertical-align: middle;">`, html.EscapeString(branding.LogoURL), height)
 }
 
+// brandAccentCSS fills every %s in rules with the custom accent color, or
+// returns "" so the inline pages keep their palette
+func brandAccentCSS(branding config.BrandingConfig, rules string) string {
+	if !branding.HasCustomAccent() {
+		return ""
+	}
+	return strings.ReplaceAll(rules, "%s", branding.HexColor())
+}
@@ -0,0 +1,326 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"free-games-scrape/internal/config"
+	"free-games-scrape/internal/database"
+	"free-games-scrape/internal/logger"
+	"free-games-scrape/internal/metrics"
+	"free-games-scrape/internal/models"
+	"free-games-scrape/internal/service"
+	"free-games-scrape/pkg/api"
+)
+
+// fakeGateway reports a fixed gateway connection state
+type fakeGateway bool
+
+func (g fakeGateway) Connected() bool { return bool(g) }
+
+// newTestServer returns a web server over a private in-memory database and
+// the handler serving its routes
+func newTestServer(t *testing.T, cfg *config.WebConfig) (*WebServer, http.Handler) {
+	t.Helper()
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := database.New(fmt.Sprintf("file:%s?mode=memory&cache=shared", name))
+	if err != nil {
+		t.Fatalf("database.New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if cfg == nil {
+		cfg = &config.WebConfig{}
+	}
+	appMetrics := metrics.New()
+	appLogger := logger.NewWithOutput(logger.LevelError, "test", io.Discard)
+	ws := NewWebServer(cfg, service.NewGameService(db, appMetrics), db, appMetrics, fakeGateway(true), appLogger)
+	if err := ws.loadTemplates(); err != nil {
+		t.Fatalf("loadTemplates: %v", err)
+	}
+	return ws, ws.setupRoutes()
+}
+
+// get serves a GET request for target and returns the recorded response
+func get(handler http.Handler, target string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	return recorder
+}
+
+func TestAPIGamesEncodesTitles(t *testing.T) {
+	ws, handler := newTestServer(t, nil)
+
+	titles := []string{"He said \"hi\"\n", `Back\slash`, "Tab\tand <html> & unicode ✓"}
+	var games []models.Game
+	for _, title := range titles {
+		games = append(games, models.Game{Title: title, Status: models.StatusFreeNow, FreeFrom: "Jul 17", FreeTo: "Jul 24"})
+	}
+	if _, err := ws.gameService.SaveGames(games); err != nil {
+		t.Fatalf("SaveGames: %v", err)
+	}
+
+	response := get(handler, "/api/games")
+	if response.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", response.Code)
+	}
+	if contentType := response.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+
+	var decoded api.GamesResponse
+	if err := json.Unmarshal(response.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("response is not valid JSON: %v\n%s", err, response.Body)
+	}
+	got := make(map[string]bool)
+	for _, game := range decoded.Games {
+		got[game.Title] = true
+	}
+	for _, title := range titles {
+		if !got[title] {
+			t.Errorf("title %q did not survive the round trip, got %v", title, decoded.Games)
+		}
+	}
+}
+
+func TestAPIStatusIsJSON(t *testing.T) {
+	_, handler := newTestServer(t, nil)
+
+	response := get(handler, "/api/status")
+	if contentType := response.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+	var status api.StatusResponse
+	if err := json.Unmarshal(response.Body.Bytes(), &status); err != nil {
+		t.Fatalf("response is not valid JSON: %v\n%s", err, response.Body)
+	}
+	if status.Status == "" || status.Uptime == "" {
+		t.Errorf("status = %+v, want status and uptime set", status)
+	}
+}
+
+func TestWriteJSONEncodingFailure(t *testing.T) {
+	ws, _ := newTestServer(t, nil)
+
+	recorder := httptest.NewRecorder()
+	ws.writeJSON(recorder, http.StatusOK, map[string]interface{}{"bad": make(chan int)})
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500 for a value that can't be encoded", recorder.Code)
+	}
+	if !json.Valid(recorder.Body.Bytes()) {
+		t.Errorf("error body is not valid JSON: %s", recorder.Body)
+	}
+}
+
+func TestStaleGamesAreFlagged(t *testing.T) {
+	tests := []struct {
+		name      string
+		age       time.Duration
+		wantStale bool
+	}{
+		{name: "fresh", age: time.Hour},
+		{name: "stale", age: 38 * time.Hour, wantStale: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws, handler := newTestServer(t, nil)
+			ws.gameService.SetRefreshInterval(24 * time.Hour)
+			if _, err := ws.gameService.SaveGames([]models.Game{{Title: "Game", Status: models.StatusFreeNow, FreeFrom: "Jul 17", FreeTo: "Jul 24"}}); err != nil {
+				t.Fatalf("SaveGames: %v", err)
+			}
+			if err := ws.db.SetLastScrapeTime(time.Now().Add(-tt.age)); err != nil {
+				t.Fatalf("SetLastScrapeTime: %v", err)
+			}
+
+			var games api.GamesResponse
+			if err := json.Unmarshal(get(handler, "/api/games").Body.Bytes(), &games); err != nil {
+				t.Fatalf("decoding /api/games: %v", err)
+			}
+			if games.Stale != tt.wantStale {
+				t.Errorf("stale = %v, want %v", games.Stale, tt.wantStale)
+			}
+			if age := time.Duration(games.DataAgeSeconds) * time.Second; age < tt.age-time.Minute || age > tt.age+time.Minute {
+				t.Errorf("data_age_seconds = %d, want about %v", games.DataAgeSeconds, tt.age)
+			}
+
+			page := get(handler, "/help").Body.String()
+			if hasBanner := strings.Contains(page, "stale-banner"); hasBanner != tt.wantStale {
+				t.Errorf("page shows the stale banner = %v, want %v", hasBanner, tt.wantStale)
+			}
+			if tt.wantStale && !strings.Contains(page, "38 hours old") {
+				t.Error("the banner does not say how old the data is")
+			}
+		})
+	}
+}
+
+func TestPagesUseBranding(t *testing.T) {
+	branding := config.BrandingConfig{
+		Name:        "Loot Herald",
+		Footer:      "Loot Herald by the Guild",
+		LogoURL:     "https://example.com/herald.png",
+		AccentColor: 0xaa3377,
+	}
+	_, handler := newTestServer(t, &config.WebConfig{Branding: branding})
+
+	for _, path := range []string{"/help", "/invite", "/privacy", "/history"} {
+		t.Run(path, func(t *testing.T) {
+			response := get(handler, path)
+			if response.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", response.Code)
+			}
+			page := response.Body.String()
+			for _, want := range []string{branding.Name, branding.LogoURL, branding.HexColor()} {
+				if !strings.Contains(page, want) {
+					t.Errorf("page does not contain %q", want)
+				}
+			}
+			if strings.Contains(page, config.DefaultBrandingName) {
+				t.Errorf("page shows the default name %q", config.DefaultBrandingName)
+			}
+		})
+	}
+}
+
+func TestAPIGameChanges(t *testing.T) {
+	ws, handler := newTestServer(t, nil)
+
+	game := models.Game{Title: "Control", Status: models.StatusFreeNow, FreeFrom: "Jul 24", FreeTo: "Jul 31", StoreURL: "https://store.epicgames.com/p/control"}
+	renamed := game
+	renamed.Title = "Control Ultimate Edition"
+	for _, games := range [][]models.Game{{game}, {renamed}} {
+		if _, err := ws.gameService.SaveGames(games); err != nil {
+			t.Fatalf("SaveGames: %v", err)
+		}
+	}
+	stored, err := ws.gameService.GetGameByTitle(renamed.Title)
+	if err != nil || stored == nil {
+		t.Fatalf("GetGameByTitle = %v, %v", stored, err)
+	}
+
+	response := get(handler, fmt.Sprintf("/api/games/%d/changes", stored.ID))
+	if response.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", response.Code, response.Body)
+	}
+	var changes api.GameChangesResponse
+	if err := json.Unmarshal(response.Body.Bytes(), &changes); err != nil {
+		t.Fatalf("decoding changes: %v", err)
+	}
+	if changes.GameID != stored.ID || changes.Title != renamed.Title || len(changes.Changes) != 1 {
+		t.Fatalf("changes = %+v, want the rename of game %d", changes, stored.ID)
+	}
+	if change := changes.Changes[0]; change.Field != models.FieldTitle || change.Diff != "Control {+Ultimate Edition+}" {
+		t.Errorf("change = %+v, want a title change with its word diff", change)
+	}
+
+	for target, want := range map[string]int{
+		"/api/games/0/changes":    http.StatusBadRequest,
+		"/api/games/abc/changes":  http.StatusBadRequest,
+		"/api/games/9999/changes": http.StatusNotFound,
+	} {
+		if got := get(handler, target).Code; got != want {
+			t.Errorf("GET %s = %d, want %d", target, got, want)
+		}
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	// Templates and static files are embedded, so the server does not care
+	// where it is started from
+	t.Chdir(t.TempDir())
+	_, handler := newTestServer(t, nil)
+
+	tests := []struct {
+		path            string
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{path: "/help", wantStatus: http.StatusOK, wantContentType: "text/html", wantBody: "/static/css/documentation.css"},
+		{path: "/invite", wantStatus: http.StatusOK, wantContentType: "text/html", wantBody: "discord.com/api/oauth2/authorize"},
+		{path: "/api/status", wantStatus: http.StatusOK, wantContentType: "application/json", wantBody: `"status"`},
+		{path: "/static/css/style.css", wantStatus: http.StatusOK, wantContentType: "text/css"},
+		{path: "/", wantStatus: http.StatusMovedPermanently},
+		{path: "/no-such-page", wantStatus: http.StatusNotFound},
+		{path: "/api/no-such-endpoint", wantStatus: http.StatusNotFound},
+		{path: "/static/missing.css", wantStatus: http.StatusNotFound},
+		{path: "/api/admin/restarts", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			response := get(handler, tt.path)
+			if response.Code != tt.wantStatus {
+				t.Fatalf("GET %s = %d, want %d", tt.path, response.Code, tt.wantStatus)
+			}
+			if contentType := response.Header().Get("Content-Type"); !strings.HasPrefix(contentType, tt.wantContentType) {
+				t.Errorf("Content-Type = %q, want %s", contentType, tt.wantContentType)
+			}
+			if !strings.Contains(response.Body.String(), tt.wantBody) {
+				t.Errorf("body does not contain %q", tt.wantBody)
+			}
+
+			// Every response, errors included, carries the security headers
+			// and a request ID
+			for header, want := range map[string]string{
+				"X-Frame-Options":        "DENY",
+				"X-Content-Type-Options": "nosniff",
+				"Referrer-Policy":        "strict-origin-when-cross-origin",
+			} {
+				if got := response.Header().Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+			if response.Header().Get("Content-Security-Policy") == "" {
+				t.Error("no Content-Security-Policy header")
+			}
+			if response.Header().Get("X-Request-ID") == "" {
+				t.Error("no X-Request-ID header")
+			}
+		})
+	}
+}
+
+func TestHomeRedirectsToHelp(t *testing.T) {
+	_, handler := newTestServer(t, nil)
+	if location := get(handler, "/").Header().Get("Location"); location != "/help" {
+		t.Errorf("Location = %q, want /help", location)
+	}
+}
+
+func TestServersHaveTheirOwnRoutes(t *testing.T) {
+	// Two servers in one process used to clash on the default mux
+	_, public := newTestServer(t, nil)
+	_, admin := newTestServer(t, &config.WebConfig{AdminToken: "secret"})
+
+	if got := get(public, "/api/admin/restarts").Code; got != http.StatusNotFound {
+		t.Errorf("server without an admin token: GET /api/admin/restarts = %d, want 404", got)
+	}
+	if got := get(admin, "/api/admin/restarts").Code; got != http.StatusUnauthorized {
+		t.Errorf("server with an admin token: unauthenticated GET /api/admin/restarts = %d, want 401", got)
+	}
+	if got := get(public, "/help").Code; got != http.StatusOK {
+		t.Errorf("GET /help on the first server = %d, want 200", got)
+	}
+}
+
+func TestRequestsAreLogged(t *testing.T) {
+	ws, handler := newTestServer(t, nil)
+	var logged strings.Builder
+	ws.logger = logger.NewWithOutput(logger.LevelInfo, "production", &logged)
+
+	get(handler, "/no-such-page")
+
+	var entry map[string]interface{}
+	if err := json.NewDecoder(strings.NewReader(logged.String())).Decode(&entry); err != nil {
+		t.Fatalf("decoding the request log: %v\n%s", err, logged.String())
+	}
+	if entry["path"] != "/no-such-page" || entry["status_code"] != float64(http.StatusNotFound) {
+		t.Errorf("request log = %v, want the path and 404 status", entry)
+	}
+}
@@ -0,0 +1,144 @@
+// Package api defines the JSON response types served by the bot's HTTP API.
+// Both the web server and pkg/client use these types so they cannot drift.
+package api
+
+import "time"
+
+// StatusResponse is returned by GET /api/status. LastUpdate is in UTC.
+type StatusResponse struct {
+	Status      string    `json:"status"`
+	ServerCount int       `json:"server_count"`
+	GameCount   int       `json:"game_count"`
+	LastUpdate  time.Time `json:"last_update"`
+	Uptime      string    `json:"uptime"`
+	// Message explains a known problem, such as a store outage
+	Message string `json:"message,omitempty"`
+}
+
+// Health statuses reported by GET /healthz and GET /readyz
+const (
+	HealthOK          = "ok"
+	HealthUnavailable = "unavailable"
+)
+
+// HealthResponse is returned by GET /healthz and GET /readyz. Status is
+// HealthUnavailable, served with HTTP 503, when any check failed. Checks is
+// keyed by component and only set by /readyz.
+type HealthResponse struct {
+	Status        string                 `json:"status"`
+	UptimeSeconds int64                  `json:"uptime_seconds"`
+	Checks        map[string]HealthCheck `json:"checks,omitempty"`
+}
+
+// HealthCheck is the result of checking one component
+type HealthCheck struct {
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// GamesResponse is returned by GET /api/games. The counts cover every active
+// game; Games holds the listed games after the status and limit filters.
+// LastUpdated is the last successful scrape and Stale is set once it is older
+// than the refresh interval allows.
+type GamesResponse struct {
+	FreeNow        int       `json:"free_now"`
+	ComingSoon     int       `json:"coming_soon"`
+	Total          int       `json:"total"`
+	LastUpdated    time.Time `json:"last_updated"`
+	Stale          bool      `json:"stale"`
+	DataAgeSeconds int64     `json:"data_age_seconds"`
+	Games          []Game    `json:"games"`
+}
+
+// Game is a free game as listed by GET /api/games. FreeFrom and FreeTo are
+// display strings; FreeFromAt and FreeToAt are the exact promotion window
+// when known, FreeToAt being the moment the game stops being free.
+type Game struct {
+	ID         int64      `json:"id"`
+	Title      string     `json:"title"`
+	Status     string     `json:"status"`
+	ImageURL   string     `json:"image_url,omitempty"`
+	Images     []string   `json:"images,omitempty"`
+	FreeFrom   string     `json:"free_from,omitempty"`
+	FreeTo     string     `json:"free_to,omitempty"`
+	FreeFromAt *time.Time `json:"free_from_at,omitempty"`
+	FreeToAt   *time.Time `json:"free_to_at,omitempty"`
+	StoreURL   string     `json:"store_url,omitempty"`
+	Source     string     `json:"source,omitempty"`
+}
+
+// GameChange is one recorded edit of a game's metadata. Diff is a word-level
+// rendering of title and period edits, with removed words as [-words-] and
+// added ones as {+words+}.
+type GameChange struct {
+	Field      string    `json:"field"`
+	OldValue   string    `json:"old_value"`
+	NewValue   string    `json:"new_value"`
+	Diff       string    `json:"diff,omitempty"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// GameChangesResponse is returned by GET /api/games/{id}/changes, oldest
+// change first
+type GameChangesResponse struct {
+	GameID  int64        `json:"game_id"`
+	Title   string       `json:"title"`
+	Changes []GameChange `json:"changes"`
+}
+
+// ErrorResponse is returned by API endpoints when a request fails
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// DeliveryDecision describes whether a game was announced to a guild and, if not, why
+type DeliveryDecision struct {
+	CycleID   string    `json:"cycle_id"`
+	GuildID   string    `json:"guild_id"`
+	ChannelID string    `json:"channel_id"`
+	GameTitle string    `json:"game_title"`
+	FreeTo    string    `json:"free_to"`
+	Delivered bool      `json:"delivered"`
+	Reason    string    `json:"reason,omitempty"`
+	Detail    string    `json:"detail"`
+	Policy    string    `json:"policy,omitempty"`
+	DecidedAt time.Time `json:"decided_at"`
+}
+
+// DeliveryDecisionsResponse is returned by GET /api/admin/decisions
+type DeliveryDecisionsResponse struct {
+	GuildID   string             `json:"guild_id"`
+	Decisions []DeliveryDecision `json:"decisions"`
+}
+
+// ScrapeRun describes one scrape of the stores
+type ScrapeRun struct {
+	StartedAt  time.Time `json:"started_at"`
+	Success    bool      `json:"success"`
+	GamesFound int       `json:"games_found"`
+	DurationMS int64     `json:"duration_ms"`
+	// FailureClass is set for failed runs: upstream_outage, extraction_failure,
+	// timeout or unknown
+	FailureClass string `json:"failure_class,omitempty"`
+}
+
+// ScrapeHistoryResponse is returned by GET /api/scrape-history. Runs are most
+// recent first; SuccessRate is the share of them that succeeded, 0 to 1.
+type ScrapeHistoryResponse struct {
+	Runs        []ScrapeRun `json:"runs"`
+	SuccessRate float64     `json:"success_rate"`
+}
+
+// Restart describes one run of the bot
+type Restart struct {
+	ID            int64      `json:"id"`
+	StartedAt     time.Time  `json:"started_at"`
+	StoppedAt     *time.Time `json:"stopped_at,omitempty"`
+	Version       string     `json:"version"`
+	CleanShutdown bool       `json:"clean_shutdown"`
+}
+
+// RestartsResponse is returned by GET /api/admin/restarts
+type RestartsResponse struct {
+	Restarts []Restart `json:"restarts"`
+}
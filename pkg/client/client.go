@@ -0,0 +1,288 @@
+// Package client provides a typed Go client for the Free Games Bot HTTP API.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"free-games-scrape/pkg/api"
+)
+
+// Sentinel errors for well-known HTTP failures, usable with errors.Is
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrNotFound     = errors.New("not found")
+	ErrRateLimited  = errors.New("rate limited")
+)
+
+// APIError describes a non-2xx response from the API
+type APIError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("api error %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("api error %d", e.StatusCode)
+}
+
+// Unwrap maps the status code onto the package sentinel errors
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// Client talks to a running bot's HTTP API
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	token      string
+	maxRetries int
+	retryDelay time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithToken sets the bearer token used for authenticated endpoints
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithHTTPClient overrides the underlying HTTP client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries sets how many times idempotent requests are retried and the base delay between attempts
+func WithRetries(maxRetries int, delay time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryDelay = delay
+	}
+}
+
+// New creates a client for the API served at baseURL (e.g. "http://localhost:3000")
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL scheme: %q", parsed.Scheme)
+	}
+
+	c := &Client{
+		baseURL:    parsed,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		maxRetries: 2,
+		retryDelay: 500 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// Status fetches GET /api/status
+func (c *Client) Status(ctx context.Context) (*api.StatusResponse, error) {
+	var status api.StatusResponse
+	if err := c.get(ctx, "/api/status", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Games fetches GET /api/games
+func (c *Client) Games(ctx context.Context) (*api.GamesResponse, error) {
+	var games api.GamesResponse
+	if err := c.get(ctx, "/api/games", nil, &games); err != nil {
+		return nil, err
+	}
+	return &games, nil
+}
+
+// FilterGames fetches GET /api/games listing only games with the given status
+// ("free_now" or "coming_soon", empty for all), at most limit of them (0 for
+// the server default)
+func (c *Client) FilterGames(ctx context.Context, status string, limit int) (*api.GamesResponse, error) {
+	query := url.Values{}
+	if status != "" {
+		query.Set("status", status)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var games api.GamesResponse
+	if err := c.get(ctx, "/api/games", query, &games); err != nil {
+		return nil, err
+	}
+	return &games, nil
+}
+
+// GameChanges fetches GET /api/games/{id}/changes, the recorded metadata
+// edits of a game
+func (c *Client) GameChanges(ctx context.Context, id int64) (*api.GameChangesResponse, error) {
+	var changes api.GameChangesResponse
+	path := "/api/games/" + strconv.FormatInt(id, 10) + "/changes"
+	if err := c.get(ctx, path, nil, &changes); err != nil {
+		return nil, err
+	}
+	return &changes, nil
+}
+
+// ScrapeHistory fetches GET /api/scrape-history, at most limit runs (0 for
+// the server default)
+func (c *Client) ScrapeHistory(ctx context.Context, limit int) (*api.ScrapeHistoryResponse, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var history api.ScrapeHistoryResponse
+	if err := c.get(ctx, "/api/scrape-history", query, &history); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// DeliveryDecisions fetches GET /api/admin/decisions for a guild. Requires WithToken.
+func (c *Client) DeliveryDecisions(ctx context.Context, guildID string) (*api.DeliveryDecisionsResponse, error) {
+	var decisions api.DeliveryDecisionsResponse
+	query := url.Values{"guild_id": {guildID}}
+	if err := c.get(ctx, "/api/admin/decisions", query, &decisions); err != nil {
+		return nil, err
+	}
+	return &decisions, nil
+}
+
+// Restarts fetches GET /api/admin/restarts. Requires WithToken.
+func (c *Client) Restarts(ctx context.Context) (*api.RestartsResponse, error) {
+	var restarts api.RestartsResponse
+	if err := c.get(ctx, "/api/admin/restarts", nil, &restarts); err != nil {
+		return nil, err
+	}
+	return &restarts, nil
+}
+
+// get performs a GET request with retries and decodes the JSON response into out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := c.retryDelay * time.Duration(attempt)
+			var apiErr *APIError
+			if errors.As(lastErr, &apiErr) && apiErr.RetryAfter > delay {
+				delay = apiErr.RetryAfter
+			}
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		lastErr = c.do(ctx, http.MethodGet, path, query, out)
+		if lastErr == nil || !retryable(lastErr) {
+			return lastErr
+		}
+	}
+
+	return lastErr
+}
+
+// do performs a single request and decodes the JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, out interface{}) error {
+	endpoint := *c.baseURL
+	endpoint.Path = c.baseURL.Path + path
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", path, err)
+	}
+	return nil
+}
+
+// newAPIError builds an APIError from a failed response
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var errResp api.ErrorResponse
+	if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+		apiErr.Message = errResp.Error
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	return apiErr
+}
+
+// retryable reports whether a failed request may be retried: transport
+// failures, rate limiting and server errors. A response that could not be
+// decoded, or any other client error, would fail the same way again.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryable(t *testing.T) {
+	transportErr := fmt.Errorf("request to /api/games failed: %w", &url.Error{Op: "Get", URL: "http://localhost", Err: errors.New("connection refused")})
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"transport failure", transportErr, true},
+		{"rate limited", &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"server error", &APIError{StatusCode: http.StatusInternalServerError}, true},
+		{"bad gateway", &APIError{StatusCode: http.StatusBadGateway}, true},
+		{"bad request", &APIError{StatusCode: http.StatusBadRequest}, false},
+		{"unauthorized", &APIError{StatusCode: http.StatusUnauthorized}, false},
+		{"not found", &APIError{StatusCode: http.StatusNotFound}, false},
+		{"undecodable response", fmt.Errorf("failed to decode response from /api/games: %w", errors.New("invalid character")), false},
+		{"canceled", fmt.Errorf("request failed: %w", &url.Error{Op: "Get", URL: "http://localhost", Err: context.Canceled}), false},
+		{"deadline", context.DeadlineExceeded, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.err); got != tt.want {
+				t.Errorf("retryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRetriesOnlyTransientFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantOK   bool
+		wantErr  error
+		wantHits int32
+	}{
+		{name: "success", status: http.StatusOK, body: `{"count": 0, "games": []}`, wantOK: true, wantHits: 1},
+		{name: "server error", status: http.StatusServiceUnavailable, body: `{"error": "down"}`, wantHits: 3},
+		{name: "rate limited", status: http.StatusTooManyRequests, body: `{"error": "slow down"}`, wantErr: ErrRateLimited, wantHits: 3},
+		{name: "not found", status: http.StatusNotFound, body: `{"error": "gone"}`, wantErr: ErrNotFound, wantHits: 1},
+		{name: "undecodable response", status: http.StatusOK, body: `not json`, wantHits: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits.Add(1)
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, WithRetries(2, time.Millisecond))
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			_, err = c.Games(context.Background())
+			if (err == nil) != tt.wantOK {
+				t.Fatalf("Games error = %v, want success %v", err, tt.wantOK)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Games error = %v, want %v", err, tt.wantErr)
+			}
+			if got := hits.Load(); got != tt.wantHits {
+				t.Errorf("made %d requests, want %d", got, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestGetRetriesTransportFailures(t *testing.T) {
+	var attempts atomic.Int32
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		attempts.Add(1)
+		return nil, errors.New("connection reset")
+	})
+
+	c, err := New("http://bot.invalid", WithRetries(2, time.Millisecond), WithHTTPClient(&http.Client{Transport: transport}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := c.Status(context.Background()); err == nil {
+		t.Fatal("Status succeeded over a failing transport")
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("made %d attempts, want 3", got)
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
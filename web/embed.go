@@ -0,0 +1,15 @@
+// Package web embeds the documentation site's templates and static files, so
+// the binary serves them whatever its working directory
+package web
+
+import "embed"
+
+// Templates holds the HTML page templates under templates/
+//
+//go:embed templates/*.html
+var Templates embed.FS
+
+// Static holds the CSS and JavaScript served under /static/
+//
+//go:embed static
+var Static embed.FS